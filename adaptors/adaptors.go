@@ -31,45 +31,54 @@ var (
 
 // Adaptors ...
 type Adaptors struct {
-	db                    *gorm.DB
-	isTx                  bool
-	Node                  *Node
-	Script                *Script
-	Workflow              *Workflow
-	WorkflowScenario      *WorkflowScenario
-	Device                *Device
-	DeviceAction          *DeviceAction
-	DeviceState           *DeviceState
-	Flow                  *Flow
-	FlowElement           *FlowElement
-	FlowSubscription      *FlowSubscription
-	FlowZigbee2mqttDevice *FlowZigbee2mqttDevice
-	Connection            *Connection
-	Worker                *Worker
-	Role                  *Role
-	Permission            *Permission
-	User                  *User
-	UserMeta              *UserMeta
-	Image                 *Image
-	Variable              *Variable
-	Map                   *Map
-	MapLayer              *MapLayer
-	MapText               *MapText
-	MapImage              *MapImage
-	MapDevice             *MapDevice
-	MapElement            *MapElement
-	MapDeviceState        *MapDeviceState
-	MapDeviceAction       *MapDeviceAction
-	Log                   *Log
-	MapZone               *MapZone
-	Template              *Template
-	Message               *Message
-	MessageDelivery       *MessageDelivery
-	Zigbee2mqtt           *Zigbee2mqtt
-	Zigbee2mqttDevice     *Zigbee2mqttDevice
-	MapDeviceHistory      *MapDeviceHistory
-	AlexaSkill            *AlexaSkill
-	AlexaIntent           *AlexaIntent
+	db                      *gorm.DB
+	isTx                    bool
+	Node                    *Node
+	NodeAlertRule           *NodeAlertRule
+	Script                  *Script
+	Workflow                *Workflow
+	WorkflowScenario        *WorkflowScenario
+	WorkflowScenarioRule    *WorkflowScenarioRule
+	WorkflowScenarioHistory *WorkflowScenarioHistory
+	Device                  *Device
+	DeviceAction            *DeviceAction
+	DeviceState             *DeviceState
+	DeviceCurrentState      *DeviceCurrentState
+	Flow                    *Flow
+	FlowElement             *FlowElement
+	FlowRun                 *FlowRun
+	FlowSubscription        *FlowSubscription
+	FlowZigbee2mqttDevice   *FlowZigbee2mqttDevice
+	Connection              *Connection
+	Worker                  *Worker
+	Role                    *Role
+	Permission              *Permission
+	User                    *User
+	UserMeta                *UserMeta
+	Image                   *Image
+	Variable                *Variable
+	StorageItem             *StorageItem
+	Map                     *Map
+	MapLayer                *MapLayer
+	MapText                 *MapText
+	MapImage                *MapImage
+	MapDevice               *MapDevice
+	MapElement              *MapElement
+	MapDeviceState          *MapDeviceState
+	MapDeviceAction         *MapDeviceAction
+	Log                     *Log
+	MapZone                 *MapZone
+	Template                *Template
+	Message                 *Message
+	MessageDelivery         *MessageDelivery
+	Zigbee2mqtt             *Zigbee2mqtt
+	Zigbee2mqttDevice       *Zigbee2mqttDevice
+	Zigbee2mqttGroup        *Zigbee2mqttGroup
+	Zigbee2mqttScene        *Zigbee2mqttScene
+	Zigbee2mqttNetworkmap   *Zigbee2mqttNetworkmap
+	MapDeviceHistory        *MapDeviceHistory
+	AlexaSkill              *AlexaSkill
+	AlexaIntent             *AlexaIntent
 }
 
 // NewAdaptors ...
@@ -82,44 +91,53 @@ func NewAdaptors(db *gorm.DB,
 	}
 
 	adaptors = &Adaptors{
-		db:                    db,
-		Node:                  GetNodeAdaptor(db),
-		Script:                GetScriptAdaptor(db),
-		Workflow:              GetWorkflowAdaptor(db),
-		WorkflowScenario:      GetWorkflowScenarioAdaptor(db),
-		Device:                GetDeviceAdaptor(db),
-		DeviceAction:          GetDeviceActionAdaptor(db),
-		DeviceState:           GetDeviceStateAdaptor(db),
-		Flow:                  GetFlowAdaptor(db),
-		FlowElement:           GetFlowElementAdaptor(db),
-		FlowSubscription:      GetFlowSubscriptionAdaptor(db),
-		FlowZigbee2mqttDevice: GetFlowZigbee2mqttDeviceAdaptor(db),
-		Connection:            GetConnectionAdaptor(db),
-		Worker:                GetWorkerAdaptor(db),
-		Role:                  GetRoleAdaptor(db),
-		Permission:            GetPermissionAdaptor(db),
-		User:                  GetUserAdaptor(db),
-		UserMeta:              GetUserMetaAdaptor(db),
-		Image:                 GetImageAdaptor(db),
-		Variable:              GetVariableAdaptor(db),
-		Map:                   GetMapAdaptor(db),
-		MapLayer:              GetMapLayerAdaptor(db),
-		MapText:               GetMapTextAdaptor(db),
-		MapImage:              GetMapImageAdaptor(db),
-		MapDevice:             GetMapDeviceAdaptor(db),
-		MapElement:            GetMapElementAdaptor(db),
-		MapDeviceState:        GetMapDeviceStateAdaptor(db),
-		MapDeviceAction:       GetMapDeviceActionAdaptor(db),
-		Log:                   GetLogAdaptor(db),
-		MapZone:               GetMapZoneAdaptor(db),
-		Template:              GetTemplateAdaptor(db),
-		Message:               GetMessageAdaptor(db),
-		MessageDelivery:       GetMessageDeliveryAdaptor(db),
-		Zigbee2mqtt:           GetZigbee2mqttAdaptor(db),
-		Zigbee2mqttDevice:     GetZigbee2mqttDeviceAdaptor(db),
-		MapDeviceHistory:      GetMapDeviceHistoryAdaptor(db),
-		AlexaSkill:            GetAlexaSkillAdaptor(db),
-		AlexaIntent:           GetAlexaIntentAdaptor(db),
+		db:                      db,
+		Node:                    GetNodeAdaptor(db),
+		NodeAlertRule:           GetNodeAlertRuleAdaptor(db),
+		Script:                  GetScriptAdaptor(db),
+		Workflow:                GetWorkflowAdaptor(db),
+		WorkflowScenario:        GetWorkflowScenarioAdaptor(db),
+		WorkflowScenarioRule:    GetWorkflowScenarioRuleAdaptor(db),
+		WorkflowScenarioHistory: GetWorkflowScenarioHistoryAdaptor(db),
+		Device:                  GetDeviceAdaptor(db),
+		DeviceAction:            GetDeviceActionAdaptor(db),
+		DeviceState:             GetDeviceStateAdaptor(db),
+		DeviceCurrentState:      GetDeviceCurrentStateAdaptor(db),
+		Flow:                    GetFlowAdaptor(db),
+		FlowElement:             GetFlowElementAdaptor(db),
+		FlowRun:                 GetFlowRunAdaptor(db),
+		FlowSubscription:        GetFlowSubscriptionAdaptor(db),
+		FlowZigbee2mqttDevice:   GetFlowZigbee2mqttDeviceAdaptor(db),
+		Connection:              GetConnectionAdaptor(db),
+		Worker:                  GetWorkerAdaptor(db),
+		Role:                    GetRoleAdaptor(db),
+		Permission:              GetPermissionAdaptor(db),
+		User:                    GetUserAdaptor(db),
+		UserMeta:                GetUserMetaAdaptor(db),
+		Image:                   GetImageAdaptor(db),
+		Variable:                GetVariableAdaptor(db),
+		StorageItem:             GetStorageItemAdaptor(db),
+		Map:                     GetMapAdaptor(db),
+		MapLayer:                GetMapLayerAdaptor(db),
+		MapText:                 GetMapTextAdaptor(db),
+		MapImage:                GetMapImageAdaptor(db),
+		MapDevice:               GetMapDeviceAdaptor(db),
+		MapElement:              GetMapElementAdaptor(db),
+		MapDeviceState:          GetMapDeviceStateAdaptor(db),
+		MapDeviceAction:         GetMapDeviceActionAdaptor(db),
+		Log:                     GetLogAdaptor(db),
+		MapZone:                 GetMapZoneAdaptor(db),
+		Template:                GetTemplateAdaptor(db),
+		Message:                 GetMessageAdaptor(db),
+		MessageDelivery:         GetMessageDeliveryAdaptor(db),
+		Zigbee2mqtt:             GetZigbee2mqttAdaptor(db),
+		Zigbee2mqttDevice:       GetZigbee2mqttDeviceAdaptor(db),
+		Zigbee2mqttGroup:        GetZigbee2mqttGroupAdaptor(db),
+		Zigbee2mqttScene:        GetZigbee2mqttSceneAdaptor(db),
+		Zigbee2mqttNetworkmap:   GetZigbee2mqttNetworkmapAdaptor(db),
+		MapDeviceHistory:        GetMapDeviceHistoryAdaptor(db),
+		AlexaSkill:              GetAlexaSkillAdaptor(db),
+		AlexaIntent:             GetAlexaIntentAdaptor(db),
 	}
 
 	return
@@ -19,6 +19,7 @@
 package adaptors
 
 import (
+	"github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/db"
 	m "github.com/e154/smart-home/models"
 	"github.com/jinzhu/gorm"
@@ -92,6 +93,23 @@ func (n *Device) GetByDeviceActionId(deviceActionId int64) (device *m.Device, er
 	return
 }
 
+// GetByZigbee2mqttDeviceId ...
+func (n *Device) GetByZigbee2mqttDeviceId(zigbeeDeviceId string) (list []*m.Device, err error) {
+
+	var dbList []*db.Device
+	if dbList, err = n.table.GetByZigbee2mqttDeviceId(zigbeeDeviceId); err != nil {
+		return
+	}
+
+	list = make([]*m.Device, 0)
+	for _, dbDevice := range dbList {
+		device := n.fromDb(dbDevice)
+		list = append(list, device)
+	}
+
+	return
+}
+
 // Update ...
 func (n *Device) Update(device *m.Device) (err error) {
 	dbDevice := n.toDb(device)
@@ -146,6 +164,7 @@ func (n *Device) fromDb(dbDevice *db.Device) (device *m.Device) {
 		Type:        dbDevice.Type,
 		Properties:  dbDevice.Properties,
 		IsGroup:     dbDevice.IsGroup,
+		GroupMode:   dbDevice.GroupMode,
 		Actions:     make([]*m.DeviceAction, 0),
 		States:      make([]*m.DeviceState, 0),
 		Devices:     make([]*m.Device, 0),
@@ -185,6 +204,12 @@ func (n *Device) fromDb(dbDevice *db.Device) (device *m.Device) {
 		device.Node = nodeAdaptor.fromDb(dbDevice.Node)
 	}
 
+	// backup node
+	if dbDevice.BackupNode != nil {
+		nodeAdaptor := GetNodeAdaptor(n.db)
+		device.BackupNode = nodeAdaptor.fromDb(dbDevice.BackupNode)
+	}
+
 	return
 }
 
@@ -197,6 +222,11 @@ func (n *Device) toDb(device *m.Device) (dbDevice *db.Device) {
 		Properties:  device.Properties,
 		Type:        device.Type,
 		IsGroup:     device.IsGroup,
+		GroupMode:   device.GroupMode,
+	}
+
+	if dbDevice.GroupMode == "" {
+		dbDevice.GroupMode = common.DeviceGroupSequence
 	}
 
 	// device
@@ -209,5 +239,10 @@ func (n *Device) toDb(device *m.Device) (dbDevice *db.Device) {
 		dbDevice.NodeId.Scan(device.Node.Id)
 	}
 
+	// backup node
+	if device.BackupNode != nil && device.BackupNode.Id != 0 {
+		dbDevice.BackupNodeId.Scan(device.BackupNode.Id)
+	}
+
 	return
 }
@@ -0,0 +1,104 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// DeviceCurrentState ...
+type DeviceCurrentState struct {
+	table *db.DeviceCurrentStates
+	db    *gorm.DB
+}
+
+// GetDeviceCurrentStateAdaptor ...
+func GetDeviceCurrentStateAdaptor(d *gorm.DB) *DeviceCurrentState {
+	return &DeviceCurrentState{
+		table: &db.DeviceCurrentStates{Db: d},
+		db:    d,
+	}
+}
+
+// Set ...
+func (n *DeviceCurrentState) Set(state *m.DeviceCurrentState) (err error) {
+	err = n.table.Set(n.toDb(state))
+	return
+}
+
+// GetByDeviceId ...
+func (n *DeviceCurrentState) GetByDeviceId(deviceId int64) (state *m.DeviceCurrentState, err error) {
+
+	var dbState *db.DeviceCurrentState
+	if dbState, err = n.table.GetByDeviceId(deviceId); err != nil {
+		return
+	}
+
+	state = n.fromDb(dbState)
+
+	return
+}
+
+// List ...
+func (n *DeviceCurrentState) List() (list []*m.DeviceCurrentState, err error) {
+
+	var dbList []*db.DeviceCurrentState
+	if dbList, err = n.table.List(); err != nil {
+		return
+	}
+
+	list = make([]*m.DeviceCurrentState, 0, len(dbList))
+	for _, dbState := range dbList {
+		list = append(list, n.fromDb(dbState))
+	}
+
+	return
+}
+
+// Delete ...
+func (n *DeviceCurrentState) Delete(deviceId int64) (err error) {
+	err = n.table.Delete(deviceId)
+	return
+}
+
+func (n *DeviceCurrentState) fromDb(dbState *db.DeviceCurrentState) (state *m.DeviceCurrentState) {
+	state = &m.DeviceCurrentState{
+		DeviceId:      dbState.DeviceId,
+		DeviceStateId: dbState.DeviceStateId,
+		ChangedAt:     dbState.ChangedAt,
+	}
+
+	if dbState.DeviceState != nil {
+		deviceStateAdaptor := GetDeviceStateAdaptor(n.db)
+		state.DeviceState = deviceStateAdaptor.fromDb(dbState.DeviceState)
+	}
+
+	return
+}
+
+func (n *DeviceCurrentState) toDb(state *m.DeviceCurrentState) (dbState *db.DeviceCurrentState) {
+	dbState = &db.DeviceCurrentState{
+		DeviceId:      state.DeviceId,
+		DeviceStateId: state.DeviceStateId,
+		ChangedAt:     state.ChangedAt,
+	}
+	return
+}
@@ -19,6 +19,7 @@
 package adaptors
 
 import (
+	"encoding/json"
 	"github.com/e154/smart-home/db"
 	m "github.com/e154/smart-home/models"
 	"github.com/jinzhu/gorm"
@@ -116,6 +117,13 @@ func (n *DeviceState) fromDb(dbVer *db.DeviceState) (ver *m.DeviceState) {
 		DeviceId:    dbVer.DeviceId,
 		CreatedAt:   dbVer.CreatedAt,
 		UpdatedAt:   dbVer.UpdatedAt,
+		Transitions: make([]string, 0),
+	}
+
+	if len(dbVer.Transitions) > 0 {
+		if err := json.Unmarshal(dbVer.Transitions, &ver.Transitions); err != nil {
+			log.Error(err.Error())
+		}
 	}
 
 	if dbVer.Device != nil {
@@ -133,5 +141,12 @@ func (n *DeviceState) toDb(device *m.DeviceState) (dbDeviceState *db.DeviceState
 		DeviceId:    device.DeviceId,
 		SystemName:  device.SystemName,
 	}
+
+	transitions := device.Transitions
+	if transitions == nil {
+		transitions = make([]string, 0)
+	}
+	dbDeviceState.Transitions, _ = json.Marshal(transitions)
+
 	return
 }
@@ -19,6 +19,7 @@
 package adaptors
 
 import (
+	"github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/db"
 	m "github.com/e154/smart-home/models"
 	"github.com/jinzhu/gorm"
@@ -150,6 +151,9 @@ func (n *Flow) fromDb(dbFlow *db.Flow) (flow *m.Flow) {
 		Description:        dbFlow.Description,
 		WorkflowId:         dbFlow.WorkflowId,
 		WorkflowScenarioId: dbFlow.WorkflowScenarioId,
+		ConcurrencyMode:    dbFlow.ConcurrencyMode,
+		Concurrency:        dbFlow.Concurrency,
+		QueueSize:          dbFlow.QueueSize,
 		Workers:            make([]*m.Worker, 0),
 		FlowElements:       make([]*m.FlowElement, 0),
 		Connections:        make([]*m.Connection, 0),
@@ -221,6 +225,18 @@ func (n *Flow) toDb(flow *m.Flow) (dbFlow *db.Flow) {
 		Description:        flow.Description,
 		WorkflowId:         flow.WorkflowId,
 		WorkflowScenarioId: flow.WorkflowScenarioId,
+		ConcurrencyMode:    flow.ConcurrencyMode,
+		Concurrency:        flow.Concurrency,
+		QueueSize:          flow.QueueSize,
+	}
+	if dbFlow.ConcurrencyMode == "" {
+		dbFlow.ConcurrencyMode = common.FlowConcurrencySerial
+	}
+	if dbFlow.Concurrency < 1 {
+		dbFlow.Concurrency = 1
+	}
+	if dbFlow.QueueSize < 1 {
+		dbFlow.QueueSize = 10
 	}
 	return
 }
@@ -116,6 +116,7 @@ func (n *Connection) fromDb(dbConnection *db.Connection) (con *m.Connection) {
 		FlowId:        dbConnection.FlowId,
 		GraphSettings: dbConnection.GraphSettings,
 		Direction:     dbConnection.Direction,
+		Condition:     dbConnection.Condition,
 		ElementFrom:   dbConnection.ElementFrom,
 		ElementTo:     dbConnection.ElementTo,
 		PointFrom:     dbConnection.PointFrom,
@@ -135,6 +136,7 @@ func (n *Connection) toDb(con *m.Connection) (dbConnection *db.Connection) {
 		ElementTo:     con.ElementTo,
 		ElementFrom:   con.ElementFrom,
 		Direction:     con.Direction,
+		Condition:     con.Condition,
 		GraphSettings: con.GraphSettings,
 		FlowId:        con.FlowId,
 	}
@@ -20,6 +20,7 @@ package adaptors
 
 import (
 	"encoding/json"
+	"github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/db"
 	m "github.com/e154/smart-home/models"
 	"github.com/e154/smart-home/system/uuid"
@@ -134,6 +135,7 @@ func (n *FlowElement) fromDb(dbFlowElement *db.FlowElement) (element *m.FlowElem
 		ScriptId:      dbFlowElement.ScriptId,
 		FlowId:        dbFlowElement.FlowId,
 		PrototypeType: dbFlowElement.PrototypeType,
+		GatewayType:   dbFlowElement.GatewayType,
 		CreatedAt:     dbFlowElement.CreatedAt,
 		UpdatedAt:     dbFlowElement.UpdatedAt,
 	}
@@ -148,6 +150,11 @@ func (n *FlowElement) fromDb(dbFlowElement *db.FlowElement) (element *m.FlowElem
 		log.Error(err.Error())
 	}
 
+	settings, _ := dbFlowElement.Settings.MarshalJSON()
+	if err := json.Unmarshal(settings, &element.Settings); err != nil {
+		log.Error(err.Error())
+	}
+
 	return
 }
 
@@ -156,6 +163,7 @@ func (n *FlowElement) toDb(element *m.FlowElement) (dbFlowElement *db.FlowElemen
 		Uuid:          element.Uuid,
 		Name:          element.Name,
 		PrototypeType: element.PrototypeType,
+		GatewayType:   element.GatewayType,
 		ScriptId:      element.ScriptId,
 		FlowLink:      element.FlowLink,
 		FlowId:        element.FlowId,
@@ -163,8 +171,15 @@ func (n *FlowElement) toDb(element *m.FlowElement) (dbFlowElement *db.FlowElemen
 		Description:   element.Description,
 	}
 
+	if dbFlowElement.GatewayType == "" {
+		dbFlowElement.GatewayType = common.FlowElementsGatewayExclusive
+	}
+
 	graphSettings, _ := json.Marshal(element.GraphSettings)
 	dbFlowElement.GraphSettings.UnmarshalJSON(graphSettings)
 
+	settings, _ := json.Marshal(element.Settings)
+	dbFlowElement.Settings.UnmarshalJSON(settings)
+
 	return
 }
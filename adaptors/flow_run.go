@@ -0,0 +1,205 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"encoding/json"
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// FlowRun ...
+type FlowRun struct {
+	table *db.FlowRuns
+	steps *db.FlowRunSteps
+	db    *gorm.DB
+}
+
+// GetFlowRunAdaptor ...
+func GetFlowRunAdaptor(d *gorm.DB) *FlowRun {
+	return &FlowRun{
+		table: &db.FlowRuns{Db: d},
+		steps: &db.FlowRunSteps{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *FlowRun) Add(run *m.FlowRun) (id uuid.UUID, err error) {
+
+	id, err = n.table.Add(n.toDb(run))
+
+	return
+}
+
+// Update ...
+func (n *FlowRun) Update(run *m.FlowRun) (err error) {
+	err = n.table.Update(n.toDb(run))
+	return
+}
+
+// AddStep ...
+func (n *FlowRun) AddStep(step *m.FlowRunStep) (id int64, err error) {
+
+	id, err = n.steps.Add(n.stepToDb(step))
+
+	return
+}
+
+// AddSteps store the steps of the run in the single transaction
+func (n *FlowRun) AddSteps(steps []*m.FlowRunStep) (err error) {
+
+	if len(steps) == 0 {
+		return
+	}
+
+	transaction := true
+	tx := n.db.Begin()
+	if err = tx.Error; err != nil {
+		tx = n.db
+		transaction = false
+	}
+
+	defer func() {
+		if err != nil && transaction {
+			tx.Rollback()
+		}
+	}()
+
+	table := db.FlowRunSteps{Db: tx}
+	for _, step := range steps {
+		if step.Id, err = table.Add(n.stepToDb(step)); err != nil {
+			return
+		}
+	}
+
+	if transaction {
+		err = tx.Commit().Error
+	}
+
+	return
+}
+
+// GetById ...
+func (n *FlowRun) GetById(id uuid.UUID) (run *m.FlowRun, err error) {
+
+	var dbRun *db.FlowRun
+	if dbRun, err = n.table.GetById(id); err != nil {
+		return
+	}
+
+	run = n.fromDb(dbRun)
+
+	return
+}
+
+// ListByFlowId ...
+func (n *FlowRun) ListByFlowId(flowId int64, limit, offset int) (list []*m.FlowRun, total int64, err error) {
+
+	var dbList []*db.FlowRun
+	if dbList, total, err = n.table.ListByFlowId(flowId, limit, offset); err != nil {
+		return
+	}
+
+	list = make([]*m.FlowRun, len(dbList))
+	for i, dbRun := range dbList {
+		list[i] = n.fromDb(dbRun)
+	}
+
+	return
+}
+
+// DeleteOld keep the last runs of each flow
+func (n *FlowRun) DeleteOld(keep int) (err error) {
+	err = n.table.DeleteOld(keep)
+	return
+}
+
+func (n *FlowRun) fromDb(dbRun *db.FlowRun) (run *m.FlowRun) {
+	run = &m.FlowRun{
+		Id:         dbRun.Id,
+		FlowId:     dbRun.FlowId,
+		Status:     dbRun.Status,
+		Error:      dbRun.Error,
+		Steps:      make([]*m.FlowRunStep, 0, len(dbRun.Steps)),
+		StartedAt:  dbRun.StartedAt,
+		FinishedAt: dbRun.FinishedAt,
+	}
+
+	for _, dbStep := range dbRun.Steps {
+		run.Steps = append(run.Steps, n.stepFromDb(dbStep))
+	}
+
+	return
+}
+
+func (n *FlowRun) toDb(run *m.FlowRun) (dbRun *db.FlowRun) {
+	dbRun = &db.FlowRun{
+		Id:         run.Id,
+		FlowId:     run.FlowId,
+		Status:     run.Status,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+	return
+}
+
+func (n *FlowRun) stepFromDb(dbStep *db.FlowRunStep) (step *m.FlowRunStep) {
+	step = &m.FlowRunStep{
+		Id:              dbStep.Id,
+		FlowRunId:       dbStep.FlowRunId,
+		FlowElementUuid: dbStep.FlowElementUuid,
+		Status:          dbStep.Status,
+		Error:           dbStep.Error,
+		StartedAt:       dbStep.StartedAt,
+		FinishedAt:      dbStep.FinishedAt,
+	}
+
+	vars, _ := dbStep.Vars.MarshalJSON()
+	if err := json.Unmarshal(vars, &step.Vars); err != nil {
+		log.Error(err.Error())
+	}
+
+	return
+}
+
+func (n *FlowRun) stepToDb(step *m.FlowRunStep) (dbStep *db.FlowRunStep) {
+	dbStep = &db.FlowRunStep{
+		Id:              step.Id,
+		FlowRunId:       step.FlowRunId,
+		FlowElementUuid: step.FlowElementUuid,
+		Status:          step.Status,
+		Error:           step.Error,
+		StartedAt:       step.StartedAt,
+		FinishedAt:      step.FinishedAt,
+	}
+
+	// message vars may hold values which can not be encoded
+	vars, err := json.Marshal(step.Vars)
+	if err != nil {
+		log.Warn(err.Error())
+		vars = []byte("{}")
+	}
+	dbStep.Vars.UnmarshalJSON(vars)
+
+	return
+}
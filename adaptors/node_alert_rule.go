@@ -0,0 +1,150 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// NodeAlertRule ...
+type NodeAlertRule struct {
+	table *db.NodeAlertRules
+	db    *gorm.DB
+}
+
+// GetNodeAlertRuleAdaptor ...
+func GetNodeAlertRuleAdaptor(d *gorm.DB) *NodeAlertRule {
+	return &NodeAlertRule{
+		table: &db.NodeAlertRules{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *NodeAlertRule) Add(rule *m.NodeAlertRule) (id int64, err error) {
+	id, err = n.table.Add(n.toDb(rule))
+	return
+}
+
+// GetById ...
+func (n *NodeAlertRule) GetById(ruleId int64) (rule *m.NodeAlertRule, err error) {
+
+	var dbRule *db.NodeAlertRule
+	if dbRule, err = n.table.GetById(ruleId); err != nil {
+		return
+	}
+
+	rule = n.fromDb(dbRule)
+
+	return
+}
+
+// GetAllEnabled ...
+func (n *NodeAlertRule) GetAllEnabled() (list []*m.NodeAlertRule, err error) {
+
+	var dbList []*db.NodeAlertRule
+	if dbList, err = n.table.GetAllEnabled(); err != nil {
+		return
+	}
+
+	list = make([]*m.NodeAlertRule, 0)
+	for _, dbRule := range dbList {
+		list = append(list, n.fromDb(dbRule))
+	}
+
+	return
+}
+
+// Update ...
+func (n *NodeAlertRule) Update(rule *m.NodeAlertRule) (err error) {
+	err = n.table.Update(n.toDb(rule))
+	return
+}
+
+// Delete ...
+func (n *NodeAlertRule) Delete(ruleId int64) (err error) {
+	err = n.table.Delete(ruleId)
+	return
+}
+
+// List ...
+func (n *NodeAlertRule) List(limit, offset int64, orderBy, sort string) (list []*m.NodeAlertRule, total int64, err error) {
+
+	var dbList []*db.NodeAlertRule
+	if dbList, total, err = n.table.List(limit, offset, orderBy, sort); err != nil {
+		return
+	}
+
+	list = make([]*m.NodeAlertRule, 0)
+	for _, dbRule := range dbList {
+		list = append(list, n.fromDb(dbRule))
+	}
+
+	return
+}
+
+func (n *NodeAlertRule) fromDb(dbRule *db.NodeAlertRule) (rule *m.NodeAlertRule) {
+	rule = &m.NodeAlertRule{
+		Id:        dbRule.Id,
+		Name:      dbRule.Name,
+		Kind:      m.NodeAlertKind(dbRule.Kind),
+		Threshold: dbRule.Threshold,
+		Period:    dbRule.Period,
+		Channel:   m.NodeAlertChannel(dbRule.Channel),
+		Address:   dbRule.Address,
+		Enabled:   dbRule.Enabled,
+		CreatedAt: dbRule.CreatedAt,
+		UpdatedAt: dbRule.UpdatedAt,
+	}
+
+	// node
+	if dbRule.NodeId.Valid {
+		rule.NodeId = &dbRule.NodeId.Int64
+	}
+	if dbRule.Node != nil {
+		nodeAdaptor := GetNodeAdaptor(n.db)
+		rule.Node = nodeAdaptor.fromDb(dbRule.Node)
+	}
+
+	return
+}
+
+func (n *NodeAlertRule) toDb(rule *m.NodeAlertRule) (dbRule *db.NodeAlertRule) {
+	dbRule = &db.NodeAlertRule{
+		Id:        rule.Id,
+		Name:      rule.Name,
+		Kind:      string(rule.Kind),
+		Threshold: rule.Threshold,
+		Period:    rule.Period,
+		Channel:   string(rule.Channel),
+		Address:   rule.Address,
+		Enabled:   rule.Enabled,
+	}
+
+	// node
+	if rule.NodeId != nil {
+		dbRule.NodeId.Scan(*rule.NodeId)
+	} else if rule.Node != nil && rule.Node.Id != 0 {
+		dbRule.NodeId.Scan(rule.Node.Id)
+	}
+
+	return
+}
@@ -19,6 +19,7 @@
 package adaptors
 
 import (
+	"encoding/json"
 	"github.com/e154/smart-home/db"
 	m "github.com/e154/smart-home/models"
 	"github.com/jinzhu/copier"
@@ -64,6 +65,19 @@ func (n *Script) GetById(scriptId int64) (script *m.Script, err error) {
 	return
 }
 
+// GetByName ...
+func (n *Script) GetByName(name string) (script *m.Script, err error) {
+
+	var dbScript *db.Script
+	if dbScript, err = n.table.GetByName(name); err != nil {
+		return
+	}
+
+	script, _ = n.fromDb(dbScript)
+
+	return
+}
+
 // Update ...
 func (n *Script) Update(script *m.Script) (err error) {
 	var dbScript *db.Script
@@ -114,12 +128,27 @@ func (n *Script) Search(query string, limit, offset int) (list []*m.Script, tota
 
 func (n *Script) fromDb(dbScript *db.Script) (script *m.Script, err error) {
 	script = &m.Script{}
-	err = copier.Copy(&script, &dbScript)
+	if err = copier.Copy(&script, &dbScript); err != nil {
+		return
+	}
+
+	script.TestCases = make([]*m.ScriptTestCase, 0)
+	if len(dbScript.TestCases) > 0 {
+		err = json.Unmarshal(dbScript.TestCases, &script.TestCases)
+	}
 	return
 }
 
 func (n *Script) toDb(script *m.Script) (dbScript *db.Script, err error) {
 	dbScript = &db.Script{}
-	err = copier.Copy(&dbScript, &script)
+	if err = copier.Copy(&dbScript, &script); err != nil {
+		return
+	}
+
+	testCases := script.TestCases
+	if testCases == nil {
+		testCases = make([]*m.ScriptTestCase, 0)
+	}
+	dbScript.TestCases, err = json.Marshal(testCases)
 	return
 }
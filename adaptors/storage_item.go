@@ -0,0 +1,140 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"encoding/json"
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// StorageItem ...
+type StorageItem struct {
+	table *db.StorageItems
+	db    *gorm.DB
+}
+
+// GetStorageItemAdaptor ...
+func GetStorageItemAdaptor(d *gorm.DB) *StorageItem {
+	return &StorageItem{
+		table: &db.StorageItems{Db: d},
+		db:    d,
+	}
+}
+
+// Set ...
+func (n *StorageItem) Set(item *m.StorageItem) (err error) {
+
+	var dbItem *db.StorageItem
+	if dbItem, err = n.toDb(item); err != nil {
+		return
+	}
+
+	err = n.table.Set(dbItem)
+
+	return
+}
+
+// Get ...
+func (n *StorageItem) Get(namespace, key string) (item *m.StorageItem, err error) {
+
+	var dbItem *db.StorageItem
+	if dbItem, err = n.table.Get(namespace, key); err != nil {
+		return
+	}
+
+	item = n.fromDb(dbItem)
+
+	return
+}
+
+// GetByNamespace ...
+func (n *StorageItem) GetByNamespace(namespace string) (list []*m.StorageItem, err error) {
+
+	var dbList []*db.StorageItem
+	if dbList, err = n.table.GetByNamespace(namespace); err != nil {
+		return
+	}
+
+	list = make([]*m.StorageItem, 0, len(dbList))
+	for _, dbItem := range dbList {
+		list = append(list, n.fromDb(dbItem))
+	}
+
+	return
+}
+
+// Delete ...
+func (n *StorageItem) Delete(namespace, key string) (err error) {
+	err = n.table.Delete(namespace, key)
+	return
+}
+
+// DeleteExpired ...
+func (n *StorageItem) DeleteExpired() (err error) {
+	err = n.table.DeleteExpired()
+	return
+}
+
+// List ...
+func (n *StorageItem) List(limit, offset int64, orderBy, sort, namespace string) (list []*m.StorageItem, total int64, err error) {
+
+	var dbList []*db.StorageItem
+	if dbList, total, err = n.table.List(limit, offset, orderBy, sort, namespace); err != nil {
+		return
+	}
+
+	list = make([]*m.StorageItem, 0, len(dbList))
+	for _, dbItem := range dbList {
+		list = append(list, n.fromDb(dbItem))
+	}
+
+	return
+}
+
+func (n *StorageItem) fromDb(dbItem *db.StorageItem) (item *m.StorageItem) {
+	item = &m.StorageItem{
+		Namespace: dbItem.Namespace,
+		Key:       dbItem.Key,
+		ExpireAt:  dbItem.ExpireAt,
+		CreatedAt: dbItem.CreatedAt,
+		UpdatedAt: dbItem.UpdatedAt,
+	}
+
+	if len(dbItem.Value) > 0 {
+		_ = json.Unmarshal(dbItem.Value, &item.Value)
+	}
+
+	return
+}
+
+func (n *StorageItem) toDb(item *m.StorageItem) (dbItem *db.StorageItem, err error) {
+	dbItem = &db.StorageItem{
+		Namespace: item.Namespace,
+		Key:       item.Key,
+		ExpireAt:  item.ExpireAt,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+
+	dbItem.Value, err = json.Marshal(item.Value)
+
+	return
+}
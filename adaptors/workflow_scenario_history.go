@@ -0,0 +1,116 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// WorkflowScenarioHistory ...
+type WorkflowScenarioHistory struct {
+	table *db.WorkflowScenarioHistories
+	db    *gorm.DB
+}
+
+// GetWorkflowScenarioHistoryAdaptor ...
+func GetWorkflowScenarioHistoryAdaptor(d *gorm.DB) *WorkflowScenarioHistory {
+	return &WorkflowScenarioHistory{
+		table: &db.WorkflowScenarioHistories{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *WorkflowScenarioHistory) Add(record *m.WorkflowScenarioHistory) (id int64, err error) {
+	id, err = n.table.Add(n.toDb(record))
+	return
+}
+
+// GetLast ...
+func (n *WorkflowScenarioHistory) GetLast(workflowId int64) (record *m.WorkflowScenarioHistory, err error) {
+
+	var dbRecord *db.WorkflowScenarioHistory
+	if dbRecord, err = n.table.GetLast(workflowId); err != nil {
+		return
+	}
+
+	record = n.fromDb(dbRecord)
+
+	return
+}
+
+// ListByWorkflow ...
+func (n *WorkflowScenarioHistory) ListByWorkflow(workflowId, limit, offset int64, orderBy, sort string) (list []*m.WorkflowScenarioHistory, total int64, err error) {
+
+	var dbList []*db.WorkflowScenarioHistory
+	if dbList, total, err = n.table.ListByWorkflow(workflowId, limit, offset, orderBy, sort); err != nil {
+		return
+	}
+
+	list = make([]*m.WorkflowScenarioHistory, 0)
+	for _, dbRecord := range dbList {
+		list = append(list, n.fromDb(dbRecord))
+	}
+
+	return
+}
+
+func (n *WorkflowScenarioHistory) fromDb(dbRecord *db.WorkflowScenarioHistory) (record *m.WorkflowScenarioHistory) {
+	record = &m.WorkflowScenarioHistory{
+		Id:           dbRecord.Id,
+		WorkflowId:   dbRecord.WorkflowId,
+		FromScenario: dbRecord.FromScenario,
+		ToScenario:   dbRecord.ToScenario,
+		Trigger:      m.ScenarioTrigger(dbRecord.Trigger),
+		Initiator:    dbRecord.Initiator,
+		CreatedAt:    dbRecord.CreatedAt,
+	}
+
+	if dbRecord.FromScenarioId.Valid {
+		record.FromScenarioId = &dbRecord.FromScenarioId.Int64
+	}
+	if dbRecord.ToScenarioId.Valid {
+		record.ToScenarioId = &dbRecord.ToScenarioId.Int64
+	}
+
+	return
+}
+
+func (n *WorkflowScenarioHistory) toDb(record *m.WorkflowScenarioHistory) (dbRecord *db.WorkflowScenarioHistory) {
+	dbRecord = &db.WorkflowScenarioHistory{
+		Id:           record.Id,
+		WorkflowId:   record.WorkflowId,
+		FromScenario: record.FromScenario,
+		ToScenario:   record.ToScenario,
+		Trigger:      string(record.Trigger),
+		Initiator:    record.Initiator,
+		CreatedAt:    record.CreatedAt,
+	}
+
+	if record.FromScenarioId != nil {
+		dbRecord.FromScenarioId.Scan(*record.FromScenarioId)
+	}
+	if record.ToScenarioId != nil {
+		dbRecord.ToScenarioId.Scan(*record.ToScenarioId)
+	}
+
+	return
+}
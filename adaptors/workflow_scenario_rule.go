@@ -0,0 +1,154 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"encoding/json"
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// WorkflowScenarioRule ...
+type WorkflowScenarioRule struct {
+	table *db.WorkflowScenarioRules
+	db    *gorm.DB
+}
+
+// GetWorkflowScenarioRuleAdaptor ...
+func GetWorkflowScenarioRuleAdaptor(d *gorm.DB) *WorkflowScenarioRule {
+	return &WorkflowScenarioRule{
+		table: &db.WorkflowScenarioRules{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *WorkflowScenarioRule) Add(rule *m.WorkflowScenarioRule) (id int64, err error) {
+	id, err = n.table.Add(n.toDb(rule))
+	return
+}
+
+// GetById ...
+func (n *WorkflowScenarioRule) GetById(ruleId int64) (rule *m.WorkflowScenarioRule, err error) {
+
+	var dbRule *db.WorkflowScenarioRule
+	if dbRule, err = n.table.GetById(ruleId); err != nil {
+		return
+	}
+
+	rule = n.fromDb(dbRule)
+
+	return
+}
+
+// GetAllEnabled the rules ordered by priority
+func (n *WorkflowScenarioRule) GetAllEnabled() (list []*m.WorkflowScenarioRule, err error) {
+
+	var dbList []*db.WorkflowScenarioRule
+	if dbList, err = n.table.GetAllEnabled(); err != nil {
+		return
+	}
+
+	list = make([]*m.WorkflowScenarioRule, 0)
+	for _, dbRule := range dbList {
+		list = append(list, n.fromDb(dbRule))
+	}
+
+	return
+}
+
+// Update ...
+func (n *WorkflowScenarioRule) Update(rule *m.WorkflowScenarioRule) (err error) {
+	err = n.table.Update(n.toDb(rule))
+	return
+}
+
+// Delete ...
+func (n *WorkflowScenarioRule) Delete(ruleId int64) (err error) {
+	err = n.table.Delete(ruleId)
+	return
+}
+
+// ListByWorkflow ...
+func (n *WorkflowScenarioRule) ListByWorkflow(workflowId, limit, offset int64, orderBy, sort string) (list []*m.WorkflowScenarioRule, total int64, err error) {
+
+	var dbList []*db.WorkflowScenarioRule
+	if dbList, total, err = n.table.ListByWorkflow(workflowId, limit, offset, orderBy, sort); err != nil {
+		return
+	}
+
+	list = make([]*m.WorkflowScenarioRule, 0)
+	for _, dbRule := range dbList {
+		list = append(list, n.fromDb(dbRule))
+	}
+
+	return
+}
+
+func (n *WorkflowScenarioRule) fromDb(dbRule *db.WorkflowScenarioRule) (rule *m.WorkflowScenarioRule) {
+	rule = &m.WorkflowScenarioRule{
+		Id:                 dbRule.Id,
+		Name:               dbRule.Name,
+		WorkflowId:         dbRule.WorkflowId,
+		WorkflowScenarioId: dbRule.WorkflowScenarioId,
+		Priority:           dbRule.Priority,
+		Conditions:         make([]*m.ScenarioCondition, 0),
+		Delay:              dbRule.Delay,
+		Hold:               dbRule.Hold,
+		Enabled:            dbRule.Enabled,
+		CreatedAt:          dbRule.CreatedAt,
+		UpdatedAt:          dbRule.UpdatedAt,
+	}
+
+	if len(dbRule.Conditions) > 0 {
+		if err := json.Unmarshal(dbRule.Conditions, &rule.Conditions); err != nil {
+			log.Error(err.Error())
+		}
+	}
+
+	// scenario
+	if dbRule.WorkflowScenario != nil {
+		scenarioAdaptor := GetWorkflowScenarioAdaptor(n.db)
+		rule.WorkflowScenario = scenarioAdaptor.fromDb(dbRule.WorkflowScenario)
+	}
+
+	return
+}
+
+func (n *WorkflowScenarioRule) toDb(rule *m.WorkflowScenarioRule) (dbRule *db.WorkflowScenarioRule) {
+	dbRule = &db.WorkflowScenarioRule{
+		Id:                 rule.Id,
+		Name:               rule.Name,
+		WorkflowId:         rule.WorkflowId,
+		WorkflowScenarioId: rule.WorkflowScenarioId,
+		Priority:           rule.Priority,
+		Delay:              rule.Delay,
+		Hold:               rule.Hold,
+		Enabled:            rule.Enabled,
+	}
+
+	conditions := rule.Conditions
+	if conditions == nil {
+		conditions = make([]*m.ScenarioCondition, 0)
+	}
+	dbRule.Conditions, _ = json.Marshal(conditions)
+
+	return
+}
@@ -108,6 +108,8 @@ func (n *Zigbee2mqtt) fromDb(dbVer *db.Zigbee2mqtt) (ver *m.Zigbee2mqtt) {
 		Name:              dbVer.Name,
 		PermitJoin:        dbVer.PermitJoin,
 		BaseTopic:         dbVer.BaseTopic,
+		OtaWindowStart:    dbVer.OtaWindowStart,
+		OtaWindowEnd:      dbVer.OtaWindowEnd,
 		CreatedAt:         dbVer.CreatedAt,
 		UpdatedAt:         dbVer.UpdatedAt,
 		EncryptedPassword: dbVer.EncryptedPassword,
@@ -133,6 +135,8 @@ func (n *Zigbee2mqtt) toDb(ver *m.Zigbee2mqtt) (dbVer *db.Zigbee2mqtt) {
 		Name:              ver.Name,
 		PermitJoin:        ver.PermitJoin,
 		BaseTopic:         ver.BaseTopic,
+		OtaWindowStart:    ver.OtaWindowStart,
+		OtaWindowEnd:      ver.OtaWindowEnd,
 		EncryptedPassword: ver.EncryptedPassword,
 	}
 	if ver.Password != nil {
@@ -19,6 +19,7 @@
 package adaptors
 
 import (
+	"encoding/json"
 	"github.com/e154/smart-home/db"
 	m "github.com/e154/smart-home/models"
 	"github.com/jinzhu/gorm"
@@ -114,10 +115,14 @@ func (n *Zigbee2mqttDevice) fromDb(dbVer *db.Zigbee2mqttDevice) (ver *m.Zigbee2m
 		Description:   dbVer.Description,
 		Manufacturer:  dbVer.Manufacturer,
 		Functions:     dbVer.Functions,
+		Exposes:       make([]*m.Zigbee2mqttExpose, 0),
 		Status:        dbVer.Status,
 		CreatedAt:     dbVer.CreatedAt,
 		UpdatedAt:     dbVer.UpdatedAt,
 	}
+	if len(dbVer.Exposes) > 0 {
+		_ = json.Unmarshal(dbVer.Exposes, &ver.Exposes)
+	}
 	ver.GetImageUrl()
 	return
 }
@@ -136,5 +141,10 @@ func (n *Zigbee2mqttDevice) toDb(ver *m.Zigbee2mqttDevice) (dbVer *db.Zigbee2mqt
 		CreatedAt:     ver.CreatedAt,
 		UpdatedAt:     ver.UpdatedAt,
 	}
+	exposes := ver.Exposes
+	if exposes == nil {
+		exposes = make([]*m.Zigbee2mqttExpose, 0)
+	}
+	dbVer.Exposes, _ = json.Marshal(exposes)
 	return
 }
@@ -0,0 +1,158 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// Zigbee2mqttGroup ...
+type Zigbee2mqttGroup struct {
+	table *db.Zigbee2mqttGroups
+	db    *gorm.DB
+}
+
+// GetZigbee2mqttGroupAdaptor ...
+func GetZigbee2mqttGroupAdaptor(d *gorm.DB) *Zigbee2mqttGroup {
+	return &Zigbee2mqttGroup{
+		table: &db.Zigbee2mqttGroups{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *Zigbee2mqttGroup) Add(group *m.Zigbee2mqttGroup) (id int64, err error) {
+	id, err = n.table.Add(n.toDb(group))
+	return
+}
+
+// GetById ...
+func (n *Zigbee2mqttGroup) GetById(id int64) (group *m.Zigbee2mqttGroup, err error) {
+
+	var dbGroup *db.Zigbee2mqttGroup
+	if dbGroup, err = n.table.GetById(id); err != nil {
+		return
+	}
+
+	group = n.fromDb(dbGroup)
+
+	return
+}
+
+// GetByFriendlyName ...
+func (n *Zigbee2mqttGroup) GetByFriendlyName(friendlyName string) (group *m.Zigbee2mqttGroup, err error) {
+
+	var dbGroup *db.Zigbee2mqttGroup
+	if dbGroup, err = n.table.GetByFriendlyName(friendlyName); err != nil {
+		return
+	}
+
+	group = n.fromDb(dbGroup)
+
+	return
+}
+
+// NextGroupId ...
+func (n *Zigbee2mqttGroup) NextGroupId(bridgeId int64) (groupId int, err error) {
+	groupId, err = n.table.NextGroupId(bridgeId)
+	return
+}
+
+// Update ...
+func (n *Zigbee2mqttGroup) Update(group *m.Zigbee2mqttGroup) (err error) {
+	err = n.table.Update(n.toDb(group))
+	return
+}
+
+// Delete ...
+func (n *Zigbee2mqttGroup) Delete(id int64) (err error) {
+	err = n.table.Delete(id)
+	return
+}
+
+// ListByBridge ...
+func (n *Zigbee2mqttGroup) ListByBridge(bridgeId, limit, offset int64, orderBy, sort string) (list []*m.Zigbee2mqttGroup, total int64, err error) {
+
+	var dbList []*db.Zigbee2mqttGroup
+	if dbList, total, err = n.table.ListByBridge(bridgeId, limit, offset, orderBy, sort); err != nil {
+		return
+	}
+
+	list = make([]*m.Zigbee2mqttGroup, 0)
+	for _, dbGroup := range dbList {
+		list = append(list, n.fromDb(dbGroup))
+	}
+
+	return
+}
+
+// AddDevice ...
+func (n *Zigbee2mqttGroup) AddDevice(groupId int64, deviceId string) (err error) {
+	err = n.table.AddDevice(groupId, deviceId)
+	return
+}
+
+// DeleteDevice ...
+func (n *Zigbee2mqttGroup) DeleteDevice(groupId int64, deviceId string) (err error) {
+	err = n.table.DeleteDevice(groupId, deviceId)
+	return
+}
+
+func (n *Zigbee2mqttGroup) fromDb(dbGroup *db.Zigbee2mqttGroup) (group *m.Zigbee2mqttGroup) {
+	group = &m.Zigbee2mqttGroup{
+		Id:            dbGroup.Id,
+		Zigbee2mqttId: dbGroup.Zigbee2mqttId,
+		GroupId:       dbGroup.GroupId,
+		FriendlyName:  dbGroup.FriendlyName,
+		Description:   dbGroup.Description,
+		Devices:       make([]*m.Zigbee2mqttDevice, 0),
+		Scenes:        make([]*m.Zigbee2mqttScene, 0),
+		CreatedAt:     dbGroup.CreatedAt,
+		UpdatedAt:     dbGroup.UpdatedAt,
+	}
+
+	// devices
+	deviceAdaptor := GetZigbee2mqttDeviceAdaptor(n.db)
+	for _, dbDevice := range dbGroup.Devices {
+		group.Devices = append(group.Devices, deviceAdaptor.fromDb(dbDevice))
+	}
+
+	// scenes
+	sceneAdaptor := GetZigbee2mqttSceneAdaptor(n.db)
+	for _, dbScene := range dbGroup.Scenes {
+		group.Scenes = append(group.Scenes, sceneAdaptor.fromDb(dbScene))
+	}
+
+	return
+}
+
+func (n *Zigbee2mqttGroup) toDb(group *m.Zigbee2mqttGroup) (dbGroup *db.Zigbee2mqttGroup) {
+	dbGroup = &db.Zigbee2mqttGroup{
+		Id:            group.Id,
+		Zigbee2mqttId: group.Zigbee2mqttId,
+		GroupId:       group.GroupId,
+		FriendlyName:  group.FriendlyName,
+		Description:   group.Description,
+		CreatedAt:     group.CreatedAt,
+		UpdatedAt:     group.UpdatedAt,
+	}
+	return
+}
@@ -0,0 +1,130 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"encoding/json"
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// Zigbee2mqttNetworkmap ...
+type Zigbee2mqttNetworkmap struct {
+	table *db.Zigbee2mqttNetworkmaps
+	db    *gorm.DB
+}
+
+// GetZigbee2mqttNetworkmapAdaptor ...
+func GetZigbee2mqttNetworkmapAdaptor(d *gorm.DB) *Zigbee2mqttNetworkmap {
+	return &Zigbee2mqttNetworkmap{
+		table: &db.Zigbee2mqttNetworkmaps{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *Zigbee2mqttNetworkmap) Add(networkmap *m.Zigbee2mqttNetworkmap) (id int64, err error) {
+	id, err = n.table.Add(n.toDb(networkmap))
+	return
+}
+
+// GetById ...
+func (n *Zigbee2mqttNetworkmap) GetById(bridgeId, id int64) (networkmap *m.Zigbee2mqttNetworkmap, err error) {
+
+	var dbNetworkmap *db.Zigbee2mqttNetworkmap
+	if dbNetworkmap, err = n.table.GetById(bridgeId, id); err != nil {
+		return
+	}
+
+	networkmap = n.fromDb(dbNetworkmap)
+
+	return
+}
+
+// GetLast ...
+func (n *Zigbee2mqttNetworkmap) GetLast(bridgeId int64) (networkmap *m.Zigbee2mqttNetworkmap, err error) {
+
+	var dbNetworkmap *db.Zigbee2mqttNetworkmap
+	if dbNetworkmap, err = n.table.GetLast(bridgeId); err != nil {
+		return
+	}
+
+	networkmap = n.fromDb(dbNetworkmap)
+
+	return
+}
+
+// ListByBridge ...
+func (n *Zigbee2mqttNetworkmap) ListByBridge(bridgeId, limit, offset int64) (list []*m.Zigbee2mqttNetworkmap, total int64, err error) {
+
+	var dbList []*db.Zigbee2mqttNetworkmap
+	if dbList, total, err = n.table.ListByBridge(bridgeId, limit, offset); err != nil {
+		return
+	}
+
+	list = make([]*m.Zigbee2mqttNetworkmap, 0)
+	for _, dbNetworkmap := range dbList {
+		list = append(list, n.fromDb(dbNetworkmap))
+	}
+
+	return
+}
+
+// Delete ...
+func (n *Zigbee2mqttNetworkmap) Delete(id int64) (err error) {
+	err = n.table.Delete(id)
+	return
+}
+
+func (n *Zigbee2mqttNetworkmap) fromDb(dbNetworkmap *db.Zigbee2mqttNetworkmap) (networkmap *m.Zigbee2mqttNetworkmap) {
+	networkmap = &m.Zigbee2mqttNetworkmap{
+		Id:            dbNetworkmap.Id,
+		Zigbee2mqttId: dbNetworkmap.Zigbee2mqttId,
+		Nodes:         make([]*m.Zigbee2mqttNetworkNode, 0),
+		Links:         make([]*m.Zigbee2mqttNetworkLink, 0),
+		CreatedAt:     dbNetworkmap.CreatedAt,
+	}
+	if len(dbNetworkmap.Nodes) > 0 {
+		_ = json.Unmarshal(dbNetworkmap.Nodes, &networkmap.Nodes)
+	}
+	if len(dbNetworkmap.Links) > 0 {
+		_ = json.Unmarshal(dbNetworkmap.Links, &networkmap.Links)
+	}
+	return
+}
+
+func (n *Zigbee2mqttNetworkmap) toDb(networkmap *m.Zigbee2mqttNetworkmap) (dbNetworkmap *db.Zigbee2mqttNetworkmap) {
+	dbNetworkmap = &db.Zigbee2mqttNetworkmap{
+		Id:            networkmap.Id,
+		Zigbee2mqttId: networkmap.Zigbee2mqttId,
+		CreatedAt:     networkmap.CreatedAt,
+	}
+	nodes := networkmap.Nodes
+	if nodes == nil {
+		nodes = make([]*m.Zigbee2mqttNetworkNode, 0)
+	}
+	links := networkmap.Links
+	if links == nil {
+		links = make([]*m.Zigbee2mqttNetworkLink, 0)
+	}
+	dbNetworkmap.Nodes, _ = json.Marshal(nodes)
+	dbNetworkmap.Links, _ = json.Marshal(links)
+	return
+}
@@ -0,0 +1,94 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"github.com/e154/smart-home/db"
+	m "github.com/e154/smart-home/models"
+	"github.com/jinzhu/gorm"
+)
+
+// Zigbee2mqttScene ...
+type Zigbee2mqttScene struct {
+	table *db.Zigbee2mqttScenes
+	db    *gorm.DB
+}
+
+// GetZigbee2mqttSceneAdaptor ...
+func GetZigbee2mqttSceneAdaptor(d *gorm.DB) *Zigbee2mqttScene {
+	return &Zigbee2mqttScene{
+		table: &db.Zigbee2mqttScenes{Db: d},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *Zigbee2mqttScene) Add(scene *m.Zigbee2mqttScene) (id int64, err error) {
+	id, err = n.table.Add(n.toDb(scene))
+	return
+}
+
+// GetBySceneId ...
+func (n *Zigbee2mqttScene) GetBySceneId(groupId int64, sceneId int) (scene *m.Zigbee2mqttScene, err error) {
+
+	var dbScene *db.Zigbee2mqttScene
+	if dbScene, err = n.table.GetBySceneId(groupId, sceneId); err != nil {
+		return
+	}
+
+	scene = n.fromDb(dbScene)
+
+	return
+}
+
+// Update ...
+func (n *Zigbee2mqttScene) Update(scene *m.Zigbee2mqttScene) (err error) {
+	err = n.table.Update(n.toDb(scene))
+	return
+}
+
+// Delete ...
+func (n *Zigbee2mqttScene) Delete(id int64) (err error) {
+	err = n.table.Delete(id)
+	return
+}
+
+func (n *Zigbee2mqttScene) fromDb(dbScene *db.Zigbee2mqttScene) (scene *m.Zigbee2mqttScene) {
+	scene = &m.Zigbee2mqttScene{
+		Id:                 dbScene.Id,
+		Zigbee2mqttGroupId: dbScene.Zigbee2mqttGroupId,
+		SceneId:            dbScene.SceneId,
+		Name:               dbScene.Name,
+		CreatedAt:          dbScene.CreatedAt,
+		UpdatedAt:          dbScene.UpdatedAt,
+	}
+	return
+}
+
+func (n *Zigbee2mqttScene) toDb(scene *m.Zigbee2mqttScene) (dbScene *db.Zigbee2mqttScene) {
+	dbScene = &db.Zigbee2mqttScene{
+		Id:                 scene.Id,
+		Zigbee2mqttGroupId: scene.Zigbee2mqttGroupId,
+		SceneId:            scene.SceneId,
+		Name:               scene.Name,
+		CreatedAt:          scene.CreatedAt,
+		UpdatedAt:          scene.UpdatedAt,
+	}
+	return
+}
@@ -198,7 +198,9 @@ func (c ControllerWorkflow) UpdateScenario(ctx *gin.Context) {
 		return
 	}
 
-	err = c.endpoint.Workflow.UpdateScenario(int64(aid), workflowScenario.WorkflowScenarioId)
+	user, _ := c.getUser(ctx)
+
+	err = c.endpoint.Workflow.UpdateScenario(int64(aid), workflowScenario.WorkflowScenarioId, user)
 	if err != nil {
 		code := 500
 		if err.Error() == "record not found" {
@@ -32,6 +32,8 @@ type NewDevice struct {
 	Type        string           `json:"type"`
 	Node        *NewDeviceNode   `json:"node"`
 	Properties  DeviceProperties `json:"properties"`
+	IsGroup     bool             `json:"is_group"`
+	GroupMode   string           `json:"group_mode"`
 }
 
 // swagger:model
@@ -43,6 +45,8 @@ type UpdateDevice struct {
 	Type        string           `json:"type"`
 	Node        *NewDeviceNode   `json:"node"`
 	Properties  DeviceProperties `json:"properties"`
+	IsGroup     bool             `json:"is_group"`
+	GroupMode   string           `json:"group_mode"`
 }
 
 // ParentDevice ...
@@ -60,6 +64,7 @@ type Device struct {
 	Type       string           `json:"type"`
 	Status     string           `json:"status"`
 	IsGroup    bool             `json:"is_group"`
+	GroupMode  string           `json:"group_mode"`
 	Actions    []DeviceAction   `json:"actions"`
 	States     []DeviceState    `json:"states"`
 	Device     *ParentDevice    `json:"device"`
@@ -28,6 +28,7 @@ type NewDeviceState struct {
 	Description string             `json:"description"`
 	SystemName  string             `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *DeviceStateDevice `json:"device" valid:"Required"`
+	Transitions []string           `json:"transitions"`
 }
 
 // swagger:model
@@ -35,6 +36,7 @@ type UpdateDeviceState struct {
 	Description string             `json:"description"`
 	SystemName  string             `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *DeviceStateDevice `json:"device" valid:"Required"`
+	Transitions []string           `json:"transitions"`
 }
 
 // swagger:model
@@ -44,4 +46,5 @@ type DeviceState struct {
 	SystemName  string             `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *DeviceStateDevice `json:"device" valid:"Required"`
 	DeviceId    int64              `json:"device_id"`
+	Transitions []string           `json:"transitions"`
 }
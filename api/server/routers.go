@@ -59,6 +59,13 @@ func (s *Server) setControllers() {
 	v1.GET("/nodes", s.af.Auth, s.ControllersV1.Node.GetList)
 	v1.GET("/nodes/search", s.af.Auth, s.ControllersV1.Node.Search)
 
+	// node alert rules
+	v1.POST("/node_alert_rule", s.af.Auth, s.ControllersV1.NodeAlertRule.Add)
+	v1.GET("/node_alert_rule/:id", s.af.Auth, s.ControllersV1.NodeAlertRule.GetById)
+	v1.PUT("/node_alert_rule/:id", s.af.Auth, s.ControllersV1.NodeAlertRule.Update)
+	v1.DELETE("/node_alert_rule/:id", s.af.Auth, s.ControllersV1.NodeAlertRule.Delete)
+	v1.GET("/node_alert_rules", s.af.Auth, s.ControllersV1.NodeAlertRule.GetList)
+
 	// scripts
 	v1.POST("/script", s.af.Auth, s.ControllersV1.Script.Add)
 	v1.GET("/script/:id", s.af.Auth, s.ControllersV1.Script.GetById)
@@ -68,6 +75,8 @@ func (s *Server) setControllers() {
 	v1.POST("/script/:id/exec", s.af.Auth, s.ControllersV1.Script.Exec)
 	v1.POST("/script/:id/copy", s.af.Auth, s.ControllersV1.Script.Copy)
 	v1.POST("/script/:id/exec_src", s.af.Auth, s.ControllersV1.Script.ExecSrc)
+	v1.POST("/script/:id/dry_run", s.af.Auth, s.ControllersV1.Script.DryRun)
+	v1.POST("/script/:id/test", s.af.Auth, s.ControllersV1.Script.Test)
 	v1.GET("/scripts/search", s.af.Auth, s.ControllersV1.Script.Search)
 
 	// workflow
@@ -78,6 +87,7 @@ func (s *Server) setControllers() {
 	v1.GET("/workflows", s.af.Auth, s.ControllersV1.Workflow.GetList)
 	v1.GET("/workflows/search", s.af.Auth, s.ControllersV1.Workflow.Search)
 	v1.PUT("/workflow/:id/update_scenario", s.af.Auth, s.ControllersV1.Workflow.UpdateScenario)
+	v1.GET("/workflow/:id/scenario_history", s.af.Auth, s.ControllersV1.Workflow.ScenarioHistory)
 
 	// workflow scenario
 	v1.POST("/workflow/:id/scenario", s.af.Auth, s.ControllersV1.WorkflowScenario.Add)
@@ -87,9 +97,18 @@ func (s *Server) setControllers() {
 	v1.GET("/workflow/:id/scenarios/search", s.af.Auth, s.ControllersV1.WorkflowScenario.Search)
 	v1.DELETE("/workflow/:id/scenario/:scenario_id", s.af.Auth, s.ControllersV1.WorkflowScenario.Delete)
 
+	// workflow scenario rules
+	v1.POST("/workflow/:id/scenario_rule", s.af.Auth, s.ControllersV1.WorkflowScenarioRule.Add)
+	v1.GET("/workflow/:id/scenario_rule/:rule_id", s.af.Auth, s.ControllersV1.WorkflowScenarioRule.GetById)
+	v1.PUT("/workflow/:id/scenario_rule/:rule_id", s.af.Auth, s.ControllersV1.WorkflowScenarioRule.Update)
+	v1.DELETE("/workflow/:id/scenario_rule/:rule_id", s.af.Auth, s.ControllersV1.WorkflowScenarioRule.Delete)
+	v1.GET("/workflow/:id/scenario_rules", s.af.Auth, s.ControllersV1.WorkflowScenarioRule.GetList)
+
 	// device
 	v1.POST("/device", s.af.Auth, s.ControllersV1.Device.Add)
 	v1.GET("/device/:id", s.af.Auth, s.ControllersV1.Device.GetById)
+	v1.GET("/device/:id/state", s.af.Auth, s.ControllersV1.Device.GetState)
+	v1.POST("/device/:id/capabilities", s.af.Auth, s.ControllersV1.Device.AddCapabilities)
 	v1.PUT("/device/:id", s.af.Auth, s.ControllersV1.Device.UpdateDevice)
 	v1.DELETE("/device/:id", s.af.Auth, s.ControllersV1.Device.Delete)
 	v1.GET("/devices", s.af.Auth, s.ControllersV1.Device.GetList)
@@ -172,11 +191,22 @@ func (s *Server) setControllers() {
 	v1.GET("/flow/:id", s.af.Auth, s.ControllersV1.Flow.GetById)
 	v1.GET("/flows", s.af.Auth, s.ControllersV1.Flow.GetList)
 	v1.GET("/flow/:id/redactor", s.af.Auth, s.ControllersV1.Flow.GetRedactor)
+	v1.GET("/flow/:id/runs", s.af.Auth, s.ControllersV1.Flow.GetRuns)
+	v1.GET("/flow/:id/run/:run_id", s.af.Auth, s.ControllersV1.Flow.GetRun)
 	v1.PUT("/flow/:id/redactor", s.af.Auth, s.ControllersV1.Flow.UpdateRedactor)
 	v1.PUT("/flow/:id", s.af.Auth, s.ControllersV1.Flow.Update)
 	v1.DELETE("/flow/:id", s.af.Auth, s.ControllersV1.Flow.Delete)
 	v1.GET("/flows/search", s.af.Auth, s.ControllersV1.Flow.Search)
 
+	// worker
+	v1.GET("/worker/next_time", s.af.Auth, s.ControllersV1.Worker.NextTime)
+
+	// storage
+	v1.GET("/storage/:namespace/:key", s.af.Auth, s.ControllersV1.Storage.Get)
+	v1.PUT("/storage/:namespace/:key", s.af.Auth, s.ControllersV1.Storage.Set)
+	v1.DELETE("/storage/:namespace/:key", s.af.Auth, s.ControllersV1.Storage.Delete)
+	v1.GET("/storages", s.af.Auth, s.ControllersV1.Storage.GetList)
+
 	// logs
 	v1.POST("/log", s.af.Auth, s.ControllersV1.Log.Add)
 	v1.GET("/log/:id", s.af.Auth, s.ControllersV1.Log.GetById)
@@ -243,8 +273,25 @@ func (s *Server) setControllers() {
 	v1.POST("/zigbee2mqtt/:id/device_whitelist", s.af.Auth, s.ControllersV1.Zigbee2mqtt.DeviceWhitelist)
 	v1.GET("/zigbee2mqtt/:id/networkmap", s.af.Auth, s.ControllersV1.Zigbee2mqtt.Networkmap)
 	v1.POST("/zigbee2mqtt/:id/update_networkmap", s.af.Auth, s.ControllersV1.Zigbee2mqtt.UpdateNetworkmap)
+	v1.GET("/zigbee2mqtt/:id/networkmap/analysis", s.af.Auth, s.ControllersV1.Zigbee2mqttNetworkmap.Analysis)
+	v1.GET("/zigbee2mqtt/:id/networkmaps", s.af.Auth, s.ControllersV1.Zigbee2mqttNetworkmap.GetList)
+	v1.GET("/zigbee2mqtt/:id/networkmaps/:networkmap_id", s.af.Auth, s.ControllersV1.Zigbee2mqttNetworkmap.GetById)
+	v1.GET("/zigbee2mqtt/:id/ota", s.af.Auth, s.ControllersV1.Zigbee2mqtt.OtaStatus)
+	v1.POST("/zigbee2mqtt/:id/ota/check", s.af.Auth, s.ControllersV1.Zigbee2mqtt.OtaCheck)
+	v1.POST("/zigbee2mqtt/:id/ota/update", s.af.Auth, s.ControllersV1.Zigbee2mqtt.OtaUpdate)
 	v1.PATCH("/zigbee2mqtts/device_rename", s.af.Auth, s.ControllersV1.Zigbee2mqtt.DeviceRename)
 	v1.GET("/zigbee2mqtts/search_device", s.af.Auth, s.ControllersV1.Zigbee2mqtt.Search)
+	v1.POST("/zigbee2mqtt/:id/groups", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.Add)
+	v1.GET("/zigbee2mqtt/:id/groups", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.GetList)
+	v1.GET("/zigbee2mqtt/:id/groups/:group_id", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.GetById)
+	v1.PUT("/zigbee2mqtt/:id/groups/:group_id", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.Update)
+	v1.DELETE("/zigbee2mqtt/:id/groups/:group_id", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.Delete)
+	v1.POST("/zigbee2mqtt/:id/groups/:group_id/devices", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.AddDevice)
+	v1.DELETE("/zigbee2mqtt/:id/groups/:group_id/devices/:device_id", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.RemoveDevice)
+	v1.PUT("/zigbee2mqtt/:id/groups/:group_id/state", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.SetState)
+	v1.POST("/zigbee2mqtt/:id/groups/:group_id/scenes", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.StoreScene)
+	v1.POST("/zigbee2mqtt/:id/groups/:group_id/scenes/:scene_id/recall", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.RecallScene)
+	v1.DELETE("/zigbee2mqtt/:id/groups/:group_id/scenes/:scene_id", s.af.Auth, s.ControllersV1.Zigbee2mqttGroup.DeleteScene)
 
 	// map device history
 	v1.GET("/history/map", s.af.Auth, s.ControllersV1.MapDeviceHistory.GetList)
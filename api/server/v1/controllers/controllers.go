@@ -28,34 +28,40 @@ import (
 
 // ControllersV1 ...
 type ControllersV1 struct {
-	Index            *ControllerIndex
-	Node             *ControllerNode
-	Swagger          *ControllerSwagger
-	Script           *ControllerScript
-	Workflow         *ControllerWorkflow
-	Device           *ControllerDevice
-	Role             *ControllerRole
-	User             *ControllerUser
-	Auth             *ControllerAuth
-	DeviceAction     *ControllerDeviceAction
-	DeviceState      *ControllerDeviceState
-	Map              *ControllerMap
-	MapLayer         *ControllerMapLayer
-	MapElement       *ControllerMapElement
-	Image            *ControllerImage
-	WorkflowScenario *ControllerWorkflowScenario
-	Flow             *ControllerFlow
-	Log              *ControllerLog
-	Gate             *ControllerGate
-	MapZone          *ControllerMapZone
-	Template         *ControllerTemplate
-	TemplateItem     *ControllerTemplateItem
-	Notifr           *ControllerNotifr
-	Mqtt             *ControllerMqtt
-	Version          *ControllerVersion
-	Zigbee2mqtt      *ControllerZigbee2mqtt
-	MapDeviceHistory *ControllerMapDeviceHistory
-	Alexa            *ControllerAlexa
+	Index                 *ControllerIndex
+	Node                  *ControllerNode
+	NodeAlertRule         *ControllerNodeAlertRule
+	Swagger               *ControllerSwagger
+	Script                *ControllerScript
+	Workflow              *ControllerWorkflow
+	Device                *ControllerDevice
+	Role                  *ControllerRole
+	User                  *ControllerUser
+	Auth                  *ControllerAuth
+	DeviceAction          *ControllerDeviceAction
+	DeviceState           *ControllerDeviceState
+	Map                   *ControllerMap
+	MapLayer              *ControllerMapLayer
+	MapElement            *ControllerMapElement
+	Image                 *ControllerImage
+	WorkflowScenario      *ControllerWorkflowScenario
+	WorkflowScenarioRule  *ControllerWorkflowScenarioRule
+	Flow                  *ControllerFlow
+	Log                   *ControllerLog
+	Gate                  *ControllerGate
+	MapZone               *ControllerMapZone
+	Template              *ControllerTemplate
+	TemplateItem          *ControllerTemplateItem
+	Notifr                *ControllerNotifr
+	Mqtt                  *ControllerMqtt
+	Version               *ControllerVersion
+	Zigbee2mqtt           *ControllerZigbee2mqtt
+	Zigbee2mqttGroup      *ControllerZigbee2mqttGroup
+	Zigbee2mqttNetworkmap *ControllerZigbee2mqttNetworkmap
+	MapDeviceHistory      *ControllerMapDeviceHistory
+	Alexa                 *ControllerAlexa
+	Worker                *ControllerWorker
+	Storage               *ControllerStorage
 }
 
 // NewControllersV1 ...
@@ -66,33 +72,39 @@ func NewControllersV1(adaptors *adaptors.Adaptors,
 	command *endpoint.Endpoint) *ControllersV1 {
 	common := NewControllerCommon(adaptors, core, accessList, command)
 	return &ControllersV1{
-		Index:            NewControllerIndex(common),
-		Node:             NewControllerNode(common),
-		Swagger:          NewControllerSwagger(common),
-		Script:           NewControllerScript(common, scriptService),
-		Workflow:         NewControllerWorkflow(common),
-		Device:           NewControllerDevice(common),
-		Role:             NewControllerRole(common),
-		User:             NewControllerUser(common),
-		Auth:             NewControllerAuth(common),
-		DeviceAction:     NewControllerDeviceAction(common),
-		DeviceState:      NewControllerDeviceState(common),
-		Map:              NewControllerMap(common),
-		MapLayer:         NewControllerMapLayer(common),
-		MapElement:       NewControllerMapElement(common),
-		Image:            NewControllerImage(common),
-		WorkflowScenario: NewControllerWorkflowScenario(common),
-		Flow:             NewControllerFlow(common),
-		Log:              NewControllerLog(common),
-		Gate:             NewControllerGate(common),
-		MapZone:          NewControllerMapZone(common),
-		Template:         NewControllerTemplate(common),
-		TemplateItem:     NewControllerTemplateItem(common),
-		Notifr:           NewControllerNotifr(common),
-		Mqtt:             NewControllerMqtt(common),
-		Version:          NewControllerVersion(common),
-		Zigbee2mqtt:      NewControllerZigbee2mqtt(common),
-		MapDeviceHistory: NewControllerMapDeviceHistory(common),
-		Alexa:            NewControllerAlexa(common),
+		Index:                 NewControllerIndex(common),
+		Node:                  NewControllerNode(common),
+		NodeAlertRule:         NewControllerNodeAlertRule(common),
+		Swagger:               NewControllerSwagger(common),
+		Script:                NewControllerScript(common, scriptService),
+		Workflow:              NewControllerWorkflow(common),
+		Device:                NewControllerDevice(common),
+		Role:                  NewControllerRole(common),
+		User:                  NewControllerUser(common),
+		Auth:                  NewControllerAuth(common),
+		DeviceAction:          NewControllerDeviceAction(common),
+		DeviceState:           NewControllerDeviceState(common),
+		Map:                   NewControllerMap(common),
+		MapLayer:              NewControllerMapLayer(common),
+		MapElement:            NewControllerMapElement(common),
+		Image:                 NewControllerImage(common),
+		WorkflowScenario:      NewControllerWorkflowScenario(common),
+		WorkflowScenarioRule:  NewControllerWorkflowScenarioRule(common),
+		Flow:                  NewControllerFlow(common),
+		Log:                   NewControllerLog(common),
+		Gate:                  NewControllerGate(common),
+		MapZone:               NewControllerMapZone(common),
+		Template:              NewControllerTemplate(common),
+		TemplateItem:          NewControllerTemplateItem(common),
+		Notifr:                NewControllerNotifr(common),
+		Mqtt:                  NewControllerMqtt(common),
+		Version:               NewControllerVersion(common),
+		Zigbee2mqtt:           NewControllerZigbee2mqtt(common),
+		Zigbee2mqttGroup:      NewControllerZigbee2mqttGroup(common),
+		Zigbee2mqttNetworkmap: NewControllerZigbee2mqttNetworkmap(common),
+		MapDeviceHistory:      NewControllerMapDeviceHistory(common),
+		Alexa:                 NewControllerAlexa(common),
+		Worker:                NewControllerWorker(common),
+		Storage:               NewControllerStorage(common),
 	}
 }
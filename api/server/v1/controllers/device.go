@@ -97,6 +97,10 @@ func (c ControllerDevice) Add(ctx *gin.Context) {
 		device.Node = &m.Node{Id: params.Node.Id}
 	}
 
+	if params.BackupNode != nil && params.BackupNode.Id != 0 {
+		device.BackupNode = &m.Node{Id: params.BackupNode.Id}
+	}
+
 	device, errs, err := c.endpoint.Device.Add(device)
 	if len(errs) > 0 {
 		err400 := NewError(400)
@@ -172,6 +176,118 @@ func (c ControllerDevice) GetById(ctx *gin.Context) {
 	resp.SetData(device).Send(ctx)
 }
 
+// swagger:operation POST /device/{id}/capabilities deviceAddCapabilities
+// ---
+// parameters:
+// - description: Device ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// summary: add the actions and the states of the zigbee2mqtt device
+// description: the actions and the states are generated from the exposes of the zigbee2mqtt device, the existing ones are kept
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - device
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Device'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerDevice) AddCapabilities(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	device, err := c.endpoint.Device.AddCapabilities(int64(aid))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Device{}
+	_ = common.Copy(&result, &device, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /device/{id}/state deviceGetState
+// ---
+// parameters:
+// - description: Device ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// summary: get current device state
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - device
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/DeviceCurrentState'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerDevice) GetState(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	state, err := c.endpoint.Device.GetState(int64(aid))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.DeviceCurrentState{}
+	_ = common.Copy(&result, &state, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
 // swagger:operation PUT /device/{id} deviceUpdateById
 // ---
 // parameters:
@@ -237,6 +353,11 @@ func (c ControllerDevice) UpdateDevice(ctx *gin.Context) {
 		Status:      params.Status,
 		Type:        common.DeviceType(params.Type),
 	}
+
+	if params.BackupNode != nil && params.BackupNode.Id != 0 {
+		device.BackupNode = &m.Node{Id: params.BackupNode.Id}
+	}
+
 	device, errs, err := c.endpoint.Device.Update(device)
 	if len(errs) > 0 {
 		err400 := NewError(400)
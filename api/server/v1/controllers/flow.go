@@ -22,6 +22,7 @@ import (
 	"github.com/e154/smart-home/api/server/v1/models"
 	"github.com/e154/smart-home/common"
 	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/uuid"
 	"github.com/gin-gonic/gin"
 	"strconv"
 )
@@ -529,3 +530,138 @@ func (c ControllerFlow) Search(ctx *gin.Context) {
 	resp.Item("flows", result)
 	resp.Send(ctx)
 }
+
+// swagger:operation GET /flow/{id}/runs flowRunList
+// ---
+// summary: get flow run history
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - flow
+// parameters:
+// - description: Flow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// responses:
+//   "200":
+//	   $ref: '#/responses/FlowRunList'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerFlow) GetRuns(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	_, _, _, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.Flow.GetRuns(int64(aid), limit, offset)
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.FlowRun, 0)
+	_ = common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+}
+
+// swagger:operation GET /flow/{id}/run/{run_id} flowGetRun
+// ---
+// parameters:
+// - description: Flow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Run ID
+//   in: path
+//   name: run_id
+//   required: true
+//   type: string
+// summary: get flow run with the steps
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - flow
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/FlowRun'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerFlow) GetRun(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	runId, err := uuid.FromString(ctx.Param("run_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	run, err := c.endpoint.Flow.GetRun(int64(aid), runId)
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.FlowRun{}
+	_ = common.Copy(&result, &run, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
@@ -0,0 +1,359 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ControllerNodeAlertRule ...
+type ControllerNodeAlertRule struct {
+	*ControllerCommon
+}
+
+// NewControllerNodeAlertRule ...
+func NewControllerNodeAlertRule(common *ControllerCommon) *ControllerNodeAlertRule {
+	return &ControllerNodeAlertRule{ControllerCommon: common}
+}
+
+// swagger:operation POST /node_alert_rule nodeAlertRuleAdd
+// ---
+// parameters:
+// - description: alert rule params
+//   in: body
+//   name: rule
+//   required: true
+//   schema:
+//     $ref: '#/definitions/NewNodeAlertRule'
+//     type: object
+// summary: add new node alert rule
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - node_alert_rule
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/NodeAlertRule'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerNodeAlertRule) Add(ctx *gin.Context) {
+
+	params := &models.NewNodeAlertRule{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	rule := &m.NodeAlertRule{
+		Name:      params.Name,
+		Kind:      m.NodeAlertKind(params.Kind),
+		Threshold: params.Threshold,
+		Period:    params.Period,
+		Channel:   m.NodeAlertChannel(params.Channel),
+		Address:   params.Address,
+		Enabled:   params.Enabled,
+	}
+
+	if params.Node != nil && params.Node.Id != 0 {
+		rule.NodeId = &params.Node.Id
+	}
+
+	rule, errs, err := c.endpoint.NodeAlertRule.Add(rule)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		NewError(500, err).Send(ctx)
+		return
+	}
+
+	result := &models.NodeAlertRule{}
+	common.Copy(&result, &rule, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /node_alert_rule/{id} nodeAlertRuleGetById
+// ---
+// parameters:
+// - description: Rule ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// summary: get node alert rule by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - node_alert_rule
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/NodeAlertRule'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerNodeAlertRule) GetById(ctx *gin.Context) {
+
+	aid, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	rule, err := c.endpoint.NodeAlertRule.GetById(int64(aid))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.NodeAlertRule{}
+	common.Copy(&result, &rule, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation PUT /node_alert_rule/{id} nodeAlertRuleUpdateById
+// ---
+// parameters:
+// - description: Rule ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Update alert rule params
+//   in: body
+//   name: rule
+//   required: true
+//   schema:
+//     $ref: '#/definitions/UpdateNodeAlertRule'
+//     type: object
+// summary: update node alert rule by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - node_alert_rule
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/NodeAlertRule'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerNodeAlertRule) Update(ctx *gin.Context) {
+
+	aid, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.UpdateNodeAlertRule{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	rule := &m.NodeAlertRule{
+		Id:        int64(aid),
+		Name:      params.Name,
+		Kind:      m.NodeAlertKind(params.Kind),
+		Threshold: params.Threshold,
+		Period:    params.Period,
+		Channel:   m.NodeAlertChannel(params.Channel),
+		Address:   params.Address,
+		Enabled:   params.Enabled,
+	}
+
+	if params.Node != nil && params.Node.Id != 0 {
+		rule.NodeId = &params.Node.Id
+	}
+
+	rule, errs, err := c.endpoint.NodeAlertRule.Update(rule)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.NodeAlertRule{}
+	common.Copy(&result, &rule, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /node_alert_rules nodeAlertRuleList
+// ---
+// summary: get node alert rule list
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - node_alert_rule
+// parameters:
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// - default: DESC
+//   description: order
+//   in: query
+//   name: order
+//   type: string
+// - default: id
+//   description: sort_by
+//   in: query
+//   name: sort_by
+//   type: string
+// responses:
+//   "200":
+//	   $ref: '#/responses/NodeAlertRuleList'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerNodeAlertRule) GetList(ctx *gin.Context) {
+
+	_, sortBy, order, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.NodeAlertRule.GetList(int64(limit), int64(offset), order, sortBy)
+	if err != nil {
+		NewError(500, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.NodeAlertRule, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+	return
+}
+
+// swagger:operation DELETE /node_alert_rule/{id} nodeAlertRuleDeleteById
+// ---
+// parameters:
+// - description: Rule ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// summary: delete node alert rule by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - node_alert_rule
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerNodeAlertRule) Delete(ctx *gin.Context) {
+
+	aid, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.NodeAlertRule.Delete(int64(aid)); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
@@ -85,7 +85,12 @@ func (c ControllerScript) Add(ctx *gin.Context) {
 		Name:        params.Name,
 		Source:      params.Source,
 		Description: params.Description,
+		Timeout:     params.Timeout,
+		MaxStack:    params.MaxStack,
+		MaxMemory:   params.MaxMemory,
+		AllowExec:   c.allowExec(ctx),
 	}
+	common.Copy(&script.TestCases, &params.TestCases, common.JsonEngine)
 
 	script, errs, err := c.endpoint.Script.Add(script)
 	if len(errs) > 0 {
@@ -218,6 +223,7 @@ func (c ControllerScript) Update(ctx *gin.Context) {
 
 	script := &m.Script{}
 	common.Copy(&script, &params, common.JsonEngine)
+	script.AllowExec = c.allowExec(ctx)
 
 	script, errs, err := c.endpoint.Script.Update(script)
 	if len(errs) > 0 {
@@ -439,7 +445,7 @@ func (c ControllerScript) Copy(ctx *gin.Context) {
 		return
 	}
 
-	script, err := c.endpoint.Script.Copy(int64(aid))
+	script, err := c.endpoint.Script.Copy(int64(aid), c.allowExec(ctx))
 	if err != nil {
 		code := 500
 		if err.Error() == "record not found" {
@@ -492,6 +498,8 @@ func (c ControllerScript) ExecSrc(ctx *gin.Context) {
 		return
 	}
 
+	script.AllowExec = c.allowExec(ctx)
+
 	result, err := c.endpoint.Script.ExecuteSource(script)
 	if err != nil {
 		NewError(500, err).Send(ctx)
@@ -502,6 +510,135 @@ func (c ControllerScript) ExecSrc(ctx *gin.Context) {
 	resp.Item("result", result).Send(ctx)
 }
 
+// swagger:operation POST /script/{id}/dry_run scriptDryRun
+// ---
+// parameters:
+// - description: Script ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: global variables of the script and results of the mocks
+//   in: body
+//   name: params
+//   required: true
+//   schema:
+//     $ref: '#/definitions/ScriptDryRunParams'
+//     type: object
+// summary: run script with mocked devices
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - script
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/ScriptDryRun'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerScript) DryRun(ctx *gin.Context) {
+
+	aid, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.ScriptDryRunParams{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	run, err := c.endpoint.Script.DryRun(int64(aid), params.Vars, params.Returns)
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.ScriptDryRun{}
+	common.Copy(&result, &run, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation POST /script/{id}/test scriptTest
+// ---
+// parameters:
+// - description: Script ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// summary: run test cases of the script
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - script
+// responses:
+//   "200":
+//	   $ref: '#/responses/ScriptTest'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerScript) Test(ctx *gin.Context) {
+
+	aid, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	list, err := c.endpoint.Script.Test(int64(aid))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	passed := true
+	for _, item := range list {
+		passed = passed && item.Passed
+	}
+
+	result := make([]*models.ScriptTestResult, 0)
+	common.Copy(&result, &list, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Item("passed", passed).
+		Item("results", result).
+		Send(ctx)
+}
+
 // swagger:operation GET /scripts/search scriptSearch
 // ---
 // summary: search script
@@ -552,3 +689,12 @@ func (c ControllerScript) Search(ctx *gin.Context) {
 	resp.Item("scripts", result)
 	resp.Send(ctx)
 }
+
+// allowExec the role of the current user may use the shell commands in the scripts
+func (c ControllerScript) allowExec(ctx *gin.Context) bool {
+	user, err := c.getUser(ctx)
+	if err != nil {
+		return false
+	}
+	return c.accessList.HasPermission(user, "script", "exec_command")
+}
@@ -0,0 +1,271 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// ControllerStorage ...
+type ControllerStorage struct {
+	*ControllerCommon
+}
+
+// NewControllerStorage ...
+func NewControllerStorage(common *ControllerCommon) *ControllerStorage {
+	return &ControllerStorage{ControllerCommon: common}
+}
+
+// swagger:operation GET /storage/{namespace}/{key} storageGet
+// ---
+// parameters:
+// - description: namespace, e.g. "workflow.1" or "flow.2"
+//   in: path
+//   name: namespace
+//   required: true
+//   type: string
+// - description: key
+//   in: path
+//   name: key
+//   required: true
+//   type: string
+// summary: get storage value
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - storage
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/StorageItem'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerStorage) Get(ctx *gin.Context) {
+
+	item, err := c.endpoint.Storage.Get(ctx.Param("namespace"), ctx.Param("key"))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.StorageItem{}
+	common.Copy(&result, &item, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation PUT /storage/{namespace}/{key} storageSet
+// ---
+// parameters:
+// - description: namespace, e.g. "workflow.1" or "flow.2"
+//   in: path
+//   name: namespace
+//   required: true
+//   type: string
+// - description: key
+//   in: path
+//   name: key
+//   required: true
+//   type: string
+// - description: Update storage value params
+//   in: body
+//   name: storage
+//   required: true
+//   schema:
+//     $ref: '#/definitions/UpdateStorageItem'
+//     type: object
+// summary: set storage value
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - storage
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/StorageItem'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerStorage) Set(ctx *gin.Context) {
+
+	params := &models.UpdateStorageItem{}
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if params.Ttl < 0 {
+		NewError(400, "ttl must not be negative").Send(ctx)
+		return
+	}
+
+	item := &m.StorageItem{
+		Namespace: ctx.Param("namespace"),
+		Key:       ctx.Param("key"),
+		Value:     params.Value,
+	}
+
+	item, errs, err := c.endpoint.Storage.Set(item, time.Duration(params.Ttl)*time.Second)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		NewError(500, err).Send(ctx)
+		return
+	}
+
+	result := &models.StorageItem{}
+	common.Copy(&result, &item, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /storages storageList
+// ---
+// summary: get storage value list
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - storage
+// parameters:
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// - default: DESC
+//   description: order
+//   in: query
+//   name: order
+//   type: string
+// - default: created_at
+//   description: sortby
+//   in: query
+//   name: sortby
+//   type: string
+// - description: namespace, e.g. "workflow.1" or "flow.2"
+//   in: query
+//   name: query
+//   type: string
+// responses:
+//   "200":
+//	   $ref: '#/responses/StorageItemList'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerStorage) GetList(ctx *gin.Context) {
+
+	query, sortBy, order, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.Storage.GetList(int64(limit), int64(offset), order, sortBy, query)
+	if err != nil {
+		NewError(500, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.StorageItem, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+}
+
+// swagger:operation DELETE /storage/{namespace}/{key} storageDelete
+// ---
+// parameters:
+// - description: namespace, e.g. "workflow.1" or "flow.2"
+//   in: path
+//   name: namespace
+//   required: true
+//   type: string
+// - description: key
+//   in: path
+//   name: key
+//   required: true
+//   type: string
+// summary: delete storage value
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - storage
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerStorage) Delete(ctx *gin.Context) {
+
+	if err := c.endpoint.Storage.Delete(ctx.Param("namespace"), ctx.Param("key")); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
@@ -0,0 +1,97 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ControllerWorker ...
+type ControllerWorker struct {
+	*ControllerCommon
+}
+
+// NewControllerWorker ...
+func NewControllerWorker(common *ControllerCommon) *ControllerWorker {
+	return &ControllerWorker{ControllerCommon: common}
+}
+
+// swagger:operation GET /worker/next_time workerNextTime
+// ---
+// summary: validate worker time string and get upcoming fire times
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - worker
+// parameters:
+// - description: time string, e.g. "0 */5 * * * MON-FRI" or "@sunset +30m"
+//   in: query
+//   name: time
+//   required: true
+//   type: string
+// - default: 5
+//   description: count
+//   in: query
+//   name: count
+//   type: integer
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/WorkerNextTime'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+func (c ControllerWorker) NextTime(ctx *gin.Context) {
+
+	spec := ctx.Query("time")
+	if spec == "" {
+		NewError(400, "time is required param").Send(ctx)
+		return
+	}
+
+	var count int
+	if countStr := ctx.Query("count"); countStr != "" {
+		var err error
+		if count, err = strconv.Atoi(countStr); err != nil {
+			NewError(400, err).Send(ctx)
+			return
+		}
+	}
+
+	times, err := c.endpoint.Worker.NextTime(spec, count)
+	if err != nil {
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	result := &models.WorkerNextTime{
+		Time:  spec,
+		Items: times,
+	}
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
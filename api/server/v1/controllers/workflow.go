@@ -444,7 +444,9 @@ func (c ControllerWorkflow) UpdateScenario(ctx *gin.Context) {
 		return
 	}
 
-	err = c.endpoint.Workflow.UpdateScenario(int64(aid), workflowScenario.WorkflowScenarioId)
+	user, _ := c.getUser(ctx)
+
+	err = c.endpoint.Workflow.UpdateScenario(int64(aid), workflowScenario.WorkflowScenarioId, user)
 	if err != nil {
 		code := 500
 		if err.Error() == "record not found" {
@@ -459,3 +461,80 @@ func (c ControllerWorkflow) UpdateScenario(ctx *gin.Context) {
 
 	return
 }
+
+// swagger:operation GET /workflow/{id}/scenario_history workflowScenarioHistory
+// ---
+// summary: get the scenario switches of the workflow
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - workflow
+// parameters:
+// - description: Workflow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// - default: DESC
+//   description: order
+//   in: query
+//   name: order
+//   type: string
+// - default: created_at
+//   description: sort_by
+//   in: query
+//   name: sort_by
+//   type: string
+// responses:
+//   "200":
+//	   $ref: '#/responses/WorkflowScenarioHistoryList'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerWorkflow) ScenarioHistory(ctx *gin.Context) {
+
+	aid, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	_, sortBy, order, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.Workflow.ScenarioHistory(int64(aid), int64(limit), int64(offset), order, sortBy)
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.WorkflowScenarioHistory, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+	return
+}
@@ -0,0 +1,423 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ControllerWorkflowScenarioRule ...
+type ControllerWorkflowScenarioRule struct {
+	*ControllerCommon
+}
+
+// NewControllerWorkflowScenarioRule ...
+func NewControllerWorkflowScenarioRule(common *ControllerCommon) *ControllerWorkflowScenarioRule {
+	return &ControllerWorkflowScenarioRule{ControllerCommon: common}
+}
+
+// swagger:operation POST /workflow/{id}/scenario_rule workflowScenarioRuleAdd
+// ---
+// parameters:
+// - description: Workflow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: scenario rule params
+//   in: body
+//   name: rule
+//   required: true
+//   schema:
+//     $ref: '#/definitions/NewWorkflowScenarioRule'
+//     type: object
+// summary: add new workflow scenario rule
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - workflow_scenario_rule
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/WorkflowScenarioRule'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerWorkflowScenarioRule) Add(ctx *gin.Context) {
+
+	workflowId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.NewWorkflowScenarioRule{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	rule := &m.WorkflowScenarioRule{
+		Name:               params.Name,
+		WorkflowId:         int64(workflowId),
+		WorkflowScenarioId: params.WorkflowScenarioId,
+		Priority:           params.Priority,
+		Conditions:         make([]*m.ScenarioCondition, 0),
+		Delay:              params.Delay,
+		Hold:               params.Hold,
+		Enabled:            params.Enabled,
+	}
+	_ = common.Copy(&rule.Conditions, &params.Conditions, common.JsonEngine)
+
+	rule, errs, err := c.endpoint.WorkflowScenarioRule.Add(rule)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.WorkflowScenarioRule{}
+	common.Copy(&result, &rule, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /workflow/{id}/scenario_rule/{rule_id} workflowScenarioRuleGetById
+// ---
+// parameters:
+// - description: Workflow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Rule ID
+//   in: path
+//   name: rule_id
+//   required: true
+//   type: integer
+// summary: get workflow scenario rule by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - workflow_scenario_rule
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/WorkflowScenarioRule'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerWorkflowScenarioRule) GetById(ctx *gin.Context) {
+
+	workflowId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	ruleId, err := strconv.Atoi(ctx.Param("rule_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	rule, err := c.endpoint.WorkflowScenarioRule.GetById(int64(workflowId), int64(ruleId))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.WorkflowScenarioRule{}
+	common.Copy(&result, &rule, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation PUT /workflow/{id}/scenario_rule/{rule_id} workflowScenarioRuleUpdateById
+// ---
+// parameters:
+// - description: Workflow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Rule ID
+//   in: path
+//   name: rule_id
+//   required: true
+//   type: integer
+// - description: Update scenario rule params
+//   in: body
+//   name: rule
+//   required: true
+//   schema:
+//     $ref: '#/definitions/UpdateWorkflowScenarioRule'
+//     type: object
+// summary: update workflow scenario rule by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - workflow_scenario_rule
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/WorkflowScenarioRule'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerWorkflowScenarioRule) Update(ctx *gin.Context) {
+
+	workflowId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	ruleId, err := strconv.Atoi(ctx.Param("rule_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.UpdateWorkflowScenarioRule{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	rule := &m.WorkflowScenarioRule{
+		Id:                 int64(ruleId),
+		Name:               params.Name,
+		WorkflowId:         int64(workflowId),
+		WorkflowScenarioId: params.WorkflowScenarioId,
+		Priority:           params.Priority,
+		Conditions:         make([]*m.ScenarioCondition, 0),
+		Delay:              params.Delay,
+		Hold:               params.Hold,
+		Enabled:            params.Enabled,
+	}
+	_ = common.Copy(&rule.Conditions, &params.Conditions, common.JsonEngine)
+
+	rule, errs, err := c.endpoint.WorkflowScenarioRule.Update(rule)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.WorkflowScenarioRule{}
+	common.Copy(&result, &rule, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /workflow/{id}/scenario_rules workflowScenarioRuleList
+// ---
+// summary: get workflow scenario rule list
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - workflow_scenario_rule
+// parameters:
+// - description: Workflow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// - default: DESC
+//   description: order
+//   in: query
+//   name: order
+//   type: string
+// - default: id
+//   description: sort_by
+//   in: query
+//   name: sort_by
+//   type: string
+// responses:
+//   "200":
+//	   $ref: '#/responses/WorkflowScenarioRuleList'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerWorkflowScenarioRule) GetList(ctx *gin.Context) {
+
+	workflowId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	_, sortBy, order, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.WorkflowScenarioRule.GetList(int64(workflowId), int64(limit), int64(offset), order, sortBy)
+	if err != nil {
+		NewError(500, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.WorkflowScenarioRule, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+	return
+}
+
+// swagger:operation DELETE /workflow/{id}/scenario_rule/{rule_id} workflowScenarioRuleDeleteById
+// ---
+// parameters:
+// - description: Workflow ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Rule ID
+//   in: path
+//   name: rule_id
+//   required: true
+//   type: integer
+// summary: delete workflow scenario rule by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - workflow_scenario_rule
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerWorkflowScenarioRule) Delete(ctx *gin.Context) {
+
+	workflowId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	ruleId, err := strconv.Atoi(ctx.Param("rule_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.WorkflowScenarioRule.Delete(int64(workflowId), int64(ruleId)); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
@@ -620,6 +620,188 @@ func (c ControllerZigbee2mqtt) UpdateNetworkmap(ctx *gin.Context) {
 	NewSuccess().Send(ctx)
 }
 
+// swagger:operation POST /zigbee2mqtt/{id}/ota/check bridgeOtaCheck
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: device of the bridge, all devices if it is empty
+//   in: body
+//   name: device
+//   required: true
+//   schema:
+//     $ref: '#/definitions/Zigbee2mqttOtaRequest'
+//     type: object
+// summary: check the firmware updates
+// description: the result comes with the update status
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqtt) OtaCheck(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.Zigbee2mqttOtaRequest{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.Zigbee2mqtt.OtaCheck(int64(aid), params.DeviceId); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	NewSuccess().Send(ctx)
+}
+
+// swagger:operation POST /zigbee2mqtt/{id}/ota/update bridgeOtaUpdate
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: device of the bridge, all devices if it is empty
+//   in: body
+//   name: device
+//   required: true
+//   schema:
+//     $ref: '#/definitions/Zigbee2mqttOtaRequest'
+//     type: object
+// summary: update the firmware of the devices
+// description: the update starts in the update window of the bridge, one device at a time
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqtt) OtaUpdate(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.Zigbee2mqttOtaRequest{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.Zigbee2mqtt.OtaUpdate(int64(aid), params.DeviceId); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	NewSuccess().Send(ctx)
+}
+
+// swagger:operation GET /zigbee2mqtt/{id}/ota bridgeOtaStatus
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// summary: get the firmware update statuses of the devices
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt
+// responses:
+//   "200":
+//	   $ref: '#/responses/Zigbee2mqttOtaStatusList'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqtt) OtaStatus(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+	aid, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	items, err := c.endpoint.Zigbee2mqtt.OtaStatus(int64(aid))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.Zigbee2mqttOtaStatus, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(map[string]interface{}{
+		"items": result,
+	}).Send(ctx)
+}
+
 // swagger:operation PATCH /zigbee2mqtts/device_rename deviceRename
 // ---
 // parameters:
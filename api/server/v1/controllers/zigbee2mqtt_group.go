@@ -0,0 +1,878 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ControllerZigbee2mqttGroup ...
+type ControllerZigbee2mqttGroup struct {
+	*ControllerCommon
+}
+
+// NewControllerZigbee2mqttGroup ...
+func NewControllerZigbee2mqttGroup(common *ControllerCommon) *ControllerZigbee2mqttGroup {
+	return &ControllerZigbee2mqttGroup{ControllerCommon: common}
+}
+
+// swagger:operation POST /zigbee2mqtt/{id}/groups zigbee2mqttGroupAdd
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: group params
+//   in: body
+//   name: group
+//   required: true
+//   schema:
+//     $ref: '#/definitions/NewZigbee2mqttGroup'
+//     type: object
+// summary: add new zigbee2mqtt group
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttGroup'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) Add(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.NewZigbee2mqttGroup{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	group := &m.Zigbee2mqttGroup{
+		Zigbee2mqttId: int64(bridgeId),
+		GroupId:       params.GroupId,
+		FriendlyName:  params.FriendlyName,
+		Description:   params.Description,
+	}
+
+	group, errs, err := c.endpoint.Zigbee2mqttGroup.Add(group)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttGroup{}
+	common.Copy(&result, &group, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /zigbee2mqtt/{id}/groups/{group_id} zigbee2mqttGroupGetById
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// summary: get zigbee2mqtt group by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttGroup'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) GetById(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	group, err := c.endpoint.Zigbee2mqttGroup.GetById(int64(bridgeId), int64(groupId))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttGroup{}
+	common.Copy(&result, &group, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation PUT /zigbee2mqtt/{id}/groups/{group_id} zigbee2mqttGroupUpdateById
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: Update group params
+//   in: body
+//   name: group
+//   required: true
+//   schema:
+//     $ref: '#/definitions/UpdateZigbee2mqttGroup'
+//     type: object
+// summary: update zigbee2mqtt group by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttGroup'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) Update(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.UpdateZigbee2mqttGroup{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	group := &m.Zigbee2mqttGroup{
+		Id:            int64(groupId),
+		Zigbee2mqttId: int64(bridgeId),
+		Description:   params.Description,
+	}
+
+	group, errs, err := c.endpoint.Zigbee2mqttGroup.Update(group)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttGroup{}
+	common.Copy(&result, &group, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /zigbee2mqtt/{id}/groups zigbee2mqttGroupList
+// ---
+// summary: get zigbee2mqtt group list
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// - default: DESC
+//   description: order
+//   in: query
+//   name: order
+//   type: string
+// - default: id
+//   description: sort_by
+//   in: query
+//   name: sort_by
+//   type: string
+// responses:
+//   "200":
+//	   $ref: '#/responses/Zigbee2mqttGroupList'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) GetList(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	_, sortBy, order, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.Zigbee2mqttGroup.GetList(int64(bridgeId), int64(limit), int64(offset), order, sortBy)
+	if err != nil {
+		NewError(500, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.Zigbee2mqttGroup, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+	return
+}
+
+// swagger:operation DELETE /zigbee2mqtt/{id}/groups/{group_id} zigbee2mqttGroupDeleteById
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// summary: delete zigbee2mqtt group by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) Delete(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.Zigbee2mqttGroup.Delete(int64(bridgeId), int64(groupId)); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
+
+// swagger:operation POST /zigbee2mqtt/{id}/groups/{group_id}/devices zigbee2mqttGroupAddDevice
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: device of the bridge
+//   in: body
+//   name: device
+//   required: true
+//   schema:
+//     $ref: '#/definitions/Zigbee2mqttGroupDevice'
+//     type: object
+// summary: add device to zigbee2mqtt group
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttGroup'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) AddDevice(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.Zigbee2mqttGroupDevice{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	group, err := c.endpoint.Zigbee2mqttGroup.AddDevice(int64(bridgeId), int64(groupId), params.DeviceId)
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttGroup{}
+	common.Copy(&result, &group, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation DELETE /zigbee2mqtt/{id}/groups/{group_id}/devices/{device_id} zigbee2mqttGroupRemoveDevice
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: Device ID (friendly name)
+//   in: path
+//   name: device_id
+//   required: true
+//   type: string
+// summary: remove device from zigbee2mqtt group
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttGroup'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) RemoveDevice(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	group, err := c.endpoint.Zigbee2mqttGroup.RemoveDevice(int64(bridgeId), int64(groupId), ctx.Param("device_id"))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttGroup{}
+	common.Copy(&result, &group, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation PUT /zigbee2mqtt/{id}/groups/{group_id}/state zigbee2mqttGroupSetState
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: state of all the devices of the group
+//   in: body
+//   name: state
+//   required: true
+//   schema:
+//     $ref: '#/definitions/Zigbee2mqttGroupState'
+//     type: object
+// summary: switch all the devices of zigbee2mqtt group by one message
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) SetState(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.Zigbee2mqttGroupState{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.Zigbee2mqttGroup.SetState(int64(bridgeId), int64(groupId), params.State); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
+
+// swagger:operation POST /zigbee2mqtt/{id}/groups/{group_id}/scenes zigbee2mqttGroupStoreScene
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: scene params
+//   in: body
+//   name: scene
+//   required: true
+//   schema:
+//     $ref: '#/definitions/NewZigbee2mqttScene'
+//     type: object
+// summary: store the current state of zigbee2mqtt group as scene
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttScene'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) StoreScene(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	params := &models.NewZigbee2mqttScene{}
+	if err := ctx.ShouldBindJSON(params); err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	scene := &m.Zigbee2mqttScene{
+		Zigbee2mqttGroupId: int64(groupId),
+		SceneId:            params.SceneId,
+		Name:               params.Name,
+	}
+
+	scene, errs, err := c.endpoint.Zigbee2mqttGroup.StoreScene(int64(bridgeId), scene)
+	if len(errs) > 0 {
+		err400 := NewError(400)
+		err400.ValidationToErrors(errs).Send(ctx)
+		return
+	}
+
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttScene{}
+	common.Copy(&result, &scene, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation POST /zigbee2mqtt/{id}/groups/{group_id}/scenes/{scene_id}/recall zigbee2mqttGroupRecallScene
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: Scene ID
+//   in: path
+//   name: scene_id
+//   required: true
+//   type: integer
+// summary: recall scene of zigbee2mqtt group
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) RecallScene(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	sceneId, err := strconv.Atoi(ctx.Param("scene_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.Zigbee2mqttGroup.RecallScene(int64(bridgeId), int64(groupId), sceneId); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
+
+// swagger:operation DELETE /zigbee2mqtt/{id}/groups/{group_id}/scenes/{scene_id} zigbee2mqttGroupDeleteScene
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Group ID
+//   in: path
+//   name: group_id
+//   required: true
+//   type: integer
+// - description: Scene ID
+//   in: path
+//   name: scene_id
+//   required: true
+//   type: integer
+// summary: delete scene of zigbee2mqtt group
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_group
+// responses:
+//   "200":
+//	   $ref: '#/responses/Success'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttGroup) DeleteScene(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	groupId, err := strconv.Atoi(ctx.Param("group_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	sceneId, err := strconv.Atoi(ctx.Param("scene_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	if err := c.endpoint.Zigbee2mqttGroup.DeleteScene(int64(bridgeId), int64(groupId), sceneId); err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	resp := NewSuccess()
+	resp.Send(ctx)
+}
@@ -0,0 +1,255 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+	"github.com/e154/smart-home/common"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ControllerZigbee2mqttNetworkmap ...
+type ControllerZigbee2mqttNetworkmap struct {
+	*ControllerCommon
+}
+
+// NewControllerZigbee2mqttNetworkmap ...
+func NewControllerZigbee2mqttNetworkmap(common *ControllerCommon) *ControllerZigbee2mqttNetworkmap {
+	return &ControllerZigbee2mqttNetworkmap{ControllerCommon: common}
+}
+
+// swagger:operation GET /zigbee2mqtt/{id}/networkmaps zigbee2mqttNetworkmapList
+// ---
+// summary: get the stored scans of the network
+// description: the newest scans first
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_networkmap
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - default: 10
+//   description: limit
+//   in: query
+//   name: limit
+//   required: true
+//   type: integer
+// - default: 0
+//   description: offset
+//   in: query
+//   name: offset
+//   required: true
+//   type: integer
+// responses:
+//   "200":
+//	   $ref: '#/responses/Zigbee2mqttNetworkmapList'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttNetworkmap) GetList(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	_, _, _, limit, offset := c.list(ctx)
+	items, total, err := c.endpoint.Zigbee2mqtt.NetworkmapList(int64(bridgeId), int64(limit), int64(offset))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := make([]*models.Zigbee2mqttNetworkmap, 0)
+	common.Copy(&result, &items, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.Page(limit, offset, total, result).Send(ctx)
+	return
+}
+
+// swagger:operation GET /zigbee2mqtt/{id}/networkmaps/{networkmap_id} zigbee2mqttNetworkmapGetById
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: Networkmap ID
+//   in: path
+//   name: networkmap_id
+//   required: true
+//   type: integer
+// summary: get the scan of the network by id
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_networkmap
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttNetworkmap'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttNetworkmap) GetById(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	networkmapId, err := strconv.Atoi(ctx.Param("networkmap_id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	networkmap, err := c.endpoint.Zigbee2mqtt.GetNetworkmap(int64(bridgeId), int64(networkmapId))
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttNetworkmap{}
+	common.Copy(&result, &networkmap, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
+
+// swagger:operation GET /zigbee2mqtt/{id}/networkmap/analysis zigbee2mqttNetworkmapAnalysis
+// ---
+// parameters:
+// - description: Bridge ID
+//   in: path
+//   name: id
+//   required: true
+//   type: integer
+// - description: the scan, the last scan if it is empty
+//   in: query
+//   name: networkmap_id
+//   type: integer
+// - default: 50
+//   description: the link with the lower quality is weak
+//   in: query
+//   name: min_lqi
+//   type: integer
+// - default: 20
+//   description: the router with more children is overloaded
+//   in: query
+//   name: max_children
+//   type: integer
+// summary: find the weak links, the orphaned end devices and the overloaded routers
+// description:
+// security:
+// - ApiKeyAuth: []
+// tags:
+// - zigbee2mqtt_networkmap
+// responses:
+//   "200":
+//     description: OK
+//     schema:
+//       $ref: '#/definitions/Zigbee2mqttNetworkmapReport'
+//   "400":
+//	   $ref: '#/responses/Error'
+//   "401":
+//     description: "Unauthorized"
+//   "403":
+//     description: "Forbidden"
+//   "404":
+//	   $ref: '#/responses/Error'
+//   "500":
+//	   $ref: '#/responses/Error'
+func (c ControllerZigbee2mqttNetworkmap) Analysis(ctx *gin.Context) {
+
+	bridgeId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Error(err.Error())
+		NewError(400, err).Send(ctx)
+		return
+	}
+
+	var networkmapId, minLqi, maxChildren int
+	for name, value := range map[string]*int{
+		"networkmap_id": &networkmapId,
+		"min_lqi":       &minLqi,
+		"max_children":  &maxChildren,
+	} {
+		if ctx.Query(name) == "" {
+			continue
+		}
+		if *value, err = strconv.Atoi(ctx.Query(name)); err != nil {
+			log.Error(err.Error())
+			NewError(400, err).Send(ctx)
+			return
+		}
+	}
+
+	report, err := c.endpoint.Zigbee2mqtt.NetworkmapAnalysis(int64(bridgeId), int64(networkmapId), minLqi, maxChildren)
+	if err != nil {
+		code := 500
+		if err.Error() == "record not found" {
+			code = 404
+		}
+		NewError(code, err).Send(ctx)
+		return
+	}
+
+	result := &models.Zigbee2mqttNetworkmapReport{}
+	common.Copy(&result, &report, common.JsonEngine)
+
+	resp := NewSuccess()
+	resp.SetData(result).Send(ctx)
+}
@@ -33,7 +33,10 @@ type NewDevice struct {
 	Device      *ParentDevice    `json:"device"`
 	Type        string           `json:"type"`
 	Node        *NewDeviceNode   `json:"node"`
+	BackupNode  *NewDeviceNode   `json:"backup_node"`
 	Properties  DeviceProperties `json:"properties"`
+	IsGroup     bool             `json:"is_group"`
+	GroupMode   string           `json:"group_mode"`
 }
 
 // swagger:model
@@ -44,7 +47,10 @@ type UpdateDevice struct {
 	Device      *ParentDevice    `json:"device"`
 	Type        string           `json:"type"`
 	Node        *NewDeviceNode   `json:"node"`
+	BackupNode  *NewDeviceNode   `json:"backup_node"`
 	Properties  DeviceProperties `json:"properties"`
+	IsGroup     bool             `json:"is_group"`
+	GroupMode   string           `json:"group_mode"`
 }
 
 // ParentDevice ...
@@ -58,10 +64,12 @@ type Device struct {
 	Name        string           `json:"name"`
 	Description string           `json:"description"`
 	Node        *Node            `json:"node"`
+	BackupNode  *Node            `json:"backup_node"`
 	Properties  DeviceProperties `json:"properties"`
 	Type        string           `json:"type"`
 	Status      string           `json:"status"`
 	IsGroup     bool             `json:"is_group"`
+	GroupMode   string           `json:"group_mode"`
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
 	Actions     []DeviceAction   `json:"actions"`
@@ -30,6 +30,7 @@ type NewDeviceState struct {
 	Description string             `json:"description"`
 	SystemName  string             `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *DeviceStateDevice `json:"device" valid:"Required"`
+	Transitions []string           `json:"transitions"`
 }
 
 // swagger:model
@@ -37,6 +38,7 @@ type UpdateDeviceState struct {
 	Description string             `json:"description"`
 	SystemName  string             `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *DeviceStateDevice `json:"device" valid:"Required"`
+	Transitions []string           `json:"transitions"`
 }
 
 // swagger:model
@@ -45,6 +47,24 @@ type DeviceState struct {
 	Description string             `json:"description"`
 	SystemName  string             `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *DeviceStateDevice `json:"device" valid:"Required"`
+	Transitions []string           `json:"transitions"`
 	CreatedAt   time.Time          `json:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at"`
 }
+
+// swagger:model
+type DeviceCurrentState struct {
+	DeviceId    int64             `json:"device_id"`
+	DeviceState *DeviceState      `json:"device_state"`
+	ChangedAt   time.Time         `json:"changed_at"`
+	Group       *DeviceGroupState `json:"group,omitempty"`
+}
+
+// swagger:model
+type DeviceGroupState struct {
+	DeviceId int64            `json:"device_id"`
+	State    string           `json:"state"`
+	Mixed    bool             `json:"mixed"`
+	Counts   map[string]int   `json:"counts"`
+	Members  map[int64]string `json:"members"`
+}
@@ -30,8 +30,22 @@ type DevModBusRtuConfig struct {
 
 // DevModBusTcpConfig ...
 type DevModBusTcpConfig struct {
-	SlaveId     int    `json:"slave_id"`
-	AddressPort string `json:"address_port"`
+	SlaveId     int                      `json:"slave_id"`
+	AddressPort string                   `json:"address_port"`
+	Timeout     int                      `json:"timeout"`
+	Retries     int                      `json:"retries"`
+	PoolSize    int                      `json:"pool_size"`
+	Polling     []*DevModBusPollingGroup `json:"polling"`
+}
+
+// DevModBusPollingGroup ...
+type DevModBusPollingGroup struct {
+	Name     string            `json:"name"`
+	Function string            `json:"function"`
+	Address  uint16            `json:"address"`
+	Count    uint16            `json:"count"`
+	Interval int               `json:"interval"`
+	States   map[string]string `json:"states"`
 }
 
 // DevSmartBusConfig ...
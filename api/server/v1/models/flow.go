@@ -70,10 +70,13 @@ type FlowWorker struct {
 
 // swagger:model
 type NewFlow struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	Workflow    struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Status          string `json:"status"`
+	ConcurrencyMode string `json:"concurrency_mode"`
+	Concurrency     int    `json:"concurrency"`
+	QueueSize       int    `json:"queue_size"`
+	Workflow        struct {
 		Id int64 `json:"id"`
 	} `json:"workflow"`
 	Scenario struct {
@@ -83,11 +86,14 @@ type NewFlow struct {
 
 // swagger:model
 type UpdateFlow struct {
-	Id          int64  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	Workflow    struct {
+	Id              int64  `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Status          string `json:"status"`
+	ConcurrencyMode string `json:"concurrency_mode"`
+	Concurrency     int    `json:"concurrency"`
+	QueueSize       int    `json:"queue_size"`
+	Workflow        struct {
 		Id int64 `json:"id"`
 	} `json:"workflow"`
 	Scenario struct {
@@ -104,6 +110,9 @@ type Flow struct {
 	Workflow           *FlowWorkflow             `json:"workflow"`
 	WorkflowId         int64                     `json:"workflow_id" valid:"Required"`
 	WorkflowScenarioId int64                     `json:"workflow_scenario_id" valid:"Required"`
+	ConcurrencyMode    string                    `json:"concurrency_mode"`
+	Concurrency        int                       `json:"concurrency"`
+	QueueSize          int                       `json:"queue_size"`
 	Connections        []*FlowConnection         `json:"connections"`
 	FlowElements       []*FlowElement            `json:"flow_elements"`
 	Workers            []*FlowWorker             `json:"workers"`
@@ -32,6 +32,7 @@ type FlowConnection struct {
 	PointTo       int64     `json:"point_to" valid:"Required"`
 	FlowId        int64     `json:"flow_id" valid:"Required"`
 	Direction     string    `json:"direction"`
+	Condition     string    `json:"condition"`
 	GraphSettings string    `json:"graph_settings"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
@@ -22,18 +22,30 @@ import (
 	"time"
 )
 
+// swagger:model
+type FlowElementSettings struct {
+	Delay               int    `json:"delay,omitempty"`
+	Interval            int    `json:"interval,omitempty"`
+	Timeout             int    `json:"timeout,omitempty"`
+	Topic               string `json:"topic,omitempty"`
+	Zigbee2mqttDeviceId string `json:"zigbee2mqtt_device_id,omitempty"`
+	Condition           string `json:"condition,omitempty"`
+}
+
 // swagger:model
 type FlowElement struct {
-	Uuid          string    `json:"uuid"`
-	Name          string    `json:"name" valid:"MaxSize(254);Required"`
-	Description   string    `json:"description"`
-	FlowId        int64     `json:"flow_id" valid:"Required"`
-	Script        *Script   `json:"script"`
-	ScriptId      *int64    `json:"script_id"`
-	Status        string    `json:"status" valid:"Required"`
-	FlowLink      *int64    `json:"flow_link"`
-	PrototypeType string    `json:"prototype_type" valid:"Required"`
-	GraphSettings string    `json:"graph_settings"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Uuid          string              `json:"uuid"`
+	Name          string              `json:"name" valid:"MaxSize(254);Required"`
+	Description   string              `json:"description"`
+	FlowId        int64               `json:"flow_id" valid:"Required"`
+	Script        *Script             `json:"script"`
+	ScriptId      *int64              `json:"script_id"`
+	Status        string              `json:"status" valid:"Required"`
+	FlowLink      *int64              `json:"flow_link"`
+	PrototypeType string              `json:"prototype_type" valid:"Required"`
+	GatewayType   string              `json:"gateway_type"`
+	GraphSettings string              `json:"graph_settings"`
+	Settings      FlowElementSettings `json:"settings"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
 }
@@ -0,0 +1,46 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+)
+
+// swagger:model
+type FlowRunStep struct {
+	Id              int64                  `json:"id"`
+	FlowRunId       string                 `json:"flow_run_id"`
+	FlowElementUuid string                 `json:"flow_element_uuid"`
+	Status          string                 `json:"status"`
+	Error           string                 `json:"error"`
+	Vars            map[string]interface{} `json:"vars"`
+	StartedAt       time.Time              `json:"started_at"`
+	FinishedAt      *time.Time             `json:"finished_at"`
+}
+
+// swagger:model
+type FlowRun struct {
+	Id         string         `json:"id"`
+	FlowId     int64          `json:"flow_id"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error"`
+	Steps      []*FlowRunStep `json:"steps"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at"`
+}
@@ -46,6 +46,7 @@ type RedactorConnector struct {
 	FlowType  string `json:"flow_type"`
 	Title     string `json:"title"`
 	Direction string `json:"direction"`
+	Condition string `json:"condition"`
 }
 
 // RedactorObject ...
@@ -62,13 +63,15 @@ type RedactorObject struct {
 		Top  int64 `json:"top"`
 		Left int64 `json:"left"`
 	} `json:"position"`
-	Status        string  `json:"status"`
-	Error         string  `json:"error"`
-	Title         string  `json:"title"`
-	Description   string  `json:"description"`
-	PrototypeType string  `json:"prototype_type"`
-	Script        *Script `json:"script"`
-	FlowLink      *Flow   `json:"flow_link"`
+	Status        string              `json:"status"`
+	Error         string              `json:"error"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	PrototypeType string              `json:"prototype_type"`
+	GatewayType   string              `json:"gateway_type"`
+	Settings      FlowElementSettings `json:"settings"`
+	Script        *Script             `json:"script"`
+	FlowLink      *Flow               `json:"flow_link"`
 }
 
 // swagger:model
@@ -77,6 +80,9 @@ type RedactorFlow struct {
 	Name               string                    `json:"name"`
 	Description        string                    `json:"description"`
 	Status             string                    `json:"status"`
+	ConcurrencyMode    string                    `json:"concurrency_mode"`
+	Concurrency        int                       `json:"concurrency"`
+	QueueSize          int                       `json:"queue_size"`
 	Objects            []*RedactorObject         `json:"objects"`
 	Connectors         []*RedactorConnector      `json:"connectors"`
 	CreatedAt          time.Time                 `json:"created_at"`
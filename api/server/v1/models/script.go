@@ -24,19 +24,27 @@ import (
 
 // swagger:model
 type NewScript struct {
-	Lang        string `json:"lang"`
-	Name        string `json:"name"`
-	Source      string `json:"source"`
-	Description string `json:"description"`
+	Lang        string            `json:"lang"`
+	Name        string            `json:"name"`
+	Source      string            `json:"source"`
+	Description string            `json:"description"`
+	Timeout     int               `json:"timeout"`
+	MaxStack    int               `json:"max_stack"`
+	MaxMemory   int               `json:"max_memory"`
+	TestCases   []*ScriptTestCase `json:"test_cases"`
 }
 
 // swagger:model
 type UpdateScript struct {
-	Id          int64  `json:"id"`
-	Lang        string `json:"lang"`
-	Name        string `json:"name"`
-	Source      string `json:"source"`
-	Description string `json:"description"`
+	Id          int64             `json:"id"`
+	Lang        string            `json:"lang"`
+	Name        string            `json:"name"`
+	Source      string            `json:"source"`
+	Description string            `json:"description"`
+	Timeout     int               `json:"timeout"`
+	MaxStack    int               `json:"max_stack"`
+	MaxMemory   int               `json:"max_memory"`
+	TestCases   []*ScriptTestCase `json:"test_cases"`
 }
 
 // swagger:model
@@ -45,15 +53,62 @@ type ExecScript struct {
 	Name        string `json:"name"`
 	Source      string `json:"source"`
 	Description string `json:"description"`
+	Timeout     int    `json:"timeout"`
+	MaxStack    int    `json:"max_stack"`
+	MaxMemory   int    `json:"max_memory"`
 }
 
 // swagger:model
 type Script struct {
-	Id          int64     `json:"id"`
-	Lang        string    `json:"lang"`
-	Name        string    `json:"name"`
-	Source      string    `json:"source"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id          int64             `json:"id"`
+	Lang        string            `json:"lang"`
+	Name        string            `json:"name"`
+	Source      string            `json:"source"`
+	Description string            `json:"description"`
+	Timeout     int               `json:"timeout"`
+	MaxStack    int               `json:"max_stack"`
+	MaxMemory   int               `json:"max_memory"`
+	AllowExec   bool              `json:"allow_exec"`
+	TestCases   []*ScriptTestCase `json:"test_cases"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// swagger:model
+type ScriptTestCase struct {
+	Name    string                 `json:"name"`
+	Vars    map[string]interface{} `json:"vars"`
+	Returns map[string]interface{} `json:"returns"`
+	Calls   []*ScriptCall          `json:"calls"`
+	Result  *string                `json:"result"`
+	Error   string                 `json:"error"`
+}
+
+// swagger:model
+type ScriptCall struct {
+	Name string        `json:"name"`
+	Args []interface{} `json:"args"`
+}
+
+// swagger:model
+type ScriptDryRunParams struct {
+	Vars    map[string]interface{} `json:"vars"`
+	Returns map[string]interface{} `json:"returns"`
+}
+
+// swagger:model
+type ScriptDryRun struct {
+	Result string        `json:"result"`
+	Error  string        `json:"error"`
+	Calls  []*ScriptCall `json:"calls"`
+}
+
+// swagger:model
+type ScriptTestResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures"`
+	Result   string        `json:"result"`
+	Error    string        `json:"error"`
+	Calls    []*ScriptCall `json:"calls"`
 }
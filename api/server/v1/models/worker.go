@@ -35,3 +35,9 @@ type Worker struct {
 	CreatedAt      time.Time     `json:"created_at"`
 	UpdatedAt      time.Time     `json:"updated_at"`
 }
+
+// WorkerNextTime ...
+type WorkerNextTime struct {
+	Time  string      `json:"time"`
+	Items []time.Time `json:"items"`
+}
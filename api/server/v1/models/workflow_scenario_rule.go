@@ -0,0 +1,83 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import "time"
+
+// swagger:model
+type ScenarioCondition struct {
+	Kind      string   `json:"kind"`
+	From      string   `json:"from,omitempty"`
+	To        string   `json:"to,omitempty"`
+	DeviceIds []int64  `json:"device_ids,omitempty"`
+	States    []string `json:"states,omitempty"`
+	Timeout   int      `json:"timeout,omitempty"`
+	Not       bool     `json:"not,omitempty"`
+}
+
+// swagger:model
+type NewWorkflowScenarioRule struct {
+	Name               string               `json:"name"`
+	WorkflowScenarioId int64                `json:"workflow_scenario_id"`
+	Priority           int                  `json:"priority"`
+	Conditions         []*ScenarioCondition `json:"conditions"`
+	Delay              int                  `json:"delay"`
+	Hold               int                  `json:"hold"`
+	Enabled            bool                 `json:"enabled"`
+}
+
+// swagger:model
+type UpdateWorkflowScenarioRule struct {
+	Name               string               `json:"name"`
+	WorkflowScenarioId int64                `json:"workflow_scenario_id"`
+	Priority           int                  `json:"priority"`
+	Conditions         []*ScenarioCondition `json:"conditions"`
+	Delay              int                  `json:"delay"`
+	Hold               int                  `json:"hold"`
+	Enabled            bool                 `json:"enabled"`
+}
+
+// swagger:model
+type WorkflowScenarioRule struct {
+	Id                 int64                  `json:"id"`
+	Name               string                 `json:"name"`
+	WorkflowId         int64                  `json:"workflow_id"`
+	WorkflowScenario   *WorkflowScenarioShort `json:"workflow_scenario"`
+	WorkflowScenarioId int64                  `json:"workflow_scenario_id"`
+	Priority           int                    `json:"priority"`
+	Conditions         []*ScenarioCondition   `json:"conditions"`
+	Delay              int                    `json:"delay"`
+	Hold               int                    `json:"hold"`
+	Enabled            bool                   `json:"enabled"`
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+}
+
+// swagger:model
+type WorkflowScenarioHistory struct {
+	Id             int64     `json:"id"`
+	WorkflowId     int64     `json:"workflow_id"`
+	FromScenarioId *int64    `json:"from_scenario_id"`
+	FromScenario   string    `json:"from_scenario"`
+	ToScenarioId   *int64    `json:"to_scenario_id"`
+	ToScenario     string    `json:"to_scenario"`
+	Trigger        string    `json:"trigger"`
+	Initiator      string    `json:"initiator"`
+	CreatedAt      time.Time `json:"created_at"`
+}
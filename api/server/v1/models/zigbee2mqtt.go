@@ -28,6 +28,8 @@ type NewZigbee2mqtt struct {
 	PasswordRepeat *string `json:"password_repeat"`
 	PermitJoin     bool    `json:"permit_join"`
 	BaseTopic      string  `json:"base_topic"`
+	OtaWindowStart string  `json:"ota_window_start"`
+	OtaWindowEnd   string  `json:"ota_window_end"`
 }
 
 // swagger:model
@@ -38,18 +40,22 @@ type UpdateZigbee2mqtt struct {
 	PasswordRepeat *string `json:"password_repeat"`
 	PermitJoin     bool    `json:"permit_join"`
 	BaseTopic      string  `json:"base_topic"`
+	OtaWindowStart string  `json:"ota_window_start"`
+	OtaWindowEnd   string  `json:"ota_window_end"`
 }
 
 // swagger:model
 type Zigbee2mqtt struct {
-	Id         int64                `json:"id"`
-	Name       string               `json:"name"`
-	Login      string               `json:"login"`
-	Devices    []*Zigbee2mqttDevice `json:"devices"`
-	PermitJoin bool                 `json:"permit_join"`
-	BaseTopic  string               `json:"base_topic"`
-	CreatedAt  time.Time            `json:"created_at"`
-	UpdatedAt  time.Time            `json:"updated_at"`
+	Id             int64                `json:"id"`
+	Name           string               `json:"name"`
+	Login          string               `json:"login"`
+	Devices        []*Zigbee2mqttDevice `json:"devices"`
+	PermitJoin     bool                 `json:"permit_join"`
+	BaseTopic      string               `json:"base_topic"`
+	OtaWindowStart string               `json:"ota_window_start"`
+	OtaWindowEnd   string               `json:"ota_window_end"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
 }
 
 // swagger:model
@@ -58,5 +64,24 @@ type Zigbee2mqttInfo struct {
 	LastScan      time.Time   `json:"last_scan"`
 	Networkmap    string      `json:"networkmap"`
 	Status        string      `json:"status"`
+	Version       string      `json:"version"`
+	Protocol      string      `json:"protocol"`
 	Model         Zigbee2mqtt `json:"model"`
 }
+
+// swagger:model
+type Zigbee2mqttOtaRequest struct {
+	DeviceId string `json:"device_id"`
+}
+
+// swagger:model
+type Zigbee2mqttOtaStatus struct {
+	BridgeId  int64      `json:"bridge_id"`
+	DeviceId  string     `json:"device_id"`
+	Status    string     `json:"status"`
+	Progress  float64    `json:"progress"`
+	Remaining int64      `json:"remaining"`
+	Error     string     `json:"error"`
+	CheckedAt *time.Time `json:"checked_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
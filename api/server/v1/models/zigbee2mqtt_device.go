@@ -24,18 +24,38 @@ import (
 
 // swagger:model
 type Zigbee2mqttDevice struct {
-	Id            string    `json:"id"`
-	Zigbee2mqttId int64     `json:"zigbee2mqtt_id"`
-	Name          string    `json:"name"`
-	Type          string    `json:"type"`
-	Model         string    `json:"model"`
-	Description   string    `json:"description"`
-	Manufacturer  string    `json:"manufacturer"`
-	Functions     []string  `json:"functions"`
-	ImageUrl      string    `json:"image_url"`
-	Status        string    `json:"status"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Id            string               `json:"id"`
+	Zigbee2mqttId int64                `json:"zigbee2mqtt_id"`
+	Name          string               `json:"name"`
+	Type          string               `json:"type"`
+	Model         string               `json:"model"`
+	Description   string               `json:"description"`
+	Manufacturer  string               `json:"manufacturer"`
+	Functions     []string             `json:"functions"`
+	Exposes       []*Zigbee2mqttExpose `json:"exposes"`
+	ImageUrl      string               `json:"image_url"`
+	Status        string               `json:"status"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// swagger:model
+type Zigbee2mqttExpose struct {
+	Type        string               `json:"type"`
+	Name        string               `json:"name"`
+	Property    string               `json:"property"`
+	Description string               `json:"description"`
+	Endpoint    string               `json:"endpoint"`
+	Access      int                  `json:"access"`
+	Unit        string               `json:"unit"`
+	ValueMin    *float64             `json:"value_min"`
+	ValueMax    *float64             `json:"value_max"`
+	ValueStep   *float64             `json:"value_step"`
+	ValueOn     interface{}          `json:"value_on"`
+	ValueOff    interface{}          `json:"value_off"`
+	ValueToggle interface{}          `json:"value_toggle"`
+	Values      []interface{}        `json:"values"`
+	Features    []*Zigbee2mqttExpose `json:"features"`
 }
 
 // swagger:model
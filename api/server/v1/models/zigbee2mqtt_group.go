@@ -0,0 +1,74 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+)
+
+// swagger:model
+type NewZigbee2mqttGroup struct {
+	GroupId      int    `json:"group_id"`
+	FriendlyName string `json:"friendly_name"`
+	Description  string `json:"description"`
+}
+
+// swagger:model
+type UpdateZigbee2mqttGroup struct {
+	Description string `json:"description"`
+}
+
+// swagger:model
+type Zigbee2mqttGroupDevice struct {
+	DeviceId string `json:"device_id"`
+}
+
+// swagger:model
+type Zigbee2mqttGroupState struct {
+	State map[string]interface{} `json:"state"`
+}
+
+// swagger:model
+type NewZigbee2mqttScene struct {
+	SceneId int    `json:"scene_id"`
+	Name    string `json:"name"`
+}
+
+// swagger:model
+type Zigbee2mqttScene struct {
+	Id                 int64     `json:"id"`
+	Zigbee2mqttGroupId int64     `json:"zigbee2mqtt_group_id"`
+	SceneId            int       `json:"scene_id"`
+	Name               string    `json:"name"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// swagger:model
+type Zigbee2mqttGroup struct {
+	Id            int64                     `json:"id"`
+	Zigbee2mqttId int64                     `json:"zigbee2mqtt_id"`
+	GroupId       int                       `json:"group_id"`
+	FriendlyName  string                    `json:"friendly_name"`
+	Description   string                    `json:"description"`
+	Devices       []*Zigbee2mqttDeviceShort `json:"devices"`
+	Scenes        []*Zigbee2mqttScene       `json:"scenes"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
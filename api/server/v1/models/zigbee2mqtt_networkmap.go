@@ -0,0 +1,87 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+)
+
+// swagger:model
+type Zigbee2mqttNetworkNode struct {
+	IeeeAddr       string     `json:"ieee_addr"`
+	FriendlyName   string     `json:"friendly_name"`
+	Type           string     `json:"type"`
+	NetworkAddress int        `json:"network_address"`
+	Manufacturer   string     `json:"manufacturer"`
+	Model          string     `json:"model"`
+	Failed         []string   `json:"failed"`
+	LastSeen       *time.Time `json:"last_seen"`
+}
+
+// swagger:model
+type Zigbee2mqttNetworkLink struct {
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+	Lqi          int    `json:"lqi"`
+	Depth        int    `json:"depth"`
+	Relationship int    `json:"relationship"`
+}
+
+// swagger:model
+type Zigbee2mqttNetworkmap struct {
+	Id            int64                     `json:"id"`
+	Zigbee2mqttId int64                     `json:"zigbee2mqtt_id"`
+	Nodes         []*Zigbee2mqttNetworkNode `json:"nodes"`
+	Links         []*Zigbee2mqttNetworkLink `json:"links"`
+	CreatedAt     time.Time                 `json:"created_at"`
+}
+
+// swagger:model
+type Zigbee2mqttNetworkmapLink struct {
+	Source       string `json:"source"`
+	SourceName   string `json:"source_name"`
+	Target       string `json:"target"`
+	TargetName   string `json:"target_name"`
+	Lqi          int    `json:"lqi"`
+	Relationship int    `json:"relationship"`
+}
+
+// swagger:model
+type Zigbee2mqttNetworkmapNode struct {
+	IeeeAddr     string `json:"ieee_addr"`
+	FriendlyName string `json:"friendly_name"`
+	Type         string `json:"type"`
+	Children     int    `json:"children"`
+}
+
+// swagger:model
+type Zigbee2mqttNetworkmapReport struct {
+	NetworkmapId      int64                        `json:"networkmap_id"`
+	BridgeId          int64                        `json:"bridge_id"`
+	CreatedAt         time.Time                    `json:"created_at"`
+	MinLqi            int                          `json:"min_lqi"`
+	MaxChildren       int                          `json:"max_children"`
+	Nodes             int                          `json:"nodes"`
+	Links             int                          `json:"links"`
+	Routers           int                          `json:"routers"`
+	EndDevices        int                          `json:"end_devices"`
+	WeakLinks         []*Zigbee2mqttNetworkmapLink `json:"weak_links"`
+	Orphans           []*Zigbee2mqttNetworkmapNode `json:"orphans"`
+	OverloadedRouters []*Zigbee2mqttNetworkmapNode `json:"overloaded_routers"`
+}
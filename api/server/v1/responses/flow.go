@@ -42,3 +42,16 @@ type FlowSearch struct {
 		Flows []*models.Flow `json:"flows"`
 	}
 }
+
+// swagger:response FlowRunList
+type FlowRunList struct {
+	// in:body
+	Body struct {
+		Items []*models.FlowRun `json:"items"`
+		Meta  struct {
+			Limit       int64 `json:"limit"`
+			ObjectCount int64 `json:"objects_count"`
+			Offset      int64 `json:"offset"`
+		} `json:"meta"`
+	}
+}
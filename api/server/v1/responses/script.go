@@ -50,3 +50,12 @@ type ScriptExec struct {
 		Result string `json:"result"`
 	}
 }
+
+// swagger:response ScriptTest
+type ScriptTest struct {
+	// in:body
+	Body struct {
+		Passed  bool                       `json:"passed"`
+		Results []*models.ScriptTestResult `json:"results"`
+	}
+}
@@ -16,16 +16,21 @@
 // License along with this library.  If not, see
 // <https://www.gnu.org/licenses/>.
 
-package cron
+package responses
 
-import "time"
+import (
+	"github.com/e154/smart-home/api/server/v1/models"
+)
 
-// Timer ...
-type Timer struct {
-	second  int
-	min     int
-	hour    int
-	weekday time.Weekday
-	day     int
-	month   time.Month
+// swagger:response StorageItemList
+type StorageItemList struct {
+	// in:body
+	Body struct {
+		Items []*models.StorageItem `json:"items"`
+		Meta  struct {
+			Limit       int64 `json:"limit"`
+			ObjectCount int64 `json:"objects_count"`
+			Offset      int64 `json:"offset"`
+		} `json:"meta"`
+	}
 }
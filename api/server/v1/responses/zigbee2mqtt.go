@@ -42,3 +42,37 @@ type Zigbee2mqttDeviceSearch struct {
 		Zigbee2mqttDevices []*models.Zigbee2mqttDeviceShort `json:"devices"`
 	}
 }
+
+// swagger:response Zigbee2mqttGroupList
+type Zigbee2mqttGroupList struct {
+	// in:body
+	Body struct {
+		Items []*models.Zigbee2mqttGroup `json:"items"`
+		Meta  struct {
+			Limit       int64 `json:"limit"`
+			ObjectCount int64 `json:"objects_count"`
+			Offset      int64 `json:"offset"`
+		} `json:"meta"`
+	}
+}
+
+// swagger:response Zigbee2mqttOtaStatusList
+type Zigbee2mqttOtaStatusList struct {
+	// in:body
+	Body struct {
+		Items []*models.Zigbee2mqttOtaStatus `json:"items"`
+	}
+}
+
+// swagger:response Zigbee2mqttNetworkmapList
+type Zigbee2mqttNetworkmapList struct {
+	// in:body
+	Body struct {
+		Items []*models.Zigbee2mqttNetworkmap `json:"items"`
+		Meta  struct {
+			Limit       int64 `json:"limit"`
+			ObjectCount int64 `json:"objects_count"`
+			Offset      int64 `json:"offset"`
+		} `json:"meta"`
+	}
+}
@@ -21,6 +21,8 @@ package controllers
 import (
 	"github.com/e154/smart-home/adaptors"
 	"github.com/e154/smart-home/endpoint"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/access_list"
 	"github.com/e154/smart-home/system/core"
 	"github.com/e154/smart-home/system/metrics"
 	"github.com/e154/smart-home/system/mqtt"
@@ -39,6 +41,7 @@ type ControllerCommon struct {
 	metric      *metrics.MetricManager
 	mqtt        *mqtt.Mqtt
 	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt
+	accessList  *access_list.AccessListService
 }
 
 // NewControllerCommon ...
@@ -49,7 +52,8 @@ func NewControllerCommon(adaptors *adaptors.Adaptors,
 	core *core.Core,
 	metric *metrics.MetricManager,
 	mqtt *mqtt.Mqtt,
-	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) *ControllerCommon {
+	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt,
+	accessList *access_list.AccessListService) *ControllerCommon {
 	return &ControllerCommon{
 		adaptors:    adaptors,
 		endpoint:    endpoint,
@@ -59,9 +63,19 @@ func NewControllerCommon(adaptors *adaptors.Adaptors,
 		metric:      metric,
 		mqtt:        mqtt,
 		zigbee2mqtt: zigbee2mqtt,
+		accessList:  accessList,
 	}
 }
 
+// hasPermission the access level of the user of the session, the client without the user has no access
+func (c *ControllerCommon) hasPermission(client stream.IStreamClient, packageName, levelName string) bool {
+	var user *m.User
+	if userClient, ok := client.(stream.IUserClient); ok {
+		user = userClient.CurrentUser()
+	}
+	return c.accessList.HasPermission(user, packageName, levelName)
+}
+
 // Err ...
 func (c *ControllerCommon) Err(client stream.IStreamClient, message stream.Message, err error) {
 	msg := stream.Message{
@@ -22,6 +22,7 @@ import (
 	"github.com/e154/smart-home/adaptors"
 	"github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/endpoint"
+	"github.com/e154/smart-home/system/access_list"
 	"github.com/e154/smart-home/system/core"
 	metrics2 "github.com/e154/smart-home/system/metrics"
 	"github.com/e154/smart-home/system/mqtt"
@@ -36,11 +37,14 @@ var (
 
 // Controllers ...
 type Controllers struct {
-	Image     *ControllerImage
-	Worker    *ControllerWorker
-	Action    *ControllerAction
-	Dashboard *ControllerDashboard
-	Map       *ControllerMap
+	Image       *ControllerImage
+	Worker      *ControllerWorker
+	Action      *ControllerAction
+	Dashboard   *ControllerDashboard
+	Map         *ControllerMap
+	Script      *ControllerScript
+	Events      *ControllerEvents
+	Zigbee2mqtt *ControllerZigbee2mqtt
 }
 
 // NewControllers ...
@@ -51,14 +55,18 @@ func NewControllers(adaptors *adaptors.Adaptors,
 	endpoint *endpoint.Endpoint,
 	metrics *metrics2.MetricManager,
 	mqtt *mqtt.Mqtt,
-	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) *Controllers {
-	common := NewControllerCommon(adaptors, stream, endpoint, scripts, core, metrics, mqtt, zigbee2mqtt)
+	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt,
+	accessList *access_list.AccessListService) *Controllers {
+	common := NewControllerCommon(adaptors, stream, endpoint, scripts, core, metrics, mqtt, zigbee2mqtt, accessList)
 	return &Controllers{
-		Image:     NewControllerImage(common),
-		Worker:    NewControllerWorker(common),
-		Action:    NewControllerAction(common),
-		Dashboard: NewControllerDashboard(common),
-		Map:       NewControllerMap(common),
+		Image:       NewControllerImage(common),
+		Worker:      NewControllerWorker(common),
+		Action:      NewControllerAction(common),
+		Dashboard:   NewControllerDashboard(common),
+		Map:         NewControllerMap(common),
+		Script:      NewControllerScript(common),
+		Events:      NewControllerEvents(common),
+		Zigbee2mqtt: NewControllerZigbee2mqtt(common),
 	}
 }
 
@@ -69,6 +77,9 @@ func (s *Controllers) Start() {
 	s.Action.Start()
 	s.Dashboard.Start()
 	s.Map.Start()
+	s.Script.Start()
+	s.Events.Start()
+	s.Zigbee2mqtt.Start()
 }
 
 // Stop ...
@@ -78,4 +89,7 @@ func (s *Controllers) Stop() {
 	s.Action.Stop()
 	s.Dashboard.Stop()
 	s.Map.Stop()
+	s.Script.Stop()
+	s.Events.Stop()
+	s.Zigbee2mqtt.Stop()
 }
@@ -0,0 +1,144 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"errors"
+	"github.com/e154/smart-home/common"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/stream"
+	"sync"
+)
+
+var (
+	// ErrEventSubscriptionNotFound ...
+	ErrEventSubscriptionNotFound = errors.New("event subscription not found")
+)
+
+// ControllerEvents the client subscribes to the events of the core,
+// the events come in the 'events.event' messages until the unsubscription or the end of the session
+type ControllerEvents struct {
+	*ControllerCommon
+	sync.Mutex
+	subscriptions map[stream.IStreamClient]map[int64]bool
+}
+
+// NewControllerEvents ...
+func NewControllerEvents(common *ControllerCommon) *ControllerEvents {
+	return &ControllerEvents{
+		ControllerCommon: common,
+		subscriptions:    make(map[stream.IStreamClient]map[int64]bool),
+	}
+}
+
+// Start ...
+func (c *ControllerEvents) Start() {
+	c.stream.Subscribe("events.subscribe", c.Subscribe)
+	c.stream.Subscribe("events.unsubscribe", c.Unsubscribe)
+	c.stream.SubscribeClose("events", c.closeClient)
+}
+
+// Stop ...
+func (c *ControllerEvents) Stop() {
+	c.stream.UnSubscribe("events.subscribe")
+	c.stream.UnSubscribe("events.unsubscribe")
+	c.stream.UnSubscribeClose("events")
+
+	c.Lock()
+	for client := range c.subscriptions {
+		c.unsafeRemove(client)
+	}
+	c.Unlock()
+}
+
+// Stream
+// the filter is {types: [], fields: {}}, the empty types match all events,
+// the field value may be the list of the values
+func (c *ControllerEvents) Subscribe(client stream.IStreamClient, message stream.Message) {
+
+	filter := core.EventFilter{}
+	if err := common.Copy(&filter, message.Payload, common.JsonEngine); err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	// the id is read under the lock, the event may come before Subscribe returns
+	var id int64
+	c.Lock()
+	id = c.core.Events.Subscribe(filter, func(event core.Event) {
+		c.Lock()
+		subscriptionId := id
+		c.Unlock()
+		msg := stream.Message{
+			Command: "events.event",
+			Forward: stream.Request,
+			Payload: map[string]interface{}{
+				"subscription_id": subscriptionId,
+				"event":           core.EventFields(event),
+			},
+		}
+		client.Write(msg.Pack())
+	})
+
+	if _, ok := c.subscriptions[client]; !ok {
+		c.subscriptions[client] = make(map[int64]bool)
+	}
+	c.subscriptions[client][id] = true
+	c.Unlock()
+
+	client.Write(message.Response(map[string]interface{}{
+		"subscription_id": id,
+	}).Pack())
+}
+
+// Stream
+func (c *ControllerEvents) Unsubscribe(client stream.IStreamClient, message stream.Message) {
+
+	value, _ := message.Payload["subscription_id"].(float64)
+	id := int64(value)
+
+	c.Lock()
+	ok := c.subscriptions[client][id]
+	if ok {
+		delete(c.subscriptions[client], id)
+	}
+	c.Unlock()
+
+	if !ok {
+		c.Err(client, message, ErrEventSubscriptionNotFound)
+		return
+	}
+
+	c.core.Events.Unsubscribe(id)
+
+	client.Write(message.Success().Pack())
+}
+
+func (c *ControllerEvents) closeClient(client stream.IStreamClient) {
+	c.Lock()
+	c.unsafeRemove(client)
+	c.Unlock()
+}
+
+func (c *ControllerEvents) unsafeRemove(client stream.IStreamClient) {
+	for id := range c.subscriptions[client] {
+		c.core.Events.Unsubscribe(id)
+	}
+	delete(c.subscriptions, client)
+}
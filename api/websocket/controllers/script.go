@@ -0,0 +1,314 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"errors"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/scripts"
+	"github.com/e154/smart-home/system/stream"
+	"github.com/e154/smart-home/system/uuid"
+	"sync"
+)
+
+var (
+	// ErrDebugSessionNotFound ...
+	ErrDebugSessionNotFound = errors.New("debug session not found")
+	// ErrDebugForbidden ...
+	ErrDebugForbidden = errors.New("access denied")
+)
+
+// debugSession the session belongs to the client that started it
+type debugSession struct {
+	*scripts.DebugSession
+	client stream.IStreamClient
+}
+
+// ControllerScript ...
+type ControllerScript struct {
+	*ControllerCommon
+	sync.Mutex
+	sessions map[string]*debugSession
+}
+
+// NewControllerScript ...
+func NewControllerScript(common *ControllerCommon) *ControllerScript {
+	return &ControllerScript{
+		ControllerCommon: common,
+		sessions:         make(map[string]*debugSession),
+	}
+}
+
+// Start ...
+func (c *ControllerScript) Start() {
+	c.stream.Subscribe("script.debug.start", c.DebugStart)
+	c.stream.Subscribe("script.debug.step", c.DebugStep)
+	c.stream.Subscribe("script.debug.continue", c.DebugContinue)
+	c.stream.Subscribe("script.debug.stop", c.DebugStop)
+	c.stream.Subscribe("script.debug.breakpoints", c.DebugBreakpoints)
+	c.stream.Subscribe("script.debug.state", c.DebugState)
+	c.stream.SubscribeClose("script.debug", c.closeClient)
+}
+
+// Stop ...
+func (c *ControllerScript) Stop() {
+	c.stream.UnSubscribe("script.debug.start")
+	c.stream.UnSubscribe("script.debug.step")
+	c.stream.UnSubscribe("script.debug.continue")
+	c.stream.UnSubscribe("script.debug.stop")
+	c.stream.UnSubscribe("script.debug.breakpoints")
+	c.stream.UnSubscribe("script.debug.state")
+	c.stream.UnSubscribeClose("script.debug")
+
+	c.Lock()
+	for _, session := range c.sessions {
+		session.Stop()
+	}
+	c.Unlock()
+}
+
+// Stream
+// run the stored script (script_id) or the source (lang, name, source) in the debug session,
+// the client receives 'script.debug.paused' on the every pause and 'script.debug.finished' at the end,
+// the session needs 'script.exec_script', the stored script with the shell commands also 'script.exec_command'
+func (c *ControllerScript) DebugStart(client stream.IStreamClient, message stream.Message) {
+
+	if !c.hasPermission(client, "script", "exec_script") {
+		c.Err(client, message, ErrDebugForbidden)
+		return
+	}
+
+	v := message.Payload
+
+	script := &m.Script{}
+	if scriptId, ok := v["script_id"].(float64); ok {
+		var err error
+		if script, err = c.endpoint.Script.GetById(int64(scriptId)); err != nil {
+			c.Err(client, message, err)
+			return
+		}
+		if script.AllowExec && !c.hasPermission(client, "script", "exec_command") {
+			c.Err(client, message, ErrDebugForbidden)
+			return
+		}
+	} else {
+		lang, _ := v["lang"].(string)
+		script.Lang = common.ScriptLang(lang)
+		script.Name, _ = v["name"].(string)
+		script.Source, _ = v["source"].(string)
+	}
+
+	engine, err := c.endpoint.Script.NewDebugEngine(script)
+	if err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	sessionId := uuid.NewV4().String()
+
+	// the session of the closed client is removed, nothing is written to it
+	active := func() bool {
+		c.Lock()
+		defer c.Unlock()
+		_, ok := c.sessions[sessionId]
+		return ok
+	}
+
+	onPause := func(state *scripts.DebugState) {
+		if !active() {
+			return
+		}
+		msg := stream.Message{
+			Command: "script.debug.paused",
+			Forward: stream.Request,
+			Payload: map[string]interface{}{
+				"session_id": sessionId,
+				"line":       state.Line,
+				"globals":    state.Globals,
+				"vars":       state.Vars,
+			},
+		}
+		client.Write(msg.Pack())
+	}
+
+	onFinish := func(result string, err error) {
+		c.Lock()
+		_, ok := c.sessions[sessionId]
+		delete(c.sessions, sessionId)
+		c.Unlock()
+
+		if !ok {
+			return
+		}
+
+		payload := map[string]interface{}{
+			"session_id": sessionId,
+			"result":     result,
+		}
+		if err != nil {
+			payload["error"] = err.Error()
+		}
+		msg := stream.Message{
+			Command: "script.debug.finished",
+			Forward: stream.Request,
+			Payload: payload,
+		}
+		client.Write(msg.Pack())
+	}
+
+	session := &debugSession{
+		DebugSession: scripts.NewDebugSession(lines(v["breakpoints"]), onPause, onFinish),
+		client:       client,
+	}
+
+	c.Lock()
+	c.sessions[sessionId] = session
+	c.Unlock()
+
+	client.Write(message.Response(map[string]interface{}{
+		"session_id": sessionId,
+		"compiled":   script.Compiled,
+	}).Pack())
+
+	go func() {
+		_, _ = engine.Debug(session.DebugSession)
+		engine.Close()
+	}()
+}
+
+// Stream
+func (c *ControllerScript) DebugStep(client stream.IStreamClient, message stream.Message) {
+
+	session, err := c.session(client, message)
+	if err == nil {
+		err = session.Step()
+	}
+	if err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	client.Write(message.Success().Pack())
+}
+
+// Stream
+func (c *ControllerScript) DebugContinue(client stream.IStreamClient, message stream.Message) {
+
+	session, err := c.session(client, message)
+	if err == nil {
+		err = session.Continue()
+	}
+	if err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	client.Write(message.Success().Pack())
+}
+
+// Stream
+func (c *ControllerScript) DebugStop(client stream.IStreamClient, message stream.Message) {
+
+	session, err := c.session(client, message)
+	if err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	session.Stop()
+
+	client.Write(message.Success().Pack())
+}
+
+// Stream
+func (c *ControllerScript) DebugBreakpoints(client stream.IStreamClient, message stream.Message) {
+
+	session, err := c.session(client, message)
+	if err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	session.SetBreakpoints(lines(message.Payload["breakpoints"]))
+
+	client.Write(message.Response(map[string]interface{}{
+		"breakpoints": session.Breakpoints(),
+	}).Pack())
+}
+
+// Stream
+func (c *ControllerScript) DebugState(client stream.IStreamClient, message stream.Message) {
+
+	session, err := c.session(client, message)
+	if err != nil {
+		c.Err(client, message, err)
+		return
+	}
+
+	client.Write(message.Response(map[string]interface{}{
+		"state":       session.State(),
+		"breakpoints": session.Breakpoints(),
+	}).Pack())
+}
+
+// session the session of the other client is not found
+func (c *ControllerScript) session(client stream.IStreamClient, message stream.Message) (session *debugSession, err error) {
+
+	sessionId, _ := message.Payload["session_id"].(string)
+
+	c.Lock()
+	defer c.Unlock()
+
+	var ok bool
+	if session, ok = c.sessions[sessionId]; !ok || session.client != client {
+		session = nil
+		err = ErrDebugSessionNotFound
+	}
+
+	return
+}
+
+// closeClient the paused sessions of the closed client are stopped, they do not wait for the idle timeout
+func (c *ControllerScript) closeClient(client stream.IStreamClient) {
+
+	var list []*debugSession
+	c.Lock()
+	for sessionId, session := range c.sessions {
+		if session.client == client {
+			list = append(list, session)
+			delete(c.sessions, sessionId)
+		}
+	}
+	c.Unlock()
+
+	for _, session := range list {
+		session.Stop()
+	}
+}
+
+func lines(v interface{}) (lines []int) {
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		if line, ok := item.(float64); ok {
+			lines = append(lines, int(line))
+		}
+	}
+	return
+}
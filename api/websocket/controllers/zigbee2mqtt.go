@@ -0,0 +1,83 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/e154/smart-home/system/stream"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+)
+
+// ControllerZigbee2mqtt the changes of the firmware updates come in the 'zigbee2mqtt.ota' broadcast
+type ControllerZigbee2mqtt struct {
+	*ControllerCommon
+}
+
+// NewControllerZigbee2mqtt ...
+func NewControllerZigbee2mqtt(common *ControllerCommon) *ControllerZigbee2mqtt {
+	return &ControllerZigbee2mqtt{
+		ControllerCommon: common,
+	}
+}
+
+// Start ...
+func (c *ControllerZigbee2mqtt) Start() {
+	c.zigbee2mqtt.SubscribeOta("websocket", c.broadcastOta)
+	c.stream.Subscribe("zigbee2mqtt.get.ota", c.GetOta)
+}
+
+// Stop ...
+func (c *ControllerZigbee2mqtt) Stop() {
+	c.zigbee2mqtt.UnsubscribeOta("websocket")
+	c.stream.UnSubscribe("zigbee2mqtt.get.ota")
+}
+
+// Stream
+// the statuses of all bridges or of the bridge from the 'bridge_id'
+func (c *ControllerZigbee2mqtt) GetOta(client stream.IStreamClient, message stream.Message) {
+
+	var list []zigbee2mqtt.OtaStatus
+
+	if bridgeId, ok := message.Payload["bridge_id"].(float64); ok {
+		var err error
+		if list, err = c.zigbee2mqtt.BridgeOtaStatus(int64(bridgeId)); err != nil {
+			c.Err(client, message, err)
+			return
+		}
+	} else {
+		list = c.zigbee2mqtt.OtaStatus()
+	}
+
+	client.Write(message.Response(map[string]interface{}{
+		"items": list,
+	}).Pack())
+}
+
+func (c *ControllerZigbee2mqtt) broadcastOta(status zigbee2mqtt.OtaStatus) {
+
+	msg := stream.Message{
+		Command: "zigbee2mqtt.ota",
+		Type:    stream.Broadcast,
+		Forward: stream.Request,
+		Payload: map[string]interface{}{
+			"status": status,
+		},
+	}
+
+	c.stream.Broadcast(msg.Pack())
+}
@@ -23,6 +23,7 @@ import (
 	. "github.com/e154/smart-home/api/websocket/controllers"
 	"github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/endpoint"
+	"github.com/e154/smart-home/system/access_list"
 	"github.com/e154/smart-home/system/core"
 	"github.com/e154/smart-home/system/graceful_service"
 	metrics2 "github.com/e154/smart-home/system/metrics"
@@ -50,10 +51,11 @@ func NewWebSocket(adaptors *adaptors.Adaptors,
 	graceful *graceful_service.GracefulService,
 	metrics *metrics2.MetricManager,
 	mqtt *mqtt.Mqtt,
-	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) *WebSocket {
+	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt,
+	accessList *access_list.AccessListService) *WebSocket {
 
 	server := &WebSocket{
-		Controllers: NewControllers(adaptors, stream, scripts, core, endpoint, metrics, mqtt, zigbee2mqtt),
+		Controllers: NewControllers(adaptors, stream, scripts, core, endpoint, metrics, mqtt, zigbee2mqtt, accessList),
 	}
 
 	graceful.Subscribe(server)
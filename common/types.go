@@ -48,6 +48,50 @@ const (
 	FlowElementsPrototypeGateway = FlowElementsPrototypeType("Gateway")
 	// FlowElementsPrototypeFlow ...
 	FlowElementsPrototypeFlow = FlowElementsPrototypeType("Flow")
+	// FlowElementsPrototypeDelay ...
+	FlowElementsPrototypeDelay = FlowElementsPrototypeType("Delay")
+	// FlowElementsPrototypeWaitEvent ...
+	FlowElementsPrototypeWaitEvent = FlowElementsPrototypeType("WaitEvent")
+	// FlowElementsPrototypeDebounce ...
+	FlowElementsPrototypeDebounce = FlowElementsPrototypeType("Debounce")
+	// FlowElementsPrototypeThrottle ...
+	FlowElementsPrototypeThrottle = FlowElementsPrototypeType("Throttle")
+)
+
+// FlowElementsGatewayType ...
+type FlowElementsGatewayType string
+
+const (
+	// FlowElementsGatewayExclusive ...
+	FlowElementsGatewayExclusive = FlowElementsGatewayType("exclusive")
+	// FlowElementsGatewayParallel ...
+	FlowElementsGatewayParallel = FlowElementsGatewayType("parallel")
+	// FlowElementsGatewayInclusive ...
+	FlowElementsGatewayInclusive = FlowElementsGatewayType("inclusive")
+)
+
+// FlowConcurrencyMode ...
+type FlowConcurrencyMode string
+
+const (
+	// FlowConcurrencySerial ...
+	FlowConcurrencySerial = FlowConcurrencyMode("serial")
+	// FlowConcurrencyParallel ...
+	FlowConcurrencyParallel = FlowConcurrencyMode("parallel")
+	// FlowConcurrencyDropOldest ...
+	FlowConcurrencyDropOldest = FlowConcurrencyMode("drop_oldest")
+)
+
+// FlowRunStatus ...
+type FlowRunStatus string
+
+const (
+	// FlowRunInProcess ...
+	FlowRunInProcess = FlowRunStatus("in_process")
+	// FlowRunDone ...
+	FlowRunDone = FlowRunStatus("done")
+	// FlowRunError ...
+	FlowRunError = FlowRunStatus("error")
 )
 
 // StatusType ...
@@ -65,6 +109,16 @@ const (
 // DeviceType ...
 type DeviceType string
 
+// DeviceGroupMode how the group action runs on the member devices
+type DeviceGroupMode string
+
+const (
+	// DeviceGroupSequence one member after another
+	DeviceGroupSequence = DeviceGroupMode("sequence")
+	// DeviceGroupParallel all members at once
+	DeviceGroupParallel = DeviceGroupMode("parallel")
+)
+
 // PrototypeType ...
 type PrototypeType string
 
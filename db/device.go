@@ -35,22 +35,25 @@ type Devices struct {
 
 // Device ...
 type Device struct {
-	Id          int64 `gorm:"primary_key"`
-	Name        string
-	Description string
-	Device      *Device `gorm:"foreignkey:DeviceId"`
-	DeviceId    sql.NullInt64
-	Node        *Node
-	NodeId      sql.NullInt64
-	Status      string
-	Type        common.DeviceType
-	Properties  json.RawMessage `gorm:"type:jsonb;not null"`
-	States      []*DeviceState
-	Actions     []*DeviceAction
-	Devices     []*Device
-	IsGroup     bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Id           int64 `gorm:"primary_key"`
+	Name         string
+	Description  string
+	Device       *Device `gorm:"foreignkey:DeviceId"`
+	DeviceId     sql.NullInt64
+	Node         *Node
+	NodeId       sql.NullInt64
+	BackupNode   *Node `gorm:"foreignkey:BackupNodeId"`
+	BackupNodeId sql.NullInt64
+	Status       string
+	Type         common.DeviceType
+	Properties   json.RawMessage `gorm:"type:jsonb;not null"`
+	States       []*DeviceState
+	Actions      []*DeviceAction
+	Devices      []*Device
+	IsGroup      bool
+	GroupMode    common.DeviceGroupMode
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 // TableName ...
@@ -111,16 +114,35 @@ where da.id = ? and da notnull`, deviceActionId).Scan(device).Error
 	return
 }
 
+// GetByZigbee2mqttDeviceId the devices bound to the zigbee2mqtt device
+func (n Devices) GetByZigbee2mqttDeviceId(zigbeeDeviceId string) (list []*Device, err error) {
+	list = make([]*Device, 0)
+	err = n.Db.Where("type = ? and properties->>'zigbee2mqtt_device_id' = ?", "zigbee2mqtt", zigbeeDeviceId).
+		Find(&list).Error
+	if err != nil {
+		return
+	}
+
+	for _, device := range list {
+		n.DependencyLoading(device)
+	}
+
+	return
+}
+
 // Update ...
 func (n Devices) Update(m *Device) (err error) {
 	err = n.Db.Model(&Device{Id: m.Id}).Updates(map[string]interface{}{
-		"name":        m.Name,
-		"description": m.Description,
-		"status":      m.Status,
-		"properties":  m.Properties,
-		"device_id":   m.DeviceId,
-		"node":        m.Node,
-		"type":        m.Type,
+		"name":           m.Name,
+		"description":    m.Description,
+		"status":         m.Status,
+		"properties":     m.Properties,
+		"device_id":      m.DeviceId,
+		"node":           m.Node,
+		"backup_node_id": m.BackupNodeId,
+		"type":           m.Type,
+		"is_group":       m.IsGroup,
+		"group_mode":     m.GroupMode,
 	}).Error
 	return
 }
@@ -141,6 +163,7 @@ func (n *Devices) List(limit, offset int64, orderBy, sort string) (list []*Devic
 	q := n.Db.Model(&Device{}).
 		Preload("Device").
 		Preload("Node").
+		Preload("BackupNode").
 		Limit(limit).
 		Offset(offset)
 
@@ -187,6 +210,13 @@ func (n *Devices) DependencyLoading(device *Device) (err error) {
 			Find(&device.Node)
 	}
 
+	// backup node
+	if device.BackupNodeId.Valid {
+		device.BackupNode = &Node{Id: device.BackupNodeId.Int64}
+		n.Db.Model(device.BackupNode).
+			Find(&device.BackupNode)
+	}
+
 	// parent device
 	if device.DeviceId.Valid {
 		device.Device = &Device{}
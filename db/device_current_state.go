@@ -0,0 +1,78 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// DeviceCurrentStates ...
+type DeviceCurrentStates struct {
+	Db *gorm.DB
+}
+
+// DeviceCurrentState ...
+type DeviceCurrentState struct {
+	DeviceId      int64 `gorm:"primary_key"`
+	DeviceState   *DeviceState
+	DeviceStateId int64
+	ChangedAt     time.Time
+}
+
+// TableName ...
+func (d *DeviceCurrentState) TableName() string {
+	return "device_current_states"
+}
+
+// Set insert or update the current state of the device
+func (n DeviceCurrentStates) Set(state *DeviceCurrentState) (err error) {
+	err = n.Db.Exec(`INSERT INTO device_current_states (device_id, device_state_id, changed_at)
+VALUES (?, ?, ?)
+ON CONFLICT (device_id) DO UPDATE SET device_state_id = excluded.device_state_id, changed_at = excluded.changed_at`,
+		state.DeviceId, state.DeviceStateId, state.ChangedAt).Error
+	return
+}
+
+// GetByDeviceId ...
+func (n DeviceCurrentStates) GetByDeviceId(deviceId int64) (state *DeviceCurrentState, err error) {
+	state = &DeviceCurrentState{}
+	err = n.Db.Model(&DeviceCurrentState{}).
+		Where("device_id = ?", deviceId).
+		Preload("DeviceState").
+		First(&state).
+		Error
+	return
+}
+
+// List ...
+func (n DeviceCurrentStates) List() (list []*DeviceCurrentState, err error) {
+	list = make([]*DeviceCurrentState, 0)
+	err = n.Db.Model(&DeviceCurrentState{}).
+		Preload("DeviceState").
+		Find(&list).
+		Error
+	return
+}
+
+// Delete ...
+func (n DeviceCurrentStates) Delete(deviceId int64) (err error) {
+	err = n.Db.Delete(&DeviceCurrentState{DeviceId: deviceId}).Error
+	return
+}
@@ -19,6 +19,7 @@
 package db
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/jinzhu/gorm"
 	"time"
@@ -36,6 +37,7 @@ type DeviceState struct {
 	DeviceId    int64 `gorm:"column:device_id"`
 	Description string
 	SystemName  string
+	Transitions json.RawMessage `gorm:"type:jsonb;not null"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -66,6 +68,7 @@ func (n DeviceStates) Update(m *DeviceState) (err error) {
 	err = n.Db.Model(&DeviceState{Id: m.Id}).Updates(map[string]interface{}{
 		"system_name": m.SystemName,
 		"description": m.Description,
+		"transitions": m.Transitions,
 	}).Error
 	return
 }
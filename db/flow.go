@@ -39,6 +39,9 @@ type Flow struct {
 	Workflow           *Workflow
 	WorkflowId         int64
 	WorkflowScenarioId int64
+	ConcurrencyMode    FlowConcurrencyMode
+	Concurrency        int
+	QueueSize          int
 	Connections        []*Connection
 	FlowElements       []*FlowElement
 	Workers            []*Worker
@@ -116,11 +119,14 @@ func (n Flows) GetById(flowId int64) (flow *Flow, err error) {
 // Update ...
 func (n Flows) Update(m *Flow) (err error) {
 	err = n.Db.Model(&Flow{Id: m.Id}).Updates(map[string]interface{}{
-		"name":        m.Name,
-		"description": m.Description,
-		"status":      m.Status,
-		"workflow_id": m.WorkflowId,
-		"scenario_id": m.WorkflowScenarioId,
+		"name":             m.Name,
+		"description":      m.Description,
+		"status":           m.Status,
+		"workflow_id":      m.WorkflowId,
+		"scenario_id":      m.WorkflowScenarioId,
+		"concurrency_mode": m.ConcurrencyMode,
+		"concurrency":      m.Concurrency,
+		"queue_size":       m.QueueSize,
 	}).Error
 	return
 }
@@ -233,6 +239,7 @@ func (n *Flows) DependencyLoading(flow *Flow) (err error) {
 		Preload("DeviceAction.Device").
 		Preload("DeviceAction.Device.Devices").
 		Preload("DeviceAction.Device.Node").
+		Preload("DeviceAction.Device.BackupNode").
 		Preload("DeviceAction.Device.States").
 		Preload("DeviceAction.Device.Actions").
 		Preload("DeviceAction.Device.Actions.Script").
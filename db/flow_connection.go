@@ -42,6 +42,7 @@ type Connection struct {
 	Flow          *Flow
 	FlowId        int64
 	Direction     string
+	Condition     string
 	GraphSettings json.RawMessage `gorm:"type:jsonb;not null"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
@@ -78,6 +79,7 @@ func (n Connections) Update(m *Connection) (err error) {
 		"point_to":       m.PointTo,
 		"flow_id":        m.FlowId,
 		"direction":      m.Direction,
+		"condition":      m.Condition,
 		"graph_settings": m.GraphSettings,
 	}).Error
 	return
@@ -44,7 +44,9 @@ type FlowElement struct {
 	Status        StatusType
 	FlowLink      *int64
 	PrototypeType FlowElementsPrototypeType
+	GatewayType   FlowElementsGatewayType
 	GraphSettings json.RawMessage `gorm:"type:jsonb;not null"`
+	Settings      json.RawMessage `gorm:"type:jsonb;not null"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -88,7 +90,9 @@ func (n FlowElements) Update(m *FlowElement) (err error) {
 		"script_id":      m.ScriptId,
 		"flow_link":      m.FlowLink,
 		"prototype_type": m.PrototypeType,
+		"gateway_type":   m.GatewayType,
 		"graph_settings": m.GraphSettings,
+		"settings":       m.Settings,
 	}).Error
 
 	return
@@ -0,0 +1,108 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	. "github.com/e154/smart-home/common"
+	"github.com/e154/smart-home/system/uuid"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// FlowRuns ...
+type FlowRuns struct {
+	Db *gorm.DB
+}
+
+// FlowRun ...
+type FlowRun struct {
+	Id         uuid.UUID `gorm:"primary_key"`
+	Flow       *Flow
+	FlowId     int64
+	Status     FlowRunStatus
+	Error      string
+	Steps      []*FlowRunStep
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// TableName ...
+func (d *FlowRun) TableName() string {
+	return "flow_runs"
+}
+
+// Add ...
+func (n FlowRuns) Add(run *FlowRun) (id uuid.UUID, err error) {
+	if err = n.Db.Create(&run).Error; err != nil {
+		return
+	}
+	id = run.Id
+	return
+}
+
+// GetById ...
+func (n FlowRuns) GetById(id uuid.UUID) (run *FlowRun, err error) {
+	run = &FlowRun{Id: id}
+	err = n.Db.
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("flow_run_steps.id asc")
+		}).
+		First(&run).Error
+	return
+}
+
+// Update ...
+func (n FlowRuns) Update(m *FlowRun) (err error) {
+	err = n.Db.Model(&FlowRun{Id: m.Id}).Updates(map[string]interface{}{
+		"status":      m.Status,
+		"error":       m.Error,
+		"finished_at": m.FinishedAt,
+	}).Error
+	return
+}
+
+// ListByFlowId ...
+func (n FlowRuns) ListByFlowId(flowId int64, limit, offset int) (list []*FlowRun, total int64, err error) {
+
+	if err = n.Db.Model(FlowRun{}).
+		Where("flow_id = ?", flowId).
+		Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*FlowRun, 0)
+	err = n.Db.Model(&FlowRun{}).
+		Limit(limit).
+		Offset(offset).
+		Where("flow_id = ?", flowId).
+		Order("started_at desc").
+		Find(&list).Error
+
+	return
+}
+
+// DeleteOld keep the last runs of each flow
+func (n FlowRuns) DeleteOld(keep int) (err error) {
+	err = n.Db.Exec(`delete from flow_runs
+where id in (select id
+             from (select id, row_number() over (partition by flow_id order by started_at desc) as num
+                   from flow_runs) as runs
+             where runs.num > ?)`, keep).Error
+	return
+}
@@ -0,0 +1,58 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"encoding/json"
+	. "github.com/e154/smart-home/common"
+	"github.com/e154/smart-home/system/uuid"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// FlowRunSteps ...
+type FlowRunSteps struct {
+	Db *gorm.DB
+}
+
+// FlowRunStep ...
+type FlowRunStep struct {
+	Id              int64 `gorm:"primary_key"`
+	FlowRunId       uuid.UUID
+	FlowElementUuid uuid.UUID
+	Status          FlowRunStatus
+	Error           string
+	Vars            json.RawMessage `gorm:"type:jsonb;not null"`
+	StartedAt       time.Time
+	FinishedAt      *time.Time
+}
+
+// TableName ...
+func (d *FlowRunStep) TableName() string {
+	return "flow_run_steps"
+}
+
+// Add ...
+func (n FlowRunSteps) Add(step *FlowRunStep) (id int64, err error) {
+	if err = n.Db.Create(&step).Error; err != nil {
+		return
+	}
+	id = step.Id
+	return
+}
@@ -0,0 +1,129 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// NodeAlertRules ...
+type NodeAlertRules struct {
+	Db *gorm.DB
+}
+
+// NodeAlertRule ...
+type NodeAlertRule struct {
+	Id        int64 `gorm:"primary_key"`
+	Name      string
+	Node      *Node
+	NodeId    sql.NullInt64
+	Kind      string
+	Threshold float64
+	Period    int
+	Channel   string
+	Address   string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName ...
+func (d *NodeAlertRule) TableName() string {
+	return "node_alert_rules"
+}
+
+// Add ...
+func (n NodeAlertRules) Add(rule *NodeAlertRule) (id int64, err error) {
+	if err = n.Db.Create(&rule).Error; err != nil {
+		return
+	}
+	id = rule.Id
+	return
+}
+
+// GetById ...
+func (n NodeAlertRules) GetById(ruleId int64) (rule *NodeAlertRule, err error) {
+	rule = &NodeAlertRule{Id: ruleId}
+	err = n.Db.Model(rule).
+		Preload("Node").
+		First(&rule).
+		Error
+	return
+}
+
+// GetAllEnabled ...
+func (n NodeAlertRules) GetAllEnabled() (list []*NodeAlertRule, err error) {
+	list = make([]*NodeAlertRule, 0)
+	err = n.Db.Model(&NodeAlertRule{}).
+		Where("enabled = true").
+		Preload("Node").
+		Order("id ASC").
+		Find(&list).
+		Error
+	return
+}
+
+// Update ...
+func (n NodeAlertRules) Update(m *NodeAlertRule) (err error) {
+	err = n.Db.Model(&NodeAlertRule{Id: m.Id}).Updates(map[string]interface{}{
+		"name":      m.Name,
+		"node_id":   m.NodeId,
+		"kind":      m.Kind,
+		"threshold": m.Threshold,
+		"period":    m.Period,
+		"channel":   m.Channel,
+		"address":   m.Address,
+		"enabled":   m.Enabled,
+	}).Error
+	return
+}
+
+// Delete ...
+func (n NodeAlertRules) Delete(ruleId int64) (err error) {
+	err = n.Db.Delete(&NodeAlertRule{Id: ruleId}).Error
+	return
+}
+
+// List ...
+func (n *NodeAlertRules) List(limit, offset int64, orderBy, sort string) (list []*NodeAlertRule, total int64, err error) {
+
+	if err = n.Db.Model(NodeAlertRule{}).Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*NodeAlertRule, 0)
+	q := n.Db.Model(&NodeAlertRule{}).
+		Preload("Node").
+		Limit(limit).
+		Offset(offset)
+
+	if sort != "" && orderBy != "" {
+		q = q.
+			Order(fmt.Sprintf("%s %s", sort, orderBy))
+	}
+
+	err = q.
+		Find(&list).
+		Error
+
+	return
+}
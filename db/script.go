@@ -19,6 +19,7 @@
 package db
 
 import (
+	"encoding/json"
 	"fmt"
 	. "github.com/e154/smart-home/common"
 	"github.com/jinzhu/gorm"
@@ -38,6 +39,11 @@ type Script struct {
 	Source      string
 	Description string
 	Compiled    string
+	Timeout     int
+	MaxStack    int
+	MaxMemory   int
+	AllowExec   bool
+	TestCases   json.RawMessage `gorm:"type:jsonb;not null"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -63,6 +69,17 @@ func (n Scripts) GetById(nodeId int64) (node *Script, err error) {
 	return
 }
 
+// GetByName the oldest script with the name
+func (n Scripts) GetByName(name string) (script *Script, err error) {
+	script = &Script{}
+	err = n.Db.Model(script).
+		Where("name = ?", name).
+		Order("id ASC").
+		First(&script).
+		Error
+	return
+}
+
 // Update ...
 func (n Scripts) Update(m *Script) (err error) {
 	err = n.Db.Model(&Script{Id: m.Id}).Updates(map[string]interface{}{
@@ -71,6 +88,11 @@ func (n Scripts) Update(m *Script) (err error) {
 		"lang":        m.Lang,
 		"source":      m.Source,
 		"compiled":    m.Compiled,
+		"timeout":     m.Timeout,
+		"max_stack":   m.MaxStack,
+		"max_memory":  m.MaxMemory,
+		"allow_exec":  m.AllowExec,
+		"test_cases":  m.TestCases,
 	}).Error
 	return
 }
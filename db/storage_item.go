@@ -0,0 +1,118 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// StorageItems ...
+type StorageItems struct {
+	Db *gorm.DB
+}
+
+// StorageItem ...
+type StorageItem struct {
+	Namespace string `gorm:"primary_key"`
+	Key       string `gorm:"primary_key"`
+	Value     json.RawMessage
+	ExpireAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName ...
+func (d *StorageItem) TableName() string {
+	return "storage_items"
+}
+
+// Set insert or update the value of the key
+func (n StorageItems) Set(item *StorageItem) (err error) {
+	now := time.Now()
+	err = n.Db.Exec(`INSERT INTO storage_items (namespace, key, value, expire_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (namespace, key) DO UPDATE SET value = excluded.value, expire_at = excluded.expire_at, updated_at = excluded.updated_at`,
+		item.Namespace, item.Key, item.Value, item.ExpireAt, now, now).Error
+	return
+}
+
+// Get ...
+func (n StorageItems) Get(namespace, key string) (item *StorageItem, err error) {
+	item = &StorageItem{}
+	err = n.Db.Model(item).
+		Where("namespace = ? and key = ?", namespace, key).
+		First(&item).
+		Error
+	return
+}
+
+// GetByNamespace all not expired keys of the namespace
+func (n StorageItems) GetByNamespace(namespace string) (list []*StorageItem, err error) {
+	list = make([]*StorageItem, 0)
+	err = n.Db.Model(&StorageItem{}).
+		Where("namespace = ? and (expire_at is null or expire_at > ?)", namespace, time.Now()).
+		Find(&list).
+		Error
+	return
+}
+
+// Delete ...
+func (n StorageItems) Delete(namespace, key string) (err error) {
+	err = n.Db.
+		Where("namespace = ? and key = ?", namespace, key).
+		Delete(&StorageItem{}).
+		Error
+	return
+}
+
+// DeleteExpired ...
+func (n StorageItems) DeleteExpired() (err error) {
+	err = n.Db.
+		Where("expire_at is not null and expire_at <= ?", time.Now()).
+		Delete(&StorageItem{}).
+		Error
+	return
+}
+
+// List ...
+func (n *StorageItems) List(limit, offset int64, orderBy, sort, namespace string) (list []*StorageItem, total int64, err error) {
+
+	q := n.Db.Model(StorageItem{}).
+		Where("expire_at is null or expire_at > ?", time.Now())
+	if namespace != "" {
+		q = q.Where("namespace = ?", namespace)
+	}
+
+	if err = q.Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*StorageItem, 0)
+	err = q.
+		Limit(limit).
+		Offset(offset).
+		Order(fmt.Sprintf("%s %s", sort, orderBy)).
+		Find(&list).
+		Error
+
+	return
+}
@@ -0,0 +1,94 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// WorkflowScenarioHistories ...
+type WorkflowScenarioHistories struct {
+	Db *gorm.DB
+}
+
+// WorkflowScenarioHistory ...
+type WorkflowScenarioHistory struct {
+	Id             int64 `gorm:"primary_key"`
+	WorkflowId     int64
+	FromScenarioId sql.NullInt64
+	FromScenario   string
+	ToScenarioId   sql.NullInt64
+	ToScenario     string
+	Trigger        string
+	Initiator      string
+	CreatedAt      time.Time
+}
+
+// TableName ...
+func (d *WorkflowScenarioHistory) TableName() string {
+	return "workflow_scenario_history"
+}
+
+// Add ...
+func (n WorkflowScenarioHistories) Add(record *WorkflowScenarioHistory) (id int64, err error) {
+	if err = n.Db.Create(&record).Error; err != nil {
+		return
+	}
+	id = record.Id
+	return
+}
+
+// GetLast the last switch of the workflow scenario
+func (n WorkflowScenarioHistories) GetLast(workflowId int64) (record *WorkflowScenarioHistory, err error) {
+	record = &WorkflowScenarioHistory{}
+	err = n.Db.Model(&WorkflowScenarioHistory{}).
+		Where("workflow_id = ?", workflowId).
+		Order("created_at DESC, id DESC").
+		First(&record).
+		Error
+	return
+}
+
+// ListByWorkflow ...
+func (n *WorkflowScenarioHistories) ListByWorkflow(workflowId, limit, offset int64, orderBy, sort string) (list []*WorkflowScenarioHistory, total int64, err error) {
+
+	if err = n.Db.Model(WorkflowScenarioHistory{}).Where("workflow_id = ?", workflowId).Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*WorkflowScenarioHistory, 0)
+	q := n.Db.Model(&WorkflowScenarioHistory{}).
+		Where("workflow_id = ?", workflowId).
+		Limit(limit).
+		Offset(offset)
+
+	if sort != "" && orderBy != "" {
+		q = q.
+			Order(fmt.Sprintf("%s %s", sort, orderBy))
+	}
+
+	err = q.
+		Find(&list).
+		Error
+
+	return
+}
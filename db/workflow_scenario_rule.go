@@ -0,0 +1,129 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// WorkflowScenarioRules ...
+type WorkflowScenarioRules struct {
+	Db *gorm.DB
+}
+
+// WorkflowScenarioRule ...
+type WorkflowScenarioRule struct {
+	Id                 int64 `gorm:"primary_key"`
+	Name               string
+	WorkflowId         int64
+	WorkflowScenario   *WorkflowScenario
+	WorkflowScenarioId int64
+	Priority           int
+	Conditions         json.RawMessage `gorm:"type:jsonb;not null"`
+	Delay              int
+	Hold               int
+	Enabled            bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// TableName ...
+func (d *WorkflowScenarioRule) TableName() string {
+	return "workflow_scenario_rules"
+}
+
+// Add ...
+func (n WorkflowScenarioRules) Add(rule *WorkflowScenarioRule) (id int64, err error) {
+	if err = n.Db.Create(&rule).Error; err != nil {
+		return
+	}
+	id = rule.Id
+	return
+}
+
+// GetById ...
+func (n WorkflowScenarioRules) GetById(ruleId int64) (rule *WorkflowScenarioRule, err error) {
+	rule = &WorkflowScenarioRule{Id: ruleId}
+	err = n.Db.Model(rule).
+		Preload("WorkflowScenario").
+		First(&rule).
+		Error
+	return
+}
+
+// GetAllEnabled ...
+func (n WorkflowScenarioRules) GetAllEnabled() (list []*WorkflowScenarioRule, err error) {
+	list = make([]*WorkflowScenarioRule, 0)
+	err = n.Db.Model(&WorkflowScenarioRule{}).
+		Where("enabled = true").
+		Preload("WorkflowScenario").
+		Order("priority DESC, id ASC").
+		Find(&list).
+		Error
+	return
+}
+
+// Update ...
+func (n WorkflowScenarioRules) Update(m *WorkflowScenarioRule) (err error) {
+	err = n.Db.Model(&WorkflowScenarioRule{Id: m.Id}).Updates(map[string]interface{}{
+		"name":                 m.Name,
+		"workflow_scenario_id": m.WorkflowScenarioId,
+		"priority":             m.Priority,
+		"conditions":           m.Conditions,
+		"delay":                m.Delay,
+		"hold":                 m.Hold,
+		"enabled":              m.Enabled,
+	}).Error
+	return
+}
+
+// Delete ...
+func (n WorkflowScenarioRules) Delete(ruleId int64) (err error) {
+	err = n.Db.Delete(&WorkflowScenarioRule{Id: ruleId}).Error
+	return
+}
+
+// ListByWorkflow ...
+func (n *WorkflowScenarioRules) ListByWorkflow(workflowId, limit, offset int64, orderBy, sort string) (list []*WorkflowScenarioRule, total int64, err error) {
+
+	if err = n.Db.Model(WorkflowScenarioRule{}).Where("workflow_id = ?", workflowId).Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*WorkflowScenarioRule, 0)
+	q := n.Db.Model(&WorkflowScenarioRule{}).
+		Where("workflow_id = ?", workflowId).
+		Preload("WorkflowScenario").
+		Limit(limit).
+		Offset(offset)
+
+	if sort != "" && orderBy != "" {
+		q = q.
+			Order(fmt.Sprintf("%s %s", sort, orderBy))
+	}
+
+	err = q.
+		Find(&list).
+		Error
+
+	return
+}
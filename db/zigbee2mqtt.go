@@ -37,6 +37,8 @@ type Zigbee2mqtt struct {
 	EncryptedPassword string
 	PermitJoin        bool
 	BaseTopic         string
+	OtaWindowStart    string
+	OtaWindowEnd      string
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -70,6 +72,8 @@ func (z Zigbee2mqtts) Update(m *Zigbee2mqtt) (err error) {
 		"Login":              m.Login,
 		"PermitJoin":         m.PermitJoin,
 		"BaseTopic":          m.BaseTopic,
+		"OtaWindowStart":     m.OtaWindowStart,
+		"OtaWindowEnd":       m.OtaWindowEnd,
 		"encrypted_password": m.EncryptedPassword,
 	}
 
@@ -19,6 +19,7 @@
 package db
 
 import (
+	"encoding/json"
 	"github.com/jinzhu/gorm"
 	"github.com/lib/pq"
 	"time"
@@ -40,7 +41,8 @@ type Zigbee2mqttDevice struct {
 	Description   string
 	Manufacturer  string
 	Status        string
-	Functions     pq.StringArray `gorm:"type:varchar(100)[]"`
+	Functions     pq.StringArray  `gorm:"type:varchar(100)[]"`
+	Exposes       json.RawMessage `gorm:"type:jsonb;not null"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -74,6 +76,7 @@ func (z Zigbee2mqttDevices) Update(m *Zigbee2mqttDevice) (err error) {
 		"Description":  m.Description,
 		"Manufacturer": m.Manufacturer,
 		"Functions":    m.Functions,
+		"Exposes":      m.Exposes,
 		"Status":       m.Status,
 	}).Error
 	return
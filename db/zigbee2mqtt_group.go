@@ -0,0 +1,169 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// Zigbee2mqttGroups ...
+type Zigbee2mqttGroups struct {
+	Db *gorm.DB
+}
+
+// Zigbee2mqttGroup ...
+type Zigbee2mqttGroup struct {
+	Id            int64 `gorm:"primary_key"`
+	Zigbee2mqtt   *Zigbee2mqtt
+	Zigbee2mqttId int64
+	GroupId       int
+	FriendlyName  string
+	Description   string
+	Devices       []*Zigbee2mqttDevice `gorm:"many2many:zigbee2mqtt_group_devices;"`
+	Scenes        []*Zigbee2mqttScene
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// TableName ...
+func (m *Zigbee2mqttGroup) TableName() string {
+	return "zigbee2mqtt_groups"
+}
+
+// Zigbee2mqttGroupDevice ...
+type Zigbee2mqttGroupDevice struct {
+	Zigbee2mqttGroupId  int64  `gorm:"primary_key"`
+	Zigbee2mqttDeviceId string `gorm:"primary_key"`
+}
+
+// TableName ...
+func (m *Zigbee2mqttGroupDevice) TableName() string {
+	return "zigbee2mqtt_group_devices"
+}
+
+// Add ...
+func (z Zigbee2mqttGroups) Add(v *Zigbee2mqttGroup) (id int64, err error) {
+	if err = z.Db.Create(&v).Error; err != nil {
+		return
+	}
+	id = v.Id
+	return
+}
+
+// GetById ...
+func (z Zigbee2mqttGroups) GetById(id int64) (v *Zigbee2mqttGroup, err error) {
+	v = &Zigbee2mqttGroup{Id: id}
+	err = z.Db.Model(v).
+		Preload("Devices").
+		Preload("Scenes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scene_id ASC")
+		}).
+		First(&v).
+		Error
+	return
+}
+
+// GetByFriendlyName ...
+func (z Zigbee2mqttGroups) GetByFriendlyName(friendlyName string) (v *Zigbee2mqttGroup, err error) {
+	v = &Zigbee2mqttGroup{}
+	err = z.Db.Model(v).
+		Where("friendly_name = ?", friendlyName).
+		Preload("Devices").
+		Preload("Scenes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scene_id ASC")
+		}).
+		Order("id ASC").
+		First(&v).
+		Error
+	return
+}
+
+// NextGroupId the first free zigbee group id of the bridge
+func (z Zigbee2mqttGroups) NextGroupId(bridgeId int64) (groupId int, err error) {
+	var row struct {
+		GroupId int
+	}
+	err = z.Db.Model(&Zigbee2mqttGroup{}).
+		Select("coalesce(max(group_id), 0) + 1 as group_id").
+		Where("zigbee2mqtt_id = ?", bridgeId).
+		Scan(&row).
+		Error
+	groupId = row.GroupId
+	return
+}
+
+// Update ...
+func (z Zigbee2mqttGroups) Update(m *Zigbee2mqttGroup) (err error) {
+	err = z.Db.Model(&Zigbee2mqttGroup{Id: m.Id}).Updates(map[string]interface{}{
+		"description": m.Description,
+	}).Error
+	return
+}
+
+// Delete ...
+func (z Zigbee2mqttGroups) Delete(id int64) (err error) {
+	err = z.Db.Delete(&Zigbee2mqttGroup{Id: id}).Error
+	return
+}
+
+// ListByBridge ...
+func (z *Zigbee2mqttGroups) ListByBridge(bridgeId, limit, offset int64, orderBy, sort string) (list []*Zigbee2mqttGroup, total int64, err error) {
+
+	if err = z.Db.Model(Zigbee2mqttGroup{}).Where("zigbee2mqtt_id = ?", bridgeId).Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*Zigbee2mqttGroup, 0)
+	q := z.Db.Model(&Zigbee2mqttGroup{}).
+		Where("zigbee2mqtt_id = ?", bridgeId).
+		Preload("Devices").
+		Preload("Scenes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scene_id ASC")
+		}).
+		Limit(limit).
+		Offset(offset)
+
+	if sort != "" && orderBy != "" {
+		q = q.
+			Order(fmt.Sprintf("%s %s", sort, orderBy))
+	}
+
+	err = q.
+		Find(&list).
+		Error
+
+	return
+}
+
+// AddDevice ...
+func (z Zigbee2mqttGroups) AddDevice(groupId int64, deviceId string) (err error) {
+	err = z.Db.Create(&Zigbee2mqttGroupDevice{
+		Zigbee2mqttGroupId:  groupId,
+		Zigbee2mqttDeviceId: deviceId,
+	}).Error
+	return
+}
+
+// DeleteDevice ...
+func (z Zigbee2mqttGroups) DeleteDevice(groupId int64, deviceId string) (err error) {
+	err = z.Db.Delete(&Zigbee2mqttGroupDevice{}, "zigbee2mqtt_group_id = ? and zigbee2mqtt_device_id = ?", groupId, deviceId).Error
+	return
+}
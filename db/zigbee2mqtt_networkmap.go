@@ -0,0 +1,99 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"encoding/json"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// Zigbee2mqttNetworkmaps ...
+type Zigbee2mqttNetworkmaps struct {
+	Db *gorm.DB
+}
+
+// Zigbee2mqttNetworkmap ...
+type Zigbee2mqttNetworkmap struct {
+	Id            int64 `gorm:"primary_key"`
+	Zigbee2mqttId int64
+	Nodes         json.RawMessage `gorm:"type:jsonb;not null"`
+	Links         json.RawMessage `gorm:"type:jsonb;not null"`
+	CreatedAt     time.Time
+}
+
+// TableName ...
+func (m *Zigbee2mqttNetworkmap) TableName() string {
+	return "zigbee2mqtt_networkmaps"
+}
+
+// Add ...
+func (z Zigbee2mqttNetworkmaps) Add(v *Zigbee2mqttNetworkmap) (id int64, err error) {
+	if err = z.Db.Create(&v).Error; err != nil {
+		return
+	}
+	id = v.Id
+	return
+}
+
+// GetById ...
+func (z Zigbee2mqttNetworkmaps) GetById(bridgeId, id int64) (v *Zigbee2mqttNetworkmap, err error) {
+	v = &Zigbee2mqttNetworkmap{}
+	err = z.Db.Model(v).
+		Where("zigbee2mqtt_id = ? and id = ?", bridgeId, id).
+		First(&v).
+		Error
+	return
+}
+
+// GetLast ...
+func (z Zigbee2mqttNetworkmaps) GetLast(bridgeId int64) (v *Zigbee2mqttNetworkmap, err error) {
+	v = &Zigbee2mqttNetworkmap{}
+	err = z.Db.Model(v).
+		Where("zigbee2mqtt_id = ?", bridgeId).
+		Order("created_at DESC, id DESC").
+		First(&v).
+		Error
+	return
+}
+
+// ListByBridge the newest scans first
+func (z Zigbee2mqttNetworkmaps) ListByBridge(bridgeId, limit, offset int64) (list []*Zigbee2mqttNetworkmap, total int64, err error) {
+
+	if err = z.Db.Model(Zigbee2mqttNetworkmap{}).Where("zigbee2mqtt_id = ?", bridgeId).Count(&total).Error; err != nil {
+		return
+	}
+
+	list = make([]*Zigbee2mqttNetworkmap, 0)
+	err = z.Db.Model(&Zigbee2mqttNetworkmap{}).
+		Where("zigbee2mqtt_id = ?", bridgeId).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&list).
+		Error
+
+	return
+}
+
+// Delete ...
+func (z Zigbee2mqttNetworkmaps) Delete(id int64) (err error) {
+	err = z.Db.Delete(&Zigbee2mqttNetworkmap{Id: id}).Error
+	return
+}
@@ -0,0 +1,78 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// Zigbee2mqttScenes ...
+type Zigbee2mqttScenes struct {
+	Db *gorm.DB
+}
+
+// Zigbee2mqttScene ...
+type Zigbee2mqttScene struct {
+	Id                 int64 `gorm:"primary_key"`
+	Zigbee2mqttGroup   *Zigbee2mqttGroup
+	Zigbee2mqttGroupId int64
+	SceneId            int
+	Name               string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// TableName ...
+func (m *Zigbee2mqttScene) TableName() string {
+	return "zigbee2mqtt_scenes"
+}
+
+// Add ...
+func (z Zigbee2mqttScenes) Add(v *Zigbee2mqttScene) (id int64, err error) {
+	if err = z.Db.Create(&v).Error; err != nil {
+		return
+	}
+	id = v.Id
+	return
+}
+
+// GetBySceneId ...
+func (z Zigbee2mqttScenes) GetBySceneId(groupId int64, sceneId int) (v *Zigbee2mqttScene, err error) {
+	v = &Zigbee2mqttScene{}
+	err = z.Db.Model(v).
+		Where("zigbee2mqtt_group_id = ? and scene_id = ?", groupId, sceneId).
+		First(&v).
+		Error
+	return
+}
+
+// Update ...
+func (z Zigbee2mqttScenes) Update(m *Zigbee2mqttScene) (err error) {
+	err = z.Db.Model(&Zigbee2mqttScene{Id: m.Id}).Updates(map[string]interface{}{
+		"name": m.Name,
+	}).Error
+	return
+}
+
+// Delete ...
+func (z Zigbee2mqttScenes) Delete(id int64) (err error) {
+	err = z.Db.Delete(&Zigbee2mqttScene{Id: id}).Error
+	return
+}
@@ -19,10 +19,16 @@
 package endpoint
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/e154/smart-home/common"
 	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/models/devices"
 	"github.com/e154/smart-home/system/metrics"
+	"github.com/e154/smart-home/system/scripts"
 	"github.com/e154/smart-home/system/validation"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
 )
 
 // DeviceEndpoint ...
@@ -54,12 +60,129 @@ func (d *DeviceEndpoint) Add(params *m.Device) (device *m.Device, errs []*valida
 		return
 	}
 
+	d.core.ModbusPolling.Reload(device.Id)
+
 	var disabled int64
 	if device.Status == "disabled" {
 		disabled++
 	}
 	d.metric.Update(metrics.DeviceAdd{TotalNum: 1, DisabledNum: disabled})
 
+	// the zigbee device may be not paired yet, the capabilities are added later by the request
+	if device.Type == devices.DevTypeZigbee2mqtt {
+		var result *m.Device
+		if result, err = d.AddCapabilities(device.Id); err != nil {
+			log.Warnf("device id %v: %v", device.Id, err.Error())
+			err = nil
+			return
+		}
+		device = result
+	}
+
+	return
+}
+
+// AddCapabilities the actions and the states of the zigbee2mqtt device are generated from the exposes of the definition,
+// the actions and the states with the same names are kept as is. The scripts of the actions are named
+// {ieee}_{device id}_{action}, all records are added in the single transaction
+func (d *DeviceEndpoint) AddCapabilities(deviceId int64) (device *m.Device, err error) {
+
+	if device, err = d.adaptors.Device.GetById(deviceId); err != nil {
+		return
+	}
+
+	if device.Type != devices.DevTypeZigbee2mqtt {
+		err = fmt.Errorf("device id %v is not a zigbee2mqtt device", deviceId)
+		return
+	}
+
+	params := &devices.DevZigbee2mqttConfig{}
+	if err = json.Unmarshal(device.Properties, params); err != nil {
+		return
+	}
+
+	var zigbeeDevice *m.Zigbee2mqttDevice
+	if zigbeeDevice, err = d.adaptors.Zigbee2mqttDevice.GetById(params.Zigbee2mqttDeviceId); err != nil {
+		return
+	}
+
+	capabilities := zigbee2mqtt.NewCapabilities(zigbeeDevice.Exposes)
+
+	tx := d.adaptors.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	exist := make(map[string]bool)
+	for _, action := range device.Actions {
+		exist[action.Name] = true
+	}
+
+	for _, action := range capabilities.Actions {
+		if exist[action.Name] {
+			continue
+		}
+
+		script := &m.Script{
+			Lang:        common.ScriptLangJavascript,
+			Name:        fmt.Sprintf("%s_%d_%s", zigbeeDevice.Id, device.Id, action.Name),
+			Source:      action.Script(),
+			Description: action.Description,
+		}
+
+		var engine *scripts.Engine
+		if engine, err = d.scriptService.NewEngine(script); err != nil {
+			return
+		}
+		err = engine.Compile()
+		engine.Close()
+		if err != nil {
+			return
+		}
+
+		if script.Id, err = tx.Script.Add(script); err != nil {
+			return
+		}
+
+		if _, err = tx.DeviceAction.Add(&m.DeviceAction{
+			Name:        action.Name,
+			Description: action.Description,
+			DeviceId:    device.Id,
+			ScriptId:    script.Id,
+		}); err != nil {
+			return
+		}
+	}
+
+	exist = make(map[string]bool)
+	for _, state := range device.States {
+		exist[state.SystemName] = true
+	}
+
+	for _, state := range capabilities.States {
+		if exist[state.SystemName] {
+			continue
+		}
+
+		if _, err = tx.DeviceState.Add(&m.DeviceState{
+			SystemName:  state.SystemName,
+			Description: state.Description,
+			DeviceId:    device.Id,
+		}); err != nil {
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	d.core.DeviceStates.Reload(device.Id)
+
+	device, err = d.adaptors.Device.GetById(deviceId)
+
 	return
 }
 
@@ -83,6 +206,8 @@ func (d *DeviceEndpoint) Update(device *m.Device) (result *m.Device, errs []*val
 
 	result, err = d.adaptors.Device.GetById(device.Id)
 
+	d.core.ModbusPolling.Reload(device.Id)
+
 	return
 }
 
@@ -111,6 +236,9 @@ func (d *DeviceEndpoint) Delete(deviceId int64) (err error) {
 		return
 	}
 
+	d.core.DeviceStates.Reload(device.Id)
+	d.core.ModbusPolling.Reload(device.Id)
+
 	var disabled int64
 	if device.Status == "disabled" {
 		disabled++
@@ -121,6 +249,29 @@ func (d *DeviceEndpoint) Delete(deviceId int64) (err error) {
 	return
 }
 
+// GetState ...
+func (d *DeviceEndpoint) GetState(deviceId int64) (state *m.DeviceCurrentState, err error) {
+
+	var device *m.Device
+	if device, err = d.adaptors.Device.GetById(deviceId); err != nil {
+		return
+	}
+
+	if state, err = d.core.DeviceStates.Get(deviceId); err != nil && !device.IsGroup {
+		return
+	}
+
+	// the group may have no own state, only derived from the members
+	if device.IsGroup {
+		if err != nil {
+			state, err = &m.DeviceCurrentState{DeviceId: deviceId}, nil
+		}
+		state.Group = d.core.DeviceStates.GroupState(device)
+	}
+
+	return
+}
+
 // Search ...
 func (d *DeviceEndpoint) Search(query string, limit, offset int) (devices []*m.Device, total int64, err error) {
 
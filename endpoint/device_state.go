@@ -82,6 +82,8 @@ func (d *DeviceStateEndpoint) Update(params *m.DeviceState) (state *m.DeviceStat
 		return
 	}
 
+	d.core.DeviceStates.Reload(state.DeviceId)
+
 	state, err = d.adaptors.DeviceState.GetById(state.Id)
 
 	return
@@ -100,7 +102,11 @@ func (d *DeviceStateEndpoint) Delete(id int64) (err error) {
 		return
 	}
 
-	err = d.adaptors.DeviceState.Delete(device.Id)
+	if err = d.adaptors.DeviceState.Delete(device.Id); err != nil {
+		return
+	}
+
+	d.core.DeviceStates.Reload(device.DeviceId)
 
 	return
 }
@@ -38,32 +38,37 @@ var (
 
 // Endpoint ...
 type Endpoint struct {
-	Auth             *AuthEndpoint
-	Device           *DeviceEndpoint
-	DeviceAction     *DeviceActionEndpoint
-	DeviceState      *DeviceStateEndpoint
-	Flow             *FlowEndpoint
-	Image            *ImageEndpoint
-	Log              *LogEndpoint
-	Map              *MapEndpoint
-	MapElement       *MapElementEndpoint
-	MapLayer         *MapLayerEndpoint
-	MapZone          *MapZoneEndpoint
-	Node             *NodeEndpoint
-	Role             *RoleEndpoint
-	Script           *ScriptEndpoint
-	Workflow         *WorkflowEndpoint
-	WorkflowScenario *WorkflowScenarioEndpoint
-	User             *UserEndpoint
-	Gate             *GateEndpoint
-	Template         *TemplateEndpoint
-	Notify           *NotifyEndpoint
-	MessageDelivery  *MessageDeliveryEndpoint
-	Mqtt             *MqttEndpoint
-	Version          *VersionEndpoint
-	Zigbee2mqtt      *Zigbee2mqttEndpoint
-	MapDeviceHistory *MapDeviceHistoryEndpoint
-	AlexaSkill       *AlexaSkillEndpoint
+	Auth                 *AuthEndpoint
+	Device               *DeviceEndpoint
+	DeviceAction         *DeviceActionEndpoint
+	DeviceState          *DeviceStateEndpoint
+	Flow                 *FlowEndpoint
+	Image                *ImageEndpoint
+	Log                  *LogEndpoint
+	Map                  *MapEndpoint
+	MapElement           *MapElementEndpoint
+	MapLayer             *MapLayerEndpoint
+	MapZone              *MapZoneEndpoint
+	Node                 *NodeEndpoint
+	NodeAlertRule        *NodeAlertRuleEndpoint
+	Role                 *RoleEndpoint
+	Script               *ScriptEndpoint
+	Workflow             *WorkflowEndpoint
+	WorkflowScenario     *WorkflowScenarioEndpoint
+	WorkflowScenarioRule *WorkflowScenarioRuleEndpoint
+	User                 *UserEndpoint
+	Gate                 *GateEndpoint
+	Template             *TemplateEndpoint
+	Notify               *NotifyEndpoint
+	MessageDelivery      *MessageDeliveryEndpoint
+	Mqtt                 *MqttEndpoint
+	Version              *VersionEndpoint
+	Zigbee2mqtt          *Zigbee2mqttEndpoint
+	Zigbee2mqttGroup     *Zigbee2mqttGroupEndpoint
+	MapDeviceHistory     *MapDeviceHistoryEndpoint
+	AlexaSkill           *AlexaSkillEndpoint
+	Worker               *WorkerEndpoint
+	Storage              *StorageEndpoint
 }
 
 // NewEndpoint ...
@@ -79,31 +84,36 @@ func NewEndpoint(adaptors *adaptors.Adaptors,
 	alexa *alexa.Alexa) *Endpoint {
 	common := NewCommonEndpoint(adaptors, core, accessList, scriptService, gate, notify, mqtt, zigbee2mqtt, metric, alexa)
 	return &Endpoint{
-		Auth:             NewAuthEndpoint(common),
-		Device:           NewDeviceEndpoint(common),
-		DeviceAction:     NewDeviceActionEndpoint(common),
-		DeviceState:      NewDeviceStateEndpoint(common),
-		Flow:             NewFlowEndpoint(common),
-		Image:            NewImageEndpoint(common),
-		Log:              NewLogEndpoint(common),
-		Map:              NewMapEndpoint(common),
-		MapElement:       NewMapElementEndpoint(common),
-		MapLayer:         NewMapLayerEndpoint(common),
-		Node:             NewNodeEndpoint(common),
-		Role:             NewRoleEndpoint(common),
-		Script:           NewScriptEndpoint(common),
-		Workflow:         NewWorkflowEndpoint(common),
-		WorkflowScenario: NewWorkflowScenarioEndpoint(common),
-		User:             NewUserEndpoint(common),
-		Gate:             NewGateEndpoint(common),
-		MapZone:          NewMapZoneEndpoint(common),
-		Template:         NewTemplateEndpoint(common),
-		Notify:           NewNotifyEndpoint(common),
-		MessageDelivery:  NewMessageDeliveryEndpoint(common),
-		Mqtt:             NewMqttEndpoint(common),
-		Version:          NewVersionEndpoint(common),
-		Zigbee2mqtt:      NewZigbee2mqttEndpoint(common),
-		MapDeviceHistory: NewMapDeviceHistoryEndpoint(common),
-		AlexaSkill:       NewAlexaSkillEndpoint(common),
+		Auth:                 NewAuthEndpoint(common),
+		Device:               NewDeviceEndpoint(common),
+		DeviceAction:         NewDeviceActionEndpoint(common),
+		DeviceState:          NewDeviceStateEndpoint(common),
+		Flow:                 NewFlowEndpoint(common),
+		Image:                NewImageEndpoint(common),
+		Log:                  NewLogEndpoint(common),
+		Map:                  NewMapEndpoint(common),
+		MapElement:           NewMapElementEndpoint(common),
+		MapLayer:             NewMapLayerEndpoint(common),
+		Node:                 NewNodeEndpoint(common),
+		NodeAlertRule:        NewNodeAlertRuleEndpoint(common),
+		Role:                 NewRoleEndpoint(common),
+		Script:               NewScriptEndpoint(common),
+		Workflow:             NewWorkflowEndpoint(common),
+		WorkflowScenario:     NewWorkflowScenarioEndpoint(common),
+		WorkflowScenarioRule: NewWorkflowScenarioRuleEndpoint(common),
+		User:                 NewUserEndpoint(common),
+		Gate:                 NewGateEndpoint(common),
+		MapZone:              NewMapZoneEndpoint(common),
+		Template:             NewTemplateEndpoint(common),
+		Notify:               NewNotifyEndpoint(common),
+		MessageDelivery:      NewMessageDeliveryEndpoint(common),
+		Mqtt:                 NewMqttEndpoint(common),
+		Version:              NewVersionEndpoint(common),
+		Zigbee2mqtt:          NewZigbee2mqttEndpoint(common),
+		Zigbee2mqttGroup:     NewZigbee2mqttGroupEndpoint(common),
+		MapDeviceHistory:     NewMapDeviceHistoryEndpoint(common),
+		AlexaSkill:           NewAlexaSkillEndpoint(common),
+		Worker:               NewWorkerEndpoint(common),
+		Storage:              NewStorageEndpoint(common),
 	}
 }
@@ -104,6 +104,32 @@ func (f *FlowEndpoint) Search(query string, limit, offset int) (list []*m.Flow,
 	return
 }
 
+// GetRuns ...
+func (f *FlowEndpoint) GetRuns(flowId int64, limit, offset int) (list []*m.FlowRun, total int64, err error) {
+
+	if _, err = f.adaptors.Flow.GetById(flowId); err != nil {
+		return
+	}
+
+	list, total, err = f.adaptors.FlowRun.ListByFlowId(flowId, limit, offset)
+
+	return
+}
+
+// GetRun ...
+func (f *FlowEndpoint) GetRun(flowId int64, runId uuid.UUID) (run *m.FlowRun, err error) {
+
+	if run, err = f.adaptors.FlowRun.GetById(runId); err != nil {
+		return
+	}
+
+	if run.FlowId != flowId {
+		err = errors.New("record not found")
+	}
+
+	return
+}
+
 // Update ...
 func (f *FlowEndpoint) Update(params *m.Flow) (result *m.Flow, errs []*validation.Error, err error) {
 
@@ -244,8 +270,22 @@ func (f *FlowEndpoint) UpdateRedactor(params *m.RedactorFlow) (result *m.Redacto
 			fl.PrototypeType = common.FlowElementsPrototypeTask
 		case "gateway":
 			fl.PrototypeType = common.FlowElementsPrototypeGateway
+			switch element.GatewayType {
+			case common.FlowElementsGatewayParallel, common.FlowElementsGatewayInclusive:
+				fl.GatewayType = element.GatewayType
+			default:
+				fl.GatewayType = common.FlowElementsGatewayExclusive
+			}
 		case "flow":
 			fl.PrototypeType = common.FlowElementsPrototypeFlow
+		case "timer":
+			switch element.PrototypeType {
+			case common.FlowElementsPrototypeWaitEvent, common.FlowElementsPrototypeDebounce,
+				common.FlowElementsPrototypeThrottle:
+				fl.PrototypeType = element.PrototypeType
+			default:
+				fl.PrototypeType = common.FlowElementsPrototypeDelay
+			}
 		default:
 			fl.PrototypeType = common.FlowElementsPrototypeDefault
 		}
@@ -293,6 +333,7 @@ func (f *FlowEndpoint) UpdateRedactor(params *m.RedactorFlow) (result *m.Redacto
 			PointTo:   c.End.Point,
 			FlowId:    newFlow.Id,
 			Direction: c.Direction,
+			Condition: c.Condition,
 		}
 		conn.Uuid.Scan(c.Id)
 		conn.ElementFrom.Scan(c.Start.Object)
@@ -463,6 +504,9 @@ func (n *FlowEndpoint) ExportToRedactor(f *m.Flow) (redactorFlow *m.RedactorFlow
 		Id:                 f.Id,
 		Name:               f.Name,
 		Status:             f.Status,
+		ConcurrencyMode:    f.ConcurrencyMode,
+		Concurrency:        f.Concurrency,
+		QueueSize:          f.QueueSize,
 		Description:        f.Description,
 		Workflow:           f.Workflow,
 		Subscriptions:      f.Subscriptions,
@@ -482,6 +526,8 @@ func (n *FlowEndpoint) ExportToRedactor(f *m.Flow) (redactorFlow *m.RedactorFlow
 			Title:         el.Name,
 			Description:   el.Description,
 			PrototypeType: el.PrototypeType,
+			GatewayType:   el.GatewayType,
+			Settings:      el.Settings,
 			Script:        el.Script,
 		}
 
@@ -507,6 +553,8 @@ func (n *FlowEndpoint) ExportToRedactor(f *m.Flow) (redactorFlow *m.RedactorFlow
 		case "Gateway":
 			object.Type.Name = "gateway"
 			object.Type.Start = map[int64]interface{}{0: &map[int64]interface{}{0: true}}
+		case "Delay", "WaitEvent", "Debounce", "Throttle":
+			object.Type.Name = "timer"
 		default:
 
 		}
@@ -522,6 +570,7 @@ func (n *FlowEndpoint) ExportToRedactor(f *m.Flow) (redactorFlow *m.RedactorFlow
 			FlowType:  "default",
 			Title:     con.Name,
 			Direction: con.Direction,
+			Condition: con.Condition,
 		}
 		connector.Start.Object = con.ElementFrom
 		connector.Start.Point = con.PointFrom
@@ -0,0 +1,121 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"errors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/validation"
+)
+
+// NodeAlertRuleEndpoint ...
+type NodeAlertRuleEndpoint struct {
+	*CommonEndpoint
+}
+
+// NewNodeAlertRuleEndpoint ...
+func NewNodeAlertRuleEndpoint(common *CommonEndpoint) *NodeAlertRuleEndpoint {
+	return &NodeAlertRuleEndpoint{
+		CommonEndpoint: common,
+	}
+}
+
+// Add ...
+func (n *NodeAlertRuleEndpoint) Add(params *m.NodeAlertRule) (result *m.NodeAlertRule, errs []*validation.Error, err error) {
+
+	_, errs = params.Valid()
+	if len(errs) > 0 {
+		return
+	}
+
+	var id int64
+	if id, err = n.adaptors.NodeAlertRule.Add(params); err != nil {
+		return
+	}
+
+	if result, err = n.adaptors.NodeAlertRule.GetById(id); err != nil {
+		return
+	}
+
+	err = n.core.NodeMonitor.Reload()
+
+	return
+}
+
+// GetById ...
+func (n *NodeAlertRuleEndpoint) GetById(ruleId int64) (result *m.NodeAlertRule, err error) {
+
+	result, err = n.adaptors.NodeAlertRule.GetById(ruleId)
+
+	return
+}
+
+// Update ...
+func (n *NodeAlertRuleEndpoint) Update(params *m.NodeAlertRule) (result *m.NodeAlertRule, errs []*validation.Error, err error) {
+
+	if _, err = n.adaptors.NodeAlertRule.GetById(params.Id); err != nil {
+		return
+	}
+
+	_, errs = params.Valid()
+	if len(errs) > 0 {
+		return
+	}
+
+	if err = n.adaptors.NodeAlertRule.Update(params); err != nil {
+		return
+	}
+
+	if result, err = n.adaptors.NodeAlertRule.GetById(params.Id); err != nil {
+		return
+	}
+
+	err = n.core.NodeMonitor.Reload()
+
+	return
+}
+
+// GetList ...
+func (n *NodeAlertRuleEndpoint) GetList(limit, offset int64, order, sortBy string) (result []*m.NodeAlertRule, total int64, err error) {
+
+	result, total, err = n.adaptors.NodeAlertRule.List(limit, offset, order, sortBy)
+
+	return
+}
+
+// Delete ...
+func (n *NodeAlertRuleEndpoint) Delete(ruleId int64) (err error) {
+
+	if ruleId == 0 {
+		err = errors.New("rule id is null")
+		return
+	}
+
+	if _, err = n.adaptors.NodeAlertRule.GetById(ruleId); err != nil {
+		return
+	}
+
+	if err = n.adaptors.NodeAlertRule.Delete(ruleId); err != nil {
+		return
+	}
+
+	err = n.core.NodeMonitor.Reload()
+
+	return
+}
@@ -76,14 +76,15 @@ func (n *ScriptEndpoint) GetById(scriptId int64) (result *m.Script, err error) {
 	return
 }
 
-// Copy ...
-func (n *ScriptEndpoint) Copy(scriptId int64) (script *m.Script, err error) {
+// Copy the shell commands stay allowed for the copy only if the caller may allow them
+func (n *ScriptEndpoint) Copy(scriptId int64, allowExec bool) (script *m.Script, err error) {
 
 	if script, err = n.adaptors.Script.GetById(scriptId); err != nil {
 		return
 	}
 
 	script.Id = 0
+	script.AllowExec = script.AllowExec && allowExec
 
 	const cpy = "[CPY]"
 	if res := strings.Split(script.Name, cpy); len(res) > 1 {
@@ -111,6 +112,8 @@ func (n *ScriptEndpoint) Update(params *m.Script) (result *m.Script, errs []*val
 		return
 	}
 
+	oldName := script.Name
+
 	if err = common.Copy(&script, &params); err != nil {
 		return
 	}
@@ -134,6 +137,11 @@ func (n *ScriptEndpoint) Update(params *m.Script) (result *m.Script, errs []*val
 		return
 	}
 
+	n.updateModule(oldName)
+	if script.Name != oldName {
+		n.updateModule(script.Name)
+	}
+
 	result, err = n.adaptors.Script.GetById(script.Id)
 
 	return
@@ -160,7 +168,11 @@ func (n *ScriptEndpoint) DeleteScriptById(scriptId int64) (err error) {
 		return
 	}
 
-	err = n.adaptors.Script.Delete(script.Id)
+	if err = n.adaptors.Script.Delete(script.Id); err != nil {
+		return
+	}
+
+	n.updateModule(script.Name)
 
 	return
 }
@@ -177,6 +189,7 @@ func (n *ScriptEndpoint) Execute(scriptId int64) (result string, err error) {
 	if engine, err = n.scriptService.NewEngine(script); err != nil {
 		return
 	}
+	defer engine.Close()
 
 	result, err = engine.DoFull()
 
@@ -190,6 +203,7 @@ func (n *ScriptEndpoint) ExecuteSource(script *m.Script) (result string, err err
 	if engine, err = n.scriptService.NewEngine(script); err != nil {
 		return
 	}
+	defer engine.Close()
 
 	if err = engine.Compile(); err != nil {
 		return
@@ -200,6 +214,76 @@ func (n *ScriptEndpoint) ExecuteSource(script *m.Script) (result string, err err
 	return
 }
 
+// DryRun run the stored script with the mocked devices, the calls of the mocks are returned
+func (n *ScriptEndpoint) DryRun(scriptId int64, vars, returns map[string]interface{}) (result *m.ScriptDryRun, err error) {
+
+	var script *m.Script
+	if script, err = n.adaptors.Script.GetById(scriptId); err != nil {
+		return
+	}
+
+	result, err = n.scriptService.DryRun(script, vars, returns)
+
+	return
+}
+
+// Test run the test cases of the script
+func (n *ScriptEndpoint) Test(scriptId int64) (results []*m.ScriptTestResult, err error) {
+
+	var script *m.Script
+	if script, err = n.adaptors.Script.GetById(scriptId); err != nil {
+		return
+	}
+
+	results, err = n.scriptService.RunTests(script)
+
+	return
+}
+
+// TestAll run the test cases of all scripts, the results by the script name
+func (n *ScriptEndpoint) TestAll() (results map[string][]*m.ScriptTestResult, err error) {
+
+	results = make(map[string][]*m.ScriptTestResult)
+
+	const limit = 100
+	var offset int64
+	for {
+		var list []*m.Script
+		var total int64
+		if list, total, err = n.adaptors.Script.List(limit, offset, "asc", "id"); err != nil {
+			return
+		}
+
+		for _, script := range list {
+			if len(script.TestCases) == 0 {
+				continue
+			}
+			if results[script.Name], err = n.scriptService.RunTests(script); err != nil {
+				return
+			}
+		}
+
+		offset += limit
+		if offset >= total || len(list) == 0 {
+			break
+		}
+	}
+
+	return
+}
+
+// NewDebugEngine the compiled engine for the debug session, the lines of the breakpoints are of script.Compiled
+func (n *ScriptEndpoint) NewDebugEngine(script *m.Script) (engine *scripts.Engine, err error) {
+
+	if engine, err = n.scriptService.NewEngine(script); err != nil {
+		return
+	}
+
+	err = engine.Compile()
+
+	return
+}
+
 // Search ...
 func (n *ScriptEndpoint) Search(query string, limit, offset int) (devices []*m.Script, total int64, err error) {
 
@@ -207,3 +291,18 @@ func (n *ScriptEndpoint) Search(query string, limit, offset int) (devices []*m.S
 
 	return
 }
+
+// updateModule the scripts that require the changed script reload it on the next run,
+// the workflows with such scripts are restarted
+func (n *ScriptEndpoint) updateModule(name string) {
+
+	modules := n.scriptService.Modules()
+	dependents := modules.Dependents(name)
+	if len(dependents) > 0 {
+		log.Infof("script %s was updated, reload dependents: %s", name, strings.Join(dependents, ", "))
+	}
+
+	modules.Update(name)
+
+	n.core.UpdateModule(dependents)
+}
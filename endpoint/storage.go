@@ -0,0 +1,83 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"errors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/validation"
+	"time"
+)
+
+// StorageEndpoint ...
+type StorageEndpoint struct {
+	*CommonEndpoint
+}
+
+// NewStorageEndpoint ...
+func NewStorageEndpoint(common *CommonEndpoint) *StorageEndpoint {
+	return &StorageEndpoint{
+		CommonEndpoint: common,
+	}
+}
+
+// GetList ...
+func (n *StorageEndpoint) GetList(limit, offset int64, order, sortBy, namespace string) (list []*m.StorageItem, total int64, err error) {
+	list, total, err = n.core.Storage.List(limit, offset, order, sortBy, namespace)
+	return
+}
+
+// Get ...
+func (n *StorageEndpoint) Get(namespace, key string) (item *m.StorageItem, err error) {
+
+	var ok bool
+	if item, ok = n.core.Storage.GetItem(namespace, key); !ok {
+		err = errors.New("record not found")
+	}
+
+	return
+}
+
+// Set the value of the key, zero ttl means the key never expires
+func (n *StorageEndpoint) Set(params *m.StorageItem, ttl time.Duration) (result *m.StorageItem, errs []*validation.Error, err error) {
+
+	if _, errs = params.Valid(); len(errs) > 0 {
+		return
+	}
+
+	if err = n.core.Storage.Set(params.Namespace, params.Key, params.Value, ttl); err != nil {
+		return
+	}
+
+	result, err = n.Get(params.Namespace, params.Key)
+
+	return
+}
+
+// Delete ...
+func (n *StorageEndpoint) Delete(namespace, key string) (err error) {
+
+	if _, err = n.Get(namespace, key); err != nil {
+		return
+	}
+
+	err = n.core.Storage.Delete(namespace, key)
+
+	return
+}
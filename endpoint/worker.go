@@ -0,0 +1,67 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"github.com/e154/smart-home/system/cron"
+	"time"
+)
+
+const (
+	workerNextTimeDefault = 5
+	workerNextTimeMax     = 100
+)
+
+// WorkerEndpoint ...
+type WorkerEndpoint struct {
+	*CommonEndpoint
+}
+
+// NewWorkerEndpoint ...
+func NewWorkerEndpoint(common *CommonEndpoint) *WorkerEndpoint {
+	return &WorkerEndpoint{
+		CommonEndpoint: common,
+	}
+}
+
+// NextTime parse worker time string and return the upcoming fire times
+func (n *WorkerEndpoint) NextTime(spec string, count int) (times []time.Time, err error) {
+
+	var schedule *cron.Schedule
+	if schedule, err = n.core.ParseTime(spec); err != nil {
+		return
+	}
+
+	if count <= 0 {
+		count = workerNextTimeDefault
+	}
+	if count > workerNextTimeMax {
+		count = workerNextTimeMax
+	}
+
+	t := time.Now()
+	for i := 0; i < count; i++ {
+		if t = schedule.Next(t); t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+
+	return
+}
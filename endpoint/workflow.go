@@ -154,7 +154,7 @@ func (n *WorkflowEndpoint) Search(query string, limit, offset int) (result []*m.
 }
 
 // UpdateScenario ...
-func (n *WorkflowEndpoint) UpdateScenario(workflowId int64, workflowScenarioId int64) (err error) {
+func (n *WorkflowEndpoint) UpdateScenario(workflowId int64, workflowScenarioId int64, user *m.User) (err error) {
 
 	var workflow *m.Workflow
 	workflow, err = n.adaptors.Workflow.GetById(workflowId)
@@ -162,11 +162,41 @@ func (n *WorkflowEndpoint) UpdateScenario(workflowId int64, workflowScenarioId i
 		return
 	}
 
-	if err = n.adaptors.Workflow.SetScenario(workflow, workflowScenarioId); err != nil {
+	var scenario *m.WorkflowScenario
+	if scenario, err = n.adaptors.WorkflowScenario.GetById(workflowScenarioId); err != nil {
 		return
 	}
 
-	err = n.core.UpdateWorkflowScenario(workflowId)
+	if scenario.WorkflowId != workflow.Id {
+		err = errors.New("scenario not found")
+		return
+	}
+
+	// the disabled workflow is not running, only the base is updated
+	if workflow.Status != "enabled" {
+		err = n.adaptors.Workflow.SetScenario(workflow, workflowScenarioId)
+		return
+	}
+
+	var initiator string
+	if user != nil {
+		initiator = user.Nickname
+	}
+
+	// the running workflow stores the scenario, records the switch and publishes the event
+	err = n.core.SetWorkflowScenario(workflowId, scenario.SystemName, m.ScenarioTriggerUser, initiator)
+
+	return
+}
+
+// ScenarioHistory the scenario switches of the workflow
+func (n *WorkflowEndpoint) ScenarioHistory(workflowId, limit, offset int64, order, sortBy string) (result []*m.WorkflowScenarioHistory, total int64, err error) {
+
+	if _, err = n.adaptors.Workflow.GetById(workflowId); err != nil {
+		return
+	}
+
+	result, total, err = n.adaptors.WorkflowScenarioHistory.ListByWorkflow(workflowId, limit, offset, order, sortBy)
 
 	return
 }
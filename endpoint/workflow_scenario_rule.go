@@ -0,0 +1,147 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"errors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/validation"
+)
+
+// WorkflowScenarioRuleEndpoint ...
+type WorkflowScenarioRuleEndpoint struct {
+	*CommonEndpoint
+}
+
+// NewWorkflowScenarioRuleEndpoint ...
+func NewWorkflowScenarioRuleEndpoint(common *CommonEndpoint) *WorkflowScenarioRuleEndpoint {
+	return &WorkflowScenarioRuleEndpoint{
+		CommonEndpoint: common,
+	}
+}
+
+// Add ...
+func (n *WorkflowScenarioRuleEndpoint) Add(params *m.WorkflowScenarioRule) (result *m.WorkflowScenarioRule, errs []*validation.Error, err error) {
+
+	if errs, err = n.valid(params); len(errs) > 0 || err != nil {
+		return
+	}
+
+	var id int64
+	if id, err = n.adaptors.WorkflowScenarioRule.Add(params); err != nil {
+		return
+	}
+
+	if result, err = n.adaptors.WorkflowScenarioRule.GetById(id); err != nil {
+		return
+	}
+
+	err = n.core.ScenarioRules.Reload()
+
+	return
+}
+
+// GetById ...
+func (n *WorkflowScenarioRuleEndpoint) GetById(workflowId, ruleId int64) (result *m.WorkflowScenarioRule, err error) {
+
+	if result, err = n.adaptors.WorkflowScenarioRule.GetById(ruleId); err != nil {
+		return
+	}
+
+	if result.WorkflowId != workflowId {
+		err = errors.New("record not found")
+	}
+
+	return
+}
+
+// Update ...
+func (n *WorkflowScenarioRuleEndpoint) Update(params *m.WorkflowScenarioRule) (result *m.WorkflowScenarioRule, errs []*validation.Error, err error) {
+
+	if _, err = n.GetById(params.WorkflowId, params.Id); err != nil {
+		return
+	}
+
+	if errs, err = n.valid(params); len(errs) > 0 || err != nil {
+		return
+	}
+
+	if err = n.adaptors.WorkflowScenarioRule.Update(params); err != nil {
+		return
+	}
+
+	if result, err = n.adaptors.WorkflowScenarioRule.GetById(params.Id); err != nil {
+		return
+	}
+
+	err = n.core.ScenarioRules.Reload()
+
+	return
+}
+
+// GetList ...
+func (n *WorkflowScenarioRuleEndpoint) GetList(workflowId, limit, offset int64, order, sortBy string) (result []*m.WorkflowScenarioRule, total int64, err error) {
+
+	result, total, err = n.adaptors.WorkflowScenarioRule.ListByWorkflow(workflowId, limit, offset, order, sortBy)
+
+	return
+}
+
+// Delete ...
+func (n *WorkflowScenarioRuleEndpoint) Delete(workflowId, ruleId int64) (err error) {
+
+	if ruleId == 0 {
+		err = errors.New("rule id is null")
+		return
+	}
+
+	if _, err = n.GetById(workflowId, ruleId); err != nil {
+		return
+	}
+
+	if err = n.adaptors.WorkflowScenarioRule.Delete(ruleId); err != nil {
+		return
+	}
+
+	err = n.core.ScenarioRules.Reload()
+
+	return
+}
+
+// valid the rule and its scenario of the same workflow
+func (n *WorkflowScenarioRuleEndpoint) valid(params *m.WorkflowScenarioRule) (errs []*validation.Error, err error) {
+
+	if _, errs = params.Valid(); len(errs) > 0 {
+		return
+	}
+
+	if _, err = n.adaptors.Workflow.GetById(params.WorkflowId); err != nil {
+		return
+	}
+
+	scenario, err := n.adaptors.WorkflowScenario.GetById(params.WorkflowScenarioId)
+	if err != nil || scenario.WorkflowId != params.WorkflowId {
+		err = nil
+		valid := validation.Validation{}
+		valid.SetError("workflow_scenario_id", "the scenario does not belong to the workflow")
+		errs = valid.Errors
+	}
+
+	return
+}
@@ -74,6 +74,8 @@ func (n *Zigbee2mqttEndpoint) Update(params *m.Zigbee2mqtt) (bridge *m.Zigbee2mq
 	bridge.BaseTopic = params.BaseTopic
 	bridge.Login = params.Login
 	bridge.PermitJoin = params.PermitJoin
+	bridge.OtaWindowStart = params.OtaWindowStart
+	bridge.OtaWindowEnd = params.OtaWindowEnd
 
 	// validation
 	_, errs = bridge.Valid()
@@ -147,6 +149,68 @@ func (n *Zigbee2mqttEndpoint) UpdateNetworkmap(id int64) (err error) {
 	return
 }
 
+// NetworkmapList the stored scans of the bridge, the newest first
+func (n *Zigbee2mqttEndpoint) NetworkmapList(id, limit, offset int64) (result []*m.Zigbee2mqttNetworkmap, total int64, err error) {
+
+	if _, err = n.zigbee2mqtt.GetBridgeById(id); err != nil {
+		return
+	}
+
+	result, total, err = n.adaptors.Zigbee2mqttNetworkmap.ListByBridge(id, limit, offset)
+
+	return
+}
+
+// GetNetworkmap ...
+func (n *Zigbee2mqttEndpoint) GetNetworkmap(id, networkmapId int64) (result *m.Zigbee2mqttNetworkmap, err error) {
+
+	result, err = n.adaptors.Zigbee2mqttNetworkmap.GetById(id, networkmapId)
+
+	return
+}
+
+// NetworkmapAnalysis the zero networkmap id takes the last scan
+func (n *Zigbee2mqttEndpoint) NetworkmapAnalysis(id, networkmapId int64, minLqi, maxChildren int) (result *zigbee2mqtt.NetworkmapReport, err error) {
+
+	var networkmap *m.Zigbee2mqttNetworkmap
+	if networkmapId == 0 {
+		networkmap, err = n.adaptors.Zigbee2mqttNetworkmap.GetLast(id)
+	} else {
+		networkmap, err = n.adaptors.Zigbee2mqttNetworkmap.GetById(id, networkmapId)
+	}
+	if err != nil {
+		return
+	}
+
+	result = zigbee2mqtt.AnalyzeNetworkmap(networkmap, minLqi, maxChildren)
+
+	return
+}
+
+// OtaCheck ...
+func (n *Zigbee2mqttEndpoint) OtaCheck(id int64, friendlyName string) (err error) {
+
+	err = n.zigbee2mqtt.BridgeOtaCheck(id, friendlyName)
+
+	return
+}
+
+// OtaUpdate ...
+func (n *Zigbee2mqttEndpoint) OtaUpdate(id int64, friendlyName string) (err error) {
+
+	err = n.zigbee2mqtt.BridgeOtaUpdate(id, friendlyName)
+
+	return
+}
+
+// OtaStatus ...
+func (n *Zigbee2mqttEndpoint) OtaStatus(id int64) (result []zigbee2mqtt.OtaStatus, err error) {
+
+	result, err = n.zigbee2mqtt.BridgeOtaStatus(id)
+
+	return
+}
+
 // DeviceRename ...
 func (n *Zigbee2mqttEndpoint) DeviceRename(friendlyName, name string) (err error) {
 
@@ -0,0 +1,166 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"errors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/validation"
+)
+
+// Zigbee2mqttGroupEndpoint ...
+type Zigbee2mqttGroupEndpoint struct {
+	*CommonEndpoint
+}
+
+// NewZigbee2mqttGroupEndpoint ...
+func NewZigbee2mqttGroupEndpoint(common *CommonEndpoint) *Zigbee2mqttGroupEndpoint {
+	return &Zigbee2mqttGroupEndpoint{
+		CommonEndpoint: common,
+	}
+}
+
+// Add ...
+func (n *Zigbee2mqttGroupEndpoint) Add(params *m.Zigbee2mqttGroup) (result *m.Zigbee2mqttGroup, errs []*validation.Error, err error) {
+
+	if _, errs = params.Valid(); len(errs) > 0 {
+		return
+	}
+
+	result, err = n.zigbee2mqtt.AddGroup(params)
+
+	return
+}
+
+// GetById ...
+func (n *Zigbee2mqttGroupEndpoint) GetById(bridgeId, groupId int64) (result *m.Zigbee2mqttGroup, err error) {
+
+	result, err = n.zigbee2mqtt.GetGroup(bridgeId, groupId)
+
+	return
+}
+
+// Update only the description, the friendly name and the id are the address of the group in the network
+func (n *Zigbee2mqttGroupEndpoint) Update(params *m.Zigbee2mqttGroup) (result *m.Zigbee2mqttGroup, errs []*validation.Error, err error) {
+
+	var group *m.Zigbee2mqttGroup
+	if group, err = n.GetById(params.Zigbee2mqttId, params.Id); err != nil {
+		return
+	}
+
+	group.Description = params.Description
+
+	if _, errs = group.Valid(); len(errs) > 0 {
+		return
+	}
+
+	if err = n.adaptors.Zigbee2mqttGroup.Update(group); err != nil {
+		return
+	}
+
+	result, err = n.adaptors.Zigbee2mqttGroup.GetById(group.Id)
+
+	return
+}
+
+// GetList ...
+func (n *Zigbee2mqttGroupEndpoint) GetList(bridgeId, limit, offset int64, order, sortBy string) (result []*m.Zigbee2mqttGroup, total int64, err error) {
+
+	result, total, err = n.adaptors.Zigbee2mqttGroup.ListByBridge(bridgeId, limit, offset, order, sortBy)
+
+	return
+}
+
+// Delete ...
+func (n *Zigbee2mqttGroupEndpoint) Delete(bridgeId, groupId int64) (err error) {
+
+	if groupId == 0 {
+		err = errors.New("group id is null")
+		return
+	}
+
+	err = n.zigbee2mqtt.DeleteGroup(bridgeId, groupId)
+
+	return
+}
+
+// AddDevice ...
+func (n *Zigbee2mqttGroupEndpoint) AddDevice(bridgeId, groupId int64, deviceId string) (result *m.Zigbee2mqttGroup, err error) {
+
+	if err = n.zigbee2mqtt.AddGroupDevice(bridgeId, groupId, deviceId); err != nil {
+		return
+	}
+
+	result, err = n.adaptors.Zigbee2mqttGroup.GetById(groupId)
+
+	return
+}
+
+// RemoveDevice ...
+func (n *Zigbee2mqttGroupEndpoint) RemoveDevice(bridgeId, groupId int64, deviceId string) (result *m.Zigbee2mqttGroup, err error) {
+
+	if err = n.zigbee2mqtt.RemoveGroupDevice(bridgeId, groupId, deviceId); err != nil {
+		return
+	}
+
+	result, err = n.adaptors.Zigbee2mqttGroup.GetById(groupId)
+
+	return
+}
+
+// SetState ...
+func (n *Zigbee2mqttGroupEndpoint) SetState(bridgeId, groupId int64, state map[string]interface{}) (err error) {
+
+	if len(state) == 0 {
+		err = errors.New("state is empty")
+		return
+	}
+
+	err = n.zigbee2mqtt.SetGroupState(bridgeId, groupId, state)
+
+	return
+}
+
+// StoreScene ...
+func (n *Zigbee2mqttGroupEndpoint) StoreScene(bridgeId int64, params *m.Zigbee2mqttScene) (result *m.Zigbee2mqttScene, errs []*validation.Error, err error) {
+
+	if _, errs = params.Valid(); len(errs) > 0 {
+		return
+	}
+
+	result, err = n.zigbee2mqtt.StoreScene(bridgeId, params)
+
+	return
+}
+
+// RecallScene ...
+func (n *Zigbee2mqttGroupEndpoint) RecallScene(bridgeId, groupId int64, sceneId int) (err error) {
+
+	err = n.zigbee2mqtt.RecallScene(bridgeId, groupId, sceneId)
+
+	return
+}
+
+// DeleteScene ...
+func (n *Zigbee2mqttGroupEndpoint) DeleteScene(bridgeId, groupId int64, sceneId int) (err error) {
+
+	err = n.zigbee2mqtt.DeleteScene(bridgeId, groupId, sceneId)
+
+	return
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/e154/smart-home/api/server"
 	"github.com/e154/smart-home/api/websocket"
 	"github.com/e154/smart-home/common"
+	"github.com/e154/smart-home/endpoint"
+	m "github.com/e154/smart-home/models"
 	"github.com/e154/smart-home/system/alexa"
 	"github.com/e154/smart-home/system/backup"
 	"github.com/e154/smart-home/system/graceful_service"
@@ -34,6 +36,8 @@ import (
 	"github.com/e154/smart-home/system/zigbee2mqtt"
 	"github.com/e154/smart-home/version"
 	"os"
+	"sort"
+	"strconv"
 )
 
 var (
@@ -80,6 +84,25 @@ func main() {
 				graceful.Shutdown()
 			})
 			return
+		case "-test-scripts":
+			container := BuildContainer()
+			container.Invoke(func(
+				logger *logging.Logging,
+				endpoint *endpoint.Endpoint,
+				graceful *graceful_service.GracefulService) {
+
+				passed, err := testScripts(endpoint)
+				if err != nil {
+					log.Error(err.Error())
+				}
+
+				graceful.Shutdown()
+
+				if err != nil || !passed {
+					os.Exit(1)
+				}
+			})
+			return
 		case "-reset":
 			container := BuildContainer()
 			container.Invoke(func(
@@ -141,3 +164,54 @@ func start() {
 		panic(err.Error())
 	}
 }
+
+// testScripts run the test cases of the script by id (the second argument) or of all scripts
+func testScripts(endpoint *endpoint.Endpoint) (passed bool, err error) {
+
+	results := make(map[string][]*m.ScriptTestResult)
+	if len(os.Args) > 2 {
+		var id int64
+		if id, err = strconv.ParseInt(os.Args[2], 10, 64); err != nil {
+			return
+		}
+		var script *m.Script
+		if script, err = endpoint.Script.GetById(id); err != nil {
+			return
+		}
+		if results[script.Name], err = endpoint.Script.Test(id); err != nil {
+			return
+		}
+	} else {
+		if results, err = endpoint.Script.TestAll(); err != nil {
+			return
+		}
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	passed = true
+	var total, failed int
+	for _, name := range names {
+		for _, result := range results[name] {
+			total++
+			if result.Passed {
+				fmt.Printf("ok\t%s: %s\n", name, result.Name)
+				continue
+			}
+			passed = false
+			failed++
+			fmt.Printf("FAIL\t%s: %s\n", name, result.Name)
+			for _, failure := range result.Failures {
+				fmt.Printf("\t\t%s\n", failure)
+			}
+		}
+	}
+
+	fmt.Printf("%d test cases, %d failed\n", total, failed)
+
+	return
+}
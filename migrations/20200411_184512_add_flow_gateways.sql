@@ -0,0 +1,19 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create type flow_elements_gateway_type as enum ('exclusive', 'parallel', 'inclusive');
+
+ALTER TABLE flow_elements
+    ADD COLUMN gateway_type flow_elements_gateway_type NOT NULL DEFAULT 'exclusive';
+
+ALTER TABLE connections
+    ADD COLUMN condition text NULL;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE connections
+    DROP COLUMN IF EXISTS condition;
+
+ALTER TABLE flow_elements
+    DROP COLUMN IF EXISTS gateway_type;
+
+drop type flow_elements_gateway_type cascade;
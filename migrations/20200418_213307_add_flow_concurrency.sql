@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create type flows_concurrency_mode as enum ('serial', 'parallel', 'drop_oldest');
+
+ALTER TABLE flows
+    ADD COLUMN concurrency_mode flows_concurrency_mode NOT NULL DEFAULT 'serial',
+    ADD COLUMN concurrency      int                    NOT NULL DEFAULT 1,
+    ADD COLUMN queue_size       int                    NOT NULL DEFAULT 10;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE flows
+    DROP COLUMN IF EXISTS concurrency_mode,
+    DROP COLUMN IF EXISTS concurrency,
+    DROP COLUMN IF EXISTS queue_size;
+
+drop type flows_concurrency_mode cascade;
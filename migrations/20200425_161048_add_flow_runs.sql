@@ -0,0 +1,36 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create type flow_runs_status as enum ('in_process', 'done', 'error');
+
+CREATE TABLE flow_runs
+(
+    id          UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    flow_id     BIGINT                   NOT NULL
+        CONSTRAINT flow_runs_2_flows_fk REFERENCES flows (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    status      flow_runs_status         NOT NULL DEFAULT 'in_process',
+    error       text                     NULL,
+    started_at  timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone NULL
+);
+
+CREATE INDEX flow_runs_flow_id_idx
+    ON flow_runs (flow_id, started_at);
+
+CREATE TABLE flow_run_steps
+(
+    id                BIGSERIAL PRIMARY KEY,
+    flow_run_id       UUID                     NOT NULL
+        CONSTRAINT flow_run_steps_2_flow_runs_fk REFERENCES flow_runs (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    flow_element_uuid UUID                     NOT NULL,
+    status            flow_runs_status         NOT NULL DEFAULT 'in_process',
+    error             text                     NULL,
+    vars              JSONB                             DEFAULT '{}',
+    started_at        timestamp with time zone NOT NULL,
+    finished_at       timestamp with time zone NULL
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS flow_run_steps CASCADE;
+DROP TABLE IF EXISTS flow_runs CASCADE;
+drop type flow_runs_status cascade;
@@ -0,0 +1,16 @@
+-- +migrate Up notransaction
+-- SQL in section 'Up' is executed when this migration is applied
+-- enum values can not be added inside of the transaction block
+ALTER TYPE flow_elements_prototype_type ADD VALUE IF NOT EXISTS 'Delay';
+ALTER TYPE flow_elements_prototype_type ADD VALUE IF NOT EXISTS 'WaitEvent';
+ALTER TYPE flow_elements_prototype_type ADD VALUE IF NOT EXISTS 'Debounce';
+ALTER TYPE flow_elements_prototype_type ADD VALUE IF NOT EXISTS 'Throttle';
+
+ALTER TABLE flow_elements
+    ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+-- postgres can not drop the enum values, they are left as is
+ALTER TABLE flow_elements
+    DROP COLUMN IF EXISTS settings;
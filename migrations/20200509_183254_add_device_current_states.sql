@@ -0,0 +1,20 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE device_states
+    ADD COLUMN transitions JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE device_current_states
+(
+    device_id       BIGINT                   NOT NULL PRIMARY KEY
+        CONSTRAINT device_current_states_2_devices_fk REFERENCES devices (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    device_state_id BIGINT                   NOT NULL
+        CONSTRAINT device_current_states_2_device_states_fk REFERENCES device_states (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    changed_at      timestamp with time zone NOT NULL
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS device_current_states CASCADE;
+
+ALTER TABLE device_states
+    DROP COLUMN IF EXISTS transitions;
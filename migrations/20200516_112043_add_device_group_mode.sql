@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE devices
+    ADD COLUMN group_mode text NOT NULL DEFAULT 'sequence';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS group_mode;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE storage_items
+(
+    namespace  text                     NOT NULL,
+    key        text                     NOT NULL,
+    value      JSONB                    NOT NULL DEFAULT 'null',
+    expire_at  timestamp with time zone NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (namespace, key)
+);
+
+CREATE INDEX storage_items_expire_at_idx
+    ON storage_items (expire_at);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS storage_items CASCADE;
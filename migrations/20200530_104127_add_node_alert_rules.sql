@@ -0,0 +1,28 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE devices
+    ADD COLUMN backup_node_id BIGINT NULL
+        CONSTRAINT backup_node_at_devices_fk REFERENCES nodes (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE TABLE node_alert_rules
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       text                     NOT NULL,
+    node_id    BIGINT                   NULL
+        CONSTRAINT node_at_node_alert_rules_fk REFERENCES nodes (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    kind       text                     NOT NULL,
+    threshold  double precision         NOT NULL DEFAULT 0,
+    period     INTEGER                  NOT NULL DEFAULT 0,
+    channel    text                     NOT NULL,
+    address    text                     NOT NULL DEFAULT '',
+    enabled    BOOLEAN                  NOT NULL DEFAULT TRUE,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS node_alert_rules CASCADE;
+
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS backup_node_id;
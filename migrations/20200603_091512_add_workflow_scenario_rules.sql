@@ -0,0 +1,41 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE workflow_scenario_rules
+(
+    id                   BIGSERIAL PRIMARY KEY,
+    name                 text                     NOT NULL,
+    workflow_id          BIGINT                   NOT NULL
+        CONSTRAINT workflow_at_workflow_scenario_rules_fk REFERENCES workflows (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    workflow_scenario_id BIGINT                   NOT NULL
+        CONSTRAINT scenario_at_workflow_scenario_rules_fk REFERENCES workflow_scenarios (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    priority             INTEGER                  NOT NULL DEFAULT 0,
+    conditions           JSONB DEFAULT '[]'       NOT NULL,
+    delay                INTEGER                  NOT NULL DEFAULT 0,
+    hold                 INTEGER                  NOT NULL DEFAULT 0,
+    enabled              BOOLEAN                  NOT NULL DEFAULT TRUE,
+    created_at           timestamp with time zone NOT NULL,
+    updated_at           timestamp with time zone NOT NULL
+);
+
+CREATE TABLE workflow_scenario_history
+(
+    id               BIGSERIAL PRIMARY KEY,
+    workflow_id      BIGINT                   NOT NULL
+        CONSTRAINT workflow_at_workflow_scenario_history_fk REFERENCES workflows (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    from_scenario_id BIGINT                   NULL
+        CONSTRAINT from_scenario_at_workflow_scenario_history_fk REFERENCES workflow_scenarios (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    from_scenario    text                     NOT NULL DEFAULT '',
+    to_scenario_id   BIGINT                   NULL
+        CONSTRAINT to_scenario_at_workflow_scenario_history_fk REFERENCES workflow_scenarios (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    to_scenario      text                     NOT NULL DEFAULT '',
+    trigger          text                     NOT NULL,
+    initiator        text                     NOT NULL DEFAULT '',
+    created_at       timestamp with time zone NOT NULL
+);
+
+CREATE INDEX workflow_scenario_history_workflow_at_idx ON workflow_scenario_history (workflow_id, created_at);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS workflow_scenario_history CASCADE;
+DROP TABLE IF EXISTS workflow_scenario_rules CASCADE;
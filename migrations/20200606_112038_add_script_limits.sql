@@ -0,0 +1,27 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE scripts
+    ADD COLUMN timeout INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scripts
+    ADD COLUMN max_stack INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scripts
+    ADD COLUMN max_memory INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scripts
+    ADD COLUMN allow_exec BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- the scripts already using the shell commands keep working
+UPDATE scripts
+SET allow_exec = TRUE
+WHERE source LIKE '%ExecuteSync%'
+   OR source LIKE '%ExecuteAsync%';
+
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+ALTER TABLE scripts
+    DROP COLUMN allow_exec;
+ALTER TABLE scripts
+    DROP COLUMN max_memory;
+ALTER TABLE scripts
+    DROP COLUMN max_stack;
+ALTER TABLE scripts
+    DROP COLUMN timeout;
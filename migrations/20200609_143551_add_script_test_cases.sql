@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE scripts
+    ADD COLUMN test_cases JSONB DEFAULT '[]' NOT NULL;
+
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+ALTER TABLE scripts
+    DROP COLUMN test_cases;
@@ -0,0 +1,44 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE zigbee2mqtt_groups
+(
+    id             BIGSERIAL PRIMARY KEY,
+    zigbee2mqtt_id BIGINT                   NOT NULL
+        CONSTRAINT zigbee2mqtt_groups_at_zigbee2mqtt_fk REFERENCES zigbee2mqtt (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    group_id       INTEGER                  NOT NULL,
+    friendly_name  TEXT                     NOT NULL,
+    description    TEXT,
+    created_at     timestamp with time zone NOT NULL,
+    updated_at     timestamp with time zone NOT NULL
+);
+
+CREATE UNIQUE INDEX group_id_at_zigbee2mqtt_groups_unq ON zigbee2mqtt_groups (zigbee2mqtt_id, group_id);
+CREATE UNIQUE INDEX friendly_name_at_zigbee2mqtt_groups_unq ON zigbee2mqtt_groups (zigbee2mqtt_id, friendly_name);
+
+CREATE TABLE zigbee2mqtt_group_devices
+(
+    zigbee2mqtt_group_id  BIGINT NOT NULL
+        CONSTRAINT zigbee2mqtt_group_devices_at_zigbee2mqtt_groups_fk REFERENCES zigbee2mqtt_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    zigbee2mqtt_device_id TEXT   NOT NULL
+        CONSTRAINT zigbee2mqtt_group_devices_at_zigbee2mqtt_devices_fk REFERENCES zigbee2mqtt_devices (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT zigbee2mqtt_group_devices_pkey PRIMARY KEY (zigbee2mqtt_group_id, zigbee2mqtt_device_id)
+);
+
+CREATE TABLE zigbee2mqtt_scenes
+(
+    id                   BIGSERIAL PRIMARY KEY,
+    zigbee2mqtt_group_id BIGINT                   NOT NULL
+        CONSTRAINT zigbee2mqtt_scenes_at_zigbee2mqtt_groups_fk REFERENCES zigbee2mqtt_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    scene_id             INTEGER                  NOT NULL,
+    name                 TEXT,
+    created_at           timestamp with time zone NOT NULL,
+    updated_at           timestamp with time zone NOT NULL
+);
+
+CREATE UNIQUE INDEX scene_id_at_zigbee2mqtt_scenes_unq ON zigbee2mqtt_scenes (zigbee2mqtt_group_id, scene_id);
+
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS zigbee2mqtt_scenes CASCADE;
+DROP TABLE IF EXISTS zigbee2mqtt_group_devices CASCADE;
+DROP TABLE IF EXISTS zigbee2mqtt_groups CASCADE;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE zigbee2mqtt_devices
+    ADD COLUMN exposes JSONB DEFAULT '[]' NOT NULL;
+
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+ALTER TABLE zigbee2mqtt_devices
+    DROP COLUMN exposes;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE zigbee2mqtt
+    ADD COLUMN ota_window_start VARCHAR(5) DEFAULT '' NOT NULL,
+    ADD COLUMN ota_window_end VARCHAR(5) DEFAULT '' NOT NULL;
+
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+ALTER TABLE zigbee2mqtt
+    DROP COLUMN ota_window_start,
+    DROP COLUMN ota_window_end;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE zigbee2mqtt_networkmaps
+(
+    id             BIGSERIAL PRIMARY KEY,
+    zigbee2mqtt_id BIGINT                   NOT NULL
+        CONSTRAINT zigbee2mqtt_networkmaps_at_zigbee2mqtt_fk REFERENCES zigbee2mqtt (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    nodes          JSONB DEFAULT '[]'       NOT NULL,
+    links          JSONB DEFAULT '[]'       NOT NULL,
+    created_at     timestamp with time zone NOT NULL
+);
+
+CREATE INDEX created_at_at_zigbee2mqtt_networkmaps_idx ON zigbee2mqtt_networkmaps (zigbee2mqtt_id, created_at);
+
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS zigbee2mqtt_networkmaps CASCADE;
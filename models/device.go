@@ -37,12 +37,14 @@ type Device struct {
 	Device      *Device         `json:"device"`
 	DeviceId    *int64          `json:"device_id"`
 	Node        *Node           `json:"node"`
+	BackupNode  *Node           `json:"backup_node"`
 	Type        DeviceType      `json:"type"`
 	Properties  json.RawMessage `json:"properties" valid:"Required"`
 	States      []*DeviceState  `json:"states"`
 	Actions     []*DeviceAction `json:"actions"`
 	Devices     []*Device       `json:"devices"`
 	IsGroup     bool            `json:"is_group"`
+	GroupMode   DeviceGroupMode `json:"group_mode"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 }
@@ -56,6 +58,14 @@ func (d *Device) Valid() (ok bool, errs []*validation.Error) {
 		return
 	}
 
+	switch d.GroupMode {
+	case "", DeviceGroupSequence, DeviceGroupParallel:
+	default:
+		valid.SetError("group_mode", fmt.Sprintf("unknown group mode '%s'", d.GroupMode))
+		ok, errs = false, valid.Errors
+		return
+	}
+
 	var out interface{}
 	switch d.Type {
 	case DevTypeModbusRtu:
@@ -0,0 +1,49 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import "time"
+
+// DeviceCurrentState ...
+type DeviceCurrentState struct {
+	DeviceId      int64             `json:"device_id"`
+	DeviceState   *DeviceState      `json:"device_state"`
+	DeviceStateId int64             `json:"device_state_id"`
+	ChangedAt     time.Time         `json:"changed_at"`
+	Group         *DeviceGroupState `json:"group,omitempty"`
+}
+
+// DeviceGroupState state of the group derived from the member devices
+type DeviceGroupState struct {
+	DeviceId int64            `json:"device_id"`
+	State    string           `json:"state"`
+	Mixed    bool             `json:"mixed"`
+	Counts   map[string]int   `json:"counts"`
+	Members  map[int64]string `json:"members"`
+}
+
+// All all members are in the state
+func (d *DeviceGroupState) All(systemName string) bool {
+	return len(d.Members) > 0 && d.Counts[systemName] == len(d.Members)
+}
+
+// Any at least one member is in the state
+func (d *DeviceGroupState) Any(systemName string) bool {
+	return d.Counts[systemName] > 0
+}
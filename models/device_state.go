@@ -30,6 +30,7 @@ type DeviceState struct {
 	SystemName  string    `json:"system_name" valid:"MaxSize(254);Required"`
 	Device      *Device   `json:"device"`
 	DeviceId    int64     `json:"device_id" valid:"Required"`
+	Transitions []string  `json:"transitions"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -44,3 +45,19 @@ func (d *DeviceState) Valid() (ok bool, errs []*validation.Error) {
 
 	return
 }
+
+// CanTransitTo the state system names allowed after this state, any state if the list is empty
+func (d *DeviceState) CanTransitTo(systemName string) bool {
+
+	if len(d.Transitions) == 0 {
+		return true
+	}
+
+	for _, name := range d.Transitions {
+		if name == systemName {
+			return true
+		}
+	}
+
+	return false
+}
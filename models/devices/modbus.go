@@ -19,7 +19,9 @@
 package devices
 
 import (
+	"fmt"
 	. "github.com/e154/smart-home/common"
+	"github.com/e154/smart-home/system/validation"
 )
 
 const (
@@ -60,11 +62,75 @@ type DevModBusRtuConfig struct {
 	Timeout  int    `json:"timeout"`                            // milliseconds
 }
 
-// DevModBusTcpConfig ...
+// DevModBusTcpConfig the device without the node is served by the server itself
 type DevModBusTcpConfig struct {
 	Validation
-	SlaveId     int    `json:"slave_id" mapstructure:"slave_id"`
-	AddressPort string `json:"address_port" mapstructure:"address_port"`
+	SlaveId     int                      `json:"slave_id" mapstructure:"slave_id"`
+	AddressPort string                   `json:"address_port" mapstructure:"address_port"`
+	Timeout     int                      `json:"timeout"`                            // milliseconds
+	Retries     int                      `json:"retries"`                            // -1 without retries
+	PoolSize    int                      `json:"pool_size" mapstructure:"pool_size"` // connections per address
+	Polling     []*DevModBusPollingGroup `json:"polling"`
+}
+
+// DevModBusPollingGroup the register range is read periodically by the server,
+// the first value can be mapped to the device state
+type DevModBusPollingGroup struct {
+	Name     string            `json:"name"`
+	Function string            `json:"function"`
+	Address  uint16            `json:"address"`
+	Count    uint16            `json:"count"`
+	Interval int               `json:"interval"` // milliseconds
+	States   map[string]string `json:"states"`   // value -> state system name
+}
+
+// minimal interval of the polling group, milliseconds
+const devModBusPollingMinInterval = 100
+
+// Valid ...
+func (d DevModBusTcpConfig) Valid() (ok bool, errs []*validation.Error) {
+
+	valid := validation.Validation{}
+
+	if d.SlaveId < 0 || d.SlaveId > 247 {
+		valid.SetError("slave_id", "slave id must be between 0 and 247")
+	}
+
+	names := make(map[string]bool)
+	for i, group := range d.Polling {
+		field := fmt.Sprintf("polling[%d]", i)
+		if group == nil {
+			valid.SetError(field, "empty polling group")
+			continue
+		}
+		if group.Name == "" {
+			valid.SetError(field+".name", "name is required")
+		} else if names[group.Name] {
+			valid.SetError(field+".name", fmt.Sprintf("duplicate polling group '%s'", group.Name))
+		}
+		names[group.Name] = true
+
+		switch group.Function {
+		case ReadCoils, ReadDiscreteInputs, ReadInputRegisters, ReadHoldingRegisters:
+		default:
+			valid.SetError(field+".function", fmt.Sprintf("function '%s' can't be used for polling", group.Function))
+		}
+		if group.Count == 0 {
+			valid.SetError(field+".count", "count must be greater than zero")
+		}
+		if group.Interval < devModBusPollingMinInterval {
+			valid.SetError(field+".interval", fmt.Sprintf("interval must be at least %d ms", devModBusPollingMinInterval))
+		}
+	}
+
+	if valid.HasErrors() {
+		errs = valid.Errors
+		return
+	}
+
+	ok = true
+
+	return
 }
 
 // DevModBusRequest ...
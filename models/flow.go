@@ -33,6 +33,9 @@ type Flow struct {
 	Workflow           *Workflow            `json:"workflow"`
 	WorkflowId         int64                `json:"workflow_id" valid:"Required"`
 	WorkflowScenarioId int64                `json:"workflow_scenario_id" valid:"Required"`
+	ConcurrencyMode    FlowConcurrencyMode  `json:"concurrency_mode"`
+	Concurrency        int                  `json:"concurrency"`
+	QueueSize          int                  `json:"queue_size"`
 	Connections        []*Connection        `json:"connections"`
 	FlowElements       []*FlowElement       `json:"flow_elements"`
 	Workers            []*Worker            `json:"workers"`
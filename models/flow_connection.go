@@ -36,6 +36,7 @@ type Connection struct {
 	Flow          *Flow           `json:"flow"`
 	FlowId        int64           `json:"flow_id" valid:"Required"`
 	Direction     string          `json:"direction"`
+	Condition     string          `json:"condition"`
 	GraphSettings json.RawMessage `json:"graph_settings"`
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
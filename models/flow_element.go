@@ -36,6 +36,16 @@ type FlowElementGraphSettings struct {
 	Position FlowElementGraphSettingsPosition `json:"position"`
 }
 
+// FlowElementSettings settings of the time based elements
+type FlowElementSettings struct {
+	Delay               int    `json:"delay,omitempty"`
+	Interval            int    `json:"interval,omitempty"`
+	Timeout             int    `json:"timeout,omitempty"`
+	Topic               string `json:"topic,omitempty"`
+	Zigbee2mqttDeviceId string `json:"zigbee2mqtt_device_id,omitempty"`
+	Condition           string `json:"condition,omitempty"`
+}
+
 // FlowElement ...
 type FlowElement struct {
 	Uuid          uuid.UUID                 `json:"uuid"`
@@ -47,7 +57,9 @@ type FlowElement struct {
 	Status        StatusType                `json:"status" valid:"Required"`
 	FlowLink      *int64                    `json:"flow_link"`
 	PrototypeType FlowElementsPrototypeType `json:"prototype_type" valid:"Required"`
+	GatewayType   FlowElementsGatewayType   `json:"gateway_type"`
 	GraphSettings FlowElementGraphSettings  `json:"graph_settings"`
+	Settings      FlowElementSettings       `json:"settings"`
 	CreatedAt     time.Time                 `json:"created_at"`
 	UpdatedAt     time.Time                 `json:"updated_at"`
 }
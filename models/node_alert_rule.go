@@ -0,0 +1,112 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/system/validation"
+	"time"
+)
+
+// NodeAlertKind ...
+type NodeAlertKind string
+
+const (
+	// NodeAlertOffline the node is disconnected longer than the threshold in seconds
+	NodeAlertOffline = NodeAlertKind("offline")
+	// NodeAlertErrorRate the percent of the failed commands in the period exceeds the threshold
+	NodeAlertErrorRate = NodeAlertKind("error_rate")
+)
+
+// NodeAlertChannel ...
+type NodeAlertChannel string
+
+const (
+	// NodeAlertEmail ...
+	NodeAlertEmail = NodeAlertChannel("email")
+	// NodeAlertSms ...
+	NodeAlertSms = NodeAlertChannel("sms")
+	// NodeAlertTelegram ...
+	NodeAlertTelegram = NodeAlertChannel("telegram")
+	// NodeAlertSlack ...
+	NodeAlertSlack = NodeAlertChannel("slack")
+)
+
+// NodeAlertRule the rule is applied to the node or to all nodes without the node
+type NodeAlertRule struct {
+	Id        int64            `json:"id"`
+	Name      string           `json:"name" valid:"MaxSize(254);Required"`
+	Node      *Node            `json:"node"`
+	NodeId    *int64           `json:"node_id"`
+	Kind      NodeAlertKind    `json:"kind"`
+	Threshold float64          `json:"threshold"`
+	Period    int              `json:"period"`
+	Channel   NodeAlertChannel `json:"channel"`
+	Address   string           `json:"address" valid:"MaxSize(254)"`
+	Enabled   bool             `json:"enabled"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Valid ...
+func (d *NodeAlertRule) Valid() (ok bool, errs []*validation.Error) {
+
+	valid := validation.Validation{}
+	if ok, _ = valid.Valid(d); !ok {
+		errs = valid.Errors
+		return
+	}
+
+	switch d.Kind {
+	case NodeAlertOffline:
+		if d.Threshold <= 0 {
+			valid.SetError("threshold", "offline threshold must be greater than zero seconds")
+		}
+	case NodeAlertErrorRate:
+		if d.Threshold <= 0 || d.Threshold > 100 {
+			valid.SetError("threshold", "error rate threshold must be between 0 and 100 percent")
+		}
+		if d.Period <= 0 {
+			valid.SetError("period", "error rate period must be greater than zero seconds")
+		}
+	default:
+		valid.SetError("kind", fmt.Sprintf("unknown alert kind '%s'", d.Kind))
+	}
+
+	switch d.Channel {
+	case NodeAlertEmail, NodeAlertSms, NodeAlertSlack:
+		if d.Address == "" {
+			valid.SetError("address", fmt.Sprintf("address is required for the %s channel", d.Channel))
+		}
+	case NodeAlertTelegram:
+	default:
+		valid.SetError("channel", fmt.Sprintf("unknown alert channel '%s'", d.Channel))
+	}
+
+	if valid.HasErrors() {
+		ok, errs = false, valid.Errors
+	}
+
+	return
+}
+
+// IsApplied the rule is applied to the node
+func (d *NodeAlertRule) IsApplied(nodeId int64) bool {
+	return d.NodeId == nil || *d.NodeId == nodeId
+}
@@ -46,6 +46,7 @@ type RedactorConnector struct {
 	FlowType  string `json:"flow_type"`
 	Title     string `json:"title"`
 	Direction string `json:"direction"`
+	Condition string `json:"condition"`
 }
 
 // RedactorObject ...
@@ -67,6 +68,8 @@ type RedactorObject struct {
 	Title         string                    `json:"title"`
 	Description   string                    `json:"description"`
 	PrototypeType FlowElementsPrototypeType `json:"prototype_type"`
+	GatewayType   FlowElementsGatewayType   `json:"gateway_type"`
+	Settings      FlowElementSettings       `json:"settings"`
 	Script        *Script                   `json:"script"`
 	FlowLink      *Flow                     `json:"flow_link"`
 }
@@ -77,6 +80,9 @@ type RedactorFlow struct {
 	Name               string               `json:"name"`
 	Description        string               `json:"description"`
 	Status             StatusType           `json:"status"`
+	ConcurrencyMode    FlowConcurrencyMode  `json:"concurrency_mode"`
+	Concurrency        int                  `json:"concurrency"`
+	QueueSize          int                  `json:"queue_size"`
 	Objects            []*RedactorObject    `json:"objects"`
 	Connectors         []*RedactorConnector `json:"connectors"`
 	CreatedAt          time.Time            `json:"created_at"`
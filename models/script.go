@@ -19,6 +19,7 @@
 package models
 
 import (
+	"fmt"
 	. "github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/system/validation"
 	"time"
@@ -26,14 +27,19 @@ import (
 
 // Script ...
 type Script struct {
-	Id          int64      `json:"id"`
-	Lang        ScriptLang `json:"lang" valid:"Required"`
-	Name        string     `json:"name" valid:"MaxSize(254);Required"`
-	Source      string     `json:"source"`
-	Description string     `json:"description"`
-	Compiled    string     `json:"-"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	Id          int64             `json:"id"`
+	Lang        ScriptLang        `json:"lang" valid:"Required"`
+	Name        string            `json:"name" valid:"MaxSize(254);Required"`
+	Source      string            `json:"source"`
+	Description string            `json:"description"`
+	Compiled    string            `json:"-"`
+	Timeout     int               `json:"timeout" valid:"Min(0)"`    // milliseconds, 0 - the default of the config
+	MaxStack    int               `json:"max_stack" valid:"Min(0)"`  // call depth, 0 - the default of the config
+	MaxMemory   int               `json:"max_memory" valid:"Min(0)"` // megabytes allocated during the run, 0 - the default of the config
+	AllowExec   bool              `json:"allow_exec"`
+	TestCases   []*ScriptTestCase `json:"test_cases"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
 }
 
 // Valid ...
@@ -42,6 +48,24 @@ func (d *Script) Valid() (ok bool, errs []*validation.Error) {
 	valid := validation.Validation{}
 	if ok, _ = valid.Valid(d); !ok {
 		errs = valid.Errors
+		return
+	}
+
+	names := make(map[string]bool)
+	for i, testCase := range d.TestCases {
+		key := fmt.Sprintf("test_cases.%d.name", i)
+		if testCase.Name == "" {
+			valid.SetError(key, "test case name is required")
+			continue
+		}
+		if names[testCase.Name] {
+			valid.SetError(key, fmt.Sprintf("test case %s is duplicated", testCase.Name))
+		}
+		names[testCase.Name] = true
+	}
+
+	if valid.HasErrors() {
+		ok, errs = false, valid.Errors
 	}
 
 	return
@@ -0,0 +1,52 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+// ScriptTestCase inputs and expectations of the script run in the dry run mode
+type ScriptTestCase struct {
+	Name    string                 `json:"name"`
+	Vars    map[string]interface{} `json:"vars"`    // global variables of the script
+	Returns map[string]interface{} `json:"returns"` // results of the mocks by the call name, e.g. "Device.RunCommand"
+	Calls   []*ScriptCall          `json:"calls"`   // expected calls in the order, the args are not checked if omitted
+	Result  *string                `json:"result"`  // expected result of the script
+	Error   string                 `json:"error"`   // expected part of the error message
+}
+
+// ScriptCall call of the mocked binding
+type ScriptCall struct {
+	Name string        `json:"name"`
+	Args []interface{} `json:"args"`
+}
+
+// ScriptDryRun result of the script run with the mocked bindings
+type ScriptDryRun struct {
+	Result string        `json:"result"`
+	Error  string        `json:"error"`
+	Calls  []*ScriptCall `json:"calls"`
+}
+
+// ScriptTestResult ...
+type ScriptTestResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures"`
+	Result   string        `json:"result"`
+	Error    string        `json:"error"`
+	Calls    []*ScriptCall `json:"calls"`
+}
@@ -0,0 +1,50 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"github.com/e154/smart-home/system/validation"
+	"time"
+)
+
+// StorageItem value of the persistent storage, the key is unique within the namespace
+type StorageItem struct {
+	Namespace string      `json:"namespace" valid:"MaxSize(254);Required"`
+	Key       string      `json:"key" valid:"MaxSize(254);Required"`
+	Value     interface{} `json:"value"`
+	ExpireAt  *time.Time  `json:"expire_at"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Valid ...
+func (d *StorageItem) Valid() (ok bool, errs []*validation.Error) {
+
+	valid := validation.Validation{}
+	if ok, _ = valid.Valid(d); !ok {
+		errs = valid.Errors
+	}
+
+	return
+}
+
+// IsExpired ...
+func (d *StorageItem) IsExpired(now time.Time) bool {
+	return d.ExpireAt != nil && !now.Before(*d.ExpireAt)
+}
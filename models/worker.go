@@ -19,6 +19,7 @@
 package models
 
 import (
+	"github.com/e154/smart-home/system/cron"
 	"github.com/e154/smart-home/system/validation"
 	"time"
 )
@@ -44,7 +45,15 @@ type Worker struct {
 func (d *Worker) Valid() (ok bool, errs []*validation.Error) {
 
 	valid := validation.Validation{}
-	if ok, _ = valid.Valid(d); !ok {
+	valid.Valid(d)
+
+	if d.Time != "" {
+		if _, err := cron.Parse(d.Time); err != nil {
+			valid.SetError("time", err.Error())
+		}
+	}
+
+	if ok = !valid.HasErrors(); !ok {
 		errs = valid.Errors
 	}
 
@@ -0,0 +1,46 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import "time"
+
+// ScenarioTrigger ...
+type ScenarioTrigger string
+
+const (
+	// ScenarioTriggerRule the scenario rule, the initiator is the rule name
+	ScenarioTriggerRule = ScenarioTrigger("rule")
+	// ScenarioTriggerScript the Workflow.SetScenario call from the script
+	ScenarioTriggerScript = ScenarioTrigger("script")
+	// ScenarioTriggerUser the api call, the initiator is the user nickname
+	ScenarioTriggerUser = ScenarioTrigger("user")
+)
+
+// WorkflowScenarioHistory the switch of the workflow scenario
+type WorkflowScenarioHistory struct {
+	Id             int64           `json:"id"`
+	WorkflowId     int64           `json:"workflow_id"`
+	FromScenarioId *int64          `json:"from_scenario_id"`
+	FromScenario   string          `json:"from_scenario"`
+	ToScenarioId   *int64          `json:"to_scenario_id"`
+	ToScenario     string          `json:"to_scenario"`
+	Trigger        ScenarioTrigger `json:"trigger"`
+	Initiator      string          `json:"initiator"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
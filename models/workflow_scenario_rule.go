@@ -0,0 +1,142 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/system/validation"
+	"time"
+)
+
+// ScenarioConditionKind ...
+type ScenarioConditionKind string
+
+const (
+	// ScenarioConditionTime the time of the day is within the window From - To,
+	// the window can pass midnight, "23:00" - "07:00"
+	ScenarioConditionTime = ScenarioConditionKind("time")
+	// ScenarioConditionDeviceState all devices are in one of the States
+	ScenarioConditionDeviceState = ScenarioConditionKind("device_state")
+	// ScenarioConditionPresence any device is in one of the States,
+	// the presence is kept Timeout seconds after the last device left them
+	ScenarioConditionPresence = ScenarioConditionKind("presence")
+)
+
+// ScenarioCondition ...
+type ScenarioCondition struct {
+	Kind      ScenarioConditionKind `json:"kind"`
+	From      string                `json:"from,omitempty"`
+	To        string                `json:"to,omitempty"`
+	DeviceIds []int64               `json:"device_ids,omitempty"`
+	States    []string              `json:"states,omitempty"`
+	Timeout   int                   `json:"timeout,omitempty"`
+	Not       bool                  `json:"not,omitempty"`
+}
+
+// Window the time window from the start of the day
+func (c *ScenarioCondition) Window() (from, to time.Duration, err error) {
+	if from, err = parseDayTime(c.From); err != nil {
+		return
+	}
+	to, err = parseDayTime(c.To)
+	return
+}
+
+// Valid ...
+func (c *ScenarioCondition) Valid(valid *validation.Validation, i int) {
+
+	key := fmt.Sprintf("conditions[%d]", i)
+
+	switch c.Kind {
+	case ScenarioConditionTime:
+		if _, _, err := c.Window(); err != nil {
+			valid.SetError(key, fmt.Sprintf("bad time window '%s' - '%s', expected format is 15:04", c.From, c.To))
+		}
+	case ScenarioConditionDeviceState, ScenarioConditionPresence:
+		if len(c.DeviceIds) == 0 {
+			valid.SetError(key, fmt.Sprintf("%s condition requires the devices", c.Kind))
+		}
+		if len(c.States) == 0 {
+			valid.SetError(key, fmt.Sprintf("%s condition requires the states", c.Kind))
+		}
+		if c.Timeout < 0 {
+			valid.SetError(key, "timeout must not be negative")
+		}
+	default:
+		valid.SetError(key, fmt.Sprintf("unknown condition kind '%s'", c.Kind))
+	}
+}
+
+func parseDayTime(s string) (d time.Duration, err error) {
+	var t time.Time
+	if t, err = time.Parse("15:04", s); err != nil {
+		return
+	}
+	d = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return
+}
+
+// WorkflowScenarioRule switches the workflow to the scenario when all conditions
+// are held for Delay seconds, the previous scenario is kept at least Hold seconds
+type WorkflowScenarioRule struct {
+	Id                 int64                `json:"id"`
+	Name               string               `json:"name" valid:"MaxSize(254);Required"`
+	WorkflowId         int64                `json:"workflow_id" valid:"Required"`
+	WorkflowScenario   *WorkflowScenario    `json:"workflow_scenario"`
+	WorkflowScenarioId int64                `json:"workflow_scenario_id" valid:"Required"`
+	Priority           int                  `json:"priority"`
+	Conditions         []*ScenarioCondition `json:"conditions"`
+	Delay              int                  `json:"delay"`
+	Hold               int                  `json:"hold"`
+	Enabled            bool                 `json:"enabled"`
+	CreatedAt          time.Time            `json:"created_at"`
+	UpdatedAt          time.Time            `json:"updated_at"`
+}
+
+// Valid ...
+func (d *WorkflowScenarioRule) Valid() (ok bool, errs []*validation.Error) {
+
+	valid := validation.Validation{}
+	if ok, _ = valid.Valid(d); !ok {
+		errs = valid.Errors
+		return
+	}
+
+	if len(d.Conditions) == 0 {
+		valid.SetError("conditions", "at least one condition is required")
+	}
+
+	for i, condition := range d.Conditions {
+		condition.Valid(&valid, i)
+	}
+
+	if d.Delay < 0 {
+		valid.SetError("delay", "delay must not be negative")
+	}
+
+	if d.Hold < 0 {
+		valid.SetError("hold", "hold must not be negative")
+	}
+
+	if valid.HasErrors() {
+		ok, errs = false, valid.Errors
+	}
+
+	return
+}
@@ -33,6 +33,8 @@ type Zigbee2mqtt struct {
 	Devices           []*Zigbee2mqttDevice `json:"devices"`
 	PermitJoin        bool                 `json:"permit_join"`
 	BaseTopic         string               `json:"base_topic"`
+	OtaWindowStart    string               `json:"ota_window_start" valid:"Match(/^(([01][0-9]|2[0-3]):[0-5][0-9])?$/)"` // 02:00, empty - any time
+	OtaWindowEnd      string               `json:"ota_window_end" valid:"Match(/^(([01][0-9]|2[0-3]):[0-5][0-9])?$/)"`   // 05:00
 	CreatedAt         time.Time            `json:"created_at"`
 	UpdatedAt         time.Time            `json:"updated_at"`
 }
@@ -43,6 +45,12 @@ func (d *Zigbee2mqtt) Valid() (ok bool, errs []*validation.Error) {
 	valid := validation.Validation{}
 	if ok, _ = valid.Valid(d); !ok {
 		errs = valid.Errors
+		return
+	}
+
+	if (d.OtaWindowStart == "") != (d.OtaWindowEnd == "") {
+		valid.SetError("ota_window", "the start and the end of the update window are set together")
+		ok, errs = false, valid.Errors
 	}
 
 	return
@@ -27,18 +27,19 @@ import (
 
 // Zigbee2mqttDevice ...
 type Zigbee2mqttDevice struct {
-	Id            string    `json:"id"`
-	Zigbee2mqttId int64     `json:"zigbee2mqtt_id" valid:"Required"`
-	Name          string    `json:"name" valid:"MaxSize(254);Required"`
-	Type          string    `json:"type"`
-	Model         string    `json:"model"`
-	Description   string    `json:"description"`
-	Manufacturer  string    `json:"manufacturer"`
-	Functions     []string  `json:"functions"`
-	ImageUrl      string    `json:"image_url"`
-	Status        string    `json:"status"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Id            string               `json:"id"`
+	Zigbee2mqttId int64                `json:"zigbee2mqtt_id" valid:"Required"`
+	Name          string               `json:"name" valid:"MaxSize(254);Required"`
+	Type          string               `json:"type"`
+	Model         string               `json:"model"`
+	Description   string               `json:"description"`
+	Manufacturer  string               `json:"manufacturer"`
+	Functions     []string             `json:"functions"`
+	Exposes       []*Zigbee2mqttExpose `json:"exposes"`
+	ImageUrl      string               `json:"image_url"`
+	Status        string               `json:"status"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
 }
 
 // Valid ...
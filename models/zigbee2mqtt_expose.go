@@ -0,0 +1,110 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+const (
+	// Zigbee2mqttAccessState the value is published in the state of the device
+	Zigbee2mqttAccessState = 1
+	// Zigbee2mqttAccessSet the value can be changed by {friendly_name}/set
+	Zigbee2mqttAccessSet = 2
+	// Zigbee2mqttAccessGet the value can be requested by {friendly_name}/get
+	Zigbee2mqttAccessGet = 4
+)
+
+const (
+	// Zigbee2mqttExposeBinary ...
+	Zigbee2mqttExposeBinary = "binary"
+	// Zigbee2mqttExposeNumeric ...
+	Zigbee2mqttExposeNumeric = "numeric"
+	// Zigbee2mqttExposeEnum ...
+	Zigbee2mqttExposeEnum = "enum"
+	// Zigbee2mqttExposeText ...
+	Zigbee2mqttExposeText = "text"
+	// Zigbee2mqttExposeComposite ...
+	Zigbee2mqttExposeComposite = "composite"
+)
+
+// Zigbee2mqttExpose the feature of the device from the "exposes" of the zigbee2mqtt definition,
+// the generic types are binary, numeric, enum, text and composite,
+// the specific types (light, switch, lock, cover, climate, fan) are the containers of the features
+type Zigbee2mqttExpose struct {
+	Type        string               `json:"type"`
+	Name        string               `json:"name,omitempty"`
+	Property    string               `json:"property,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Endpoint    string               `json:"endpoint,omitempty"`
+	Access      int                  `json:"access,omitempty"`
+	Unit        string               `json:"unit,omitempty"`
+	ValueMin    *float64             `json:"value_min,omitempty"`
+	ValueMax    *float64             `json:"value_max,omitempty"`
+	ValueStep   *float64             `json:"value_step,omitempty"`
+	ValueOn     interface{}          `json:"value_on,omitempty"`
+	ValueOff    interface{}          `json:"value_off,omitempty"`
+	ValueToggle interface{}          `json:"value_toggle,omitempty"`
+	Values      []interface{}        `json:"values,omitempty"`
+	Features    []*Zigbee2mqttExpose `json:"features,omitempty"`
+}
+
+// IsSpecific the container of the features
+func (e *Zigbee2mqttExpose) IsSpecific() bool {
+	switch e.Type {
+	case Zigbee2mqttExposeBinary, Zigbee2mqttExposeNumeric, Zigbee2mqttExposeEnum,
+		Zigbee2mqttExposeText, Zigbee2mqttExposeComposite:
+		return false
+	}
+	return true
+}
+
+// GetAccess the composite feature has the access of its features
+func (e *Zigbee2mqttExpose) GetAccess() (access int) {
+	access = e.Access
+	if e.Type == Zigbee2mqttExposeComposite {
+		for _, feature := range e.Features {
+			access |= feature.GetAccess()
+		}
+	}
+	return
+}
+
+// CanSet ...
+func (e *Zigbee2mqttExpose) CanSet() bool {
+	return e.GetAccess()&Zigbee2mqttAccessSet != 0
+}
+
+// HasState ...
+func (e *Zigbee2mqttExpose) HasState() bool {
+	return e.GetAccess()&Zigbee2mqttAccessState != 0
+}
+
+// Zigbee2mqttFeatures the features of the exposes without the specific containers,
+// the composite feature is kept as one feature
+func Zigbee2mqttFeatures(exposes []*Zigbee2mqttExpose) (features []*Zigbee2mqttExpose) {
+	features = make([]*Zigbee2mqttExpose, 0)
+	for _, expose := range exposes {
+		if expose.IsSpecific() {
+			features = append(features, Zigbee2mqttFeatures(expose.Features)...)
+			continue
+		}
+		if expose.Property == "" {
+			continue
+		}
+		features = append(features, expose)
+	}
+	return
+}
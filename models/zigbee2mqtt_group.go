@@ -0,0 +1,69 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"github.com/e154/smart-home/system/validation"
+	"time"
+)
+
+// Zigbee2mqttGroup ...
+type Zigbee2mqttGroup struct {
+	Id            int64                `json:"id"`
+	Zigbee2mqttId int64                `json:"zigbee2mqtt_id" valid:"Required"`
+	GroupId       int                  `json:"group_id" valid:"Range(0,65535)"` // 0 - the first free id of the bridge
+	FriendlyName  string               `json:"friendly_name" valid:"MaxSize(254);Required"`
+	Description   string               `json:"description"`
+	Devices       []*Zigbee2mqttDevice `json:"devices"`
+	Scenes        []*Zigbee2mqttScene  `json:"scenes"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// Valid ...
+func (d *Zigbee2mqttGroup) Valid() (ok bool, errs []*validation.Error) {
+
+	valid := validation.Validation{}
+	if ok, _ = valid.Valid(d); !ok {
+		errs = valid.Errors
+	}
+
+	return
+}
+
+// Zigbee2mqttScene ...
+type Zigbee2mqttScene struct {
+	Id                 int64     `json:"id"`
+	Zigbee2mqttGroupId int64     `json:"zigbee2mqtt_group_id" valid:"Required"`
+	SceneId            int       `json:"scene_id" valid:"Range(0,255)"`
+	Name               string    `json:"name" valid:"MaxSize(254)"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Valid ...
+func (d *Zigbee2mqttScene) Valid() (ok bool, errs []*validation.Error) {
+
+	valid := validation.Validation{}
+	if ok, _ = valid.Valid(d); !ok {
+		errs = valid.Errors
+	}
+
+	return
+}
@@ -0,0 +1,76 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// Zigbee2mqttNodeCoordinator ...
+	Zigbee2mqttNodeCoordinator = "Coordinator"
+	// Zigbee2mqttNodeRouter ...
+	Zigbee2mqttNodeRouter = "Router"
+	// Zigbee2mqttNodeEndDevice ...
+	Zigbee2mqttNodeEndDevice = "EndDevice"
+)
+
+const (
+	// Zigbee2mqttRelationParent the source is the parent of the target
+	Zigbee2mqttRelationParent = 0
+	// Zigbee2mqttRelationChild the source is the child of the target
+	Zigbee2mqttRelationChild = 1
+	// Zigbee2mqttRelationSibling ...
+	Zigbee2mqttRelationSibling = 2
+	// Zigbee2mqttRelationNone ...
+	Zigbee2mqttRelationNone = 3
+	// Zigbee2mqttRelationPreviousChild ...
+	Zigbee2mqttRelationPreviousChild = 4
+)
+
+// Zigbee2mqttNetworkNode the device from the raw network map
+type Zigbee2mqttNetworkNode struct {
+	IeeeAddr       string     `json:"ieee_addr"`
+	FriendlyName   string     `json:"friendly_name"`
+	Type           string     `json:"type"`
+	NetworkAddress int        `json:"network_address"`
+	Manufacturer   string     `json:"manufacturer,omitempty"`
+	Model          string     `json:"model,omitempty"`
+	Failed         []string   `json:"failed,omitempty"` // the tables the device did not answer: lqi, routingTable
+	LastSeen       *time.Time `json:"last_seen,omitempty"`
+}
+
+// Zigbee2mqttNetworkLink the entry of the neighbor table of the target,
+// the relationship is the relationship of the source to the target
+type Zigbee2mqttNetworkLink struct {
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+	Lqi          int    `json:"lqi"`
+	Depth        int    `json:"depth"`
+	Relationship int    `json:"relationship"`
+}
+
+// Zigbee2mqttNetworkmap the scan of the network
+type Zigbee2mqttNetworkmap struct {
+	Id            int64                     `json:"id"`
+	Zigbee2mqttId int64                     `json:"zigbee2mqtt_id"`
+	Nodes         []*Zigbee2mqttNetworkNode `json:"nodes"`
+	Links         []*Zigbee2mqttNetworkLink `json:"links"`
+	CreatedAt     time.Time                 `json:"created_at"`
+}
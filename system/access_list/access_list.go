@@ -104,3 +104,25 @@ func (a *AccessListService) GetShotAccessList(role *m.Role) (err error) {
 	err = a.adaptors.Role.GetAccessList(role)
 	return
 }
+
+// HasPermission the role of the user has the access level of the package, admin has all of them
+func (a *AccessListService) HasPermission(user *m.User, packageName, levelName string) bool {
+
+	if user == nil || user.Role == nil {
+		return false
+	}
+
+	if user.Id == 1 || user.Role.Name == "admin" {
+		return true
+	}
+
+	accessList, err := a.GetFullAccessList(user.Role)
+	if err != nil {
+		log.Error(err.Error())
+		return false
+	}
+
+	_, ok := accessList[packageName][levelName]
+
+	return ok
+}
@@ -62,6 +62,37 @@ const DATA = `
       "description": ""
     }
   },
+  "node_alert_rule": {
+    "read": {
+      "actions": [
+        "/api/v1/node_alert_rule/[0-9]+",
+        "/api/v1/node_alert_rules"
+      ],
+      "method": "get",
+      "description": ""
+    },
+    "create": {
+      "actions": [
+        "/api/v1/node_alert_rule"
+      ],
+      "method": "post",
+      "description": ""
+    },
+    "update": {
+      "actions": [
+        "/api/v1/node_alert_rule/[0-9]+"
+      ],
+      "method": "put",
+      "description": ""
+    },
+    "delete": {
+      "actions": [
+        "/api/v1/node_alert_rule/[0-9]+"
+      ],
+      "method": "delete",
+      "description": ""
+    }
+  },
   "device": {
     "read": {
       "actions": [
@@ -70,14 +101,16 @@ const DATA = `
         "/api/v1/device/group",
         "/api/v1/device/[0-9]+/actions",
         "/api/v1/device/search",
-        "/api/v1/device/[0-9]+/statuses"
+        "/api/v1/device/[0-9]+/statuses",
+        "/api/v1/device/[0-9]+/state"
       ],
       "method": "get",
       "description": ""
     },
     "create": {
       "actions": [
-        "/api/v1/device"
+        "/api/v1/device",
+        "/api/v1/device/[0-9]+/capabilities"
       ],
       "method": "post",
       "description": ""
@@ -101,7 +134,8 @@ const DATA = `
     "read": {
       "actions": [
         "/api/v1/workflow",
-        "/api/v1/workflow/[0-9]+"
+        "/api/v1/workflow/[0-9]+",
+        "/api/v1/workflow/[0-9]+/scenario_history"
       ],
       "method": "get",
       "description": ""
@@ -129,6 +163,37 @@ const DATA = `
       "description": ""
     }
   },
+  "workflow_scenario_rule": {
+    "read": {
+      "actions": [
+        "/api/v1/workflow/[0-9]+/scenario_rule/[0-9]+",
+        "/api/v1/workflow/[0-9]+/scenario_rules"
+      ],
+      "method": "get",
+      "description": ""
+    },
+    "create": {
+      "actions": [
+        "/api/v1/workflow/[0-9]+/scenario_rule"
+      ],
+      "method": "post",
+      "description": ""
+    },
+    "update": {
+      "actions": [
+        "/api/v1/workflow/[0-9]+/scenario_rule/[0-9]+"
+      ],
+      "method": "put",
+      "description": ""
+    },
+    "delete": {
+      "actions": [
+        "/api/v1/workflow/[0-9]+/scenario_rule/[0-9]+"
+      ],
+      "method": "delete",
+      "description": ""
+    }
+  },
   "flow": {
     "read": {
       "actions": [
@@ -136,6 +201,8 @@ const DATA = `
         "/api/v1/flow/[0-9]+",
         "/api/v1/flow/[0-9]+/flow",
         "/api/v1/flow/[0-9]+/redactor",
+        "/api/v1/flow/[0-9]+/runs",
+        "/api/v1/flow/[0-9]+/run/[a-z0-9-]+",
         "/api/v1/flow/[0-9]+/workers",
         "/api/v1/flow/[0-9]+/search"
       ],
@@ -202,7 +269,8 @@ const DATA = `
     "read": {
       "actions": [
         "/api/v1/worker",
-        "/api/v1/worker/[0-9]+"
+        "/api/v1/worker/[0-9]+",
+        "/api/v1/worker/next_time"
       ],
       "method": "get",
       "description": ""
@@ -229,6 +297,30 @@ const DATA = `
       "description": ""
     }
   },
+  "storage": {
+    "read": {
+      "actions": [
+        "/api/v1/storage/[^/]+/[^/]+",
+        "/api/v1/storages"
+      ],
+      "method": "get",
+      "description": ""
+    },
+    "update": {
+      "actions": [
+        "/api/v1/storage/[^/]+/[^/]+"
+      ],
+      "method": "put",
+      "description": ""
+    },
+    "delete": {
+      "actions": [
+        "/api/v1/storage/[^/]+/[^/]+"
+      ],
+      "method": "delete",
+      "description": ""
+    }
+  },
   "script": {
     "read": {
       "actions": [
@@ -260,6 +352,25 @@ const DATA = `
       "method": "post",
       "description": "execute script"
     },
+    "exec_command": {
+      "actions": [],
+      "method": "",
+      "description": "shell commands (ExecuteSync, ExecuteAsync) in the saved scripts"
+    },
+    "dry_run": {
+      "actions": [
+        "/api/v1/script/[0-9]+/dry_run"
+      ],
+      "method": "post",
+      "description": "execute script with mocked devices"
+    },
+    "test": {
+      "actions": [
+        "/api/v1/script/[0-9]+/test"
+      ],
+      "method": "post",
+      "description": "run test cases of the script"
+    },
     "update": {
       "actions": [
         "/api/v1/script/[0-9]+"
@@ -788,6 +899,7 @@ const DATA = `
         "/api/v1/zigbee2mqtt/[0-9]+",
         "/api/v1/zigbee2mqtts",
         "/api/v1/zigbee2mqtt/[0-9]+/networkmap",
+        "/api/v1/zigbee2mqtt/[0-9]+/ota",
         "/api/v1/zigbee2mqtts/search_device"
       ],
       "method": "get",
@@ -824,6 +936,74 @@ const DATA = `
       ],
       "method": "patch",
       "description": ""
+    },
+    "ota_update": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/ota/check",
+        "/api/v1/zigbee2mqtt/[0-9]+/ota/update"
+      ],
+      "method": "post",
+      "description": ""
+    }
+  },
+  "zigbee2mqtt_networkmap": {
+    "read": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/networkmap/analysis",
+        "/api/v1/zigbee2mqtt/[0-9]+/networkmaps",
+        "/api/v1/zigbee2mqtt/[0-9]+/networkmaps/[0-9]+"
+      ],
+      "method": "get",
+      "description": ""
+    }
+  },
+  "zigbee2mqtt_group": {
+    "read": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/groups",
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+"
+      ],
+      "method": "get",
+      "description": ""
+    },
+    "create": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/groups",
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+/devices",
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+/scenes"
+      ],
+      "method": "post",
+      "description": ""
+    },
+    "update": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+"
+      ],
+      "method": "put",
+      "description": ""
+    },
+    "delete": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+",
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+/devices/[^/]+",
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+/scenes/[0-9]+"
+      ],
+      "method": "delete",
+      "description": ""
+    },
+    "set_state": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+/state"
+      ],
+      "method": "put",
+      "description": "switch all the devices of the group"
+    },
+    "recall_scene": {
+      "actions": [
+        "/api/v1/zigbee2mqtt/[0-9]+/groups/[0-9]+/scenes/[0-9]+/recall"
+      ],
+      "method": "post",
+      "description": "recall the scene of the group"
     }
   },
   "alexa": {
@@ -113,6 +113,7 @@ func (h *Worker) newScript(s *m.Script, req *Request, resp *Response) (engine *s
 	if engine, err = h.scriptService.NewEngine(s); err != nil {
 		return
 	}
+	defer engine.Close()
 
 	engine.PushStruct("Alexa", NewAlexaBind(req, resp))
 
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -173,4 +174,48 @@ func checkEnv(conf *AppConfig) {
 		v, _ := strconv.ParseInt(metricPort, 10, 32)
 		conf.MetricPort = int(v)
 	}
+
+	if lat := os.Getenv("LAT"); lat != "" {
+		conf.Lat, _ = strconv.ParseFloat(lat, 64)
+	}
+
+	if lon := os.Getenv("LON"); lon != "" {
+		conf.Lon, _ = strconv.ParseFloat(lon, 64)
+	}
+
+	if storagePersistent := os.Getenv("STORAGE_PERSISTENT"); storagePersistent != "" {
+		conf.StoragePersistent, _ = strconv.ParseBool(storagePersistent)
+	}
+
+	if nodeCommandRetries := os.Getenv("NODE_COMMAND_RETRIES"); nodeCommandRetries != "" {
+		conf.NodeCommandRetries, _ = strconv.Atoi(nodeCommandRetries)
+	}
+
+	if nodeCommandBackoff := os.Getenv("NODE_COMMAND_BACKOFF"); nodeCommandBackoff != "" {
+		conf.NodeCommandBackoff, _ = strconv.Atoi(nodeCommandBackoff)
+	}
+
+	if nodeCommandTtl := os.Getenv("NODE_COMMAND_TTL"); nodeCommandTtl != "" {
+		conf.NodeCommandTtl, _ = strconv.Atoi(nodeCommandTtl)
+	}
+
+	if scriptTimeout := os.Getenv("SCRIPT_TIMEOUT"); scriptTimeout != "" {
+		conf.ScriptTimeout, _ = strconv.Atoi(scriptTimeout)
+	}
+
+	if scriptMaxStack := os.Getenv("SCRIPT_MAX_STACK"); scriptMaxStack != "" {
+		conf.ScriptMaxStack, _ = strconv.Atoi(scriptMaxStack)
+	}
+
+	if scriptMaxMemory := os.Getenv("SCRIPT_MAX_MEMORY"); scriptMaxMemory != "" {
+		conf.ScriptMaxMemory, _ = strconv.Atoi(scriptMaxMemory)
+	}
+
+	if scriptHttpHosts := os.Getenv("SCRIPT_HTTP_HOSTS"); scriptHttpHosts != "" {
+		conf.ScriptHttpHosts = strings.Split(scriptHttpHosts, ",")
+	}
+
+	if scriptHttpTimeout := os.Getenv("SCRIPT_HTTP_TIMEOUT"); scriptHttpTimeout != "" {
+		conf.ScriptHttpTimeout, _ = strconv.Atoi(scriptHttpTimeout)
+	}
 }
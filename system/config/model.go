@@ -22,35 +22,47 @@ import "time"
 
 // AppConfig ...
 type AppConfig struct {
-	ServerHost                     string        `json:"server_host"`
-	ServerPort                     int           `json:"server_port"`
-	PgUser                         string        `json:"pg_user"`
-	PgPass                         string        `json:"pg_pass"`
-	PgHost                         string        `json:"pg_host"`
-	PgName                         string        `json:"pg_name"`
-	PgPort                         string        `json:"pg_port"`
-	PgDebug                        bool          `json:"pg_debug"`
-	PgLogger                       bool          `json:"pg_logger"`
-	PgMaxIdleConns                 int           `json:"pg_max_idle_conns"`
-	PgMaxOpenConns                 int           `json:"pg_max_open_conns"`
-	PgConnMaxLifeTime              int           `json:"pg_conn_max_life_time"`
-	AutoMigrate                    bool          `json:"auto_migrate"`
-	SnapshotDir                    string        `json:"snapshot_dir"`
-	Mode                           RunMode       `json:"mode"`
-	MqttPort                       int           `json:"mqtt_port"`
-	MqttRetryInterval              time.Duration `json:"mqtt_retry_interval"`
-	MqttRetryCheckInterval         time.Duration `json:"mqtt_retry_check_interval"`
-	MqttSessionExpiryInterval      time.Duration `json:"mqtt_session_expiry_interval"`
-	MqttSessionExpireCheckInterval time.Duration `json:"mqtt_session_expire_check_interval"`
-	MqttQueueQos0Messages          bool          `json:"mqtt_queue_qos_0_messages"`
-	MqttMaxInflight                int           `json:"mqtt_max_inflight"`
-	MqttMaxAwaitRel                int           `json:"mqtt_max_await_rel"`
-	MqttMaxMsgQueue                int           `json:"mqtt_max_msg_queue"`
-	MqttDeliverMode                int           `json:"mqtt_deliver_mode"`
-	Logging                        bool          `json:"logging"`
-	Metric                         bool          `json:"metric"`
-	MetricPort                     int           `json:"metric_port"`
-	ColoredLogging                 bool          `json:"colored_logging"`
+	ServerHost                     string         `json:"server_host"`
+	ServerPort                     int            `json:"server_port"`
+	PgUser                         string         `json:"pg_user"`
+	PgPass                         string         `json:"pg_pass"`
+	PgHost                         string         `json:"pg_host"`
+	PgName                         string         `json:"pg_name"`
+	PgPort                         string         `json:"pg_port"`
+	PgDebug                        bool           `json:"pg_debug"`
+	PgLogger                       bool           `json:"pg_logger"`
+	PgMaxIdleConns                 int            `json:"pg_max_idle_conns"`
+	PgMaxOpenConns                 int            `json:"pg_max_open_conns"`
+	PgConnMaxLifeTime              int            `json:"pg_conn_max_life_time"`
+	AutoMigrate                    bool           `json:"auto_migrate"`
+	SnapshotDir                    string         `json:"snapshot_dir"`
+	Mode                           RunMode        `json:"mode"`
+	MqttPort                       int            `json:"mqtt_port"`
+	MqttRetryInterval              time.Duration  `json:"mqtt_retry_interval"`
+	MqttRetryCheckInterval         time.Duration  `json:"mqtt_retry_check_interval"`
+	MqttSessionExpiryInterval      time.Duration  `json:"mqtt_session_expiry_interval"`
+	MqttSessionExpireCheckInterval time.Duration  `json:"mqtt_session_expire_check_interval"`
+	MqttQueueQos0Messages          bool           `json:"mqtt_queue_qos_0_messages"`
+	MqttMaxInflight                int            `json:"mqtt_max_inflight"`
+	MqttMaxAwaitRel                int            `json:"mqtt_max_await_rel"`
+	MqttMaxMsgQueue                int            `json:"mqtt_max_msg_queue"`
+	MqttDeliverMode                int            `json:"mqtt_deliver_mode"`
+	Logging                        bool           `json:"logging"`
+	Metric                         bool           `json:"metric"`
+	MetricPort                     int            `json:"metric_port"`
+	ColoredLogging                 bool           `json:"colored_logging"`
+	Lat                            float64        `json:"lat"`
+	Lon                            float64        `json:"lon"`
+	StoragePersistent              bool           `json:"storage_persistent"`
+	NodeCommandTimeout             map[string]int `json:"node_command_timeout"` // milliseconds by device type, "default" for the rest
+	NodeCommandRetries             int            `json:"node_command_retries"`
+	NodeCommandBackoff             int            `json:"node_command_backoff"` // milliseconds, doubles after each retry
+	NodeCommandTtl                 int            `json:"node_command_ttl"`     // seconds in the queue before the command expires
+	ScriptTimeout                  int            `json:"script_timeout"`       // milliseconds
+	ScriptMaxStack                 int            `json:"script_max_stack"`
+	ScriptMaxMemory                int            `json:"script_max_memory"`   // megabytes allocated by the script during the run
+	ScriptHttpHosts                []string       `json:"script_http_hosts"`   // allowlist of the Http binding
+	ScriptHttpTimeout              int            `json:"script_http_timeout"` // milliseconds
 }
 
 // RunMode ...
@@ -22,16 +22,19 @@ import (
 	"github.com/e154/smart-home/adaptors"
 	. "github.com/e154/smart-home/common"
 	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/modbus"
 	"github.com/e154/smart-home/system/mqtt"
 	"github.com/e154/smart-home/system/scripts"
 	"github.com/e154/smart-home/system/zigbee2mqtt"
 	"sync"
+	"time"
 )
 
 // Action ...
 type Action struct {
 	Device        *m.Device
 	Node          *Node
+	BackupNode    *Node
 	flow          *Flow
 	scriptService *scripts.ScriptService
 	ScriptEngine  *scripts.Engine
@@ -40,27 +43,56 @@ type Action struct {
 	mqtt          *mqtt.Mqtt
 	adaptors      *adaptors.Adaptors
 	zigbee2mqtt   *zigbee2mqtt.Zigbee2mqtt
+	modbus        *modbus.Modbus
+	device        *Device
+	members       []*Action
+	priority      NodeCommandPriority
+	events        *EventBus
 }
 
 // NewAction ...
 func NewAction(device *m.Device,
 	deviceAction *m.DeviceAction,
 	node *Node,
+	backupNode *Node,
 	flow *Flow,
 	scriptService *scripts.ScriptService,
 	mqtt *mqtt.Mqtt,
 	adaptors *adaptors.Adaptors,
-	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) (action *Action, err error) {
+	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt,
+	modbus *modbus.Modbus,
+	events *EventBus) (action *Action, err error) {
 
 	action = &Action{
 		Device:        device,
 		Node:          node,
+		BackupNode:    backupNode,
 		flow:          flow,
 		scriptService: scriptService,
 		deviceAction:  deviceAction,
 		mqtt:          mqtt,
 		adaptors:      adaptors,
 		zigbee2mqtt:   zigbee2mqtt,
+		modbus:        modbus,
+		priority:      NodeCommandPriorityLow,
+		events:        events,
+	}
+
+	// the action without the flow is called by the user, it goes ahead of the polling
+	if flow == nil {
+		action.priority = NodeCommandPriorityHigh
+	}
+
+	// the group action runs on the member devices
+	if device.IsGroup {
+		for _, member := range GroupMembers(device) {
+			var memberAction *Action
+			if memberAction, err = NewAction(member, deviceAction, node, backupNode, flow, scriptService, mqtt, adaptors, zigbee2mqtt, modbus, events); err != nil {
+				return
+			}
+			action.members = append(action.members, memberAction)
+		}
+		return
 	}
 
 	err = action.newScript()
@@ -70,15 +102,62 @@ func NewAction(device *m.Device,
 
 // Do ...
 func (a *Action) Do() (res string, err error) {
+	if a.Device.IsGroup {
+		res, err = a.doGroup()
+		a.publish(res, err)
+		return
+	}
+
 	a.doLock.Lock()
 	defer a.doLock.Unlock()
 	if a.deviceAction.Script == nil {
 		return
 	}
+	a.device.node = a.activeNode()
 	res, err = a.ScriptEngine.EvalScript(a.deviceAction.Script)
+	a.publish(res, err)
 	return
 }
 
+// Close drop the event subscriptions of the action scripts
+func (a *Action) Close() {
+	for _, member := range a.members {
+		member.Close()
+	}
+	if a.ScriptEngine != nil {
+		a.ScriptEngine.Close()
+	}
+}
+
+func (a *Action) publish(res string, err error) {
+	event := ActionExecuted{
+		DeviceId:   a.Device.Id,
+		ActionId:   a.deviceAction.Id,
+		Name:       a.deviceAction.Name,
+		Result:     res,
+		ExecutedAt: time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	a.events.Publish(event)
+}
+
+// activeNode the backup node takes the commands while the primary node is disconnected
+func (a *Action) activeNode() *Node {
+	if a.BackupNode == nil || !a.BackupNode.IsConnected() {
+		return a.Node
+	}
+	if a.Node != nil && a.Node.IsConnected() {
+		return a.Node
+	}
+	if a.Node != nil {
+		log.Infof("device %d: node \"%s\" is disconnected, failover to the backup node \"%s\"",
+			a.Device.Id, a.Node.Model().Name, a.BackupNode.Model().Name)
+	}
+	return a.BackupNode
+}
+
 func (a *Action) newScript() (err error) {
 
 	if a.flow != nil {
@@ -98,7 +177,10 @@ func (a *Action) newScript() (err error) {
 	}
 
 	// bind device
-	a.ScriptEngine.PushStruct("Device", NewDeviceBind(a.Device, a.Node, a.mqtt, a.adaptors, a.zigbee2mqtt))
+	deviceBind := NewDeviceBind(a.Device, a.Node, a.mqtt, a.adaptors, a.zigbee2mqtt, a.modbus)
+	a.device = deviceBind.device
+	a.device.priority = a.priority
+	a.ScriptEngine.PushStruct("Device", deviceBind)
 
 	// bind action
 	a.ScriptEngine.PushStruct("Action", NewActionBind(a.deviceAction.Id, a.deviceAction.Name, a.deviceAction.Description, a))
@@ -24,10 +24,13 @@ import (
 	"github.com/e154/smart-home/adaptors"
 	"github.com/e154/smart-home/common"
 	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
 	cr "github.com/e154/smart-home/system/cron"
 	"github.com/e154/smart-home/system/graceful_service"
 	"github.com/e154/smart-home/system/metrics"
+	"github.com/e154/smart-home/system/modbus"
 	"github.com/e154/smart-home/system/mqtt"
+	"github.com/e154/smart-home/system/notify"
 	"github.com/e154/smart-home/system/scripts"
 	"github.com/e154/smart-home/system/stream"
 	"github.com/e154/smart-home/system/zigbee2mqtt"
@@ -49,10 +52,18 @@ type Core struct {
 	mqtt          *mqtt.Mqtt
 	streamService *stream.StreamService
 	Map           *Map
+	DeviceStates  *DeviceStates
+	Storage       *PersistentStorage
+	ModbusPolling *ModbusPolling
+	NodeMonitor   *NodeMonitor
+	ScenarioRules *ScenarioRules
+	Events        *EventBus
 	isRunning     bool
 	stopLock      sync.Mutex
 	zigbee2mqtt   *zigbee2mqtt.Zigbee2mqtt
 	metric        *metrics.MetricManager
+	modbus        *modbus.Modbus
+	nodeQueueConf *NodeQueueConfig
 }
 
 // NewCore ...
@@ -63,7 +74,17 @@ func NewCore(adaptors *adaptors.Adaptors,
 	mqtt *mqtt.Mqtt,
 	streamService *stream.StreamService,
 	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt,
-	metric *metrics.MetricManager) (core *Core, err error) {
+	metric *metrics.MetricManager,
+	cfg *config.AppConfig,
+	modbus *modbus.Modbus,
+	notify *notify.Notify) (core *Core, err error) {
+
+	events := NewEventBus()
+	deviceStates := NewDeviceStates(adaptors, mqtt, streamService)
+	deviceStates.Subscribe(func(change DeviceStateChange) {
+		events.Publish(DeviceStateChanged{change})
+	})
+	storage := NewPersistentStorage(adaptors, cfg.StoragePersistent)
 
 	core = &Core{
 		nodes:         make(map[int64]*Node),
@@ -73,14 +94,63 @@ func NewCore(adaptors *adaptors.Adaptors,
 		cron:          cron,
 		mqtt:          mqtt,
 		streamService: streamService,
-		Map:           NewMap(metric, adaptors),
+		Map:           NewMap(metric, adaptors, deviceStates, events),
+		DeviceStates:  deviceStates,
+		Storage:       storage,
+		ModbusPolling: NewModbusPolling(adaptors, modbus, mqtt, storage, deviceStates),
 		zigbee2mqtt:   zigbee2mqtt,
 		metric:        metric,
+		modbus:        modbus,
+		nodeQueueConf: NewNodeQueueConfig(cfg),
+		Events:        events,
 	}
+	core.NodeMonitor = NewNodeMonitor(adaptors, notify, core.safeGetNodes)
+	core.ScenarioRules = NewScenarioRules(adaptors, deviceStates, core.safeGetWorkflow)
 
 	graceful.Subscribe(core)
 
+	// remove the expired storage keys
+	if _, err = cron.NewTask("@hourly", func() {
+		if err := core.Storage.DeleteExpired(); err != nil {
+			log.Error(err.Error())
+		}
+	}); err != nil {
+		return
+	}
+
+	// keep the last runs in the history of each flow
+	if _, err = cron.NewTask("@every 10m", func() {
+		if err := adaptors.FlowRun.DeleteOld(flowRunHistoryLimit); err != nil {
+			log.Error(err.Error())
+		}
+	}); err != nil {
+		return
+	}
+
+	// the generated states of the zigbee2mqtt devices
+	zigbeeStates := newZigbee2mqttStates(adaptors, deviceStates)
+	zigbee2mqtt.SubscribeDeviceState("core.device_states", zigbeeStates.onState)
+
+	// the metrics of the map elements are updated by the events
+	events.Subscribe(EventFilter{Types: []string{EventMapElementStateChanged}}, func(event Event) {
+		e := event.(MapElementStateChanged)
+		metric.Update(metrics.MapElementSetState{
+			DeviceId:    e.DeviceId,
+			ElementName: e.ElementName,
+			StateId:     e.StateId,
+		})
+	})
+
+	scripts.SetEvents(scriptEvents{bus: events})
 	scripts.PushStruct("Map", &MapBind{Map: core.Map})
+	scripts.PushStruct("DeviceStates", &DeviceStatesBind{deviceStates: deviceStates})
+
+	// the bindings of the action and the flow, replaced by the mocks in the dry run
+	scripts.PushMock("Device", &DeviceBind{})
+	scripts.PushMock("message", &Message{})
+	scripts.PushMock("Action", &ActionBind{})
+	scripts.PushMock("Flow", &FlowBind{})
+	scripts.PushMock("Workflow", &WorkflowBind{})
 
 	return
 }
@@ -96,14 +166,32 @@ func (c *Core) Run() (err error) {
 	c.isRunning = true
 	c.Unlock()
 
+	if err = c.DeviceStates.Load(); err != nil {
+		return
+	}
+
+	c.Storage.Reset()
+
+	if err = c.ModbusPolling.Load(); err != nil {
+		return
+	}
+
 	if err = c.initNodes(); err != nil {
 		return
 	}
 
+	if err = c.NodeMonitor.Load(); err != nil {
+		return
+	}
+
 	if err = c.InitWorkflows(); err != nil {
 		return
 	}
 
+	if err = c.ScenarioRules.Load(); err != nil {
+		return
+	}
+
 	c.updateMetrics()
 
 	return
@@ -125,6 +213,10 @@ func (b *Core) Stop() (err error) {
 	b.streamService.UnSubscribe("do.worker")
 	b.streamService.UnSubscribe("do.action")
 
+	b.ModbusPolling.Stop()
+	b.NodeMonitor.Stop()
+	b.ScenarioRules.Stop()
+
 	for _, workflow := range b.workflows {
 		if err = b.DeleteWorkflow(workflow.model); err != nil {
 			return
@@ -177,7 +269,8 @@ func (c *Core) AddNode(node *m.Node) (n *Node, err error) {
 
 	log.Infof("Add node: \"%s\"", node.Name)
 
-	n = NewNode(node, c.mqtt, c.metric)
+	n = NewNode(node, c.mqtt, c.metric, c.nodeQueueConf)
+	n.SetEvents(c.Events)
 	c.safeUpdateNodeMap(node.Id, n.Connect())
 
 	go c.metric.Update(metrics.NodeAdd{Num: 1})
@@ -368,6 +461,21 @@ func (c *Core) UpdateWorkflowScenario(workflowId int64) (err error) {
 	return
 }
 
+// SetWorkflowScenario switch the scenario of the running workflow,
+// the switch is recorded to the history with the trigger
+func (c *Core) SetWorkflowScenario(workflowId int64, systemName string, trigger m.ScenarioTrigger, initiator string) (err error) {
+
+	wf, ok := c.safeGetWorkflow(workflowId)
+	if !ok {
+		err = errors.New("not found")
+		return
+	}
+
+	err = wf.setScenario(systemName, trigger, initiator)
+
+	return
+}
+
 // UpdateWorkflow ...
 func (c *Core) UpdateWorkflow(workflow *m.Workflow) (err error) {
 
@@ -387,6 +495,42 @@ func (c *Core) UpdateWorkflow(workflow *m.Workflow) (err error) {
 	return
 }
 
+// UpdateModule restart the workflows with the scripts that require the updated module,
+// the scripts of the workflow run once at the start and keep the exports of the module.
+// The flows, the actions and the scenario scripts run the whole script every time,
+// they reload the module on the next run
+func (c *Core) UpdateModule(dependents []string) {
+
+	if len(dependents) == 0 {
+		return
+	}
+
+	names := make(map[string]bool, len(dependents))
+	for _, name := range dependents {
+		names[name] = true
+	}
+
+	c.Lock()
+	workflows := make([]*Workflow, 0, len(c.workflows))
+	for _, wf := range c.workflows {
+		workflows = append(workflows, wf)
+	}
+	c.Unlock()
+
+	for _, wf := range workflows {
+		for _, script := range wf.model.Scripts {
+			if !names[script.Name] {
+				continue
+			}
+			log.Infof("script %s requires the updated module, restart workflow '%s'", script.Name, wf.model.Name)
+			if err := c.UpdateWorkflow(wf.model); err != nil {
+				log.Error(err.Error())
+			}
+			break
+		}
+	}
+}
+
 // AddFlow ...
 func (c *Core) AddFlow(flow *m.Flow) (err error) {
 
@@ -564,6 +708,17 @@ func (c *Core) safeGetNode(k int64) (w *Node, ok bool) {
 	return
 }
 
+func (c *Core) safeGetNodes() (nodes []*Node) {
+	c.Lock()
+	defer c.Unlock()
+
+	nodes = make([]*Node, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		nodes = append(nodes, node)
+	}
+	return
+}
+
 func (c *Core) safeGetOrAddNode(k int64) (w *Node, ok bool) {
 	c.Lock()
 	defer c.Unlock()
@@ -573,7 +728,8 @@ func (c *Core) safeGetOrAddNode(k int64) (w *Node, ok bool) {
 		if node, err := c.adaptors.Node.GetById(k); err == nil {
 			ok = true
 
-			w = NewNode(node, c.mqtt, c.metric)
+			w = NewNode(node, c.mqtt, c.metric, c.nodeQueueConf)
+			w.SetEvents(c.Events)
 			go c.safeUpdateNodeMap(node.Id, w.Connect())
 
 		} else {
@@ -635,19 +791,28 @@ func (c *Core) DoAction(deviceActionId int64) (result string, err error) {
 	}
 
 	// node
-	var node *Node
+	var node, backupNode *Node
 	if device.Node != nil {
 		node = c.GetNodeById(device.Node.Id)
 	}
+	if device.BackupNode != nil {
+		backupNode = c.GetNodeById(device.BackupNode.Id)
+	}
 
 	// action
 	var action *Action
-	if action, err = NewAction(device, deviceAction, node, nil, c.scripts, c.mqtt, c.adaptors, c.zigbee2mqtt); err != nil {
+	if action, err = NewAction(device, deviceAction, node, backupNode, nil, c.scripts, c.mqtt, c.adaptors, c.zigbee2mqtt, c.modbus, c.Events); err != nil {
 		return
 	}
+	defer action.Close()
 
 	// do action
 	result, err = action.Do()
 
 	return
 }
+
+// ParseTime parse worker time string with the configured coordinates
+func (c *Core) ParseTime(spec string) (*cr.Schedule, error) {
+	return c.cron.Parse(spec)
+}
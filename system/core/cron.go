@@ -19,12 +19,14 @@
 package core
 
 import (
+	"github.com/e154/smart-home/system/config"
 	cr "github.com/e154/smart-home/system/cron"
 )
 
 // NewCron ...
-func NewCron() (cron *cr.Cron) {
+func NewCron(cfg *config.AppConfig) (cron *cr.Cron) {
 	cron = cr.NewCron()
+	cron.SetCoordinates(cfg.Lat, cfg.Lon)
 	cron.Run()
 	return
 }
@@ -0,0 +1,76 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+//ActionPrototypes
+type Debounce struct {
+	sync.Mutex
+	counter int64
+}
+
+// After ...
+func (m *Debounce) After(flow *Flow) (err error) {
+	return
+}
+
+// Run ...
+func (m *Debounce) Run(flow *Flow) (err error) {
+	return
+}
+
+// Before ...
+func (m *Debounce) Before(flow *Flow) (err error) {
+	return
+}
+
+// Type ...
+func (m *Debounce) Type() string {
+	return "Debounce"
+}
+
+// Wait the message goes further if no other message came
+// during the settings.interval seconds, the previous runs are stopped
+func (m *Debounce) Wait(ctx context.Context, element *FlowElement, run *flowRun) (ok bool, err error) {
+
+	m.Lock()
+	m.counter++
+	current := m.counter
+	m.Unlock()
+
+	run.suspend()
+
+	if err = sleep(ctx, element.Model.Settings.Interval); err != nil {
+		return
+	}
+
+	m.Lock()
+	ok = current == m.counter
+	m.Unlock()
+
+	if !ok {
+		err = errFlowRunStopped
+	}
+
+	return
+}
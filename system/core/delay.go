@@ -0,0 +1,76 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"time"
+)
+
+//ActionPrototypes
+type Delay struct{}
+
+// After ...
+func (m *Delay) After(flow *Flow) (err error) {
+	return
+}
+
+// Run ...
+func (m *Delay) Run(flow *Flow) (err error) {
+	return
+}
+
+// Before ...
+func (m *Delay) Before(flow *Flow) (err error) {
+	return
+}
+
+// Type ...
+func (m *Delay) Type() string {
+	return "Delay"
+}
+
+// Wait pause the run for the settings.delay seconds
+func (m *Delay) Wait(ctx context.Context, element *FlowElement, run *flowRun) (ok bool, err error) {
+
+	run.suspend()
+
+	if err = sleep(ctx, element.Model.Settings.Delay); err != nil {
+		return
+	}
+
+	ok = true
+
+	return
+}
+
+// sleep wait for the number of seconds or the context cancellation
+func sleep(ctx context.Context, seconds int) (err error) {
+
+	timer := time.NewTimer(time.Duration(seconds) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
@@ -24,8 +24,11 @@ import (
 	"github.com/e154/smart-home/adaptors"
 	m "github.com/e154/smart-home/models"
 	. "github.com/e154/smart-home/models/devices"
+	"github.com/e154/smart-home/system/modbus"
 	"github.com/e154/smart-home/system/mqtt"
 	"github.com/e154/smart-home/system/zigbee2mqtt"
+	"sync"
+	"time"
 )
 
 // Device ...
@@ -35,20 +38,30 @@ type Device struct {
 	mqtt        *mqtt.Mqtt
 	adaptors    *adaptors.Adaptors
 	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt
+	modbus      *modbus.Modbus
+	responses   *nodeResponses
+	priority    NodeCommandPriority
 }
 
 // NewDevice ...
 func NewDevice(dev *m.Device, node *Node, mqtt *mqtt.Mqtt,
-	adaptors *adaptors.Adaptors, zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) *Device {
+	adaptors *adaptors.Adaptors, zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt, modbus *modbus.Modbus) *Device {
 	return &Device{
 		dev:         dev,
 		node:        node,
 		mqtt:        mqtt,
 		adaptors:    adaptors,
 		zigbee2mqtt: zigbee2mqtt,
+		modbus:      modbus,
+		responses:   &nodeResponses{},
 	}
 }
 
+// Responses the node responses received since the last call
+func (d Device) Responses() []NodeResponse {
+	return d.responses.take()
+}
+
 // run command
 func (d Device) RunCommand(name string, args []string) (result DevCommandResponse) {
 
@@ -69,7 +82,10 @@ func (d Device) RunCommand(name string, args []string) (result DevCommandRespons
 		return
 	}
 
-	nodeResult, err := d.node.Send(d.dev, data)
+	nodeResult, err := d.node.Send(d.dev, d.priority, data)
+	if err == nil {
+		d.responses.add(nodeResult)
+	}
 	if err != nil {
 		result.Error = err.Error()
 		return
@@ -111,7 +127,10 @@ func (d Device) SmartBus(command []byte) (result DevSmartBusResponse) {
 		return
 	}
 
-	nodeResult, err := d.node.Send(d.dev, data)
+	nodeResult, err := d.node.Send(d.dev, d.priority, data)
+	if err == nil {
+		d.responses.add(nodeResult)
+	}
 
 	if err = json.Unmarshal(nodeResult.Response, &result); err != nil {
 		result.Error = err.Error()
@@ -131,6 +150,12 @@ func (d Device) ModBus(f string, address, count uint16, command []uint16) (resul
 
 	result = DevModBusResponse{}
 
+	// modbus tcp device without the node is served by the server itself
+	if d.dev.Type == DevTypeModbusTcp && d.dev.Node == nil {
+		result = d.modBusTcp(f, address, count, command)
+		return
+	}
+
 	if d.node == nil {
 		result.Error = "node is nil"
 		return
@@ -155,7 +180,10 @@ func (d Device) ModBus(f string, address, count uint16, command []uint16) (resul
 		return
 	}
 
-	nodeResult, err := d.node.Send(d.dev, data)
+	nodeResult, err := d.node.Send(d.dev, d.priority, data)
+	if err == nil {
+		d.responses.add(nodeResult)
+	}
 	if err != nil {
 		result.Error = err.Error()
 		//log.Error(err.Error())
@@ -176,6 +204,33 @@ func (d Device) ModBus(f string, address, count uint16, command []uint16) (resul
 	return
 }
 
+// modBusTcp send the request directly to the address of the device
+func (d Device) modBusTcp(f string, address, count uint16, command []uint16) (result DevModBusResponse) {
+
+	if d.modbus == nil {
+		result.Error = "modbus is nil"
+		return
+	}
+
+	params := &DevModBusTcpConfig{}
+	b, _ := d.dev.Properties.MarshalJSON()
+	if err := json.Unmarshal(b, params); err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	startTime := time.Now()
+
+	var err error
+	if result.Result, err = d.modbus.Do(params.AddressPort, params.PoolSize, modbusRequest(params, f, address, count, command)); err != nil {
+		result.Error = err.Error()
+	}
+
+	result.Time = time.Since(startTime).Seconds()
+
+	return
+}
+
 // Zigbee2mqtt ...
 func (d Device) Zigbee2mqtt(path string, payload []byte) (result DevZigbee2mqttResponse) {
 
@@ -251,3 +306,22 @@ func (d Device) Mqtt(path string, payload []byte) (result DevMqttResponse) {
 
 	return
 }
+
+type nodeResponses struct {
+	sync.Mutex
+	list []NodeResponse
+}
+
+func (r *nodeResponses) add(resp NodeResponse) {
+	r.Lock()
+	r.list = append(r.list, resp)
+	r.Unlock()
+}
+
+func (r *nodeResponses) take() (list []NodeResponse) {
+	r.Lock()
+	list = r.list
+	r.list = nil
+	r.Unlock()
+	return
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/e154/smart-home/adaptors"
 	m "github.com/e154/smart-home/models"
 	. "github.com/e154/smart-home/models/devices"
+	"github.com/e154/smart-home/system/modbus"
 	"github.com/e154/smart-home/system/mqtt"
 	"github.com/e154/smart-home/system/zigbee2mqtt"
 )
@@ -49,12 +50,12 @@ type DeviceBind struct {
 }
 
 // NewDeviceBind ...
-func NewDeviceBind(model *m.Device, node *Node, mqtt *mqtt.Mqtt, adaptors *adaptors.Adaptors, zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) *DeviceBind {
+func NewDeviceBind(model *m.Device, node *Node, mqtt *mqtt.Mqtt, adaptors *adaptors.Adaptors, zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt, modbus *modbus.Modbus) *DeviceBind {
 	return &DeviceBind{
 		model: model,
 		node:  node,
 		mqtt:  mqtt, adaptors: adaptors,
-		device: NewDevice(model, node, mqtt, adaptors, zigbee2mqtt),
+		device: NewDevice(model, node, mqtt, adaptors, zigbee2mqtt, modbus),
 	}
 }
 
@@ -0,0 +1,145 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"sync"
+)
+
+// GroupMembers the enabled member devices of the group, the members take the type and the nodes of the group
+func GroupMembers(group *m.Device) (devices []*m.Device) {
+
+	for _, child := range group.Devices {
+		if child.Status != "enabled" {
+			continue
+		}
+
+		device := &m.Device{
+			Id:         child.Id,
+			Name:       child.Name,
+			Properties: child.Properties,
+			Type:       group.Type,
+			Node:       group.Node,
+			BackupNode: group.BackupNode,
+			Device:     &m.Device{Id: group.Id},
+		}
+
+		devices = append(devices, device)
+	}
+
+	return
+}
+
+// GroupMemberResult ...
+type GroupMemberResult struct {
+	DeviceId  int64          `json:"device_id"`
+	Result    string         `json:"result"`
+	Error     string         `json:"error,omitempty"`
+	Responses []NodeResponse `json:"responses"`
+}
+
+// GroupResult result of the group action, one item per member device
+type GroupResult struct {
+	DeviceId int64                  `json:"device_id"`
+	Mode     common.DeviceGroupMode `json:"mode"`
+	Members  []*GroupMemberResult   `json:"members"`
+	Failed   int                    `json:"failed"`
+}
+
+// GroupError some of the members failed, the result of all members is in the Result
+type GroupError struct {
+	Result *GroupResult
+}
+
+// Error ...
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("group %d: %d of %d devices failed", e.Result.DeviceId, e.Result.Failed, len(e.Result.Members))
+}
+
+// doGroup run the action on the member devices and aggregate the results,
+// res is the json of the GroupResult
+func (a *Action) doGroup() (res string, err error) {
+
+	a.doLock.Lock()
+	defer a.doLock.Unlock()
+
+	result := &GroupResult{
+		DeviceId: a.Device.Id,
+		Mode:     a.Device.GroupMode,
+		Members:  make([]*GroupMemberResult, len(a.members)),
+	}
+
+	if result.Mode == "" {
+		result.Mode = common.DeviceGroupSequence
+	}
+
+	do := func(i int) {
+		member := a.members[i]
+		memberResult := &GroupMemberResult{
+			DeviceId: member.Device.Id,
+		}
+		var memberErr error
+		if memberResult.Result, memberErr = member.Do(); memberErr != nil {
+			memberResult.Error = memberErr.Error()
+		}
+		if member.device != nil {
+			memberResult.Responses = member.device.Responses()
+		}
+		result.Members[i] = memberResult
+	}
+
+	switch result.Mode {
+	case common.DeviceGroupParallel:
+		wg := &sync.WaitGroup{}
+		wg.Add(len(a.members))
+		for i := range a.members {
+			go func(i int) {
+				do(i)
+				wg.Done()
+			}(i)
+		}
+		wg.Wait()
+	default:
+		for i := range a.members {
+			do(i)
+		}
+	}
+
+	for _, memberResult := range result.Members {
+		if memberResult.Error != "" {
+			result.Failed++
+		}
+	}
+
+	var data []byte
+	if data, err = json.Marshal(result); err != nil {
+		return
+	}
+	res = string(data)
+
+	if result.Failed > 0 {
+		err = &GroupError{Result: result}
+	}
+
+	return
+}
@@ -0,0 +1,321 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/mqtt"
+	"github.com/e154/smart-home/system/stream"
+	"sync"
+	"time"
+)
+
+// DeviceStateChange ...
+type DeviceStateChange struct {
+	DeviceId  int64     `json:"device_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	StateId   int64     `json:"state_id"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// DeviceStateHandler ...
+type DeviceStateHandler func(change DeviceStateChange)
+
+// DeviceStates current state of the devices, every change is persisted and published
+// to the "home/device/{id}/state" mqtt topic, so the flows can subscribe to it
+type DeviceStates struct {
+	sync.Mutex
+	adaptors      *adaptors.Adaptors
+	mqtt          *mqtt.Mqtt
+	streamService *stream.StreamService
+	states        map[int64]*m.DeviceCurrentState
+	handlerId     int64
+	handlers      map[int64]DeviceStateHandler
+}
+
+// NewDeviceStates ...
+func NewDeviceStates(adaptors *adaptors.Adaptors,
+	mqtt *mqtt.Mqtt,
+	streamService *stream.StreamService) *DeviceStates {
+	return &DeviceStates{
+		adaptors:      adaptors,
+		mqtt:          mqtt,
+		streamService: streamService,
+		states:        make(map[int64]*m.DeviceCurrentState),
+		handlers:      make(map[int64]DeviceStateHandler),
+	}
+}
+
+// Load restore the states saved before the restart
+func (d *DeviceStates) Load() (err error) {
+
+	var list []*m.DeviceCurrentState
+	if list, err = d.adaptors.DeviceCurrentState.List(); err != nil {
+		return
+	}
+
+	d.Lock()
+	d.states = make(map[int64]*m.DeviceCurrentState)
+	for _, state := range list {
+		d.states[state.DeviceId] = state
+	}
+	d.Unlock()
+
+	log.Infof("restored %d device states", len(list))
+
+	return
+}
+
+// Reload read the device state again after the device or its states were changed
+func (d *DeviceStates) Reload(deviceId int64) {
+
+	state, err := d.adaptors.DeviceCurrentState.GetByDeviceId(deviceId)
+
+	d.Lock()
+	if err != nil {
+		delete(d.states, deviceId)
+	} else {
+		d.states[deviceId] = state
+	}
+	d.Unlock()
+}
+
+// Get ...
+func (d *DeviceStates) Get(deviceId int64) (state *m.DeviceCurrentState, err error) {
+
+	d.Lock()
+	current, ok := d.states[deviceId]
+	d.Unlock()
+
+	if ok {
+		state = &m.DeviceCurrentState{}
+		*state = *current
+		return
+	}
+
+	state, err = d.adaptors.DeviceCurrentState.GetByDeviceId(deviceId)
+
+	return
+}
+
+// Set change the device state, the transition must be allowed by the current state
+func (d *DeviceStates) Set(deviceId int64, systemName string) (err error) {
+
+	var device *m.Device
+	if device, err = d.adaptors.Device.GetById(deviceId); err != nil {
+		return
+	}
+
+	// the group members without own states take the states of the group
+	states := device.States
+	var group *m.Device
+	if device.Device != nil && device.Device.IsGroup {
+		group = device.Device
+		if len(states) == 0 {
+			states = group.States
+		}
+	}
+
+	var next *m.DeviceState
+	for _, state := range states {
+		if state.SystemName == systemName {
+			next = state
+			break
+		}
+	}
+
+	if next == nil {
+		err = fmt.Errorf("device %d has no state '%s'", deviceId, systemName)
+		return
+	}
+
+	d.Lock()
+
+	var from string
+	if current, ok := d.states[deviceId]; ok && current.DeviceState != nil {
+		if current.DeviceStateId == next.Id {
+			d.Unlock()
+			return
+		}
+		from = current.DeviceState.SystemName
+		if !current.DeviceState.CanTransitTo(systemName) {
+			d.Unlock()
+			err = fmt.Errorf("device %d: transition from '%s' to '%s' is not allowed", deviceId, from, systemName)
+			return
+		}
+	}
+
+	state := &m.DeviceCurrentState{
+		DeviceId:      deviceId,
+		DeviceState:   next,
+		DeviceStateId: next.Id,
+		ChangedAt:     time.Now(),
+	}
+
+	if err = d.adaptors.DeviceCurrentState.Set(state); err != nil {
+		d.Unlock()
+		return
+	}
+
+	d.states[deviceId] = state
+
+	handlers := make([]DeviceStateHandler, 0, len(d.handlers))
+	for _, handler := range d.handlers {
+		handlers = append(handlers, handler)
+	}
+
+	d.Unlock()
+
+	change := DeviceStateChange{
+		DeviceId:  deviceId,
+		From:      from,
+		To:        systemName,
+		StateId:   next.Id,
+		ChangedAt: state.ChangedAt,
+	}
+
+	d.publish(change)
+
+	for _, handler := range handlers {
+		handler(change)
+	}
+
+	if group != nil {
+		d.updateGroup(group.Id)
+	}
+
+	return
+}
+
+// GroupState derive the group state from the current states of the members,
+// State is empty if the members are in different states
+func (d *DeviceStates) GroupState(group *m.Device) (state *m.DeviceGroupState) {
+
+	state = &m.DeviceGroupState{
+		DeviceId: group.Id,
+		Counts:   make(map[string]int),
+		Members:  make(map[int64]string),
+	}
+
+	for _, member := range GroupMembers(group) {
+		var systemName string
+		if current, err := d.Get(member.Id); err == nil && current.DeviceState != nil {
+			systemName = current.DeviceState.SystemName
+		}
+		state.Members[member.Id] = systemName
+		state.Counts[systemName]++
+	}
+
+	if len(state.Counts) > 1 {
+		state.Mixed = true
+		return
+	}
+
+	for systemName := range state.Counts {
+		state.State = systemName
+	}
+
+	return
+}
+
+// updateGroup the group takes the state when all members are in it
+func (d *DeviceStates) updateGroup(groupId int64) {
+
+	group, err := d.adaptors.Device.GetById(groupId)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	state := d.GroupState(group)
+	if state.Mixed || state.State == "" {
+		return
+	}
+
+	for _, groupState := range group.States {
+		if groupState.SystemName != state.State {
+			continue
+		}
+		if err = d.Set(group.Id, state.State); err != nil {
+			log.Warn(err.Error())
+		}
+		return
+	}
+}
+
+// Subscribe call the handler on every state change
+func (d *DeviceStates) Subscribe(handler DeviceStateHandler) (id int64) {
+	d.Lock()
+	d.handlerId++
+	id = d.handlerId
+	d.handlers[id] = handler
+	d.Unlock()
+	return
+}
+
+// Unsubscribe ...
+func (d *DeviceStates) Unsubscribe(id int64) {
+	d.Lock()
+	delete(d.handlers, id)
+	d.Unlock()
+}
+
+func (d *DeviceStates) publish(change DeviceStateChange) {
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+
+	if d.mqtt != nil {
+		if err = d.mqtt.Publish(DeviceStateTopic(change.DeviceId), data, 0, false); err != nil {
+			log.Warn(err.Error())
+		}
+	}
+
+	if d.streamService == nil {
+		return
+	}
+
+	msg := stream.Message{
+		Command: "device.state",
+		Type:    stream.Broadcast,
+		Forward: stream.Request,
+		Payload: map[string]interface{}{
+			"change": change,
+		},
+	}
+
+	if data, err = json.Marshal(msg); err != nil {
+		log.Warn(err.Error())
+		return
+	}
+
+	d.streamService.Broadcast(data)
+}
+
+// DeviceStateTopic ...
+func DeviceStateTopic(deviceId int64) string {
+	return fmt.Sprintf("home/device/%d/state", deviceId)
+}
@@ -0,0 +1,48 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+// Javascript Binding
+//
+// DeviceStates
+//	.Get(deviceId) -> {device_id, device_state, changed_at}
+//	.Set(deviceId, systemName) -> error
+//
+type DeviceStatesBind struct {
+	deviceStates *DeviceStates
+}
+
+// Get ...
+func (d *DeviceStatesBind) Get(deviceId int64) interface{} {
+
+	state, err := d.deviceStates.Get(deviceId)
+	if err != nil {
+		return nil
+	}
+
+	return state
+}
+
+// Set ...
+func (d *DeviceStatesBind) Set(deviceId int64, systemName string) (err error) {
+	if err = d.deviceStates.Set(deviceId, systemName); err != nil {
+		log.Warn(err.Error())
+	}
+	return
+}
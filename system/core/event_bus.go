@@ -0,0 +1,330 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/uuid"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// the events of the core, the type is the name of the event struct
+const (
+	// EventDeviceStateChanged ...
+	EventDeviceStateChanged = "DeviceStateChanged"
+	// EventActionExecuted ...
+	EventActionExecuted = "ActionExecuted"
+	// EventNodeConnected ...
+	EventNodeConnected = "NodeConnected"
+	// EventNodeDisconnected ...
+	EventNodeDisconnected = "NodeDisconnected"
+	// EventFlowRunFinished ...
+	EventFlowRunFinished = "FlowRunFinished"
+	// EventScenarioChanged ...
+	EventScenarioChanged = "ScenarioChanged"
+	// EventMapElementStateChanged ...
+	EventMapElementStateChanged = "MapElementStateChanged"
+)
+
+// EventTypes ...
+var EventTypes = []string{
+	EventDeviceStateChanged,
+	EventActionExecuted,
+	EventNodeConnected,
+	EventNodeDisconnected,
+	EventFlowRunFinished,
+	EventScenarioChanged,
+	EventMapElementStateChanged,
+}
+
+// how many events wait for the slow subscriber, the next ones are dropped
+const eventQueueSize = 100
+
+// Event ...
+type Event interface {
+	EventType() string
+}
+
+// DeviceStateChanged ...
+type DeviceStateChanged struct {
+	DeviceStateChange
+}
+
+// EventType ...
+func (e DeviceStateChanged) EventType() string { return EventDeviceStateChanged }
+
+// ActionExecuted the device action was done by the user or by the worker of the flow
+type ActionExecuted struct {
+	DeviceId   int64     `json:"device_id"`
+	ActionId   int64     `json:"action_id"`
+	Name       string    `json:"name"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error"`
+	ExecutedAt time.Time `json:"executed_at"`
+}
+
+// EventType ...
+func (e ActionExecuted) EventType() string { return EventActionExecuted }
+
+// NodeConnected ...
+type NodeConnected struct {
+	NodeId    int64     `json:"node_id"`
+	Name      string    `json:"name"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// EventType ...
+func (e NodeConnected) EventType() string { return EventNodeConnected }
+
+// NodeDisconnected the status is "wait" while the node is enabled, "disabled" otherwise
+type NodeDisconnected struct {
+	NodeId    int64     `json:"node_id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// EventType ...
+func (e NodeDisconnected) EventType() string { return EventNodeDisconnected }
+
+// FlowRunFinished ...
+type FlowRunFinished struct {
+	FlowId     int64         `json:"flow_id"`
+	WorkflowId int64         `json:"workflow_id"`
+	RunId      uuid.UUID     `json:"run_id"`
+	Status     FlowRunStatus `json:"status"`
+	Error      string        `json:"error"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+}
+
+// EventType ...
+func (e FlowRunFinished) EventType() string { return EventFlowRunFinished }
+
+// ScenarioChanged ...
+type ScenarioChanged struct {
+	WorkflowId int64             `json:"workflow_id"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Trigger    m.ScenarioTrigger `json:"trigger"`
+	Initiator  string            `json:"initiator"`
+	ChangedAt  time.Time         `json:"changed_at"`
+}
+
+// EventType ...
+func (e ScenarioChanged) EventType() string { return EventScenarioChanged }
+
+// MapElementStateChanged ...
+type MapElementStateChanged struct {
+	ElementName string    `json:"element_name"`
+	DeviceId    int64     `json:"device_id"`
+	StateId     int64     `json:"state_id"`
+	State       string    `json:"state"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// EventType ...
+func (e MapElementStateChanged) EventType() string { return EventMapElementStateChanged }
+
+// EventFields the json fields of the event with its type
+func EventFields(event Event) (fields map[string]interface{}) {
+	fields = make(map[string]interface{})
+	if data, err := json.Marshal(event); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	fields["type"] = event.EventType()
+	return
+}
+
+// EventFilter the empty types match all events, the field matches the value or one of the list of values
+type EventFilter struct {
+	Types  []string               `json:"types"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Match the fields of the event, see EventFields
+func (f EventFilter) Match(fields map[string]interface{}) bool {
+
+	if len(f.Types) > 0 {
+		var ok bool
+		for _, t := range f.Types {
+			if t == fields["type"] {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(f.Fields) == 0 {
+		return true
+	}
+
+	for name, value := range f.Fields {
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		var match bool
+		for _, v := range values {
+			if reflect.DeepEqual(v, fields[name]) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalize the values are compared as json, so 1 and 1.0 are equal
+func (f EventFilter) normalize() EventFilter {
+	if len(f.Fields) == 0 {
+		return f
+	}
+	fields := make(map[string]interface{})
+	if data, err := json.Marshal(f.Fields); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	f.Fields = fields
+	return f
+}
+
+// EventHandler ...
+type EventHandler func(event Event)
+
+// eventSubscriber the events are delivered in the order of the publication
+// by the own goroutine, so the slow handler does not hold the publisher
+type eventSubscriber struct {
+	filter  EventFilter
+	handler EventHandler
+	queue   chan Event
+	quit    chan struct{}
+}
+
+func (s *eventSubscriber) run() {
+	for {
+		select {
+		case event := <-s.queue:
+			s.handler(event)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// EventBus the typed events of the core, the flows, scripts, metrics and websocket clients subscribe
+// to them instead of polling
+type EventBus struct {
+	sync.Mutex
+	handlerId   int64
+	subscribers map[int64]*eventSubscriber
+}
+
+// NewEventBus ...
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[int64]*eventSubscriber),
+	}
+}
+
+// Subscribe call the handler on every event that matches the filter
+func (b *EventBus) Subscribe(filter EventFilter, handler EventHandler) (id int64) {
+
+	subscriber := &eventSubscriber{
+		filter:  filter.normalize(),
+		handler: handler,
+		queue:   make(chan Event, eventQueueSize),
+		quit:    make(chan struct{}),
+	}
+
+	b.Lock()
+	b.handlerId++
+	id = b.handlerId
+	b.subscribers[id] = subscriber
+	b.Unlock()
+
+	go subscriber.run()
+
+	return
+}
+
+// Unsubscribe ...
+func (b *EventBus) Unsubscribe(id int64) {
+	b.Lock()
+	if subscriber, ok := b.subscribers[id]; ok {
+		close(subscriber.quit)
+		delete(b.subscribers, id)
+	}
+	b.Unlock()
+}
+
+// Publish the event to the matching subscribers, does not wait for the handlers
+func (b *EventBus) Publish(event Event) {
+
+	if b == nil {
+		return
+	}
+
+	fields := EventFields(event)
+
+	b.Lock()
+	defer b.Unlock()
+
+	for id, subscriber := range b.subscribers {
+		if !subscriber.filter.Match(fields) {
+			continue
+		}
+		select {
+		case subscriber.queue <- event:
+		default:
+			log.Warnf("event %s dropped, the queue of the subscriber %d is full", event.EventType(), id)
+		}
+	}
+}
+
+// scriptEvents the event bus for the scripts, see scripts.EventSource
+type scriptEvents struct {
+	bus *EventBus
+}
+
+// On ...
+func (s scriptEvents) On(eventType string, fields map[string]interface{}, handler func(event map[string]interface{})) int64 {
+	filter := EventFilter{Fields: fields}
+	if eventType != "" && eventType != "*" {
+		filter.Types = []string{eventType}
+	}
+	return s.bus.Subscribe(filter, func(event Event) {
+		handler(EventFields(event))
+	})
+}
+
+// Off ...
+func (s scriptEvents) Off(id int64) {
+	s.bus.Unsubscribe(id)
+}
@@ -20,6 +20,7 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/e154/smart-home/adaptors"
@@ -28,12 +29,18 @@ import (
 	cr "github.com/e154/smart-home/system/cron"
 	"github.com/e154/smart-home/system/mqtt"
 	"github.com/e154/smart-home/system/scripts"
-	"github.com/e154/smart-home/system/uuid"
 	"github.com/e154/smart-home/system/zigbee2mqtt"
+	"go.uber.org/atomic"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
 
+// FlowEventTopicPrefix the flow subscription with the prefix takes the events of the core instead of the mqtt,
+// e.g. "events/DeviceStateChanged?device_id=1", the event is in the message vars "event_type" and "event"
+const FlowEventTopicPrefix = "events/"
+
 // Flow ...
 type Flow struct {
 	Storage
@@ -41,7 +48,6 @@ type Flow struct {
 	workflow         *Workflow
 	Connections      []*m.Connection
 	FlowElements     []*FlowElement
-	cursor           uuid.UUID
 	Node             *Node
 	adaptors         *adaptors.Adaptors
 	scriptService    *scripts.ScriptService
@@ -56,8 +62,13 @@ type Flow struct {
 	message          *Message
 	zigbee2mqtt      *zigbee2mqtt.Zigbee2mqtt
 	sync.Mutex
-	isRunning bool
-	Workers   map[int64]*Worker
+	queue      *FlowQueue
+	scriptLock sync.Mutex
+	Workers    map[int64]*Worker
+	ctx        context.Context
+	cancel     context.CancelFunc
+	suspended  atomic.Int32
+	eventIds   []int64
 }
 
 // NewFlow ...
@@ -71,7 +82,7 @@ func NewFlow(model *m.Flow,
 	zigbee2mqtt *zigbee2mqtt.Zigbee2mqtt) (flow *Flow, err error) {
 
 	flow = &Flow{
-		Storage:          NewStorage(),
+		Storage:          NewNamespaceStorage(FlowStorageNamespace(model.Id), core.Storage),
 		Model:            model,
 		workflow:         workflow,
 		adaptors:         adaptors,
@@ -84,8 +95,12 @@ func NewFlow(model *m.Flow,
 		message:          NewMessage(),
 		zigbee2mqtt:      zigbee2mqtt,
 		mqtt:             mqtt,
+		queue:            NewFlowQueue(model, workflow.metric),
 	}
 
+	// suspended runs live until the flow is removed
+	flow.ctx, flow.cancel = context.WithCancel(context.Background())
+
 	if flow.scriptEngine, err = flow.NewScript(); err != nil {
 		return
 	}
@@ -115,6 +130,10 @@ func NewFlow(model *m.Flow,
 	for _, subParams := range flow.Model.Subscriptions {
 
 		topic := fmt.Sprintf("%s", subParams.Topic)
+		if strings.HasPrefix(topic, FlowEventTopicPrefix) {
+			flow.subscribeEvents(topic)
+			continue
+		}
 		flow.mqttClient.Subscribe(topic, flow.mqttOnPublish)
 	}
 
@@ -136,6 +155,10 @@ func (f *Flow) Remove() {
 
 	log.Infof("Remove flow '%v'", f.Model.Name)
 
+	for _, id := range f.eventIds {
+		f.core.Events.Unsubscribe(id)
+	}
+
 	for _, worker := range f.Workers {
 		f.RemoveWorker(worker.Model)
 	}
@@ -146,16 +169,31 @@ func (f *Flow) Remove() {
 		f.mqttClient.UnsubscribeAll()
 	}
 
+	for _, element := range f.FlowElements {
+		element.Remove()
+	}
+
+	f.queue.Close()
+
+	if f.scriptEngine != nil {
+		f.scriptEngine.Close()
+	}
+
 	timeout := time.After(3 * time.Second)
 	for {
 		time.Sleep(time.Second * 1)
-		if !f.isRunning {
-			log.Infof("flow %v ... ok", f.Model.Id)
-			break
+		if f.queue.Running() == 0 {
+			// suspended runs are waiting for the time or the event
+			f.cancel()
+			if f.suspended.Load() == 0 {
+				log.Infof("flow %v ... ok", f.Model.Id)
+				break
+			}
 		}
 
 		select {
 		case <-timeout:
+			f.cancel()
 			return
 		default:
 
@@ -167,26 +205,25 @@ func (f *Flow) Remove() {
 // NewMessage ...
 func (f *Flow) NewMessage(ctx context.Context) (err error) {
 
-	f.Lock()
-	if f.isRunning {
-		err = errors.New("flow is running")
-		f.Unlock()
+	// circular dependency search
+	if ctx, err = f.defineCircularConnection(ctx); err != nil {
 		return
 	}
 
-	defer func() {
-		f.Lock()
-		f.isRunning = false
-		f.Unlock()
-	}()
-
-	// circular dependency search
-	if ctx, err = f.defineCircularConnection(ctx); err != nil {
+	// wait for a free slot
+	if err = f.queue.Acquire(ctx); err != nil {
 		return
 	}
 
-	f.isRunning = true
-	f.Unlock()
+	err = f.runMessage(ctx)
+
+	return
+}
+
+// runMessage run the message handler in the taken slot of the queue
+func (f *Flow) runMessage(ctx context.Context) (err error) {
+
+	defer f.queue.Release()
 
 	var _element *FlowElement
 
@@ -210,88 +247,167 @@ func (f *Flow) NewMessage(ctx context.Context) (err error) {
 		return
 	}
 
-	// ------------------------------------------------
-	getNextElements := func(element *FlowElement, isScripted, isTrue bool) (elements []*FlowElement) {
-		// each connections
-		for _, conn := range f.Connections {
-			if conn.ElementFrom != element.Model.Uuid || conn.ElementTo == element.Model.Uuid {
-				continue
-			}
+	// each run has own message
+	msg, ok := ctx.Value("msg").(*Message)
+	if !ok {
+		msg = f.GetMessage()
+	}
 
-			for _, element := range f.FlowElements {
-				if conn.ElementTo != element.Model.Uuid {
-					continue
-				}
+	run := newFlowRun(f, msg)
+	run.begin()
 
-				if isScripted {
-					if conn.Direction == "true" {
-						if !isTrue {
-							continue
-						}
-					} else if conn.Direction == "false" {
-						if isTrue {
-							continue
-						}
-					}
-				}
+	runCtx, cancel := f.runContext(ctx, run)
+
+	go func() {
+		defer cancel()
+
+		run.finish(f.runElement(runCtx, run, _element, nil))
 
-				elements = append(elements, element)
+		if run.isSuspended() {
+			f.SetMessage(run.message)
+			f.suspended.Dec()
+		}
+	}()
+
+	// wait for the end of the run, the suspended run is not waited
+	select {
+	case <-run.done:
+		err = run.err
+	case <-run.suspended:
+	}
+
+	// return the result to the sender
+	msg.Update(run.message)
+	f.SetMessage(run.message)
+
+	return
+}
+
+// runContext the run is canceled with the sender context until it is suspended,
+// after that only by the flow removal
+func (f *Flow) runContext(ctx context.Context, run *flowRun) (context.Context, context.CancelFunc) {
+
+	runCtx, cancel := context.WithCancel(f.ctx)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if !run.isSuspended() {
+				cancel()
 			}
+		case <-run.suspended:
+		case <-runCtx.Done():
 		}
+	}()
+
+	return flowRunContext{Context: runCtx, values: ctx}, cancel
+}
+
+// flowRunContext keeps the values of the sender context
+type flowRunContext struct {
+	context.Context
+	values context.Context
+}
 
+// Value ...
+func (c flowRunContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// runElement run the element and then the elements connected to it
+func (f *Flow) runElement(ctx context.Context, run *flowRun, element *FlowElement, branch *flowBranch) (err error) {
+
+	if err = ctx.Err(); err != nil {
 		return
 	}
 
-	if msg, ok := ctx.Value("msg").(*Message); ok {
-		f.SetMessage(msg)
+	var ok, isScripted bool
+	isScripted = element.ScriptEngine != nil
+
+	if ctx, ok, err = element.Run(ctx, run); err != nil {
+		//log.Error(err.Error())
+		if err == errFlowRunStopped {
+			err = nil
+		}
+		return
 	}
 
-	var runElement func(*FlowElement)
-	runElement = func(element *FlowElement) {
+	// send message to linked flow
+	if element.Model.PrototypeType == "Flow" && element.Model.FlowLink != nil {
+		if flow, ok := f.workflow.Flows[*element.Model.FlowLink]; ok {
+			childCtx := context.WithValue(ctx, "msg", run.message)
+			if err = flow.NewMessage(childCtx); err != nil {
+				return
+			}
+		}
+	}
 
-		if err = ctx.Err(); err != nil {
+	if element.Model.PrototypeType == "Gateway" {
+		err = f.runGateway(ctx, run, element, ok, branch)
+		return
+	}
+
+	for _, e := range f.getNextElements(element, isScripted, ok) {
+		if err = f.enterElement(ctx, run, e, branch); err != nil {
 			return
 		}
+	}
 
-		var ok, isScripted bool
-		isScripted = element.ScriptEngine != nil
+	return
+}
 
-		childCtx, _ := context.WithCancel(ctx)
+// enterElement inside the fork branch stops on the join gateway,
+// the gateway runs once when all the branches are done
+func (f *Flow) enterElement(ctx context.Context, run *flowRun, element *FlowElement, branch *flowBranch) (err error) {
 
-		if ctx, ok, err = element.Run(childCtx); err != nil {
-			//log.Error(err.Error())
-			return
+	if branch != nil && f.isJoinGateway(element) {
+		branch.join(element)
+		return
+	}
+
+	err = f.runElement(ctx, run, element, branch)
+
+	return
+}
+
+func (f *Flow) getNextElements(element *FlowElement, isScripted, isTrue bool) (elements []*FlowElement) {
+	// each connections
+	for _, conn := range f.Connections {
+		if conn.ElementFrom != element.Model.Uuid || conn.ElementTo == element.Model.Uuid {
+			continue
 		}
 
-		// send message to linked flow
-		if element.Model.PrototypeType == "Flow" && element.Model.FlowLink != nil {
-			if flow, ok := f.workflow.Flows[*element.Model.FlowLink]; ok {
-				childCtx, _ := context.WithCancel(ctx)
-				childCtx = context.WithValue(childCtx, "msg", f.message)
-				if err = flow.NewMessage(childCtx); err != nil {
-					return
+		for _, element := range f.FlowElements {
+			if conn.ElementTo != element.Model.Uuid {
+				continue
+			}
+
+			if isScripted {
+				if conn.Direction == "true" {
+					if !isTrue {
+						continue
+					}
+				} else if conn.Direction == "false" {
+					if isTrue {
+						continue
+					}
 				}
-				f.SetMessage(flow.message)
 			}
-		}
 
-		elements := getNextElements(element, isScripted, ok)
-		for _, e := range elements {
-			runElement(e)
+			elements = append(elements, element)
 		}
 	}
 
-	runElement(_element)
-
 	return
 }
 
-// InitWorkers ...
+// InitWorkers the worker that can not be started (e.g. by the bad time string) is skipped,
+// the flow runs with the rest of them
 func (f *Flow) InitWorkers() (err error) {
 
 	for _, worker := range f.Model.Workers {
-		if err = f.AddWorker(worker); err != nil {
-			return
+		if workerErr := f.AddWorker(worker); workerErr != nil {
+			log.Warnf("flow %d: worker %d \"%s\" skipped: %s", f.Model.Id, worker.Id, worker.Name, workerErr.Error())
 		}
 	}
 
@@ -322,33 +438,7 @@ func (f *Flow) AddWorker(model *m.Worker) (err error) {
 		devices = append(devices, model.DeviceAction.Device)
 	} else {
 		// значит тут группа устройств
-		for _, child := range model.DeviceAction.Device.Devices {
-			if child.Status != "enabled" {
-				continue
-			}
-
-			//if child.Address == nil {
-			//	continue
-			//}
-
-			device := &m.Device{
-				Id:         child.Id,
-				Name:       child.Name,
-				Properties: child.Properties,
-				Type:       model.DeviceAction.Device.Type,
-				Device:     &m.Device{Id: model.DeviceAction.Device.Id},
-			}
-
-			//*device = *model.DeviceAction.Device
-			//device.Id = child.Id
-			//device.Name = child.Name
-			//device.Address = new(int)
-			//*device.Address = *child.Address
-			//device.Device = &m.Device{Id: model.DeviceAction.Device.Id}
-			//device.Tty = child.Tty
-			//device.Sleep = model.DeviceAction.Device.Sleep
-			devices = append(devices, device)
-		}
+		devices = GroupMembers(model.DeviceAction.Device)
 	}
 
 	// get node
@@ -361,6 +451,14 @@ func (f *Flow) AddWorker(model *m.Worker) (err error) {
 		return
 	}
 
+	// the backup node of the device
+	var backupNode *Node
+	if backup := model.DeviceAction.Device.BackupNode; backup != nil {
+		if backupNode, ok = f.core.safeGetOrAddNode(backup.Id); !ok {
+			log.Warnf("backup node %d not found", backup.Id)
+		}
+	}
+
 	// generate new worker
 	worker := NewWorker(model, f, f.cron)
 
@@ -369,7 +467,7 @@ func (f *Flow) AddWorker(model *m.Worker) (err error) {
 	for _, device := range devices {
 
 		var action *Action
-		if action, err = NewAction(device, model.DeviceAction, f.Node, f, f.scriptService, f.mqtt, f.adaptors, f.zigbee2mqtt); err != nil {
+		if action, err = NewAction(device, model.DeviceAction, f.Node, backupNode, f, f.scriptService, f.mqtt, f.adaptors, f.zigbee2mqtt, f.core.modbus, f.core.Events); err != nil {
 			log.Error(err.Error())
 			continue
 		}
@@ -377,8 +475,12 @@ func (f *Flow) AddWorker(model *m.Worker) (err error) {
 		worker.AddAction(action)
 	}
 
+	if err = worker.Start(); err != nil {
+		log.Errorf("worker %d: %s", model.Id, err.Error())
+		return
+	}
+
 	f.Workers[model.Id] = worker
-	f.Workers[model.Id].Start()
 
 	return
 }
@@ -494,51 +596,112 @@ func (f *Flow) mqttOnPublish(client *mqtt.Client, msg mqtt.Message) {
 	f.mqttMessageQueue <- message
 }
 
-func (f *Flow) mqttNewMessage(message *Message) {
+func (f *Flow) subscribeEvents(topic string) {
+
+	filter, err := EventTopicFilter(topic)
+	if err != nil {
+		log.Errorf("flow '%v': bad events topic '%s': %s", f.Model.Name, topic, err.Error())
+		return
+	}
+
+	f.eventIds = append(f.eventIds, f.core.Events.Subscribe(filter, f.onEvent))
+}
+
+func (f *Flow) onEvent(event Event) {
+
+	message := NewMessage()
+	message.SetVar("event_type", event.EventType())
+	message.SetVar("event", EventFields(event))
+
+	// the message worker is stopped with the flow
+	select {
+	case f.mqttMessageQueue <- message:
+	case <-f.ctx.Done():
+	}
+}
+
+// EventTopicFilter the filter of the flow subscription "events/{type}?{field}={value}",
+// the values are json, the value that is not json is the string
+func EventTopicFilter(topic string) (filter EventFilter, err error) {
+
+	var u *url.URL
+	if u, err = url.Parse(strings.TrimPrefix(topic, FlowEventTopicPrefix)); err != nil {
+		return
+	}
+
+	if u.Path != "" && u.Path != "*" {
+		filter.Types = []string{u.Path}
+	}
+
+	query := u.Query()
+	if len(query) == 0 {
+		return
+	}
+
+	filter.Fields = make(map[string]interface{})
+	for name, values := range query {
+		list := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			var value interface{}
+			if json.Unmarshal([]byte(v), &value) != nil {
+				value = v
+			}
+			list = append(list, value)
+		}
+		if len(list) == 1 {
+			filter.Fields[name] = list[0]
+		} else {
+			filter.Fields[name] = list
+		}
+	}
+
+	return
+}
+
+func (f *Flow) mqttNewMessage(message *Message, ticket *flowTicket) {
 
 	// create context
-	ctx, _ := context.WithDeadline(context.Background(), time.Now().Add(60*time.Second))
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(60*time.Second))
+	defer cancel()
 	ctx = context.WithValue(ctx, "msg", message)
 
-	done := make(chan struct{})
-	go func() {
-		if err := f.NewMessage(ctx); err != nil {
+	var err error
+	defer func() {
+		if err != nil {
 			log.Errorf("flow '%v' end with error: '%+v'", f.Model.Name, err.Error())
 		}
-
-		if ctx.Err() != nil {
-			log.Errorf("flow '%v' end with error: '%+v'", f.Model.Name, ctx.Err())
-		}
-
-		done <- struct{}{}
 	}()
 
-	select {
-	case <-done:
-		close(done)
-	case <-ctx.Done():
+	// wait for the reserved slot
+	if err = f.queue.Wait(ctx, ticket); err != nil {
+		return
+	}
 
+	// circular dependency search
+	if ctx, err = f.defineCircularConnection(ctx); err != nil {
+		f.queue.Release()
+		return
 	}
+
+	err = f.runMessage(ctx)
 }
 
 func (f *Flow) mqttMessageWorker() {
 
 	for {
-
-		f.Lock()
-		if f.isRunning {
-			time.Sleep(time.Millisecond * 500)
-			f.Unlock()
-			continue
-		}
-		f.Unlock()
-
 		select {
 		case <-f.mqttWorkerQuit:
 			return
 
 		case message := <-f.mqttMessageQueue:
-			f.mqttNewMessage(message)
+			// the place in the flow queue is taken in the arrival order,
+			// the run waits for its slot in the own goroutine
+			ticket, err := f.queue.Reserve()
+			if err != nil {
+				log.Errorf("flow '%v' end with error: '%+v'", f.Model.Name, err.Error())
+				continue
+			}
+			go f.mqttNewMessage(message, ticket)
 		}
 	}
 }
@@ -18,13 +18,19 @@
 
 package core
 
+import "time"
+
 // Javascript Binding
 //
 // Flow
 //	 .GetName()
 //	 .GetDescription()
-//	 .SetVar(string, interface)
+//	 .SetVar(string, interface) -> error
 //	 .GetVar(string)
+//	 .SetVarTTL(string, interface, seconds) -> error
+//	 .DelVar(string) -> error
+//	 .IncrVar(string, number) -> number
+//	 .CompareAndSetVar(string, old, new) -> bool
 //	 .Node()
 //
 type FlowBind struct {
@@ -42,8 +48,8 @@ func (f *FlowBind) GetDescription() string {
 }
 
 // SetVar ...
-func (f *FlowBind) SetVar(key string, value interface{}) {
-	f.flow.SetVar(key, value)
+func (f *FlowBind) SetVar(key string, value interface{}) error {
+	return f.flow.SetVar(key, value)
 }
 
 // GetVar ...
@@ -51,6 +57,26 @@ func (f *FlowBind) GetVar(key string) interface{} {
 	return f.flow.GetVar(key)
 }
 
+// SetVarTTL ...
+func (f *FlowBind) SetVarTTL(key string, value interface{}, seconds int64) error {
+	return f.flow.SetVarTTL(key, value, time.Duration(seconds)*time.Second)
+}
+
+// DelVar ...
+func (f *FlowBind) DelVar(key string) error {
+	return f.flow.DelVar(key)
+}
+
+// IncrVar ...
+func (f *FlowBind) IncrVar(key string, delta float64) (float64, error) {
+	return f.flow.IncrVar(key, delta)
+}
+
+// CompareAndSetVar ...
+func (f *FlowBind) CompareAndSetVar(key string, old, value interface{}) (bool, error) {
+	return f.flow.CompareAndSetVar(key, old, value)
+}
+
 // Node ...
 func (f *FlowBind) Node() *NodeBind {
 	if f.flow.Node == nil {
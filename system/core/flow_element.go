@@ -34,7 +34,6 @@ type FlowElement struct {
 	Workflow     *Workflow
 	ScriptEngine *scripts.Engine
 	Prototype    ActionPrototypes
-	status       Status
 	Action       *Action
 	adaptors     *adaptors.Adaptors
 }
@@ -69,6 +68,18 @@ func NewFlowElement(model *m.FlowElement,
 	case "Flow":
 		flowElement.Prototype = &FlowLink{}
 		break
+	case "Delay":
+		flowElement.Prototype = &Delay{}
+		break
+	case "WaitEvent":
+		flowElement.Prototype = NewWaitEvent(flowElement)
+		break
+	case "Debounce":
+		flowElement.Prototype = &Debounce{}
+		break
+	case "Throttle":
+		flowElement.Prototype = &Throttle{}
+		break
 	}
 
 	return
@@ -86,71 +97,75 @@ func (f *FlowElement) Before(ctx context.Context) (newCtx context.Context, err e
 		return
 	}
 
-	f.status = Done
-
 	return
 }
 
 // run internal process
-func (f *FlowElement) Run(ctx context.Context) (newCtx context.Context, b bool, err error) {
+func (f *FlowElement) Run(ctx context.Context, run *flowRun) (newCtx context.Context, b bool, err error) {
 
 	if err = ctx.Err(); err != nil {
 		return
 	}
 
-	f.status = InProcess
-
-	//???
-	f.Flow.cursor = f.Model.Uuid
+	// each step is stored in the run history, the step keeps the status
+	// of the element in the run, the element itself is shared by the runs
+	step := run.stepStart(f)
+	defer func() {
+		run.stepEnd(step, err)
+	}()
 
 	if newCtx, err = f.Before(ctx); err != nil {
-		f.status = Error
 		return
 	}
 
 	if err = f.Prototype.Run(f.Flow); err != nil {
-		f.status = Error
 		return
 	}
 
+	// time based elements hold the message
+	if timer, ok := f.Prototype.(TimerPrototypes); ok {
+		if b, err = timer.Wait(newCtx, f, run); err != nil {
+			return
+		}
+	}
+
 	//run script if exist
 	if f.Model.Script != nil {
 
-		if _, err = f.ScriptEngine.EvalScript(f.Model.Script); err != nil {
-			f.status = Error
+		// the script works with the message of the current run, the branches of the parallel
+		// and inclusive gateways share the engine and the message, the result is read under the lock
+		var msgErr string
+		f.Flow.scriptLock.Lock()
+		f.ScriptEngine.PushStruct("message", run.message)
+		_, err = f.ScriptEngine.EvalScript(f.Model.Script)
+		msgErr, b = run.message.Error, run.message.Success
+		f.Flow.scriptLock.Unlock()
+
+		if err != nil {
 			return
 		}
 
-		if f.Flow.message.Error != "" {
-			err = errors.New(f.Flow.message.Error)
-			f.status = Error
+		if msgErr != "" {
+			err = errors.New(msgErr)
 			return
 		}
-
-		b = f.Flow.message.Success
 	}
 
-	if err = f.After(); err != nil {
-		f.status = Error
-		return
-	}
-
-	f.status = Ended
+	err = f.After()
 
 	return
 }
 
 // After ...
 func (f *FlowElement) After() error {
-	f.status = Done
 	return f.Prototype.After(f.Flow)
 }
 
-// GetStatus ...
-func (f *FlowElement) GetStatus() (status Status) {
-
-	status = f.status
-	return
+// Remove ...
+func (f *FlowElement) Remove() {
+	if waitEvent, ok := f.Prototype.(*WaitEvent); ok {
+		waitEvent.Remove()
+	}
 }
 
 func (f *FlowElement) defineCircularConnection(ctx context.Context) (newCtx context.Context, err error) {
@@ -0,0 +1,223 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"errors"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/metrics"
+	"github.com/gammazero/deque"
+	"sync"
+)
+
+var (
+	// ErrFlowQueueFull ...
+	ErrFlowQueueFull = errors.New("flow queue is full")
+	// ErrFlowMessageDropped ...
+	ErrFlowMessageDropped = errors.New("flow message dropped")
+)
+
+type flowTicket struct {
+	ready     chan error
+	cancelled bool
+}
+
+// FlowQueue limits the number of flow runs executed at the same time,
+// messages over the limit wait in the bounded queue:
+//
+// serial - one run at a time, new message is rejected when the queue is full
+// parallel - up to N runs at a time, new message is rejected when the queue is full
+// drop_oldest - one run at a time, the oldest waiting message is dropped when the queue is full
+type FlowQueue struct {
+	sync.Mutex
+	mode        FlowConcurrencyMode
+	concurrency int
+	size        int
+	running     int
+	waiting     int
+	tickets     *deque.Deque
+	metric      *metrics.MetricManager
+}
+
+// NewFlowQueue ...
+func NewFlowQueue(model *m.Flow, metric *metrics.MetricManager) *FlowQueue {
+
+	queue := &FlowQueue{
+		mode:        model.ConcurrencyMode,
+		concurrency: model.Concurrency,
+		size:        model.QueueSize,
+		tickets:     &deque.Deque{},
+		metric:      metric,
+	}
+
+	if queue.mode != FlowConcurrencyParallel {
+		queue.concurrency = 1
+	}
+
+	if queue.concurrency < 1 {
+		queue.concurrency = 1
+	}
+
+	if queue.size < 1 {
+		queue.size = 1
+	}
+
+	return queue
+}
+
+// Acquire wait for a free slot
+func (q *FlowQueue) Acquire(ctx context.Context) (err error) {
+
+	var ticket *flowTicket
+	if ticket, err = q.Reserve(); err != nil {
+		return
+	}
+
+	err = q.Wait(ctx, ticket)
+
+	return
+}
+
+// Reserve take a free slot or a place in the queue without waiting,
+// the messages take the slots in the order of the calls.
+// A nil ticket means the slot is already taken
+func (q *FlowQueue) Reserve() (ticket *flowTicket, err error) {
+
+	q.Lock()
+	if q.running < q.concurrency && q.waiting == 0 {
+		q.running++
+		q.Unlock()
+		return
+	}
+
+	if q.waiting >= q.size {
+		if q.mode != FlowConcurrencyDropOldest {
+			q.Unlock()
+			q.update(metrics.FlowMessageDropped{Num: 1})
+			err = ErrFlowQueueFull
+			return
+		}
+		q.unsafeDropOldest()
+	}
+
+	ticket = &flowTicket{ready: make(chan error, 1)}
+	q.tickets.PushBack(ticket)
+	q.waiting++
+	q.Unlock()
+
+	q.update(metrics.FlowMessageQueued{Num: 1})
+
+	return
+}
+
+// Wait wait for the slot reserved by Reserve
+func (q *FlowQueue) Wait(ctx context.Context, ticket *flowTicket) (err error) {
+
+	if ticket == nil {
+		return
+	}
+
+	select {
+	case err = <-ticket.ready:
+	case <-ctx.Done():
+		q.Lock()
+		select {
+		case err = <-ticket.ready:
+			// the slot was granted at the same time
+			if err == nil {
+				q.unsafeRelease()
+			}
+		default:
+			ticket.cancelled = true
+			q.waiting--
+			q.update(metrics.FlowMessageDequeued{Num: 1})
+		}
+		q.Unlock()
+		err = ctx.Err()
+	}
+
+	return
+}
+
+// Release free the slot, the next waiting message takes it
+func (q *FlowQueue) Release() {
+	q.Lock()
+	q.unsafeRelease()
+	q.Unlock()
+}
+
+// Running ...
+func (q *FlowQueue) Running() int {
+	q.Lock()
+	defer q.Unlock()
+	return q.running
+}
+
+// Waiting ...
+func (q *FlowQueue) Waiting() int {
+	q.Lock()
+	defer q.Unlock()
+	return q.waiting
+}
+
+// Close drop all waiting messages
+func (q *FlowQueue) Close() {
+	q.Lock()
+	for q.waiting > 0 {
+		q.unsafeDropOldest()
+	}
+	q.Unlock()
+}
+
+func (q *FlowQueue) unsafeRelease() {
+	if ticket := q.unsafePop(); ticket != nil {
+		ticket.ready <- nil
+		return
+	}
+	q.running--
+}
+
+func (q *FlowQueue) unsafeDropOldest() {
+	if ticket := q.unsafePop(); ticket != nil {
+		ticket.ready <- ErrFlowMessageDropped
+		q.update(metrics.FlowMessageDropped{Num: 1})
+	}
+}
+
+func (q *FlowQueue) unsafePop() *flowTicket {
+	for q.tickets.Len() > 0 {
+		ticket := q.tickets.PopFront().(*flowTicket)
+		if ticket.cancelled {
+			continue
+		}
+		q.waiting--
+		q.update(metrics.FlowMessageDequeued{Num: 1})
+		return ticket
+	}
+	return nil
+}
+
+func (q *FlowQueue) update(t interface{}) {
+	if q.metric == nil {
+		return
+	}
+	go q.metric.Update(t)
+}
@@ -0,0 +1,233 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/stream"
+	"github.com/e154/smart-home/system/uuid"
+	"sync"
+	"time"
+)
+
+// how many runs are kept in the history of each flow
+const flowRunHistoryLimit = 100
+
+// errFlowRunStopped the element does not pass the message further, it is not an error of the run
+var errFlowRunStopped = errors.New("flow run stopped")
+
+// flowRun state of the single message processing
+type flowRun struct {
+	sync.Mutex
+	flow          *Flow
+	model         *m.FlowRun
+	message       *Message
+	cursor        uuid.UUID
+	steps         []*m.FlowRunStep
+	err           error
+	done          chan struct{}
+	suspended     chan struct{}
+	suspendedOnce sync.Once
+}
+
+func newFlowRun(flow *Flow, msg *Message) (run *flowRun) {
+	run = &flowRun{
+		flow:      flow,
+		message:   NewMessage(),
+		done:      make(chan struct{}),
+		suspended: make(chan struct{}),
+		model: &m.FlowRun{
+			Id:        uuid.NewV4(),
+			FlowId:    flow.Model.Id,
+			Status:    FlowRunInProcess,
+			StartedAt: time.Now(),
+		},
+	}
+	run.message.Update(msg)
+	return
+}
+
+// Id ...
+func (r *flowRun) Id() uuid.UUID {
+	return r.model.Id
+}
+
+// SetCursor ...
+func (r *flowRun) SetCursor(cursor uuid.UUID) {
+	r.Lock()
+	r.cursor = cursor
+	r.Unlock()
+}
+
+// Cursor ...
+func (r *flowRun) Cursor() uuid.UUID {
+	r.Lock()
+	defer r.Unlock()
+	return r.cursor
+}
+
+// suspend detach the run from the sender, the run continues in the background
+// without the slot of the flow queue
+func (r *flowRun) suspend() {
+	r.suspendedOnce.Do(func() {
+		r.flow.suspended.Inc()
+		close(r.suspended)
+	})
+}
+
+// isSuspended ...
+func (r *flowRun) isSuspended() bool {
+	select {
+	case <-r.suspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// begin store the new run in the history
+func (r *flowRun) begin() {
+
+	if _, err := r.flow.adaptors.FlowRun.Add(r.model); err != nil {
+		log.Error(err.Error())
+	}
+
+	r.broadcast("flow.run", map[string]interface{}{
+		"run": r.model,
+	})
+}
+
+// stepStart the element takes the message
+func (r *flowRun) stepStart(element *FlowElement) (step *m.FlowRunStep) {
+
+	r.SetCursor(element.Model.Uuid)
+
+	step = &m.FlowRunStep{
+		FlowRunId:       r.model.Id,
+		FlowElementUuid: element.Model.Uuid,
+		Status:          FlowRunInProcess,
+		StartedAt:       time.Now(),
+	}
+
+	r.broadcast("flow.run.step", map[string]interface{}{
+		"step": step,
+	})
+
+	return
+}
+
+// stepEnd save the element result with the snapshot of the message vars
+func (r *flowRun) stepEnd(step *m.FlowRunStep, err error) {
+
+	now := time.Now()
+	step.FinishedAt = &now
+	step.Vars = r.message.Vars()
+	step.Status = FlowRunDone
+	if err != nil && err != errFlowRunStopped {
+		step.Status = FlowRunError
+		step.Error = err.Error()
+	}
+
+	// the steps are stored together with the result of the run
+	r.Lock()
+	r.steps = append(r.steps, step)
+	r.Unlock()
+
+	r.broadcast("flow.run.step", map[string]interface{}{
+		"step": step,
+	})
+}
+
+// finish store the steps and the result of the run,
+// the old history is removed by the core on the timer
+func (r *flowRun) finish(err error) {
+
+	r.err = err
+
+	now := time.Now()
+	r.model.FinishedAt = &now
+	r.model.Status = FlowRunDone
+	if err != nil {
+		r.model.Status = FlowRunError
+		r.model.Error = err.Error()
+	}
+
+	r.Lock()
+	steps := r.steps
+	r.steps = nil
+	r.Unlock()
+
+	if err = r.flow.adaptors.FlowRun.AddSteps(steps); err != nil {
+		log.Error(err.Error())
+	}
+
+	if err = r.flow.adaptors.FlowRun.Update(r.model); err != nil {
+		log.Error(err.Error())
+	}
+
+	r.broadcast("flow.run", map[string]interface{}{
+		"run": r.model,
+	})
+
+	if r.flow.core != nil {
+		event := FlowRunFinished{
+			FlowId:     r.model.FlowId,
+			RunId:      r.model.Id,
+			Status:     r.model.Status,
+			Error:      r.model.Error,
+			StartedAt:  r.model.StartedAt,
+			FinishedAt: now,
+		}
+		if r.flow.workflow != nil {
+			event.WorkflowId = r.flow.workflow.model.Id
+		}
+		r.flow.core.Events.Publish(event)
+	}
+
+	close(r.done)
+}
+
+// broadcast send the run state to the websocket clients
+func (r *flowRun) broadcast(command string, payload map[string]interface{}) {
+
+	if r.flow.core == nil || r.flow.core.streamService == nil {
+		return
+	}
+
+	payload["flow_id"] = r.model.FlowId
+	payload["run_id"] = r.model.Id
+
+	msg := stream.Message{
+		Command: command,
+		Type:    stream.Broadcast,
+		Forward: stream.Request,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+
+	r.flow.core.streamService.Broadcast(data)
+}
@@ -18,6 +18,14 @@
 
 package core
 
+import (
+	"context"
+	"fmt"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"sync"
+)
+
 //ActionPrototypes
 type Gateway struct{}
 
@@ -43,3 +51,207 @@ func (m *Gateway) Before(flow *Flow) (err error) {
 func (m *Gateway) Type() string {
 	return "Gateway"
 }
+
+// flowBranch collects the join gateways reached by the branches of the fork
+type flowBranch struct {
+	sync.Mutex
+	joins []*FlowElement
+}
+
+func (b *flowBranch) join(element *FlowElement) {
+	b.Lock()
+	defer b.Unlock()
+	for _, join := range b.joins {
+		if join.Model.Uuid == element.Model.Uuid {
+			return
+		}
+	}
+	b.joins = append(b.joins, element)
+}
+
+// runGateway select outgoing connections of the gateway:
+//
+// exclusive - first connection with the true condition, or the connection without condition,
+// if the gateway has no conditions the path is selected by the element script as before
+// parallel - all connections, branches run concurrently and meet on the join gateway
+// inclusive - all connections with the true condition, branches run concurrently
+func (f *Flow) runGateway(ctx context.Context, run *flowRun, element *FlowElement, isTrue bool, branch *flowBranch) (err error) {
+
+	outgoing := f.getOutgoingConnections(element)
+
+	var conditional bool
+	for _, conn := range outgoing {
+		if conn.Condition != "" {
+			conditional = true
+			break
+		}
+	}
+
+	var connections []*m.Connection
+	switch element.Model.GatewayType {
+	case FlowElementsGatewayParallel:
+		connections = outgoing
+	case FlowElementsGatewayInclusive:
+		if connections, err = f.matchConditions(run, outgoing, false); err != nil {
+			return
+		}
+	default:
+		if !conditional {
+			for _, e := range f.getNextElements(element, element.ScriptEngine != nil, isTrue) {
+				if err = f.enterElement(ctx, run, e, branch); err != nil {
+					return
+				}
+			}
+			return
+		}
+		if connections, err = f.matchConditions(run, outgoing, true); err != nil {
+			return
+		}
+	}
+
+	elements := f.getConnectionTargets(connections)
+	if len(elements) == 0 {
+		if len(outgoing) > 0 {
+			err = fmt.Errorf("gateway '%s': no outgoing connection matches", element.Model.Name)
+		}
+		return
+	}
+
+	if element.Model.GatewayType != FlowElementsGatewayParallel &&
+		element.Model.GatewayType != FlowElementsGatewayInclusive {
+		err = f.enterElement(ctx, run, elements[0], branch)
+		return
+	}
+
+	// fork, the branches share the script engine of the flow,
+	// the element scripts and the conditions run under the script lock
+	fork := &flowBranch{}
+	errs := make([]error, len(elements))
+	wg := sync.WaitGroup{}
+	for i, e := range elements {
+		wg.Add(1)
+		go func(i int, e *FlowElement) {
+			defer wg.Done()
+			errs[i] = f.enterElement(ctx, run, e, fork)
+		}(i, e)
+	}
+	wg.Wait()
+
+	for _, err = range errs {
+		if err != nil {
+			return
+		}
+	}
+
+	// join
+	for _, join := range fork.joins {
+		if err = f.runElement(ctx, run, join, branch); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// isJoinGateway parallel or inclusive gateway with several incoming connections
+func (f *Flow) isJoinGateway(element *FlowElement) bool {
+
+	if element.Model.PrototypeType != FlowElementsPrototypeGateway {
+		return false
+	}
+
+	if element.Model.GatewayType != FlowElementsGatewayParallel &&
+		element.Model.GatewayType != FlowElementsGatewayInclusive {
+		return false
+	}
+
+	var incoming int
+	for _, conn := range f.Connections {
+		if conn.ElementTo == element.Model.Uuid && conn.ElementFrom != conn.ElementTo {
+			incoming++
+		}
+	}
+
+	return incoming > 1
+}
+
+func (f *Flow) getOutgoingConnections(element *FlowElement) (connections []*m.Connection) {
+	for _, conn := range f.Connections {
+		if conn.ElementFrom != element.Model.Uuid || conn.ElementTo == element.Model.Uuid {
+			continue
+		}
+		connections = append(connections, conn)
+	}
+	return
+}
+
+func (f *Flow) getConnectionTargets(connections []*m.Connection) (elements []*FlowElement) {
+	for _, conn := range connections {
+		for _, element := range f.FlowElements {
+			if conn.ElementTo != element.Model.Uuid {
+				continue
+			}
+			elements = append(elements, element)
+		}
+	}
+	return
+}
+
+// matchConditions connections without condition are used as default path
+func (f *Flow) matchConditions(run *flowRun, outgoing []*m.Connection, first bool) (connections []*m.Connection, err error) {
+
+	var defaults []*m.Connection
+	for _, conn := range outgoing {
+		if conn.Condition == "" {
+			defaults = append(defaults, conn)
+			continue
+		}
+
+		var ok bool
+		if ok, err = f.evalCondition(run, conn.Condition); err != nil {
+			err = fmt.Errorf("connection '%s' condition: %s", conn.Name, err.Error())
+			return
+		}
+
+		if !ok {
+			continue
+		}
+
+		connections = append(connections, conn)
+		if first {
+			return
+		}
+	}
+
+	if len(connections) == 0 && len(defaults) > 0 {
+		if first {
+			connections = defaults[:1]
+		} else {
+			connections = defaults
+		}
+	}
+
+	return
+}
+
+// evalCondition evaluate the connection condition under the script lock, message vars are available by name:
+//
+// temperature > 25 && mode == 'auto'
+func (f *Flow) evalCondition(run *flowRun, condition string) (ok bool, err error) {
+
+	src := fmt.Sprintf("(function(){ with (message.Vars()) { return !!(%s); } })()", condition)
+
+	f.scriptLock.Lock()
+	f.scriptEngine.PushStruct("message", run.message)
+	var result string
+	result, err = f.scriptEngine.EvalString(src)
+	f.scriptLock.Unlock()
+
+	if err != nil {
+		return
+	}
+
+	ok = result == "true"
+
+	return
+}
@@ -21,25 +21,36 @@ package core
 import (
 	"fmt"
 	"github.com/e154/smart-home/adaptors"
-	"github.com/e154/smart-home/common"
 	"github.com/e154/smart-home/system/metrics"
 	"sync"
 )
 
 // Map ...
 type Map struct {
-	metric   *metrics.MetricManager
-	elements sync.Map
-	adaptors *adaptors.Adaptors
+	metric       *metrics.MetricManager
+	elements     sync.Map
+	adaptors     *adaptors.Adaptors
+	deviceStates *DeviceStates
+	events       *EventBus
 }
 
 // NewMap ...
 func NewMap(metric *metrics.MetricManager,
-	adaptors *adaptors.Adaptors) *Map {
-	return &Map{
-		metric:   metric,
-		adaptors: adaptors,
+	adaptors *adaptors.Adaptors,
+	deviceStates *DeviceStates,
+	events *EventBus) *Map {
+	_map := &Map{
+		metric:       metric,
+		adaptors:     adaptors,
+		deviceStates: deviceStates,
+		events:       events,
 	}
+
+	if deviceStates != nil {
+		deviceStates.Subscribe(_map.onDeviceStateChange)
+	}
+
+	return _map
 }
 
 // SetElementState ...
@@ -54,20 +65,13 @@ func (b *Map) SetElementState(elementName, stateSystemName string) {
 	if v, ok := b.elements.Load(hashKey); ok {
 		v.(*MapElement).SetState(stateSystemName)
 	} else {
-		mapElement, err := b.NewMapElement(elementName, common.String(stateSystemName))
+		mapElement, err := b.NewMapElement(elementName, nil)
 		if err != nil {
 			log.Error(err.Error())
 			return
 		}
 
-		if mapElement.State == nil {
-			return
-		}
-		b.metric.Update(metrics.MapElementSetState{
-			StateId:     mapElement.State.Id,
-			DeviceId:    mapElement.State.DeviceId,
-			ElementName: elementName,
-		})
+		mapElement.SetState(stateSystemName)
 	}
 }
 
@@ -107,6 +111,17 @@ func (b *Map) NewMapElement(elementName string, stateSystemName *string) (*MapEl
 	return element, nil
 }
 
+// onDeviceStateChange show the new device state on the map elements of the device
+func (b *Map) onDeviceStateChange(change DeviceStateChange) {
+	b.elements.Range(func(key, value interface{}) bool {
+		element := value.(*MapElement)
+		if deviceId, ok := element.deviceId(); ok && deviceId == change.DeviceId {
+			element.setState(change.To)
+		}
+		return true
+	})
+}
+
 func (b *Map) key(elementName string) string {
 	return fmt.Sprintf("%s", elementName)
 }
@@ -52,6 +52,14 @@ func NewMapElement(elementName string,
 
 	var state *m.DeviceState
 
+	// restore the persisted device state
+	if systemName == nil && _map != nil && _map.deviceStates != nil &&
+		mapElement.PrototypeType == common.PrototypeTypeDevice && mapElement.Prototype.MapDevice != nil {
+		if current, err := _map.deviceStates.Get(mapElement.Prototype.MapDevice.DeviceId); err == nil && current.DeviceState != nil {
+			systemName = common.String(current.DeviceState.SystemName)
+		}
+	}
+
 	if systemName != nil {
 		for _, _state := range mapElement.Prototype.Device.States {
 			if _state.SystemName != common.StringValue(systemName) {
@@ -71,8 +79,27 @@ func NewMapElement(elementName string,
 	}, nil
 }
 
-// SetState ...
+// SetState change the state of the device first, the transition may be forbidden
 func (e *MapElement) SetState(systemName string) {
+
+	if deviceId, ok := e.deviceId(); ok && e.Map.deviceStates != nil {
+		if err := e.Map.deviceStates.Set(deviceId, systemName); err != nil {
+			log.Warn(err.Error())
+			return
+		}
+	}
+
+	e.setState(systemName)
+}
+
+func (e *MapElement) deviceId() (id int64, ok bool) {
+	if e.mapElement.PrototypeType != common.PrototypeTypeDevice || e.mapElement.Prototype.MapDevice == nil {
+		return
+	}
+	return e.mapElement.Prototype.MapDevice.DeviceId, true
+}
+
+func (e *MapElement) setState(systemName string) {
 	e.elementLock.Lock()
 	defer e.elementLock.Unlock()
 
@@ -93,10 +120,12 @@ func (e *MapElement) SetState(systemName string) {
 
 		go e.updateDeviceHistory(state.DeviceState)
 
-		go e.Map.metric.Update(metrics.MapElementSetState{
-			DeviceId:    e.State.DeviceId,
+		e.Map.events.Publish(MapElementStateChanged{
 			ElementName: e.mapElement.Name,
+			DeviceId:    e.State.DeviceId,
 			StateId:     e.State.Id,
+			State:       e.State.SystemName,
+			ChangedAt:   time.Now(),
 		})
 	}
 }
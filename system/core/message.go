@@ -79,11 +79,16 @@ func (m *Message) SetVar(key string, value interface{}) {
 	m.storage.SetVar(key, value)
 }
 
+// Vars ...
+func (m *Message) Vars() map[string]interface{} {
+	return m.storage.vars()
+}
+
 // Update ...
 func (m *Message) Update(newMsg *Message) {
 	m.Error = newMsg.Error
 	m.Success = newMsg.Success
 	m.Direction = newMsg.Direction
 	m.Mqtt = newMsg.Mqtt
-	m.storage.copy(newMsg.storage.pull)
+	m.storage.copy(newMsg.storage.vars())
 }
@@ -0,0 +1,282 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	. "github.com/e154/smart-home/models/devices"
+	"github.com/e154/smart-home/system/modbus"
+	"github.com/e154/smart-home/system/mqtt"
+	"sync"
+	"time"
+)
+
+// DeviceStorageNamespace ...
+func DeviceStorageNamespace(deviceId int64) string {
+	return fmt.Sprintf("device.%d", deviceId)
+}
+
+// ModbusPollingTopic ...
+func ModbusPollingTopic(deviceId int64, group string) string {
+	return fmt.Sprintf("home/device/%d/modbus/%s", deviceId, group)
+}
+
+// ModbusPollingValues ...
+type ModbusPollingValues struct {
+	DeviceId int64     `json:"device_id"`
+	Group    string    `json:"group"`
+	Values   []uint16  `json:"values"`
+	Time     time.Time `json:"time"`
+}
+
+// ModbusPolling reads the polling groups of the modbus tcp devices served without the node.
+// The values are kept in the storage namespace of the device and published to the
+// "home/device/{id}/modbus/{group}" mqtt topic when changed
+type ModbusPolling struct {
+	sync.Mutex
+	adaptors     *adaptors.Adaptors
+	modbus       *modbus.Modbus
+	mqtt         *mqtt.Mqtt
+	storage      *PersistentStorage
+	deviceStates *DeviceStates
+	devices      map[int64]chan struct{}
+	isRunning    bool
+	wg           sync.WaitGroup
+}
+
+// NewModbusPolling ...
+func NewModbusPolling(adaptors *adaptors.Adaptors,
+	modbus *modbus.Modbus,
+	mqtt *mqtt.Mqtt,
+	storage *PersistentStorage,
+	deviceStates *DeviceStates) *ModbusPolling {
+	return &ModbusPolling{
+		adaptors:     adaptors,
+		modbus:       modbus,
+		mqtt:         mqtt,
+		storage:      storage,
+		deviceStates: deviceStates,
+		devices:      make(map[int64]chan struct{}),
+	}
+}
+
+// Load start the polling of all enabled devices
+func (p *ModbusPolling) Load() (err error) {
+
+	p.Stop()
+
+	var list []*m.Device
+	if list, err = p.adaptors.Device.GetAllEnabled(); err != nil {
+		return
+	}
+
+	p.Lock()
+	p.isRunning = true
+	p.Unlock()
+
+	for _, device := range list {
+		p.start(device)
+	}
+
+	return
+}
+
+// Reload restart the polling of the device after it was changed or removed
+func (p *ModbusPolling) Reload(deviceId int64) {
+
+	p.stop(deviceId)
+
+	p.Lock()
+	isRunning := p.isRunning
+	p.Unlock()
+
+	if !isRunning {
+		return
+	}
+
+	device, err := p.adaptors.Device.GetById(deviceId)
+	if err != nil {
+		return
+	}
+
+	if device.Status != "enabled" {
+		return
+	}
+
+	p.start(device)
+}
+
+// Stop ...
+func (p *ModbusPolling) Stop() {
+
+	p.Lock()
+	p.isRunning = false
+	for deviceId, quit := range p.devices {
+		close(quit)
+		delete(p.devices, deviceId)
+	}
+	p.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *ModbusPolling) stop(deviceId int64) {
+	p.Lock()
+	if quit, ok := p.devices[deviceId]; ok {
+		close(quit)
+		delete(p.devices, deviceId)
+	}
+	p.Unlock()
+}
+
+func (p *ModbusPolling) start(device *m.Device) {
+
+	if device.Type != DevTypeModbusTcp || device.Node != nil {
+		return
+	}
+
+	params := &DevModBusTcpConfig{}
+	if err := json.Unmarshal(device.Properties, params); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	if len(params.Polling) == 0 {
+		return
+	}
+
+	quit := make(chan struct{})
+
+	p.Lock()
+	if old, ok := p.devices[device.Id]; ok {
+		close(old)
+	}
+	p.devices[device.Id] = quit
+	p.Unlock()
+
+	for _, group := range params.Polling {
+		if group == nil || !modbus.IsReadFunction(group.Function) {
+			continue
+		}
+		p.wg.Add(1)
+		go p.poll(device.Id, params, group, quit)
+	}
+}
+
+// poll read the group periodically until the quit is closed
+func (p *ModbusPolling) poll(deviceId int64, params *DevModBusTcpConfig, group *DevModBusPollingGroup, quit chan struct{}) {
+
+	defer p.wg.Done()
+
+	interval := time.Duration(group.Interval) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []uint16
+	var lastErr string
+	for {
+		values, err := p.modbus.Do(params.AddressPort, params.PoolSize,
+			modbusRequest(params, group.Function, group.Address, group.Count, nil))
+
+		if err != nil {
+			// log the error once until the device is back
+			if err.Error() != lastErr {
+				lastErr = err.Error()
+				log.Warnf("modbus polling device(%d) group(%s): %s", deviceId, group.Name, lastErr)
+			}
+		} else {
+			lastErr = ""
+			if last == nil || !equalUint16(last, values) {
+				last = values
+				p.update(deviceId, group, values)
+			}
+		}
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update store, publish and map to the device state the new values of the group
+func (p *ModbusPolling) update(deviceId int64, group *DevModBusPollingGroup, values []uint16) {
+
+	if err := p.storage.Set(DeviceStorageNamespace(deviceId), group.Name, values, 0); err != nil {
+		log.Error(err.Error())
+	}
+
+	if p.mqtt != nil {
+		msg := ModbusPollingValues{
+			DeviceId: deviceId,
+			Group:    group.Name,
+			Values:   values,
+			Time:     time.Now(),
+		}
+		if data, err := json.Marshal(msg); err == nil {
+			p.mqtt.Publish(ModbusPollingTopic(deviceId, group.Name), data, 0, false)
+		}
+	}
+
+	if len(values) == 0 || len(group.States) == 0 {
+		return
+	}
+
+	state, ok := group.States[fmt.Sprintf("%d", values[0])]
+	if !ok {
+		return
+	}
+
+	if err := p.deviceStates.Set(deviceId, state); err != nil {
+		log.Warn(err.Error())
+	}
+}
+
+// modbusRequest ...
+func modbusRequest(params *DevModBusTcpConfig, f string, address, count uint16, command []uint16) modbus.Request {
+	return modbus.Request{
+		SlaveId:  uint8(params.SlaveId),
+		Function: f,
+		Address:  address,
+		Count:    count,
+		Command:  command,
+		Timeout:  time.Duration(params.Timeout) * time.Millisecond,
+		Retries:  params.Retries,
+	}
+}
+
+func equalUint16(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -26,14 +26,17 @@ import (
 
 // NodeMessage ...
 type NodeMessage struct {
+	RequestId  int64             `json:"request_id"`
 	DeviceId   int64             `json:"device_id"`
 	DeviceType common.DeviceType `json:"device_type"`
 	Properties json.RawMessage   `json:"properties"`
 	Command    json.RawMessage   `json:"command"`
 }
 
-// NodeResponse ...
+// NodeResponse the node echoes the request id of the message,
+// the response of the old node without the request id has zero value
 type NodeResponse struct {
+	RequestId  int64             `json:"request_id"`
 	DeviceId   int64             `json:"device_id"`
 	DeviceType common.DeviceType `json:"device_type"`
 	Properties json.RawMessage   `json:"properties"`
@@ -66,4 +69,5 @@ type NodeStat struct {
 	LastPing    time.Time  `json:"last_ping"`
 	ConnStatus  string     `json:"conn_status"`
 	IsConnected bool       `json:"is_connected"`
+	QueueDepth  int        `json:"queue_depth"`
 }
@@ -20,11 +20,11 @@ package core
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	m "github.com/e154/smart-home/models"
 	"github.com/e154/smart-home/system/metrics"
 	"github.com/e154/smart-home/system/mqtt"
+	"go.uber.org/atomic"
 	"sync"
 	"time"
 )
@@ -40,15 +40,22 @@ type Node struct {
 	quit       chan struct{}
 	metric     *metrics.MetricManager
 	chLock     sync.Mutex
-	ch         map[int64]chan *NodeResponse
+	ch         map[int64]*nodeRequest
+	requestId  atomic.Int64
+	requestIds atomic.Bool
 	statLock   sync.Mutex
 	stat       NodeStat
+	queue      *nodeQueue
+	queueConf  *NodeQueueConfig
+	health     *nodeHealth
+	events     *EventBus
 }
 
 // NewNode ...
 func NewNode(model *m.Node,
 	mqtt *mqtt.Mqtt,
-	metric *metrics.MetricManager) *Node {
+	metric *metrics.MetricManager,
+	queueConf *NodeQueueConfig) *Node {
 
 	node := &Node{
 		model: model,
@@ -56,10 +63,13 @@ func NewNode(model *m.Node,
 			ConnStatus: "disabled",
 			LastPing:   time.Now(),
 		},
-		ch:         make(map[int64]chan *NodeResponse, 0),
+		ch:         make(map[int64]*nodeRequest, 0),
 		quit:       make(chan struct{}),
 		metric:     metric,
 		mqttClient: mqtt.NewClient(fmt.Sprintf("node_%v", model.Name)),
+		queue:      newNodeQueue(),
+		queueConf:  queueConf,
+		health:     &nodeHealth{},
 	}
 
 	go func() {
@@ -70,12 +80,20 @@ func NewNode(model *m.Node,
 			select {
 			case <-ticker.C:
 				node.updateStatus()
+				node.dispatch()
+
+			case <-node.queue.signal:
+				node.dispatch()
 
 			case _, ok := <-node.quit:
 				if !ok {
 					return
 				}
 				close(node.quit)
+				for _, cmd := range node.queue.close() {
+					cmd.done(NodeResponse{DeviceId: cmd.device.Id, DeviceType: cmd.device.Type}, ErrNodeRemoved)
+				}
+				node.updateQueueMetric()
 				return
 			}
 		}
@@ -92,20 +110,99 @@ func (n *Node) Remove() {
 	n.quit <- struct{}{}
 }
 
-// Send ...
-func (n *Node) Send(device *m.Device, command []byte) (result NodeResponse, err error) {
+// Send put the command to the node queue and wait for the response,
+// the command waits in the queue while the node is disconnected
+func (n *Node) Send(device *m.Device, priority NodeCommandPriority, command []byte) (result NodeResponse, err error) {
 
 	//log.Debugf("send device(%v) command(%v)", device.Id, command)
 
 	// time metric
 	startTime := time.Now()
 
-	ch := make(chan *NodeResponse)
-	n.addCh(device.Id, ch)
-	defer n.delCh(device.Id)
+	cmd := &nodeCommand{
+		device:   device,
+		command:  command,
+		priority: priority,
+		expireAt: startTime.Add(n.queueConf.Ttl),
+		result:   make(chan nodeCommandResult, 1),
+	}
+
+	if !n.queue.push(cmd) {
+		err = ErrNodeRemoved
+		return
+	}
+	n.updateQueueMetric()
+
+	res := <-cmd.result
+	result, err = res.resp, res.err
+
+	result.Time = time.Since(startTime).Seconds()
+
+	n.health.add(time.Now(), err != nil)
+	go n.metric.Update(metrics.NodeUpdateLatency{Id: n.model.Id, Time: result.Time, Err: err != nil})
+
+	return
+}
+
+// dispatch drop the expired commands and start the delivery of the next ones
+func (n *Node) dispatch() {
+
+	for _, cmd := range n.queue.expired(time.Now()) {
+		cmd.done(NodeResponse{DeviceId: cmd.device.Id, DeviceType: cmd.device.Type}, ErrNodeCommandExpired)
+	}
+
+	if n.IsConnected() {
+		// the node without the request ids gets one command per device at a time
+		parallel := n.requestIds.Load()
+		for cmd := n.queue.pop(parallel); cmd != nil; cmd = n.queue.pop(parallel) {
+			go n.deliver(cmd)
+		}
+	}
+
+	n.updateQueueMetric()
+}
+
+// deliver send the command to the node, retry with backoff on timeout
+func (n *Node) deliver(cmd *nodeCommand) {
+
+	timeout := n.queueConf.TimeoutFor(cmd.device.Type)
+
+	for {
+		resp, err := n.request(cmd.device, cmd.command, timeout)
+		if err == nil {
+			n.queue.release(cmd.device.Id)
+			cmd.done(resp, nil)
+			return
+		}
+
+		// the node has gone, the command waits for the reconnection
+		if !n.IsConnected() && !cmd.isExpired(time.Now()) && !n.queue.isClosed() {
+			n.queue.requeue(cmd)
+			return
+		}
+
+		if cmd.attempt >= n.queueConf.Retries || cmd.isExpired(time.Now()) || n.queue.isClosed() {
+			n.queue.release(cmd.device.Id)
+			cmd.done(resp, err)
+			return
+		}
+
+		time.Sleep(n.queueConf.backoff(cmd.attempt))
+		cmd.attempt++
+	}
+}
+
+// request publish the command and wait for the response of the node
+func (n *Node) request(device *m.Device, command []byte, timeout time.Duration) (result NodeResponse, err error) {
+
+	requestId := n.requestId.Inc()
+	ch := make(chan *NodeResponse, 1)
+	n.addCh(requestId, device.Id, ch)
+	defer n.delCh(requestId)
 
 	// send message to node
 	msg := &NodeMessage{
+		RequestId:  requestId,
 		DeviceId:   device.Id,
 		DeviceType: device.Type,
 		Properties: device.Properties,
@@ -115,7 +212,7 @@ func (n *Node) Send(device *m.Device, command []byte) (result NodeResponse, err
 	n.MqttPublish(n.topic(fmt.Sprintf("req/device%d", device.Id)), msg)
 
 	// wait response
-	ticker := time.NewTimer(time.Second * 5)
+	ticker := time.NewTimer(timeout)
 	defer ticker.Stop()
 
 	var done bool
@@ -126,7 +223,7 @@ func (n *Node) Send(device *m.Device, command []byte) (result NodeResponse, err
 		select {
 		case <-ticker.C:
 			//log.Debugf("request timeout device(%d)", device.Id)
-			err = errors.New("timeout")
+			err = ErrNodeCommandTimeout
 			done = true
 		case resp := <-ch:
 			if resp == nil {
@@ -138,6 +235,7 @@ func (n *Node) Send(device *m.Device, command []byte) (result NodeResponse, err
 
 			// response from node
 			result = NodeResponse{
+				RequestId:  requestId,
 				DeviceId:   resp.DeviceId,
 				Status:     resp.Status,
 				DeviceType: resp.DeviceType,
@@ -148,32 +246,55 @@ func (n *Node) Send(device *m.Device, command []byte) (result NodeResponse, err
 		}
 	}
 
-	result.Time = time.Since(startTime).Seconds()
-
 	return
 }
 
-func (n *Node) addCh(deviceId int64, ch chan *NodeResponse) {
+func (n *Node) updateQueueMetric() {
+	go n.metric.Update(metrics.NodeUpdateQueue{Id: n.model.Id, Depth: n.queue.depth()})
+}
+
+func (n *Node) addCh(requestId, deviceId int64, ch chan *NodeResponse) {
 	n.chLock.Lock()
 	defer n.chLock.Unlock()
 
-	if _, ok := n.ch[deviceId]; ok {
-		return
+	n.ch[requestId] = &nodeRequest{
+		deviceId: deviceId,
+		ch:       ch,
 	}
+}
 
-	n.ch[deviceId] = ch
+func (n *Node) delCh(requestId int64) {
+	n.chLock.Lock()
+	defer n.chLock.Unlock()
+
+	delete(n.ch, requestId)
 }
 
-func (n *Node) delCh(deviceId int64) {
+// getCh the request waiting for the response, the response without the request id
+// goes to the oldest request of the device
+func (n *Node) getCh(resp *NodeResponse) (ch chan *NodeResponse, ok bool) {
 	n.chLock.Lock()
 	defer n.chLock.Unlock()
 
-	if _, ok := n.ch[deviceId]; !ok {
+	var req *nodeRequest
+	if resp.RequestId != 0 {
+		if req, ok = n.ch[resp.RequestId]; ok {
+			ch = req.ch
+		}
 		return
 	}
 
-	close(n.ch[deviceId])
-	delete(n.ch, deviceId)
+	var requestId int64
+	for id, r := range n.ch {
+		if r.deviceId != resp.DeviceId {
+			continue
+		}
+		if !ok || id < requestId {
+			requestId, ch, ok = id, r.ch, true
+		}
+	}
+
+	return
 }
 
 // Connect ...
@@ -194,20 +315,26 @@ func (n *Node) IsConnected() bool {
 
 func (n *Node) onPublish(client *mqtt.Client, msg mqtt.Message) {
 
-	n.chLock.Lock()
-	defer n.chLock.Unlock()
-
 	resp := &NodeResponse{}
 	if err := json.Unmarshal(msg.Payload, resp); err != nil {
 		log.Error(err.Error())
 		return
 	}
 
-	if _, ok := n.ch[resp.DeviceId]; !ok {
+	// the node echoes the request id, the commands for one device can go in parallel
+	if resp.RequestId != 0 {
+		n.requestIds.Store(true)
+	}
+
+	ch, ok := n.getCh(resp)
+	if !ok {
 		return
 	}
 
-	n.ch[resp.DeviceId] <- resp
+	select {
+	case ch <- resp:
+	default:
+	}
 }
 
 func (n *Node) ping(client *mqtt.Client, msg mqtt.Message) {
@@ -255,7 +382,9 @@ func (n *Node) GetConnStatus() string {
 func (n *Node) GetStat() NodeStat {
 	n.statLock.Lock()
 	defer n.statLock.Unlock()
-	return n.stat
+	stat := n.stat
+	stat.QueueDepth = n.queue.depth()
+	return stat
 }
 
 // UpdateClientParams ...
@@ -284,8 +413,10 @@ func (n *Node) updateStatus() {
 
 	n.modelLock.Lock()
 	modelStatus := n.model.Status
+	name := n.model.Name
 	n.modelLock.Unlock()
 
+	prevStatus := n.stat.ConnStatus
 	if modelStatus == "enabled" {
 		if n.stat.IsConnected {
 			n.stat.ConnStatus = "connected"
@@ -295,9 +426,31 @@ func (n *Node) updateStatus() {
 	} else {
 		n.stat.ConnStatus = "disabled"
 	}
+
+	if n.stat.ConnStatus == "connected" && prevStatus != "connected" {
+		n.events.Publish(NodeConnected{
+			NodeId:    n.model.Id,
+			Name:      name,
+			ChangedAt: time.Now(),
+		})
+	} else if n.stat.ConnStatus != "connected" && prevStatus == "connected" {
+		n.events.Publish(NodeDisconnected{
+			NodeId:    n.model.Id,
+			Name:      name,
+			Status:    n.stat.ConnStatus,
+			ChangedAt: time.Now(),
+		})
+	}
 	go n.metric.Update(metrics.NodeUpdateStatus{Id: n.model.Id, Status: n.stat.ConnStatus})
 }
 
+// SetEvents the connection and the disconnection of the node are published to the bus
+func (n *Node) SetEvents(events *EventBus) {
+	n.statLock.Lock()
+	n.events = events
+	n.statLock.Unlock()
+}
+
 // Model ...
 func (n *Node) Model() *m.Node {
 	n.modelLock.Lock()
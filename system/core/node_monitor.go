@@ -0,0 +1,267 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/notify"
+	"go.uber.org/atomic"
+	"sync"
+	"time"
+)
+
+const (
+	// the error rate is not calculated for the fewer commands
+	nodeAlertMinRequests = 5
+	// the command results are kept for the error rate
+	nodeHealthMaxAge = time.Hour
+)
+
+// nodeHealth the results of the node commands
+type nodeHealth struct {
+	sync.Mutex
+	results []nodeResult
+}
+
+type nodeResult struct {
+	at  time.Time
+	err bool
+}
+
+func (h *nodeHealth) add(now time.Time, err bool) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.results = append(h.results, nodeResult{at: now, err: err})
+
+	var i int
+	for i < len(h.results) && now.Sub(h.results[i].at) > nodeHealthMaxAge {
+		i++
+	}
+	h.results = h.results[i:]
+}
+
+// errorRate the percent of the failed commands in the period
+func (h *nodeHealth) errorRate(now time.Time, period time.Duration) (rate float64, total int) {
+	h.Lock()
+	defer h.Unlock()
+
+	var failed int
+	for _, result := range h.results {
+		if now.Sub(result.at) > period {
+			continue
+		}
+		total++
+		if result.err {
+			failed++
+		}
+	}
+
+	if total > 0 {
+		rate = float64(failed) * 100 / float64(total)
+	}
+
+	return
+}
+
+// nodeAlertKey the alert of the rule for the node
+type nodeAlertKey struct {
+	ruleId int64
+	nodeId int64
+}
+
+// NodeMonitor checks the nodes by the alert rules,
+// the alert and the recovery are sent through the notify service
+type NodeMonitor struct {
+	adaptors  *adaptors.Adaptors
+	notify    *notify.Notify
+	nodes     func() []*Node
+	rulesLock sync.Mutex
+	rules     []*m.NodeAlertRule
+	fired     map[nodeAlertKey]bool
+	isRunning atomic.Bool
+	quit      chan struct{}
+}
+
+// NewNodeMonitor ...
+func NewNodeMonitor(adaptors *adaptors.Adaptors,
+	notify *notify.Notify,
+	nodes func() []*Node) *NodeMonitor {
+	return &NodeMonitor{
+		adaptors: adaptors,
+		notify:   notify,
+		nodes:    nodes,
+		fired:    make(map[nodeAlertKey]bool),
+	}
+}
+
+// Load the enabled rules and start the checks
+func (n *NodeMonitor) Load() (err error) {
+
+	if n.isRunning.Load() {
+		return
+	}
+
+	if err = n.Reload(); err != nil {
+		return
+	}
+
+	n.quit = make(chan struct{})
+	n.isRunning.Store(true)
+
+	go func(quit chan struct{}) {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				n.check(now)
+			case <-quit:
+				return
+			}
+		}
+	}(n.quit)
+
+	return
+}
+
+// Reload the rules after the change
+func (n *NodeMonitor) Reload() (err error) {
+
+	var rules []*m.NodeAlertRule
+	if rules, err = n.adaptors.NodeAlertRule.GetAllEnabled(); err != nil {
+		return
+	}
+
+	n.rulesLock.Lock()
+	n.rules = rules
+	// the alerts of the removed rules are forgotten
+	for key := range n.fired {
+		var exist bool
+		for _, rule := range rules {
+			if rule.Id == key.ruleId {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			delete(n.fired, key)
+		}
+	}
+	n.rulesLock.Unlock()
+
+	return
+}
+
+// Stop ...
+func (n *NodeMonitor) Stop() {
+
+	if !n.isRunning.Load() {
+		return
+	}
+
+	close(n.quit)
+	n.isRunning.Store(false)
+
+	n.rulesLock.Lock()
+	n.fired = make(map[nodeAlertKey]bool)
+	n.rulesLock.Unlock()
+}
+
+func (n *NodeMonitor) check(now time.Time) {
+
+	n.rulesLock.Lock()
+	defer n.rulesLock.Unlock()
+
+	if len(n.rules) == 0 {
+		return
+	}
+
+	for _, node := range n.nodes() {
+		model := node.Model()
+		stat := node.GetStat()
+
+		for _, rule := range n.rules {
+			if !rule.IsApplied(model.Id) {
+				continue
+			}
+
+			var active bool
+			var alert, recovery string
+			switch rule.Kind {
+			case m.NodeAlertOffline:
+				active = stat.ConnStatus == "wait" && now.Sub(stat.LastPing).Seconds() >= rule.Threshold
+				alert = fmt.Sprintf("node \"%s\" is offline since %s", model.Name, stat.LastPing.Format(time.RFC3339))
+				recovery = fmt.Sprintf("node \"%s\" is online", model.Name)
+			case m.NodeAlertErrorRate:
+				rate, total := node.health.errorRate(now, time.Second*time.Duration(rule.Period))
+				active = total >= nodeAlertMinRequests && rate >= rule.Threshold
+				alert = fmt.Sprintf("node \"%s\" error rate is %.1f%% of %d commands in %d seconds", model.Name, rate, total, rule.Period)
+				recovery = fmt.Sprintf("node \"%s\" error rate is %.1f%%", model.Name, rate)
+			default:
+				continue
+			}
+
+			key := nodeAlertKey{ruleId: rule.Id, nodeId: model.Id}
+			switch {
+			case active && !n.fired[key]:
+				n.fired[key] = true
+				n.send(rule, alert)
+			case !active && n.fired[key]:
+				delete(n.fired, key)
+				n.send(rule, recovery)
+			}
+		}
+	}
+}
+
+func (n *NodeMonitor) send(rule *m.NodeAlertRule, text string) {
+
+	log.Warnf("node alert \"%s\": %s", rule.Name, text)
+
+	if n.notify == nil {
+		return
+	}
+
+	var msg notify.IMessage
+	switch rule.Channel {
+	case m.NodeAlertEmail:
+		email := notify.NewEmail()
+		email.To = rule.Address
+		email.Subject = fmt.Sprintf("node alert: %s", rule.Name)
+		email.Body = text
+		msg = email
+	case m.NodeAlertSms:
+		sms := notify.NewSMS()
+		sms.AddPhone(rule.Address)
+		sms.Text = text
+		msg = sms
+	case m.NodeAlertTelegram:
+		msg = notify.NewTelegram(text)
+	case m.NodeAlertSlack:
+		msg = notify.NewSlackMessage(rule.Address, text)
+	default:
+		return
+	}
+
+	go n.notify.Send(msg)
+}
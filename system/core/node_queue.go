@@ -0,0 +1,283 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"sync"
+	"time"
+)
+
+// NodeCommandPriority the lane of the node queue, the high lane is served first
+type NodeCommandPriority int
+
+const (
+	// NodeCommandPriorityLow polling from the flow workers
+	NodeCommandPriorityLow = NodeCommandPriority(iota)
+	// NodeCommandPriorityHigh user actions
+	NodeCommandPriorityHigh
+)
+
+const (
+	nodeCommandTimeoutDefault = 5000 // milliseconds
+	nodeCommandRetriesDefault = 2
+	nodeCommandBackoffDefault = 500 // milliseconds
+	nodeCommandBackoffMax     = 5 * time.Second
+	nodeCommandTtlDefault     = 30 // seconds
+)
+
+var (
+	// ErrNodeCommandTimeout ...
+	ErrNodeCommandTimeout = errors.New("timeout")
+	// ErrNodeCommandExpired the command was not delivered before the expiry time
+	ErrNodeCommandExpired = errors.New("command expired")
+	// ErrNodeRemoved ...
+	ErrNodeRemoved = errors.New("node removed")
+)
+
+// NodeQueueConfig ...
+type NodeQueueConfig struct {
+	Timeout map[common.DeviceType]time.Duration
+	Retries int
+	Backoff time.Duration
+	Ttl     time.Duration
+}
+
+// NewNodeQueueConfig read the queue settings from the application config
+func NewNodeQueueConfig(cfg *config.AppConfig) *NodeQueueConfig {
+
+	conf := &NodeQueueConfig{
+		Timeout: make(map[common.DeviceType]time.Duration),
+		Retries: nodeCommandRetriesDefault,
+		Backoff: time.Millisecond * nodeCommandBackoffDefault,
+		Ttl:     time.Second * nodeCommandTtlDefault,
+	}
+
+	if cfg == nil {
+		return conf
+	}
+
+	for deviceType, timeout := range cfg.NodeCommandTimeout {
+		if timeout > 0 {
+			conf.Timeout[common.DeviceType(deviceType)] = time.Millisecond * time.Duration(timeout)
+		}
+	}
+	if cfg.NodeCommandRetries != 0 {
+		conf.Retries = cfg.NodeCommandRetries
+	}
+	if conf.Retries < 0 {
+		conf.Retries = 0
+	}
+	if cfg.NodeCommandBackoff > 0 {
+		conf.Backoff = time.Millisecond * time.Duration(cfg.NodeCommandBackoff)
+	}
+	if cfg.NodeCommandTtl > 0 {
+		conf.Ttl = time.Second * time.Duration(cfg.NodeCommandTtl)
+	}
+
+	return conf
+}
+
+// TimeoutFor the response timeout of the device type
+func (c *NodeQueueConfig) TimeoutFor(deviceType common.DeviceType) time.Duration {
+	if timeout, ok := c.Timeout[deviceType]; ok {
+		return timeout
+	}
+	if timeout, ok := c.Timeout["default"]; ok {
+		return timeout
+	}
+	return time.Millisecond * nodeCommandTimeoutDefault
+}
+
+// backoff the delay before the next attempt
+func (c *NodeQueueConfig) backoff(attempt int) (delay time.Duration) {
+	delay = c.Backoff
+	for i := 0; i < attempt && delay < nodeCommandBackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > nodeCommandBackoffMax {
+		delay = nodeCommandBackoffMax
+	}
+	return
+}
+
+type nodeCommand struct {
+	device   *m.Device
+	command  []byte
+	priority NodeCommandPriority
+	expireAt time.Time
+	attempt  int
+	result   chan nodeCommandResult
+}
+
+type nodeCommandResult struct {
+	resp NodeResponse
+	err  error
+}
+
+func (c *nodeCommand) isExpired(now time.Time) bool {
+	return !c.expireAt.After(now)
+}
+
+func (c *nodeCommand) done(resp NodeResponse, err error) {
+	c.result <- nodeCommandResult{resp: resp, err: err}
+}
+
+// nodeRequest the request waiting for the response of the node
+type nodeRequest struct {
+	deviceId int64
+	ch       chan *NodeResponse
+}
+
+// nodeQueue the commands waiting for delivery
+type nodeQueue struct {
+	sync.Mutex
+	lanes  [NodeCommandPriorityHigh + 1][]*nodeCommand
+	busy   map[int64]int
+	signal chan struct{}
+	closed bool
+}
+
+func newNodeQueue() *nodeQueue {
+	return &nodeQueue{
+		busy:   make(map[int64]int),
+		signal: make(chan struct{}, 1),
+	}
+}
+
+func (q *nodeQueue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// push add the command to the tail of the lane, false if the queue is closed
+func (q *nodeQueue) push(cmd *nodeCommand) bool {
+	q.Lock()
+	if q.closed {
+		q.Unlock()
+		return false
+	}
+	q.lanes[cmd.priority] = append(q.lanes[cmd.priority], cmd)
+	q.Unlock()
+	q.notify()
+	return true
+}
+
+// requeue return the command to the head of the lane, it keeps the order of the commands
+func (q *nodeQueue) requeue(cmd *nodeCommand) {
+	q.Lock()
+	q.lanes[cmd.priority] = append([]*nodeCommand{cmd}, q.lanes[cmd.priority]...)
+	q.unsafeRelease(cmd.device.Id)
+	q.Unlock()
+	q.notify()
+}
+
+// pop the next command of the highest lane, without parallel
+// the command of the device waits until the previous one is done
+func (q *nodeQueue) pop(parallel bool) *nodeCommand {
+	q.Lock()
+	defer q.Unlock()
+
+	for priority := len(q.lanes) - 1; priority >= 0; priority-- {
+		for i, cmd := range q.lanes[priority] {
+			if !parallel && q.busy[cmd.device.Id] > 0 {
+				continue
+			}
+			q.lanes[priority] = append(q.lanes[priority][:i], q.lanes[priority][i+1:]...)
+			q.busy[cmd.device.Id]++
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+func (q *nodeQueue) release(deviceId int64) {
+	q.Lock()
+	q.unsafeRelease(deviceId)
+	q.Unlock()
+	q.notify()
+}
+
+func (q *nodeQueue) unsafeRelease(deviceId int64) {
+	if q.busy[deviceId]--; q.busy[deviceId] <= 0 {
+		delete(q.busy, deviceId)
+	}
+}
+
+// expired remove the stale commands from the lanes
+func (q *nodeQueue) expired(now time.Time) (list []*nodeCommand) {
+	q.Lock()
+	defer q.Unlock()
+
+	for priority, lane := range q.lanes {
+		var actual []*nodeCommand
+		for _, cmd := range lane {
+			if cmd.isExpired(now) {
+				list = append(list, cmd)
+				continue
+			}
+			actual = append(actual, cmd)
+		}
+		q.lanes[priority] = actual
+	}
+
+	return
+}
+
+// close remove all commands from the lanes, the queue does not accept new commands
+func (q *nodeQueue) close() (list []*nodeCommand) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.closed = true
+
+	for priority, lane := range q.lanes {
+		list = append(list, lane...)
+		q.lanes[priority] = nil
+	}
+
+	return
+}
+
+func (q *nodeQueue) isClosed() bool {
+	q.Lock()
+	defer q.Unlock()
+	return q.closed
+}
+
+// depth the number of the waiting and in flight commands
+func (q *nodeQueue) depth() int {
+	q.Lock()
+	defer q.Unlock()
+
+	var depth int
+	for _, inFlight := range q.busy {
+		depth += inFlight
+	}
+	for _, lane := range q.lanes {
+		depth += len(lane)
+	}
+	return depth
+}
@@ -0,0 +1,431 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkflowStorageNamespace ...
+func WorkflowStorageNamespace(workflowId int64) string {
+	return fmt.Sprintf("workflow.%d", workflowId)
+}
+
+// FlowStorageNamespace ...
+func FlowStorageNamespace(flowId int64) string {
+	return fmt.Sprintf("flow.%d", flowId)
+}
+
+// PersistentStorage key-value storage of the workflows and flows, split into namespaces.
+// The values are kept in memory as they are and written through to the database if persist is enabled,
+// so they survive the restart of the server. The values read back from the database after the restart
+// are decoded from json, e.g. all numbers become float64
+type PersistentStorage struct {
+	sync.Mutex
+	// the database writes are made without the lock of the values, one at a time
+	writeLock  sync.Mutex
+	adaptors   *adaptors.Adaptors
+	persist    bool
+	namespaces map[string]map[string]*m.StorageItem
+}
+
+// NewPersistentStorage ...
+func NewPersistentStorage(adaptors *adaptors.Adaptors, persist bool) *PersistentStorage {
+	return &PersistentStorage{
+		adaptors:   adaptors,
+		persist:    persist,
+		namespaces: make(map[string]map[string]*m.StorageItem),
+	}
+}
+
+// Reset drop the cached namespaces, they will be read from the database again
+func (s *PersistentStorage) Reset() {
+	s.Lock()
+	s.namespaces = make(map[string]map[string]*m.StorageItem)
+	s.Unlock()
+}
+
+// Get value of the key, nil if the key is not exist or expired
+func (s *PersistentStorage) Get(namespace, key string) interface{} {
+
+	item, ok := s.GetItem(namespace, key)
+	if !ok {
+		return nil
+	}
+
+	return item.Value
+}
+
+// GetItem ...
+func (s *PersistentStorage) GetItem(namespace, key string) (item *m.StorageItem, ok bool) {
+
+	s.Lock()
+	cached, ok, expired := s.get(namespace, key)
+	if ok {
+		itemCopy := *cached
+		item = &itemCopy
+	}
+	s.Unlock()
+
+	if expired {
+		if err := s.remove(namespace, key); err != nil {
+			log.Error(err.Error())
+		}
+	}
+
+	return
+}
+
+// Items not expired values of the namespace
+func (s *PersistentStorage) Items(namespace string) (items []*m.StorageItem) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	for _, item := range s.namespace(namespace) {
+		if item.IsExpired(now) {
+			continue
+		}
+		itemCopy := *item
+		items = append(items, &itemCopy)
+	}
+
+	return
+}
+
+// List not expired values of all namespaces, or of the single namespace if it is not empty.
+// Without persistence the values are sorted by the namespace and the key
+func (s *PersistentStorage) List(limit, offset int64, orderBy, sortBy, namespace string) (list []*m.StorageItem, total int64, err error) {
+
+	if s.persist {
+		list, total, err = s.adaptors.StorageItem.List(limit, offset, orderBy, sortBy, namespace)
+		return
+	}
+
+	s.Lock()
+	now := time.Now()
+	all := make([]*m.StorageItem, 0)
+	for name, items := range s.namespaces {
+		if namespace != "" && name != namespace {
+			continue
+		}
+		for _, item := range items {
+			if item.IsExpired(now) {
+				continue
+			}
+			itemCopy := *item
+			all = append(all, &itemCopy)
+		}
+	}
+	s.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Namespace != all[j].Namespace {
+			return all[i].Namespace < all[j].Namespace
+		}
+		return all[i].Key < all[j].Key
+	})
+
+	total = int64(len(all))
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	list = all[offset:end]
+
+	return
+}
+
+// Set store the value, the key expires after ttl, zero ttl means the key never expires.
+// The value is kept in memory even if it can not be written to the database, the error is returned
+func (s *PersistentStorage) Set(namespace, key string, value interface{}, ttl time.Duration) (err error) {
+
+	var expireAt *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expireAt = &t
+	}
+
+	s.Lock()
+	item, err := s.set(namespace, key, value, expireAt)
+	s.Unlock()
+
+	if err != nil {
+		return
+	}
+
+	err = s.save(item)
+
+	return
+}
+
+// Delete ...
+func (s *PersistentStorage) Delete(namespace, key string) (err error) {
+
+	s.Lock()
+	delete(s.namespace(namespace), key)
+	s.Unlock()
+
+	err = s.remove(namespace, key)
+
+	return
+}
+
+// Incr atomically add delta to the numeric value of the key, the missing key is counted from zero.
+// The expiration time of the key is kept
+func (s *PersistentStorage) Incr(namespace, key string, delta float64) (value float64, err error) {
+
+	s.Lock()
+
+	var expireAt *time.Time
+	if item, ok, _ := s.get(namespace, key); ok {
+		if item.Value != nil {
+			var isNumber bool
+			if value, isNumber = storageNumber(item.Value); !isNumber {
+				s.Unlock()
+				err = fmt.Errorf("value of the key '%s' is not a number", key)
+				return
+			}
+		}
+		expireAt = item.ExpireAt
+	}
+
+	value += delta
+	item, err := s.set(namespace, key, value, expireAt)
+	s.Unlock()
+
+	if err != nil {
+		return
+	}
+
+	err = s.save(item)
+
+	return
+}
+
+// CompareAndSet atomically replace the value of the key with value if the current one equal to old,
+// nil old matches the missing key. The expiration time of the key is kept
+func (s *PersistentStorage) CompareAndSet(namespace, key string, old, value interface{}) (swapped bool, err error) {
+
+	var oldJson, currentJson []byte
+	if oldJson, err = json.Marshal(old); err != nil {
+		return
+	}
+
+	s.Lock()
+
+	var current interface{}
+	var expireAt *time.Time
+	if item, ok, _ := s.get(namespace, key); ok {
+		current = item.Value
+		expireAt = item.ExpireAt
+	}
+
+	if currentJson, err = json.Marshal(current); err != nil || !bytes.Equal(oldJson, currentJson) {
+		s.Unlock()
+		return
+	}
+
+	item, err := s.set(namespace, key, value, expireAt)
+	s.Unlock()
+
+	if err != nil {
+		return
+	}
+
+	swapped = true
+	err = s.save(item)
+
+	return
+}
+
+// DeleteExpired remove the expired keys from the memory and the database
+func (s *PersistentStorage) DeleteExpired() (err error) {
+
+	s.Lock()
+	now := time.Now()
+	for _, items := range s.namespaces {
+		for key, item := range items {
+			if item.IsExpired(now) {
+				delete(items, key)
+			}
+		}
+	}
+	s.Unlock()
+
+	if s.persist {
+		s.writeLock.Lock()
+		err = s.adaptors.StorageItem.DeleteExpired()
+		s.writeLock.Unlock()
+	}
+
+	return
+}
+
+// namespace cached values of the namespace, read from the database on the first access
+func (s *PersistentStorage) namespace(namespace string) (items map[string]*m.StorageItem) {
+
+	var ok bool
+	if items, ok = s.namespaces[namespace]; ok {
+		return
+	}
+
+	items = make(map[string]*m.StorageItem)
+	s.namespaces[namespace] = items
+
+	if !s.persist {
+		return
+	}
+
+	list, err := s.adaptors.StorageItem.GetByNamespace(namespace)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	for _, item := range list {
+		items[item.Key] = item
+	}
+
+	return
+}
+
+// get the not expired item, the expired one is removed from memory,
+// the caller removes it from the database without the lock
+func (s *PersistentStorage) get(namespace, key string) (item *m.StorageItem, ok, expired bool) {
+
+	if item, ok = s.namespace(namespace)[key]; !ok {
+		return
+	}
+
+	if expired = item.IsExpired(time.Now()); expired {
+		delete(s.namespace(namespace), key)
+		item, ok = nil, false
+	}
+
+	return
+}
+
+// set replace the value in memory, the item is written to the database by save
+func (s *PersistentStorage) set(namespace, key string, value interface{}, expireAt *time.Time) (item *m.StorageItem, err error) {
+
+	items := s.namespace(namespace)
+
+	now := time.Now()
+	item = &m.StorageItem{
+		Namespace: namespace,
+		Key:       key,
+		Value:     value,
+		ExpireAt:  expireAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if old, ok := items[key]; ok {
+		item.CreatedAt = old.CreatedAt
+	}
+
+	if _, errs := item.Valid(); len(errs) > 0 {
+		err = fmt.Errorf("%s: %s", errs[0].Key, errs[0].Message)
+		return
+	}
+
+	items[key] = item
+
+	return
+}
+
+// save write the item to the database, the item replaced in memory meanwhile is skipped,
+// the newer one is written by its own call
+func (s *PersistentStorage) save(item *m.StorageItem) (err error) {
+
+	if !s.persist {
+		return
+	}
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	s.Lock()
+	current, ok := s.namespaces[item.Namespace][item.Key]
+	s.Unlock()
+
+	if !ok || current != item {
+		return
+	}
+
+	err = s.adaptors.StorageItem.Set(item)
+
+	return
+}
+
+// remove delete the key from the database if it was not set again meanwhile
+func (s *PersistentStorage) remove(namespace, key string) (err error) {
+
+	if !s.persist {
+		return
+	}
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	s.Lock()
+	_, ok := s.namespaces[namespace][key]
+	s.Unlock()
+
+	if ok {
+		return
+	}
+
+	err = s.adaptors.StorageItem.Delete(namespace, key)
+
+	return
+}
+
+// storageNumber the numeric value of the key as float64
+func storageNumber(value interface{}) (number float64, ok bool) {
+
+	ok = true
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int32:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	default:
+		ok = false
+	}
+
+	return
+}
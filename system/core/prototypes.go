@@ -18,6 +18,8 @@
 
 package core
 
+import "context"
+
 // ActionPrototypes ...
 type ActionPrototypes interface {
 	After(*Flow) error
@@ -25,3 +27,9 @@ type ActionPrototypes interface {
 	Before(*Flow) error
 	Type() string
 }
+
+// TimerPrototypes time based elements, they can hold the message for a while,
+// the run is detached from the sender when it is suspended
+type TimerPrototypes interface {
+	Wait(ctx context.Context, element *FlowElement, run *flowRun) (bool, error)
+}
@@ -0,0 +1,343 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"go.uber.org/atomic"
+	"sync"
+	"time"
+)
+
+// ScenarioRules switches the workflow scenarios by the rules,
+// the rules of the workflow are checked by priority, the first rule whose
+// conditions are held for the delay selects the scenario
+type ScenarioRules struct {
+	adaptors     *adaptors.Adaptors
+	deviceStates *DeviceStates
+	workflow     func(workflowId int64) (*Workflow, bool)
+	rulesLock    sync.Mutex
+	rules        []*m.WorkflowScenarioRule
+	since        map[int64]time.Time
+	switchLock   sync.Mutex
+	switched     map[int64]time.Time
+	leftLock     sync.Mutex
+	left         map[int64]map[string]time.Time
+	handlerId    int64
+	isRunning    atomic.Bool
+	quit         chan struct{}
+}
+
+// NewScenarioRules ...
+func NewScenarioRules(adaptors *adaptors.Adaptors,
+	deviceStates *DeviceStates,
+	workflow func(workflowId int64) (*Workflow, bool)) *ScenarioRules {
+	return &ScenarioRules{
+		adaptors:     adaptors,
+		deviceStates: deviceStates,
+		workflow:     workflow,
+		since:        make(map[int64]time.Time),
+		switched:     make(map[int64]time.Time),
+		left:         make(map[int64]map[string]time.Time),
+	}
+}
+
+// Load the enabled rules and start the checks
+func (s *ScenarioRules) Load() (err error) {
+
+	if s.isRunning.Load() {
+		return
+	}
+
+	if err = s.Reload(); err != nil {
+		return
+	}
+
+	s.handlerId = s.deviceStates.Subscribe(s.onDeviceStateChange)
+
+	s.quit = make(chan struct{})
+	s.isRunning.Store(true)
+
+	go func(quit chan struct{}) {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				s.check(now)
+			case <-quit:
+				return
+			}
+		}
+	}(s.quit)
+
+	return
+}
+
+// Reload the rules after the change
+func (s *ScenarioRules) Reload() (err error) {
+
+	var rules []*m.WorkflowScenarioRule
+	if rules, err = s.adaptors.WorkflowScenarioRule.GetAllEnabled(); err != nil {
+		return
+	}
+
+	s.rulesLock.Lock()
+	s.rules = rules
+	since := make(map[int64]time.Time)
+	for _, rule := range rules {
+		if t, ok := s.since[rule.Id]; ok {
+			since[rule.Id] = t
+		}
+	}
+	s.since = since
+	s.rulesLock.Unlock()
+
+	return
+}
+
+// Stop ...
+func (s *ScenarioRules) Stop() {
+
+	if !s.isRunning.Load() {
+		return
+	}
+
+	s.deviceStates.Unsubscribe(s.handlerId)
+
+	close(s.quit)
+	s.isRunning.Store(false)
+
+	s.rulesLock.Lock()
+	s.since = make(map[int64]time.Time)
+	s.rulesLock.Unlock()
+
+	s.leftLock.Lock()
+	s.left = make(map[int64]map[string]time.Time)
+	s.leftLock.Unlock()
+}
+
+// onDeviceStateChange remember the time the device left the state
+func (s *ScenarioRules) onDeviceStateChange(change DeviceStateChange) {
+
+	if change.From == "" {
+		return
+	}
+
+	s.leftLock.Lock()
+	states, ok := s.left[change.DeviceId]
+	if !ok {
+		states = make(map[string]time.Time)
+		s.left[change.DeviceId] = states
+	}
+	states[change.From] = change.ChangedAt
+	s.leftLock.Unlock()
+}
+
+// leftAt the last time the device left one of the states
+func (s *ScenarioRules) leftAt(deviceId int64, states []string) (t time.Time) {
+
+	s.leftLock.Lock()
+	defer s.leftLock.Unlock()
+
+	for _, state := range states {
+		if left, ok := s.left[deviceId][state]; ok && left.After(t) {
+			t = left
+		}
+	}
+
+	return
+}
+
+// Record the scenario switch to the history
+func (s *ScenarioRules) Record(workflowId int64, from, to *m.WorkflowScenario, trigger m.ScenarioTrigger, initiator string) {
+
+	record := &m.WorkflowScenarioHistory{
+		WorkflowId: workflowId,
+		Trigger:    trigger,
+		Initiator:  initiator,
+		CreatedAt:  time.Now(),
+	}
+	if from != nil {
+		record.FromScenarioId = &from.Id
+		record.FromScenario = from.SystemName
+	}
+	if to != nil {
+		record.ToScenarioId = &to.Id
+		record.ToScenario = to.SystemName
+	}
+
+	s.switchLock.Lock()
+	s.switched[workflowId] = record.CreatedAt
+	s.switchLock.Unlock()
+
+	log.Infof("workflow(%d) scenario '%s' -> '%s' by %s '%s'", workflowId, record.FromScenario, record.ToScenario, trigger, initiator)
+
+	if _, err := s.adaptors.WorkflowScenarioHistory.Add(record); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+// lastSwitch the time of the last scenario switch of the workflow
+func (s *ScenarioRules) lastSwitch(workflowId int64) time.Time {
+
+	s.switchLock.Lock()
+	defer s.switchLock.Unlock()
+
+	if t, ok := s.switched[workflowId]; ok {
+		return t
+	}
+
+	var t time.Time
+	if record, err := s.adaptors.WorkflowScenarioHistory.GetLast(workflowId); err == nil {
+		t = record.CreatedAt
+	}
+	s.switched[workflowId] = t
+
+	return t
+}
+
+type scenarioSwitch struct {
+	wf   *Workflow
+	rule *m.WorkflowScenarioRule
+}
+
+func (s *ScenarioRules) check(now time.Time) {
+
+	s.rulesLock.Lock()
+
+	selected := make(map[int64]*m.WorkflowScenarioRule)
+	for _, rule := range s.rules {
+		if !s.isHeld(rule, now) {
+			delete(s.since, rule.Id)
+			continue
+		}
+
+		since, ok := s.since[rule.Id]
+		if !ok {
+			since = now
+			s.since[rule.Id] = since
+		}
+
+		if now.Sub(since) < time.Second*time.Duration(rule.Delay) {
+			continue
+		}
+
+		// the rules are ordered by priority
+		if _, ok = selected[rule.WorkflowId]; !ok {
+			selected[rule.WorkflowId] = rule
+		}
+	}
+
+	s.rulesLock.Unlock()
+
+	switches := make([]scenarioSwitch, 0, len(selected))
+	for workflowId, rule := range selected {
+		wf, ok := s.workflow(workflowId)
+		if !ok || rule.WorkflowScenario == nil {
+			continue
+		}
+
+		if current := wf.scenario(); current != nil && current.Id == rule.WorkflowScenarioId {
+			continue
+		}
+
+		// hysteresis, the previous scenario is kept at least the hold time
+		if now.Sub(s.lastSwitch(workflowId)) < time.Second*time.Duration(rule.Hold) {
+			continue
+		}
+
+		switches = append(switches, scenarioSwitch{wf: wf, rule: rule})
+	}
+
+	for _, sw := range switches {
+		if err := sw.wf.setScenario(sw.rule.WorkflowScenario.SystemName, m.ScenarioTriggerRule, sw.rule.Name); err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+// isHeld all conditions of the rule are held
+func (s *ScenarioRules) isHeld(rule *m.WorkflowScenarioRule, now time.Time) bool {
+
+	if len(rule.Conditions) == 0 {
+		return false
+	}
+
+	for _, condition := range rule.Conditions {
+		if s.isConditionHeld(condition, now) == condition.Not {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *ScenarioRules) isConditionHeld(condition *m.ScenarioCondition, now time.Time) bool {
+
+	switch condition.Kind {
+	case m.ScenarioConditionTime:
+		from, to, err := condition.Window()
+		if err != nil {
+			return false
+		}
+		t := time.Duration(now.Hour())*time.Hour +
+			time.Duration(now.Minute())*time.Minute +
+			time.Duration(now.Second())*time.Second
+		if from <= to {
+			return from == to || (t >= from && t < to)
+		}
+		return t >= from || t < to
+
+	case m.ScenarioConditionDeviceState:
+		for _, deviceId := range condition.DeviceIds {
+			state, err := s.deviceStates.Get(deviceId)
+			if err != nil || state.DeviceState == nil || !scenarioHasState(condition.States, state.DeviceState.SystemName) {
+				return false
+			}
+		}
+		return true
+
+	case m.ScenarioConditionPresence:
+		for _, deviceId := range condition.DeviceIds {
+			state, err := s.deviceStates.Get(deviceId)
+			if err == nil && state.DeviceState != nil && scenarioHasState(condition.States, state.DeviceState.SystemName) {
+				return true
+			}
+			// the device left the presence states not long ago
+			if condition.Timeout > 0 && now.Sub(s.leftAt(deviceId, condition.States)) < time.Second*time.Duration(condition.Timeout) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+func scenarioHasState(states []string, systemName string) bool {
+	for _, state := range states {
+		if state == systemName {
+			return true
+		}
+	}
+	return false
+}
@@ -19,13 +19,19 @@
 package core
 
 import (
+	"errors"
 	"sync"
+	"time"
 )
 
+var errStorageNotPersistent = errors.New("storage is not persistent")
+
 // Storage ...
 type Storage struct {
-	mx   *sync.Mutex
-	pull map[string]interface{}
+	mx         *sync.Mutex
+	pull       map[string]interface{}
+	namespace  string
+	persistent *PersistentStorage
 }
 
 // NewStorage ...
@@ -36,9 +42,21 @@ func NewStorage() Storage {
 	}
 }
 
+// NewNamespaceStorage the storage keeps the values in the namespace of the persistent storage
+func NewNamespaceStorage(namespace string, persistent *PersistentStorage) Storage {
+	storage := NewStorage()
+	storage.namespace = namespace
+	storage.persistent = persistent
+	return storage
+}
+
 // GetVar ...
 func (s *Storage) GetVar(key string) (value interface{}) {
 
+	if s.persistent != nil {
+		return s.persistent.Get(s.namespace, key)
+	}
+
 	s.mx.Lock()
 	if v, ok := s.pull[key]; ok {
 		value = v
@@ -49,12 +67,65 @@ func (s *Storage) GetVar(key string) (value interface{}) {
 	return
 }
 
-// SetVar ...
-func (s *Storage) SetVar(key string, value interface{}) {
+// SetVar store the value, the error of the persistent storage is returned,
+// the value stays in memory anyway
+func (s *Storage) SetVar(key string, value interface{}) error {
+
+	if s.persistent != nil {
+		return s.persistent.Set(s.namespace, key, value, 0)
+	}
 
 	s.mx.Lock()
 	s.pull[key] = value
 	s.mx.Unlock()
+	return nil
+}
+
+// SetVarTTL store the value for the ttl duration
+func (s *Storage) SetVarTTL(key string, value interface{}, ttl time.Duration) error {
+	if s.persistent == nil {
+		return errStorageNotPersistent
+	}
+	return s.persistent.Set(s.namespace, key, value, ttl)
+}
+
+// DelVar ...
+func (s *Storage) DelVar(key string) error {
+
+	if s.persistent != nil {
+		return s.persistent.Delete(s.namespace, key)
+	}
+
+	s.mx.Lock()
+	delete(s.pull, key)
+	s.mx.Unlock()
+	return nil
+}
+
+// IncrVar atomically add delta to the numeric value
+func (s *Storage) IncrVar(key string, delta float64) (float64, error) {
+	if s.persistent == nil {
+		return 0, errStorageNotPersistent
+	}
+	return s.persistent.Incr(s.namespace, key, delta)
+}
+
+// CompareAndSetVar atomically replace the value if the current one equal to old
+func (s *Storage) CompareAndSetVar(key string, old, value interface{}) (bool, error) {
+	if s.persistent == nil {
+		return false, errStorageNotPersistent
+	}
+	return s.persistent.CompareAndSet(s.namespace, key, old, value)
+}
+
+func (s *Storage) vars() (vars map[string]interface{}) {
+	s.mx.Lock()
+	vars = make(map[string]interface{}, len(s.pull))
+	for k, v := range s.pull {
+		vars[k] = v
+	}
+	s.mx.Unlock()
+	return
 }
 
 func (s *Storage) copy(newPull map[string]interface{}) {
@@ -0,0 +1,71 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//ActionPrototypes
+type Throttle struct {
+	sync.Mutex
+	last time.Time
+}
+
+// After ...
+func (m *Throttle) After(flow *Flow) (err error) {
+	return
+}
+
+// Run ...
+func (m *Throttle) Run(flow *Flow) (err error) {
+	return
+}
+
+// Before ...
+func (m *Throttle) Before(flow *Flow) (err error) {
+	return
+}
+
+// Type ...
+func (m *Throttle) Type() string {
+	return "Throttle"
+}
+
+// Wait pass one message per settings.interval seconds, other runs are stopped
+func (m *Throttle) Wait(ctx context.Context, element *FlowElement, run *flowRun) (ok bool, err error) {
+
+	interval := time.Duration(element.Model.Settings.Interval) * time.Second
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	if !m.last.IsZero() && now.Sub(m.last) < interval {
+		err = errFlowRunStopped
+		return
+	}
+
+	m.last = now
+	ok = true
+
+	return
+}
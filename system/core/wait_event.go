@@ -0,0 +1,172 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/e154/smart-home/system/mqtt"
+	"sync"
+	"time"
+)
+
+//ActionPrototypes
+type WaitEvent struct {
+	sync.Mutex
+	client  *mqtt.Client
+	topic   string
+	counter int64
+	waiters map[int64]chan mqtt.Message
+}
+
+// NewWaitEvent subscribe to the mqtt topic or the zigbee2mqtt device topic from the element settings
+func NewWaitEvent(element *FlowElement) (waitEvent *WaitEvent) {
+
+	waitEvent = &WaitEvent{
+		waiters: make(map[int64]chan mqtt.Message),
+	}
+
+	settings := element.Model.Settings
+	flow := element.Flow
+
+	waitEvent.topic = settings.Topic
+	if waitEvent.topic == "" && settings.Zigbee2mqttDeviceId != "" {
+		device, err := flow.adaptors.Zigbee2mqttDevice.GetById(settings.Zigbee2mqttDeviceId)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		if waitEvent.topic, err = flow.zigbee2mqtt.GetTopicByDevice(device); err != nil {
+			log.Error(err.Error())
+			return
+		}
+	}
+
+	if waitEvent.topic == "" || flow.mqtt == nil {
+		return
+	}
+
+	waitEvent.client = flow.mqtt.NewClient(fmt.Sprintf("flow_%v_wait_%v", flow.Model.Name, element.Model.Uuid))
+	if err := waitEvent.client.Subscribe(waitEvent.topic, waitEvent.onPublish); err != nil {
+		log.Error(err.Error())
+	}
+
+	return
+}
+
+// After ...
+func (m *WaitEvent) After(flow *Flow) (err error) {
+	return
+}
+
+// Run ...
+func (m *WaitEvent) Run(flow *Flow) (err error) {
+	return
+}
+
+// Before ...
+func (m *WaitEvent) Before(flow *Flow) (err error) {
+	return
+}
+
+// Type ...
+func (m *WaitEvent) Type() string {
+	return "WaitEvent"
+}
+
+// Wait suspend the run until the message on the topic matches the settings.condition,
+// the condition can use the mqtt message vars:
+//
+// mqtt_topic == 'home/door' && mqtt_payload == 'open'
+//
+// returns false if the settings.timeout seconds passed, the run goes by the false direction
+func (m *WaitEvent) Wait(ctx context.Context, element *FlowElement, run *flowRun) (ok bool, err error) {
+
+	if m.client == nil {
+		err = fmt.Errorf("element '%s': event topic is not defined", element.Model.Name)
+		return
+	}
+
+	events := make(chan mqtt.Message, 10)
+
+	m.Lock()
+	m.counter++
+	id := m.counter
+	m.waiters[id] = events
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		delete(m.waiters, id)
+		m.Unlock()
+	}()
+
+	run.suspend()
+
+	var timeout <-chan time.Time
+	if element.Model.Settings.Timeout > 0 {
+		timer := time.NewTimer(time.Duration(element.Model.Settings.Timeout) * time.Second)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		select {
+		case msg := <-events:
+			run.message.SetVar("mqtt_payload", string(msg.Payload))
+			run.message.SetVar("mqtt_topic", msg.Topic)
+			run.message.SetVar("mqtt_qos", msg.Qos)
+			run.message.SetVar("mqtt_duplicate", msg.Dup)
+
+			if element.Model.Settings.Condition == "" {
+				ok = true
+				return
+			}
+
+			if ok, err = element.Flow.evalCondition(run, element.Model.Settings.Condition); err != nil || ok {
+				return
+			}
+
+		case <-timeout:
+			return
+
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+}
+
+// Remove ...
+func (m *WaitEvent) Remove() {
+	if m.client != nil {
+		m.client.UnsubscribeAll()
+	}
+}
+
+func (m *WaitEvent) onPublish(client *mqtt.Client, msg mqtt.Message) {
+	m.Lock()
+	defer m.Unlock()
+	for _, events := range m.waiters {
+		select {
+		case events <- msg:
+		default:
+		}
+	}
+}
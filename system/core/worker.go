@@ -54,8 +54,9 @@ func NewWorker(model *m.Worker, flow *Flow, cron *cr.Cron) (worker *Worker) {
 }
 
 // Start ...
-func (w *Worker) Start() {
-	w.CronTask = w.cron.NewTask(w.Model.Time, w.Do)
+func (w *Worker) Start() (err error) {
+	w.CronTask, err = w.cron.NewTask(w.Model.Time, w.Do)
+	return
 }
 
 // Stop ...
@@ -110,6 +111,7 @@ func (w *Worker) unsafeRemoveActions() {
 		if cancel, ok := w.cancelFunc[action.Device.Id]; ok {
 			cancel()
 		}
+		action.Close()
 		delete(w.actions, i)
 	}
 }
@@ -117,7 +119,8 @@ func (w *Worker) unsafeRemoveActions() {
 // Run worker script, and send result to flow as message struct
 func (w *Worker) Do() {
 
-	if w.isRuning.Load() || !w.flow.Node.IsConnected() {
+	// the commands of the disconnected node wait in the node queue
+	if w.isRuning.Load() {
 		return
 	}
 
@@ -62,7 +62,7 @@ func NewWorkflow(model *m.Workflow,
 	metric *metrics.MetricManager) (workflow *Workflow) {
 
 	workflow = &Workflow{
-		Storage:     NewStorage(),
+		Storage:     NewNamespaceStorage(WorkflowStorageNamespace(model.Id), core.Storage),
 		model:       model,
 		adaptors:    adaptors,
 		scripts:     scripts,
@@ -128,6 +128,10 @@ func (wf *Workflow) Stop() (err error) {
 
 	err = wf.exitScenario()
 
+	if wf.engine != nil {
+		wf.engine.Close()
+	}
+
 	wf.isRunning = false
 
 	return
@@ -249,9 +253,16 @@ func (wf *Workflow) GetFLow(flowId int64) (flow *Flow, err error) {
 
 // SetScenario ...
 func (wf *Workflow) SetScenario(systemName string) (err error) {
+	err = wf.setScenario(systemName, m.ScenarioTriggerScript, "")
+	return
+}
+
+// setScenario switch the scenario, the switch is recorded to the history with the trigger
+func (wf *Workflow) setScenario(systemName string, trigger m.ScenarioTrigger, initiator string) (err error) {
 
 	wf.Lock()
 	name := wf.model.Name
+	current := wf.model.Scenario
 	scenarios := wf.model.Scenarios
 	wf.Unlock()
 
@@ -270,6 +281,22 @@ func (wf *Workflow) SetScenario(systemName string) (err error) {
 			return
 		}
 
+		if current == nil || current.SystemName != systemName {
+			wf.core.ScenarioRules.Record(workflow.Id, current, scenario, trigger, initiator)
+
+			event := ScenarioChanged{
+				WorkflowId: workflow.Id,
+				To:         systemName,
+				Trigger:    trigger,
+				Initiator:  initiator,
+				ChangedAt:  time.Now(),
+			}
+			if current != nil {
+				event.From = current.SystemName
+			}
+			wf.core.Events.Publish(event)
+		}
+
 		wf.nextScenario = scenario
 
 		break
@@ -299,6 +326,13 @@ func (wf *Workflow) SetScenario(systemName string) (err error) {
 	return
 }
 
+// scenario the current scenario of the workflow
+func (wf *Workflow) scenario() *m.WorkflowScenario {
+	wf.Lock()
+	defer wf.Unlock()
+	return wf.model.Scenario
+}
+
 func (wf *Workflow) enterScenario() (err error) {
 
 	wf.Lock()
@@ -411,6 +445,11 @@ func (wf *Workflow) runScenarioScripts(f string) (err error) {
 
 func (wf *Workflow) runScripts() (err error) {
 
+	// the event subscriptions of the previous run are dropped
+	if wf.engine != nil {
+		wf.engine.Close()
+	}
+
 	dummy := &m.Script{
 		Lang: common.ScriptLangJavascript,
 	}
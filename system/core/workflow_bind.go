@@ -18,13 +18,19 @@
 
 package core
 
+import "time"
+
 // Javascript Binding
 //
 //Workflow
 //	 .GetName()
 //	 .GetDescription()
-//	 .SetVar(string, interface)
+//	 .SetVar(string, interface) -> error
 //	 .GetVar(string)
+//	 .SetVarTTL(string, interface, seconds) -> error
+//	 .DelVar(string) -> error
+//	 .IncrVar(string, number) -> number
+//	 .CompareAndSetVar(string, old, new) -> bool
 //	 .GetScenario() string
 //	 .GetScenarioName() string
 //	 .SetScenario(string)
@@ -44,8 +50,8 @@ func (w *WorkflowBind) GetDescription() string {
 }
 
 // SetVar ...
-func (w *WorkflowBind) SetVar(key string, value interface{}) {
-	w.wf.SetVar(key, value)
+func (w *WorkflowBind) SetVar(key string, value interface{}) error {
+	return w.wf.SetVar(key, value)
 }
 
 // GetVar ...
@@ -53,6 +59,26 @@ func (w *WorkflowBind) GetVar(key string) interface{} {
 	return w.wf.GetVar(key)
 }
 
+// SetVarTTL ...
+func (w *WorkflowBind) SetVarTTL(key string, value interface{}, seconds int64) error {
+	return w.wf.SetVarTTL(key, value, time.Duration(seconds)*time.Second)
+}
+
+// DelVar ...
+func (w *WorkflowBind) DelVar(key string) error {
+	return w.wf.DelVar(key)
+}
+
+// IncrVar ...
+func (w *WorkflowBind) IncrVar(key string, delta float64) (float64, error) {
+	return w.wf.IncrVar(key, delta)
+}
+
+// CompareAndSetVar ...
+func (w *WorkflowBind) CompareAndSetVar(key string, old, value interface{}) (bool, error) {
+	return w.wf.CompareAndSetVar(key, old, value)
+}
+
 // GetScenario ...
 func (w *WorkflowBind) GetScenario() string {
 	return w.wf.model.Scenario.SystemName
@@ -65,6 +91,7 @@ func (w *WorkflowBind) GetScenarioName() string {
 
 // SetScenario ...
 func (w *WorkflowBind) SetScenario(system_name string) {
-	//bug if call this method from scenario
+	// the workflow script calls it from the engine that runs the scenario scripts,
+	// they wait for the end of the calling script
 	go w.wf.SetScenario(system_name)
 }
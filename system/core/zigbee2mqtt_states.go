@@ -0,0 +1,113 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+	"reflect"
+	"sync"
+)
+
+// zigbee2mqttStates the states generated from the exposes follow the state payloads of the zigbee2mqtt devices,
+// only the changed values of the binary and the enum features switch the state of the device
+type zigbee2mqttStates struct {
+	sync.Mutex
+	adaptors     *adaptors.Adaptors
+	deviceStates *DeviceStates
+	values       map[string]map[string]interface{}
+}
+
+func newZigbee2mqttStates(adaptors *adaptors.Adaptors, deviceStates *DeviceStates) *zigbee2mqttStates {
+	return &zigbee2mqttStates{
+		adaptors:     adaptors,
+		deviceStates: deviceStates,
+		values:       make(map[string]map[string]interface{}),
+	}
+}
+
+func (z *zigbee2mqttStates) onState(state zigbee2mqtt.DeviceState) {
+
+	changed := z.changed(state.DeviceId, state.Payload)
+	if len(changed) == 0 {
+		return
+	}
+
+	// the device is not known yet
+	zigbeeDevice, err := z.adaptors.Zigbee2mqttDevice.GetById(state.DeviceId)
+	if err != nil {
+		return
+	}
+
+	names := zigbee2mqtt.NewCapabilities(zigbeeDevice.Exposes).MatchStates(changed)
+	if len(names) == 0 {
+		return
+	}
+
+	var devices []*m.Device
+	if devices, err = z.adaptors.Device.GetByZigbee2mqttDeviceId(state.DeviceId); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	for _, device := range devices {
+		for _, name := range names {
+			if !deviceHasState(device, name) {
+				continue
+			}
+			if err = z.deviceStates.Set(device.Id, name); err != nil {
+				log.Warn(err.Error())
+			}
+		}
+	}
+}
+
+// changed the values of the payload which differ from the previous payload of the device
+func (z *zigbee2mqttStates) changed(deviceId string, payload map[string]interface{}) (changed map[string]interface{}) {
+
+	z.Lock()
+	defer z.Unlock()
+
+	values, ok := z.values[deviceId]
+	if !ok {
+		values = make(map[string]interface{})
+		z.values[deviceId] = values
+	}
+
+	changed = make(map[string]interface{})
+	for property, value := range payload {
+		if old, ok := values[property]; ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		values[property] = value
+		changed[property] = value
+	}
+
+	return
+}
+
+func deviceHasState(device *m.Device, systemName string) bool {
+	for _, state := range device.States {
+		if state.SystemName == systemName {
+			return true
+		}
+	}
+	return false
+}
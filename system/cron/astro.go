@@ -0,0 +1,122 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package cron
+
+import (
+	"math"
+	"time"
+)
+
+// SunEvent ...
+type SunEvent string
+
+const (
+	// Sunrise upper edge of the sun touches the horizon
+	Sunrise = SunEvent("sunrise")
+	// Sunset ...
+	Sunset = SunEvent("sunset")
+	// Dawn beginning of the civil twilight, the sun is 6° below the horizon
+	Dawn = SunEvent("dawn")
+	// Dusk end of the civil twilight
+	Dusk = SunEvent("dusk")
+)
+
+const (
+	// julian day of 2000-01-01 12:00 UTC
+	j2000 = 2451545.0
+	// julian day of the unix epoch
+	jUnix = 2440587.5
+
+	// sun altitude with atmospheric refraction and the solar disc radius
+	horizonAltitude = -0.833
+	civilAltitude   = -6.0
+
+	earthObliquity = 23.4397
+)
+
+// SunTime time of the event on the day of date at the given coordinates, computed by the sunrise equation,
+// ok is false if the sun does not cross the altitude on that day (polar day or night)
+func SunTime(event SunEvent, date time.Time, lat, lon float64) (t time.Time, ok bool) {
+
+	noon := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, date.Location())
+
+	// mean solar time of the day
+	n := math.Round(toJulian(noon) - j2000 + lon/360)
+	jStar := n - lon/360
+
+	// solar mean anomaly
+	m := math.Mod(357.5291+0.98560028*jStar, 360)
+	mRad := radians(m)
+
+	// equation of the center
+	c := 1.9148*math.Sin(mRad) + 0.02*math.Sin(2*mRad) + 0.0003*math.Sin(3*mRad)
+
+	// ecliptic longitude
+	lambda := radians(math.Mod(m+c+180+102.9372, 360))
+
+	transit := j2000 + jStar + 0.0053*math.Sin(mRad) - 0.0069*math.Sin(2*lambda)
+
+	// declination of the sun
+	sinDelta := math.Sin(lambda) * math.Sin(radians(earthObliquity))
+	cosDelta := math.Cos(math.Asin(sinDelta))
+
+	altitude := horizonAltitude
+	if event == Dawn || event == Dusk {
+		altitude = civilAltitude
+	}
+
+	// hour angle
+	latRad := radians(lat)
+	cosOmega := (math.Sin(radians(altitude)) - math.Sin(latRad)*sinDelta) / (math.Cos(latRad) * cosDelta)
+	if cosOmega < -1 || cosOmega > 1 {
+		return
+	}
+	omega := degrees(math.Acos(cosOmega))
+
+	var j float64
+	switch event {
+	case Sunrise, Dawn:
+		j = transit - omega/360
+	case Sunset, Dusk:
+		j = transit + omega/360
+	default:
+		return
+	}
+
+	t = fromJulian(j).In(date.Location())
+	ok = true
+
+	return
+}
+
+func toJulian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + jUnix
+}
+
+func fromJulian(j float64) time.Time {
+	return time.Unix(0, int64((j-jUnix)*86400*float64(time.Second))).Truncate(time.Second)
+}
+
+func radians(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+func degrees(r float64) float64 {
+	return r * 180 / math.Pi
+}
@@ -0,0 +1,107 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunTime(t *testing.T) {
+
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the published times of the civil twilight, the sunrise and the sunset
+	tests := []struct {
+		name     string
+		event    SunEvent
+		lat, lon float64
+		expected time.Time
+	}{
+		{"london summer dawn", Dawn, 51.5074, -0.1278, time.Date(2020, 6, 21, 3, 57, 0, 0, london)},
+		{"london summer sunrise", Sunrise, 51.5074, -0.1278, time.Date(2020, 6, 21, 4, 43, 0, 0, london)},
+		{"london summer sunset", Sunset, 51.5074, -0.1278, time.Date(2020, 6, 21, 21, 21, 0, 0, london)},
+		{"london summer dusk", Dusk, 51.5074, -0.1278, time.Date(2020, 6, 21, 22, 7, 0, 0, london)},
+		{"new york winter dawn", Dawn, 40.7128, -74.0060, time.Date(2020, 12, 21, 6, 45, 0, 0, newYork)},
+		{"new york winter sunrise", Sunrise, 40.7128, -74.0060, time.Date(2020, 12, 21, 7, 16, 0, 0, newYork)},
+		{"new york winter sunset", Sunset, 40.7128, -74.0060, time.Date(2020, 12, 21, 16, 31, 0, 0, newYork)},
+		{"new york winter dusk", Dusk, 40.7128, -74.0060, time.Date(2020, 12, 21, 17, 3, 0, 0, newYork)},
+	}
+
+	for _, test := range tests {
+		date := time.Date(test.expected.Year(), test.expected.Month(), test.expected.Day(), 0, 0, 0, 0, test.expected.Location())
+		sun, ok := SunTime(test.event, date, test.lat, test.lon)
+		if !ok {
+			t.Errorf("%s: no event", test.name)
+			continue
+		}
+		if diff := sun.Sub(test.expected); diff < -3*time.Minute || diff > 3*time.Minute {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, sun)
+		}
+	}
+
+	// polar day and polar night in Tromsø
+	if _, ok := SunTime(Sunset, time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553); ok {
+		t.Error("polar day: unexpected sunset")
+	}
+	if _, ok := SunTime(Sunrise, time.Date(2020, 12, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553); ok {
+		t.Error("polar night: unexpected sunrise")
+	}
+}
+
+func TestScheduleNextSun(t *testing.T) {
+
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule, err := Parse("CRON_TZ=Europe/London @sunset +30m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule.SetCoordinates(51.5074, -0.1278)
+
+	sunset, _ := SunTime(Sunset, time.Date(2020, 6, 21, 0, 0, 0, 0, london), 51.5074, -0.1278)
+
+	// before and after the fire time of the day
+	if next := schedule.Next(time.Date(2020, 6, 21, 12, 0, 0, 0, london)); !next.Equal(sunset.Add(30 * time.Minute)) {
+		t.Errorf("expected %v, got %v", sunset.Add(30*time.Minute), next)
+	}
+	if next := schedule.Next(sunset.Add(time.Hour)); next.Day() != 22 {
+		t.Errorf("expected the next day, got %v", next)
+	}
+
+	// the sun does not set in the polar day, the search goes on to the autumn
+	schedule, err = Parse("TZ=UTC @sunset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule.SetCoordinates(69.6492, 18.9553)
+	if next := schedule.Next(time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC)); next.IsZero() || next.Month() != time.July {
+		t.Errorf("expected the sunset in july, got %v", next)
+	}
+}
@@ -19,13 +19,15 @@
 package cron
 
 import (
-	"log"
-	"strconv"
-	"strings"
+	"github.com/e154/smart-home/common"
 	"sync"
 	"time"
 )
 
+var (
+	log = common.MustGetLogger("cron")
+)
+
 const (
 	// SECOND ...
 	SECOND int = iota
@@ -56,155 +58,59 @@ type Cron struct {
 	quit      chan bool
 	StartTime time.Time
 	Uptime    time.Duration
+	latitude  float64
+	longitude float64
 }
 
-func (c *Cron) stringParse(args string) (result []int) {
-
-	result = make([]int, 0)
-	commas := strings.Split(args, ",")
-	if len(commas) != 0 {
-		for _, str := range commas {
-			sec := c.rangeParse(str)
-			result = append(result, sec...)
-		}
-	} else {
-		sec := c.rangeParse(args)
-		result = append(result, sec...)
-	}
-
-	return
+// SetCoordinates latitude and longitude used by the astronomical time strings
+func (c *Cron) SetCoordinates(lat, lon float64) *Cron {
+	c.Lock()
+	c.latitude = lat
+	c.longitude = lon
+	c.Unlock()
+	return c
 }
 
-func (c *Cron) rangeParse(str string) (result []int) {
+// Parse time string with the cron coordinates
+func (c *Cron) Parse(t string) (schedule *Schedule, err error) {
 
-	// [0-9] - [0-9]
-	args := strings.Split(str, "-")
-	if len(args) > 1 {
-		min, _ := strconv.ParseInt(args[0], 0, 10)
-		max, _ := strconv.ParseInt(args[1], 0, 60)
-		if len(args) != 0 {
-			for i := min; i <= max; i++ {
-				result = append(result, int(i))
-			}
-		}
+	if schedule, err = Parse(t); err != nil {
+		return
 	}
 
-	// [*] / [0-9]
-	//...
+	c.Lock()
+	schedule.SetCoordinates(c.latitude, c.longitude)
+	c.Unlock()
 
-	// [0-9]
-	if i, err := strconv.ParseInt(str, 0, 10); err == nil {
-		result = append(result, int(i))
+	// 0,0 is the default of the config, not the real place
+	if schedule.sun != "" && schedule.latitude == 0 && schedule.longitude == 0 {
+		log.Errorf("time string '%s': the coordinates are not set (lat, lon of the config), the sun events are computed for 0,0", t)
 	}
 
 	return
 }
 
-func (c *Cron) timeParser(t string) (result map[int][]int) {
-	result = make(map[int][]int)
-	args := strings.Split(t, " ")
+// NewTask ...
+func (c *Cron) NewTask(t string, h func()) (task *Task, err error) {
 
-	if len(args) != 6 {
-		log.Println("error: bad time string")
+	var schedule *Schedule
+	if schedule, err = c.Parse(t); err != nil {
 		return
 	}
 
-	// WEEKDAY
-	// ------------------------------------------------------
-	if args[WEEKDAY] == "*" {
-		result[WEEKDAY] = []int{}
-		for i := 0; i < 7; i++ {
-			result[WEEKDAY] = append(result[WEEKDAY], i)
-		}
-
-	} else {
-		weekdays := c.stringParse(args[WEEKDAY])
-		result[WEEKDAY] = append(result[WEEKDAY], weekdays...)
-	}
-
-	// MONTH
-	// ------------------------------------------------------
-	if args[MONTH] == "*" {
-		result[MONTH] = []int{}
-		for i := 1; i < 13; i++ {
-			result[MONTH] = append(result[MONTH], i)
-		}
-
-	} else {
-		months := c.stringParse(args[MONTH])
-		result[MONTH] = append(result[MONTH], months...)
-	}
-
-	// DAY
-	// ------------------------------------------------------
-	if args[DAY] == "*" {
-		result[DAY] = []int{}
-		for i := 1; i < 32; i++ {
-			result[DAY] = append(result[DAY], i)
-		}
-
-	} else {
-		days := c.stringParse(args[DAY])
-		result[DAY] = append(result[DAY], days...)
-	}
-
-	// HOUR
-	// ------------------------------------------------------
-	if args[HOUR] == "*" {
-		result[HOUR] = []int{}
-		for i := 0; i < 24; i++ {
-			result[HOUR] = append(result[HOUR], i)
-		}
-
-	} else {
-		hours := c.stringParse(args[HOUR])
-		result[HOUR] = append(result[HOUR], hours...)
-	}
-
-	// MINUTES
-	// ------------------------------------------------------
-	if args[MINUTE] == "*" {
-		result[MINUTE] = []int{}
-		for i := 0; i < 60; i++ {
-			result[MINUTE] = append(result[MINUTE], i)
-		}
-
-	} else {
-		minutes := c.stringParse(args[MINUTE])
-		result[MINUTE] = append(result[MINUTE], minutes...)
-	}
-
-	// SECONDS
-	// ------------------------------------------------------
-	if args[SECOND] == "*" {
-		result[SECOND] = []int{}
-		for i := 0; i < 60; i++ {
-			result[SECOND] = append(result[SECOND], i)
-		}
-
-	} else {
-		sec := c.stringParse(args[SECOND])
-		result[SECOND] = append(result[SECOND], sec...)
-	}
-
-	return
-}
-
-// NewTask ...
-func (c *Cron) NewTask(t string, h func()) *Task {
-	_time := c.timeParser(t)
-	task := &Task{
-		_time:   _time,
-		_func:   h,
-		cron:    c,
-		enabled: true,
+	task = &Task{
+		schedule: schedule,
+		next:     schedule.Next(time.Now()),
+		_func:    h,
+		cron:     c,
+		enabled:  true,
 	}
 
 	c.Lock()
 	c.tasks[task] = false
 	c.Unlock()
 
-	return task
+	return
 }
 
 // RemoveTask ...
@@ -223,13 +129,6 @@ func (c *Cron) RemoveTask(task *Task) {
 func (c *Cron) timePrepare(t time.Time) {
 
 	c.Uptime = t.Sub(c.StartTime)
-	var ct *Timer = &Timer{}
-	ct.second = t.Second()
-	ct.min = t.Minute()
-	ct.hour = t.Hour()
-	ct.weekday = t.Weekday()
-	ct.day = t.Day()
-	ct.month = t.Month()
 
 	// tasks
 	//-----------------------------------
@@ -241,7 +140,7 @@ func (c *Cron) timePrepare(t time.Time) {
 		if !task.Enabled() {
 			continue
 		}
-		go task.exec(ct)
+		go task.exec(t)
 	}
 }
 
@@ -0,0 +1,529 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// how far the Next searches the fire time
+const searchYears = 5
+
+type bounds struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	secondBounds = bounds{name: "second", min: 0, max: 59}
+	minuteBounds = bounds{name: "minute", min: 0, max: 59}
+	hourBounds   = bounds{name: "hour", min: 0, max: 23}
+	dayBounds    = bounds{name: "day", min: 1, max: 31}
+	monthBounds  = bounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	weekdayBounds = bounds{name: "weekday", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Schedule parsed time string
+type Schedule struct {
+	spec     string
+	location *time.Location
+	every    time.Duration
+
+	// @sunrise +30m
+	sun       SunEvent
+	offset    time.Duration
+	latitude  float64
+	longitude float64
+
+	second  uint64
+	minute  uint64
+	hour    uint64
+	day     uint64
+	month   uint64
+	weekday uint64
+
+	// day: L, LW, 15W
+	lastDay        bool
+	lastWorkday    bool
+	nearestWorkday []int
+	// weekday: 5L
+	lastWeekday uint64
+	// weekday: 5#3
+	nthWeekday []nthWeekday
+}
+
+// nthWeekday the n-th weekday of the month
+type nthWeekday struct {
+	weekday time.Weekday
+	n       int
+}
+
+// Parse time string of six fields:
+//
+//	second minute hour day month weekday
+//
+// each field accepts * ? , - / and numbers, month and weekday accept names (JAN-DEC, SUN-SAT),
+// day accepts L (last day of the month), LW (last workday) and 15W (workday nearest to the 15th),
+// weekday accepts 5L (last friday of the month) and 5#3 (third friday of the month).
+// The day and the weekday must both match, unlike the classic cron that fires on any of them
+// when both are restricted: "0 0 0 13 * FRI" is friday the 13th.
+//
+// Macros: @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly, @every 1h30m
+//
+// Astronomical macros with an optional offset: @sunrise, @sunset, @dawn, @dusk, e.g. @sunset +30m,
+// the coordinates are set by SetCoordinates
+//
+// The time zone is set by prefix, the local time zone is used by default:
+//
+//	CRON_TZ=Europe/Moscow 0 30 7 * * MON-FRI
+func Parse(spec string) (schedule *Schedule, err error) {
+
+	schedule = &Schedule{
+		spec:     spec,
+		location: time.Local,
+	}
+
+	str := strings.TrimSpace(spec)
+	if strings.HasPrefix(str, "CRON_TZ=") || strings.HasPrefix(str, "TZ=") {
+		i := strings.Index(str, " ")
+		if i == -1 {
+			err = fmt.Errorf("time string '%s': no fields after the time zone", spec)
+			return
+		}
+		tz := str[strings.Index(str, "=")+1 : i]
+		if schedule.location, err = time.LoadLocation(tz); err != nil {
+			err = fmt.Errorf("time string '%s': bad time zone '%s'", spec, tz)
+			return
+		}
+		str = strings.TrimSpace(str[i:])
+	}
+
+	if strings.HasPrefix(str, "@every ") {
+		if schedule.every, err = time.ParseDuration(strings.TrimSpace(str[len("@every "):])); err != nil {
+			err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+			return
+		}
+		if schedule.every < time.Second {
+			err = fmt.Errorf("time string '%s': interval less than one second", spec)
+		}
+		return
+	}
+
+	if sun, offset, ok := splitSunMacro(str); ok {
+		schedule.sun = SunEvent(sun)
+		if offset != "" {
+			if schedule.offset, err = time.ParseDuration(offset); err != nil {
+				err = fmt.Errorf("time string '%s': bad offset '%s'", spec, offset)
+				return
+			}
+			if schedule.offset <= -24*time.Hour || schedule.offset >= 24*time.Hour {
+				err = fmt.Errorf("time string '%s': offset out of range", spec)
+			}
+		}
+		return
+	}
+
+	if strings.HasPrefix(str, "@") {
+		var ok bool
+		if str, ok = macros[str]; !ok {
+			err = fmt.Errorf("time string '%s': unknown macro", spec)
+			return
+		}
+	}
+
+	fields := strings.Fields(str)
+	if len(fields) != 6 {
+		err = fmt.Errorf("time string '%s': expected 6 fields, got %d", spec, len(fields))
+		return
+	}
+
+	if schedule.second, err = parseField(fields[SECOND], secondBounds); err != nil {
+		err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+		return
+	}
+	if schedule.minute, err = parseField(fields[MINUTE], minuteBounds); err != nil {
+		err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+		return
+	}
+	if schedule.hour, err = parseField(fields[HOUR], hourBounds); err != nil {
+		err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+		return
+	}
+	if err = schedule.parseDay(fields[DAY]); err != nil {
+		err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+		return
+	}
+	if schedule.month, err = parseField(fields[MONTH], monthBounds); err != nil {
+		err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+		return
+	}
+	if err = schedule.parseWeekday(fields[WEEKDAY]); err != nil {
+		err = fmt.Errorf("time string '%s': %s", spec, err.Error())
+		return
+	}
+
+	if schedule.Next(time.Now()).IsZero() {
+		err = fmt.Errorf("time string '%s': never fires", spec)
+	}
+
+	return
+}
+
+// String ...
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Location ...
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// SetCoordinates latitude and longitude in degrees for the astronomical macros
+func (s *Schedule) SetCoordinates(lat, lon float64) *Schedule {
+	s.latitude = lat
+	s.longitude = lon
+	return s
+}
+
+// Next returns the first fire time after t, zero time if there is no such time
+// in the next five years
+func (s *Schedule) Next(t time.Time) time.Time {
+
+	if s.every > 0 {
+		return t.Truncate(time.Second).Add(s.every)
+	}
+
+	if s.sun != "" {
+		return s.nextSun(t)
+	}
+
+	t = t.In(s.location).Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + searchYears
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for !hasBit(s.month, int(t.Month())) {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for !hasBit(s.hour, t.Hour()) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for !hasBit(s.minute, t.Minute()) {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for !hasBit(s.second, t.Second()) {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// nextSun the sun does not rise or set every day near the poles, so the search covers a whole year
+func (s *Schedule) nextSun(t time.Time) time.Time {
+
+	t = t.In(s.location)
+	date := time.Date(t.Year(), t.Month(), t.Day()-1, 0, 0, 0, 0, s.location)
+	for i := 0; i < 368; i++ {
+		if sun, ok := SunTime(s.sun, date.AddDate(0, 0, i), s.latitude, s.longitude); ok {
+			if next := sun.Add(s.offset); next.After(t) {
+				return next
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches both of the day and weekday fields must match, as in the time strings of the previous versions
+func (s *Schedule) dayMatches(t time.Time) bool {
+
+	lastDay := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, s.location).Day()
+
+	day := hasBit(s.day, t.Day()) ||
+		(s.lastDay && t.Day() == lastDay) ||
+		(s.lastWorkday && t.Day() == nearestWorkday(t, lastDay, lastDay))
+	for _, d := range s.nearestWorkday {
+		if t.Day() == nearestWorkday(t, d, lastDay) {
+			day = true
+		}
+	}
+
+	weekday := hasBit(s.weekday, int(t.Weekday())) ||
+		(hasBit(s.lastWeekday, int(t.Weekday())) && t.Day()+7 > lastDay)
+	for _, nth := range s.nthWeekday {
+		if t.Weekday() == nth.weekday && (t.Day()-1)/7+1 == nth.n {
+			weekday = true
+		}
+	}
+
+	return day && weekday
+}
+
+func (s *Schedule) parseDay(field string) (err error) {
+
+	var parts []string
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case part == "L":
+			s.lastDay = true
+		case part == "LW":
+			s.lastWorkday = true
+		case strings.HasSuffix(part, "W"):
+			var d int
+			if d, err = parseValue(strings.TrimSuffix(part, "W"), dayBounds); err != nil {
+				return
+			}
+			s.nearestWorkday = append(s.nearestWorkday, d)
+		default:
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) > 0 {
+		s.day, err = parseField(strings.Join(parts, ","), dayBounds)
+	}
+
+	return
+}
+
+func (s *Schedule) parseWeekday(field string) (err error) {
+
+	var parts []string
+	for _, part := range strings.Split(field, ",") {
+		if len(part) > 1 && strings.HasSuffix(part, "L") {
+			var d int
+			if d, err = parseValue(strings.TrimSuffix(part, "L"), weekdayBounds); err != nil {
+				return
+			}
+			s.lastWeekday |= 1 << uint(d%7)
+			continue
+		}
+		if i := strings.Index(part, "#"); i != -1 {
+			var d, n int
+			if d, err = parseValue(part[:i], weekdayBounds); err != nil {
+				return
+			}
+			if n, err = strconv.Atoi(part[i+1:]); err != nil || n < 1 || n > 5 {
+				err = fmt.Errorf("%s: bad value '%s'", weekdayBounds.name, part)
+				return
+			}
+			s.nthWeekday = append(s.nthWeekday, nthWeekday{weekday: time.Weekday(d % 7), n: n})
+			continue
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return
+	}
+
+	if s.weekday, err = parseField(strings.Join(parts, ","), weekdayBounds); err != nil {
+		return
+	}
+
+	// 7 is sunday too
+	if hasBit(s.weekday, 7) {
+		s.weekday = s.weekday&^(1<<7) | 1
+	}
+
+	return
+}
+
+// parseField comma separated list of ranges: * ? 5 1-5 */15 10/5 1-30/2
+func parseField(field string, b bounds) (bits uint64, err error) {
+
+	for _, part := range strings.Split(field, ",") {
+		var r uint64
+		if r, err = parseRange(part, b); err != nil {
+			return
+		}
+		bits |= r
+	}
+
+	return
+}
+
+func parseRange(part string, b bounds) (bits uint64, err error) {
+
+	rangeAndStep := strings.Split(part, "/")
+	if len(rangeAndStep) > 2 {
+		err = fmt.Errorf("%s: bad step '%s'", b.name, part)
+		return
+	}
+
+	step := 1
+	if len(rangeAndStep) == 2 {
+		if step, err = strconv.Atoi(rangeAndStep[1]); err != nil || step <= 0 {
+			err = fmt.Errorf("%s: bad step '%s'", b.name, part)
+			return
+		}
+	}
+
+	var low, high int
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+	switch {
+	case rangeAndStep[0] == "*" || rangeAndStep[0] == "?":
+		low, high = b.min, b.max
+		if b.names != nil && b.min == 0 {
+			// weekday 7 is the same as 0
+			high = 6
+		}
+	case len(lowAndHigh) == 1:
+		if low, err = parseValue(lowAndHigh[0], b); err != nil {
+			return
+		}
+		high = low
+		if len(rangeAndStep) == 2 {
+			high = b.max
+		}
+	case len(lowAndHigh) == 2:
+		if low, err = parseValue(lowAndHigh[0], b); err != nil {
+			return
+		}
+		if high, err = parseValue(lowAndHigh[1], b); err != nil {
+			return
+		}
+	default:
+		err = fmt.Errorf("%s: bad range '%s'", b.name, part)
+		return
+	}
+
+	if low > high {
+		err = fmt.Errorf("%s: bad range '%s'", b.name, part)
+		return
+	}
+
+	for i := low; i <= high; i += step {
+		bits |= 1 << uint(i)
+	}
+
+	return
+}
+
+func parseValue(str string, b bounds) (value int, err error) {
+
+	if v, ok := b.names[strings.ToUpper(str)]; ok {
+		value = v
+		return
+	}
+
+	if value, err = strconv.Atoi(str); err != nil {
+		err = fmt.Errorf("%s: bad value '%s'", b.name, str)
+		return
+	}
+
+	if value < b.min || value > b.max {
+		err = fmt.Errorf("%s: value %d out of range %d-%d", b.name, value, b.min, b.max)
+	}
+
+	return
+}
+
+// splitSunMacro "@sunset +30m" -> "sunset", "+30m"
+func splitSunMacro(str string) (sun, offset string, ok bool) {
+
+	for _, event := range []SunEvent{Sunrise, Sunset, Dawn, Dusk} {
+		macro := "@" + string(event)
+		if !strings.HasPrefix(str, macro) {
+			continue
+		}
+		offset = strings.Replace(str[len(macro):], " ", "", -1)
+		if offset != "" && offset[0] != '+' && offset[0] != '-' {
+			continue
+		}
+		sun, ok = string(event), true
+		return
+	}
+
+	return
+}
+
+// nearestWorkday the monday-friday day nearest to the day of the month of t,
+// does not jump over the month boundary
+func nearestWorkday(t time.Time, day, lastDay int) int {
+
+	if day > lastDay {
+		return -1
+	}
+
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, t.Location()).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == lastDay {
+			return day - 2
+		}
+		return day + 1
+	}
+
+	return day
+}
+
+func hasBit(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
@@ -0,0 +1,121 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		next time.Time
+	}{
+		// month ends
+		{"31st skips the short months", "TZ=UTC 0 0 0 31 * *", utc(2020, 4, 15, 0, 0, 0), utc(2020, 5, 31, 0, 0, 0)},
+		{"29th of february of the leap year", "TZ=UTC 0 0 0 29 2 *", utc(2020, 3, 1, 0, 0, 0), utc(2024, 2, 29, 0, 0, 0)},
+		{"end of the year", "TZ=UTC 59 59 23 * * *", utc(2020, 12, 31, 23, 59, 59), utc(2021, 1, 1, 23, 59, 59)},
+		{"day and weekday both match", "TZ=UTC 0 0 0 13 * FRI", utc(2020, 1, 1, 0, 0, 0), utc(2020, 3, 13, 0, 0, 0)},
+
+		// L
+		{"last day of the leap february", "TZ=UTC 0 0 12 L * *", utc(2020, 2, 1, 0, 0, 0), utc(2020, 2, 29, 12, 0, 0)},
+		{"last day after the fire time", "TZ=UTC 0 0 12 L * *", utc(2021, 2, 28, 13, 0, 0), utc(2021, 3, 31, 12, 0, 0)},
+		{"last friday", "TZ=UTC 0 0 0 * * 5L", utc(2020, 6, 1, 0, 0, 0), utc(2020, 6, 26, 0, 0, 0)},
+
+		// W
+		{"saturday 15th moves to friday", "TZ=UTC 0 0 0 15W * *", utc(2020, 8, 1, 0, 0, 0), utc(2020, 8, 14, 0, 0, 0)},
+		{"saturday 1st moves to monday", "TZ=UTC 0 0 0 1W * *", utc(2020, 7, 31, 0, 0, 0), utc(2020, 8, 3, 0, 0, 0)},
+		{"sunday last day moves to friday", "TZ=UTC 0 0 0 LW * *", utc(2020, 5, 1, 0, 0, 0), utc(2020, 5, 29, 0, 0, 0)},
+
+		// #
+		{"third friday", "TZ=UTC 0 0 10 * * 5#3", utc(2020, 6, 1, 0, 0, 0), utc(2020, 6, 19, 10, 0, 0)},
+		{"first monday of the next month", "TZ=UTC 0 0 10 * * MON#1", utc(2020, 6, 2, 0, 0, 0), utc(2020, 7, 6, 10, 0, 0)},
+		{"fifth monday", "TZ=UTC 0 0 0 * * 1#5", utc(2020, 6, 1, 0, 0, 0), utc(2020, 6, 29, 0, 0, 0)},
+
+		// DST, the time that does not exist is skipped, the repeated time fires once
+		{"spring forward skips 02:30", "CRON_TZ=Europe/Berlin 0 30 2 * * *", utc(2020, 3, 28, 12, 0, 0), time.Date(2020, 3, 30, 2, 30, 0, 0, berlin)},
+		{"spring forward 03:00", "CRON_TZ=Europe/Berlin 0 0 3 * * *", utc(2020, 3, 29, 0, 0, 0), time.Date(2020, 3, 29, 3, 0, 0, 0, berlin)},
+		{"fall back 02:30", "CRON_TZ=Europe/Berlin 0 30 2 * * *", utc(2020, 10, 24, 12, 0, 0), time.Date(2020, 10, 25, 2, 30, 0, 0, berlin)},
+		{"fall back fires once", "CRON_TZ=Europe/Berlin 0 30 2 * * *", time.Date(2020, 10, 25, 2, 30, 0, 0, berlin), time.Date(2020, 10, 26, 2, 30, 0, 0, berlin)},
+		{"fall back 03:00", "CRON_TZ=Europe/Berlin 0 0 3 * * *", utc(2020, 10, 24, 12, 0, 0), utc(2020, 10, 25, 2, 0, 0)},
+
+		// macros
+		{"every", "@every 1h30m", utc(2020, 6, 1, 10, 0, 0), utc(2020, 6, 1, 11, 30, 0)},
+		{"weekly", "TZ=UTC @weekly", utc(2020, 6, 3, 0, 0, 0), utc(2020, 6, 7, 0, 0, 0)},
+	}
+
+	for _, test := range tests {
+		schedule, err := Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err.Error())
+			continue
+		}
+		if next := schedule.Next(test.from); !next.Equal(test.next) {
+			t.Errorf("%s: '%s' from %v: expected %v, got %v", test.name, test.spec, test.from, test.next, next)
+		}
+	}
+}
+
+func TestScheduleParseErrors(t *testing.T) {
+
+	tests := []string{
+		"",
+		"* * * * *",
+		"* * * * * * *",
+		"60 * * * * *",
+		"* * 24 * * *",
+		"* * * 0 * *",
+		"* * * * 13 *",
+		"* * * * * 8",
+		"* * * * FOO *",
+		"10-5 * * * * *",
+		"*/0 * * * * *",
+		"* * * 32W * *",
+		"* * * * * 5#6",
+		"* * * * * 5#0",
+		"* * * * * 9L",
+		"0 0 0 30 2 *",
+		"@yearly2",
+		"@every 100ms",
+		"@every soon",
+		"@sunset +25h",
+		"@sunset +soon",
+		"CRON_TZ=Mars/Olympus 0 0 0 * * *",
+		"CRON_TZ=Europe/Berlin",
+	}
+
+	for _, spec := range tests {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("'%s': expected the error", spec)
+		}
+	}
+}
@@ -19,15 +19,16 @@
 package cron
 
 import (
-	"strings"
 	"sync"
+	"time"
 )
 
 // Task ...
 type Task struct {
-	_time map[int][]int
-	_func func()
-	cron  *Cron
+	schedule *Schedule
+	next     time.Time
+	_func    func()
+	cron     *Cron
 	sync.Mutex
 	enabled bool
 }
@@ -36,6 +37,7 @@ type Task struct {
 func (t *Task) Enable() *Task {
 	t.Lock()
 	t.enabled = true
+	t.next = t.schedule.Next(time.Now())
 	t.Unlock()
 	return t
 }
@@ -56,111 +58,45 @@ func (t *Task) Enabled() bool {
 }
 
 // SetTime ...
-func (t *Task) SetTime(time string) {
-	args := strings.Split(time, " ")
-	switch len(args) {
-	case 1:
-		if args[0] == "*" {
-
-		}
+func (t *Task) SetTime(spec string) (err error) {
 
+	var schedule *Schedule
+	if schedule, err = t.cron.Parse(spec); err != nil {
+		return
 	}
-}
 
-// GetTime ...
-func (t *Task) GetTime() (time string) {
+	t.Lock()
+	t.schedule = schedule
+	t.next = schedule.Next(time.Now())
+	t.Unlock()
 
 	return
 }
 
-func (t *Task) exec(_timer *Timer) {
-
-	//log.Println("exec")
-
-	// WEEKDAY
-	exist := false
-	for _, weekday := range t._time[WEEKDAY] {
-		if weekday == int(_timer.weekday) {
-			exist = true
-			break
-		}
-	}
-
-	if !exist {
-		return
-	}
-
-	//log.Println("weekday")
-
-	// MONTH
-	exist = false
-	for _, month := range t._time[MONTH] {
-		if month == int(_timer.month) {
-			exist = true
-			break
-		}
-	}
-	if !exist {
-		return
-	}
-
-	//log.Println("month")
-
-	// DAY
-	exist = false
-	for _, day := range t._time[DAY] {
-		if day == _timer.day {
-			exist = true
-			break
-		}
-	}
-	if !exist {
-		return
-	}
-
-	//log.Println("day")
-
-	// HOUR
-	exist = false
-	for _, hour := range t._time[HOUR] {
-		if hour == _timer.hour {
-			exist = true
-			break
-		}
-	}
-	if !exist {
-		return
-	}
-
-	//log.Println("hour")
+// GetTime ...
+func (t *Task) GetTime() (spec string) {
+	t.Lock()
+	spec = t.schedule.String()
+	t.Unlock()
+	return
+}
 
-	// MINUTES
-	exist = false
-	for _, min := range t._time[MINUTE] {
-		if min == _timer.min {
-			exist = true
-			break
-		}
-	}
-	if !exist {
-		return
-	}
+// Next returns the next fire time after now
+func (t *Task) Next() time.Time {
+	t.Lock()
+	defer t.Unlock()
+	return t.next
+}
 
-	//log.Println("minutes")
+func (t *Task) exec(now time.Time) {
 
-	// SECONDS
-	exist = false
-	for _, sec := range t._time[SECOND] {
-		if sec == _timer.second {
-			exist = true
-			break
-		}
-	}
-	if !exist {
+	t.Lock()
+	if t.next.IsZero() || now.Before(t.next) {
+		t.Unlock()
 		return
 	}
-
-	//log.Println("seconds")
+	t.next = t.schedule.Next(now)
+	t.Unlock()
 
 	t.Run()
 }
@@ -27,6 +27,8 @@ import (
 type Flow struct {
 	Total    int64 `json:"total"`
 	Disabled int64 `json:"disabled"`
+	Queued   int64 `json:"queued"`
+	Dropped  int64 `json:"dropped"`
 }
 
 // FlowManager ...
@@ -34,6 +36,8 @@ type FlowManager struct {
 	publisher IPublisher
 	total     metrics.Counter
 	enabled   metrics.Counter
+	queued    metrics.Counter
+	dropped   metrics.Counter
 }
 
 // NewFlowManager ...
@@ -44,6 +48,8 @@ func NewFlowManager(publisher IPublisher,
 		publisher: publisher,
 		total:     metrics.NewCounter(),
 		enabled:   metrics.NewCounter(),
+		queued:    metrics.NewCounter(),
+		dropped:   metrics.NewCounter(),
 	}
 
 	if _, total, err := adaptors.Flow.List(999, 0, "", ""); err == nil {
@@ -61,6 +67,12 @@ func (d *FlowManager) update(t interface{}) {
 	case FlowDelete:
 		d.total.Dec(v.TotalNum)
 		d.enabled.Dec(v.EnabledNum)
+	case FlowMessageQueued:
+		d.queued.Inc(v.Num)
+	case FlowMessageDequeued:
+		d.queued.Dec(v.Num)
+	case FlowMessageDropped:
+		d.dropped.Inc(v.Num)
 
 	default:
 		return
@@ -75,6 +87,8 @@ func (d *FlowManager) Snapshot() Flow {
 	return Flow{
 		Total:    d.total.Count(),
 		Disabled: d.total.Count() - d.enabled.Count(),
+		Queued:   d.queued.Count(),
+		Dropped:  d.dropped.Count(),
 	}
 }
 
@@ -93,3 +107,18 @@ type FlowDelete struct {
 	TotalNum   int64
 	EnabledNum int64
 }
+
+// FlowMessageQueued ...
+type FlowMessageQueued struct {
+	Num int64
+}
+
+// FlowMessageDequeued ...
+type FlowMessageDequeued struct {
+	Num int64
+}
+
+// FlowMessageDropped ...
+type FlowMessageDropped struct {
+	Num int64
+}
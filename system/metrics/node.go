@@ -25,8 +25,22 @@ import (
 
 // Node ...
 type Node struct {
-	Total  int64            `json:"total"`
-	Status map[int64]string `json:"status"`
+	Total   int64                 `json:"total"`
+	Status  map[int64]string      `json:"status"`
+	Queue   map[int64]int         `json:"queue"`
+	Latency map[int64]NodeLatency `json:"latency"`
+}
+
+// NodeLatency the response time of the node commands in milliseconds
+type NodeLatency struct {
+	Count  int64   `json:"count"`
+	Errors int64   `json:"errors"`
+	Min    int64   `json:"min"`
+	Max    int64   `json:"max"`
+	Mean   float64 `json:"mean"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
 }
 
 // NodeManager ...
@@ -35,6 +49,9 @@ type NodeManager struct {
 	total      metrics.Counter
 	updateLock sync.Mutex
 	status     map[int64]string
+	queue      map[int64]int
+	latency    map[int64]metrics.Histogram
+	errors     map[int64]metrics.Counter
 }
 
 // NewNodeManager ...
@@ -42,6 +59,9 @@ func NewNodeManager(publisher IPublisher) *NodeManager {
 	return &NodeManager{
 		publisher: publisher,
 		status:    make(map[int64]string),
+		queue:     make(map[int64]int),
+		latency:   make(map[int64]metrics.Histogram),
+		errors:    make(map[int64]metrics.Counter),
 		total:     metrics.NewCounter(),
 	}
 }
@@ -56,6 +76,14 @@ func (d *NodeManager) update(t interface{}) {
 		if d.updateStatus(v) {
 			return
 		}
+	case NodeUpdateQueue:
+		if d.updateQueue(v) {
+			return
+		}
+	case NodeUpdateLatency:
+		// the latency goes out with the next broadcast
+		d.updateLatency(v)
+		return
 
 	default:
 		return
@@ -77,6 +105,66 @@ func (d *NodeManager) updateStatus(v NodeUpdateStatus) (exist bool) {
 	return
 }
 
+func (d *NodeManager) updateQueue(v NodeUpdateQueue) (exist bool) {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+
+	if depth, ok := d.queue[v.Id]; ok && depth == v.Depth {
+		exist = true
+		return
+	}
+	d.queue[v.Id] = v.Depth
+
+	return
+}
+
+func (d *NodeManager) updateLatency(v NodeUpdateLatency) {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+
+	histogram, ok := d.latency[v.Id]
+	if !ok {
+		histogram = metrics.NewHistogram(metrics.NewExpDecaySample(1028, 0.015))
+		d.latency[v.Id] = histogram
+		d.errors[v.Id] = metrics.NewCounter()
+	}
+
+	histogram.Update(int64(v.Time * 1000))
+	if v.Err {
+		d.errors[v.Id].Inc(1)
+	}
+}
+
+// GetLatency ...
+func (d *NodeManager) GetLatency(nodeId int64) (latency NodeLatency, err error) {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+
+	if _, ok := d.latency[nodeId]; !ok {
+		err = ErrRecordNotFound
+		return
+	}
+
+	latency = d.unsafeLatency(nodeId)
+
+	return
+}
+
+func (d *NodeManager) unsafeLatency(nodeId int64) NodeLatency {
+	snapshot := d.latency[nodeId].Snapshot()
+	percentiles := snapshot.Percentiles([]float64{0.5, 0.9, 0.99})
+	return NodeLatency{
+		Count:  snapshot.Count(),
+		Errors: d.errors[nodeId].Count(),
+		Min:    snapshot.Min(),
+		Max:    snapshot.Max(),
+		Mean:   snapshot.Mean(),
+		P50:    percentiles[0],
+		P90:    percentiles[1],
+		P99:    percentiles[2],
+	}
+}
+
 // GetStatus ...
 func (d *NodeManager) GetStatus(nodeId int64) (status string, err error) {
 
@@ -99,9 +187,19 @@ func (d *NodeManager) Snapshot() Node {
 	for k, v := range d.status {
 		status[k] = v
 	}
+	queue := make(map[int64]int)
+	for k, v := range d.queue {
+		queue[k] = v
+	}
+	latency := make(map[int64]NodeLatency)
+	for k := range d.latency {
+		latency[k] = d.unsafeLatency(k)
+	}
 	return Node{
-		Total:  d.total.Count(),
-		Status: status,
+		Total:   d.total.Count(),
+		Status:  status,
+		Queue:   queue,
+		Latency: latency,
 	}
 }
 
@@ -115,6 +213,19 @@ type NodeUpdateStatus struct {
 	Status string
 }
 
+// NodeUpdateQueue the number of commands waiting for delivery to the node
+type NodeUpdateQueue struct {
+	Id    int64
+	Depth int
+}
+
+// NodeUpdateLatency the command of the node is done, the time in seconds
+type NodeUpdateLatency struct {
+	Id   int64
+	Time float64
+	Err  bool
+}
+
 // NodeAdd ...
 type NodeAdd struct {
 	Num int64
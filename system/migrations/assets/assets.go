@@ -31,6 +31,21 @@
 // migrations/20200321_115133_update_map_device.sql
 // migrations/20200326_232201_update_map_device_history.sql
 // migrations/20200404_235500_add_alexa.sql
+// migrations/20200411_184512_add_flow_gateways.sql
+// migrations/20200418_213307_add_flow_concurrency.sql
+// migrations/20200425_161048_add_flow_runs.sql
+// migrations/20200502_124417_add_flow_timer_elements.sql
+// migrations/20200509_183254_add_device_current_states.sql
+// migrations/20200516_112043_add_device_group_mode.sql
+// migrations/20200523_152406_add_storage_items.sql
+// migrations/20200530_104127_add_node_alert_rules.sql
+// migrations/20200603_091512_add_workflow_scenario_rules.sql
+// migrations/20200606_112038_add_script_limits.sql
+// migrations/20200609_143551_add_script_test_cases.sql
+// migrations/20200613_101524_add_zigbee2mqtt_groups.sql
+// migrations/20200616_182307_add_zigbee2mqtt_device_exposes.sql
+// migrations/20200619_210841_add_zigbee2mqtt_ota_window.sql
+// migrations/20200622_193418_add_zigbee2mqtt_networkmaps.sql
 // DO NOT EDIT!
 
 package database
@@ -729,6 +744,306 @@ func migrations20200404_235500_add_alexaSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations20200411_184512_add_flow_gatewaysSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x91\xd1\x4e\x83\x30\x14\x86\xef\x79\x8a\x73\x87\x46\xfb\x04\xbb\x42\x61\xc9\x92\x0a\x3a\x20\xf1\x8e\xd4\xf6\xb8\x35\x2b\x6d\x03\x45\xd8\xdb\x5b\x58\x50\xa6\x66\xb3\x57\xed\x7f\xce\xf9\xcf\x9f\xaf\x84\xc0\x5d\x2d\x77\x0d\x73\x08\xa5\x0d\x08\x81\xfc\x85\x82\xd4\xd0\x22\x77\xd2\x68\x08\x4b\x1b\x82\x6c\x01\x07\xe4\x9d\x43\x01\xfd\x1e\x35\xb8\xbd\x97\x4e\x73\x63\x93\x7f\x30\x6b\x95\x44\x11\xf0\x06\x47\x2f\x77\xb4\x08\xef\xca\xf4\x15\x2a\xac\x51\xbb\xb6\xda\x79\xbd\x67\xc7\x6a\x2a\x31\xef\xa8\xbb\x1a\x6e\x42\x1c\xb8\xea\x5a\xf9\x81\xe1\x3d\x84\x96\x35\x4c\x29\x54\xe3\x5d\xea\xb9\x70\xbb\x0a\x82\x88\x16\xc9\x16\x8a\xe8\x81\x26\xe7\xbe\x01\xf8\x13\xc5\x31\x3c\x66\xb4\x7c\x4a\xe1\x6c\xcd\x85\x04\x69\x56\x40\x5a\x52\x0a\x71\xb2\x8e\x4a\x5a\xc0\x22\xc9\x8f\x7d\xdc\x68\x7d\xc2\xf1\x6b\x9b\x2f\x09\x39\x31\x70\x38\xb8\xc9\xd0\x0f\x93\x05\xd5\xd8\xf4\x7a\xe6\xfa\x05\x75\x14\xff\x85\xb5\x31\x1e\x87\x80\x37\xc6\x0f\x17\x23\xc5\xdb\xec\x79\xce\xb4\x59\x43\xf2\xba\xc9\x8b\xfc\x3b\xdd\x55\x80\x7f\xcf\x2f\x81\x79\x0b\xd1\x18\x7b\xf5\x67\x39\x6b\x39\x13\xbe\xfd\x13\x59\xf8\x3d\x2b\x5b\x02\x00\x00")
+
+func migrations20200411_184512_add_flow_gatewaysSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200411_184512_add_flow_gatewaysSql,
+		"migrations/20200411_184512_add_flow_gateways.sql",
+	)
+}
+
+func migrations20200411_184512_add_flow_gatewaysSql() (*asset, error) {
+	bytes, err := migrations20200411_184512_add_flow_gatewaysSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200411_184512_add_flow_gateways.sql", size: 603, mode: os.FileMode(420), modTime: time.Unix(1586630712, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200418_213307_add_flow_concurrencySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x52\x41\x4e\xc3\x30\x10\xbc\xe7\x15\x73\x0b\x88\x46\x82\x33\xa7\x40\x52\xa9\x92\x69\xa1\x4d\x24\x6e\x91\xb1\x17\x6a\x91\xda\xc6\x76\x54\xca\xeb\x71\x5a\xb5\x20\x85\x56\x65\x4e\xf6\x6a\x3c\xbb\x3b\xe3\x2c\xc3\xd5\x4a\xbd\x39\x1e\x08\xb5\x4d\xb2\x0c\x8b\x27\x06\xa5\xe1\x49\x04\x65\x34\xd2\xda\xa6\x50\x1e\xf4\x49\xa2\x0b\x24\xb1\x5e\x92\x46\x58\xc6\xd2\xee\x5d\x4f\x8a\x17\x6e\x6d\xab\x48\x26\xc2\x51\xaf\x15\x36\x96\xf0\xda\x9a\xb5\x6f\x84\xd1\xa2\x73\x8e\xb4\xd8\x34\x2b\x23\x09\x3c\xaa\xe9\x6e\x85\x8b\xd4\x93\x53\xbc\x4d\x47\x48\x2d\x77\xbc\x6d\x69\x7b\x96\xce\xd8\xc6\xb4\x92\x7c\x48\x2f\x6f\x93\x24\x67\x55\x39\x47\x95\xdf\xb1\x72\x27\x99\x20\x22\x2f\x0a\xdc\xcf\x58\xfd\x30\xc5\xa0\xc3\x91\xc6\xd3\x59\x85\x69\xcd\x18\x8a\x72\x9c\xd7\xac\xc2\x61\x80\x13\x8a\xd8\x42\xe9\x80\x3f\x30\x50\xbc\x19\x48\x7d\x74\xd4\x51\xe3\xd5\x17\xe1\x9f\x52\xd7\x71\xf7\xec\x57\x3e\x85\x59\xeb\x7d\x42\x87\x78\xfa\xe2\x59\x01\x39\x13\xfd\x95\x78\xe1\xe2\xfd\x88\xa3\xc5\x7c\xf6\xb8\x9f\x7a\x32\x46\xf9\x3c\x59\x54\x8b\x81\xb9\xa3\xf3\xb8\xa7\x68\x3f\x96\xc4\x0d\xfb\xb4\x4f\x7e\x17\xc1\xbd\xe0\x32\x52\xbf\x01\xe6\xb8\xf9\x13\xac\x02\x00\x00")
+
+func migrations20200418_213307_add_flow_concurrencySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200418_213307_add_flow_concurrencySql,
+		"migrations/20200418_213307_add_flow_concurrency.sql",
+	)
+}
+
+func migrations20200418_213307_add_flow_concurrencySql() (*asset, error) {
+	bytes, err := migrations20200418_213307_add_flow_concurrencySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200418_213307_add_flow_concurrency.sql", size: 684, mode: os.FileMode(420), modTime: time.Unix(1587245587, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200425_161048_add_flow_runsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x54\x4b\x8f\x9b\x30\x10\xbe\xf3\x2b\xe6\x06\x51\xc3\xa5\xd7\x9c\x08\x38\x15\x2d\x85\x94\x87\xb4\x7b\x42\x14\xbc\x1b\x6b\xc1\x20\xdb\x34\x69\xab\xfe\xf7\xda\xc4\x10\x76\xd9\xec\xa3\xea\x08\x09\x7b\x1e\x9f\xe7\xf1\x69\x6c\x1b\x3e\x34\xe4\x9e\x15\x02\x43\xd6\x19\xb6\x0d\xc9\xb7\x00\x08\x05\x8e\x4b\x41\x5a\x0a\x66\xd6\x99\x40\x38\xe0\x13\x2e\x7b\x81\x2b\x38\x1e\x30\x05\x71\x90\xaa\x73\x9c\x72\x92\x97\xa2\xeb\x6a\x82\x2b\xa3\x64\x58\x61\x89\x9f\x1d\x86\xbb\xba\x3d\xe6\xac\xa7\x3c\xe7\xa2\x10\xbd\x74\x92\x38\xb4\x6f\xc0\x32\x09\xcd\x3b\xd6\x96\x98\x73\x73\x0d\x66\xd5\x52\xac\xfe\x98\xb1\x96\x99\xab\x8d\x61\xb8\x31\x72\x52\x04\xa9\xb3\x0d\xd0\x05\xc7\xb0\x0c\x90\x42\x2a\x98\x24\xcb\x7c\x0f\xf6\xb1\xff\xd5\x89\x6f\xe1\x0b\xba\x85\x85\x78\x68\xe7\x64\x41\x0a\xf7\x98\xe6\xac\xa0\x55\xdb\xe4\x7d\x4f\x2a\x6b\xb5\x1e\xc0\x06\x70\x8d\xb8\xf5\x3f\xf9\x61\xba\x84\x80\x30\x4a\x21\xcc\x82\xc0\x18\x15\x6e\x14\x26\x69\xec\x28\xef\x4b\x95\x1f\x73\x75\xe6\xf9\xdd\x03\xc4\x68\x87\x62\x14\xba\x28\x19\xec\x1c\x2c\x52\xad\x20\x0a\x21\xdb\x7b\xaa\x30\xd7\x49\x5c\xc7\x43\x4a\xe3\xa1\x00\x5d\x34\xe7\xa4\x74\xbf\x60\x4a\x70\xde\xc5\xa7\x49\x4d\x15\x3e\xea\xea\x80\x33\x34\x54\xbb\x0b\x7c\x12\xf0\x9c\x28\x8c\xe9\x59\x26\x67\x9c\x17\xd2\x51\x90\x06\xcb\x7b\xd3\xc1\x91\x88\xc3\x70\x85\x5f\x72\x4e\xd3\xb3\xba\x7d\x84\x12\x7e\x38\xc7\x5c\x0f\x51\xad\x9b\x8d\xd5\x0f\x3d\x74\x33\x2b\x4c\xcf\x40\x7e\xa7\x01\x54\x76\x65\x32\x82\xa5\xad\xeb\x59\x7a\x57\x29\x22\x7b\x84\xbb\xe7\x78\x02\xe3\x80\x13\x14\xfb\x4e\x30\xa7\xcc\x8c\x07\x0a\x61\x8a\x1a\xa8\x05\xff\xc8\x86\x73\x22\x9a\x12\xba\xca\x05\x2d\x74\x81\xef\xa2\xc6\x10\x87\x6b\xdc\x60\x2a\x06\x22\xbf\x9e\xe7\x92\x53\xff\x97\x59\x6f\xe6\xd7\x8f\x82\xf1\xc7\xb6\xcf\x49\x14\x6e\xe1\x25\x99\x52\xf8\xfd\xc7\x5c\xb2\x54\x3f\xfd\x7e\xae\xbe\x16\x38\x32\xd6\x9e\x2d\x48\xaf\x3d\xd2\x71\x45\x4e\xfb\x51\x29\xdf\xb4\x21\x59\x5b\xd7\xd2\xfa\xbd\x28\x1f\x0c\x2f\x8e\xf6\x9a\xb9\xfe\x0e\xd0\x8d\x9f\xa4\xc9\x13\xea\x8c\x53\xdf\xbc\xec\x3c\xf3\xab\x58\xdb\x5d\x59\xbc\x65\xc1\xcb\xa2\xc2\x1b\xe3\x2f\x36\xdb\x79\x5d\xf0\x05\x00\x00")
+
+func migrations20200425_161048_add_flow_runsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200425_161048_add_flow_runsSql,
+		"migrations/20200425_161048_add_flow_runs.sql",
+	)
+}
+
+func migrations20200425_161048_add_flow_runsSql() (*asset, error) {
+	bytes, err := migrations20200425_161048_add_flow_runsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200425_161048_add_flow_runs.sql", size: 1520, mode: os.FileMode(420), modTime: time.Unix(1587831048, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200502_124417_add_flow_timer_elementsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x92\x4d\x4e\xc3\x30\x10\x85\xf7\x39\xc5\xdb\x65\x01\x39\x41\x57\x29\x49\xa5\x22\x93\x94\x26\xe1\x67\x55\x39\xc9\xb4\xb5\x70\xed\x28\x76\x5a\x2a\xc4\xdd\xb1\x0b\x45\x11\x1b\x90\xa8\x17\x96\xfc\x3c\xf3\x8d\x66\xe6\x45\x11\xae\x76\x62\xd3\x73\x4b\xa8\x3a\x28\x6d\x7b\xae\x0c\x6f\xac\xd0\x2a\x88\x22\x14\xf7\x0c\x42\xc1\xd0\x49\x41\x58\x75\x21\x84\x01\xbd\x52\x33\x58\x6a\x71\xd8\x92\x82\xdd\x3a\xe9\x93\xe2\x83\xdc\x83\x77\x9d\x14\xd4\x7a\x02\xa9\x61\x87\x3d\x97\x03\x19\x34\x5c\xf9\x12\xa8\x09\xbc\x6d\x5d\xba\x50\x46\xb4\x04\xbd\x76\x0c\xc2\xa8\x36\x6a\xa9\x9b\x97\x20\x66\x65\xba\x44\xf9\xbc\x48\xb1\x96\xfa\xb0\x22\x49\x3b\x52\xd6\xac\xba\x5e\x5b\x6d\x8f\x1d\xad\xfc\x85\x38\x49\xf0\x10\xb3\x2a\xc5\x7c\x86\x2c\x2f\x91\x3e\xcd\x8b\xb2\x40\x98\x90\xe4\xc7\x70\xf2\x7f\xd0\x23\x17\x36\xdd\xbb\x94\x4b\xc0\x12\xaa\xf5\xa0\x1a\xba\x04\xab\xdc\xba\x40\x2b\x3d\xeb\x0c\x8b\xa7\xec\x07\x2d\x80\x3b\x1e\x71\x93\xb3\xea\x2e\x73\xfb\xb4\x56\xa8\x8d\xc1\x6d\x91\x67\xd3\x13\x2f\xab\x18\x43\x92\xce\xe2\x8a\x95\x08\xdf\xde\x3d\x2f\x1a\xb9\x23\xd1\x87\x6f\x47\x7c\xdb\xc1\x8b\x7f\x32\x44\xaf\xa5\x74\xbf\x35\x77\x4b\x75\x90\x4e\x1b\xbb\xe9\x47\x8e\x68\x7b\xdd\x9d\x3c\x30\xb2\xcb\xb5\x17\x8e\xe0\x3d\x41\xd2\xda\x82\x1b\x47\xfa\xa5\xc7\x64\x99\x2f\xce\x4d\xba\x41\x7d\x0d\xe9\xdc\xee\x24\xf8\x00\x50\xc9\x4b\x17\xf0\x02\x00\x00")
+
+func migrations20200502_124417_add_flow_timer_elementsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200502_124417_add_flow_timer_elementsSql,
+		"migrations/20200502_124417_add_flow_timer_elements.sql",
+	)
+}
+
+func migrations20200502_124417_add_flow_timer_elementsSql() (*asset, error) {
+	bytes, err := migrations20200502_124417_add_flow_timer_elementsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200502_124417_add_flow_timer_elements.sql", size: 752, mode: os.FileMode(420), modTime: time.Unix(1588423457, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200509_183254_add_device_current_statesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x92\xcb\x6e\xc2\x30\x10\x45\xf7\xfe\x8a\xd9\x01\x6a\xb3\xe9\x96\x95\x89\x4d\x95\xd6\x38\xd4\x49\xa4\xa2\xaa\x8a\xd2\xc4\x25\x16\x90\x44\xb1\x29\x55\xbf\xbe\xce\x0b\xa8\x8a\xfa\x98\xdd\xdc\xf1\x5c\x1f\x27\xd7\x71\xe0\x6a\xa7\xd6\x75\x62\x24\x44\x15\x72\x1c\x08\x1e\x18\xa8\x02\xb4\x4c\x8d\x2a\x0b\x18\x45\xd5\x08\x94\x06\xf9\x2e\xd3\xbd\x91\x19\x1c\x72\x59\x80\xc9\xad\xd4\xed\x35\x87\x6c\x93\x54\xd5\x56\xc9\x0c\x61\x16\x52\x01\x21\x9e\x31\x0a\x99\x7c\x53\xa9\x8c\xb5\xb1\xee\x1a\x81\x2d\x4c\x08\xb8\x3e\x8b\x16\x1c\x4c\x9d\x14\x5a\x35\xeb\x1a\xee\x02\x9f\xcf\x80\xfb\x21\xf0\x88\x31\x20\x74\x8e\x23\x16\xc2\xe8\xe9\x79\x34\x45\xc8\x15\x14\x87\xf4\xab\x67\xba\xaf\x6b\x59\x98\xc1\x7b\xdc\xba\xf7\x33\x95\x41\x57\x33\xef\xd6\xe3\x21\x7c\xaf\xe3\x4d\x4b\xe1\x2d\xb0\x58\xc1\x3d\x5d\xa1\x61\xe8\xfa\x3c\x08\x05\x6e\x36\x2f\x5e\x16\xdf\xc4\x9d\xae\xe3\xd7\x0d\x08\x3a\xa7\x82\x72\x97\x06\xfd\x69\x0d\x63\x95\x4d\xc0\xe7\x10\x2d\x49\xc3\xed\xe2\xc0\xc5\x84\x36\x0a\xa1\x8c\x9e\x94\xeb\x73\xe8\xd6\xbb\x41\xff\x15\xfa\xff\xa0\x43\x7f\x09\xb7\x9f\xfd\x13\x3a\xcd\x93\x62\x2d\xb3\x38\x31\x1d\x8a\x51\x3b\x69\x8d\x76\x15\x1c\x94\xc9\xdb\x16\x3e\xca\x42\x9e\xa0\x27\xf6\x47\x3a\x67\x61\x23\xe5\xa1\x18\xe2\x76\xcc\x5a\x23\xfe\x29\x6d\x75\xb9\xdd\xda\xe9\x4b\x92\x6e\x10\x11\xfe\xb2\x0f\x87\x37\x07\xfa\xe8\x05\x61\x70\xf9\x83\x0c\x6f\xb0\x28\x3f\xc7\xb4\xb5\xec\x73\x7a\xf2\x3c\x4b\xec\x14\x7d\x02\x4b\x0f\xf3\x53\x39\x03\x00\x00")
+
+func migrations20200509_183254_add_device_current_statesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200509_183254_add_device_current_statesSql,
+		"migrations/20200509_183254_add_device_current_states.sql",
+	)
+}
+
+func migrations20200509_183254_add_device_current_statesSql() (*asset, error) {
+	bytes, err := migrations20200509_183254_add_device_current_statesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200509_183254_add_device_current_states.sql", size: 825, mode: os.FileMode(420), modTime: time.Unix(1589049174, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200516_112043_add_device_group_modeSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\xce\x41\x0e\x82\x30\x10\x05\xd0\x7d\x4f\xf1\x77\x2c\x0c\x27\x70\x85\x16\x13\x93\x0a\x2a\x6d\xe2\xce\x60\x99\x68\x23\xb6\x15\x8a\x72\x7c\x21\x06\xe3\xc6\xc4\xd9\xcd\xff\x33\xc9\x8b\x63\xcc\x6e\xe6\xdc\x94\x81\xa0\x3c\x8b\x63\x14\x3b\x01\x63\xd1\x92\x0e\xc6\x59\x44\xca\x47\x30\x2d\xa8\x27\xdd\x05\xaa\xf0\xbc\x90\x45\xb8\x0c\xd1\xfb\x6f\x3c\x1a\x96\xd2\xfb\xda\x50\xc5\x12\x21\xd3\x3d\x64\xb2\x10\x29\x2a\x7a\x18\x4d\x2d\xc3\x30\x09\xe7\x58\xe6\x42\x6d\x32\x9c\x1b\xd7\xf9\xe3\xcd\x55\x84\x40\x7d\x40\x96\x4b\x64\x4a\x08\xf0\x74\x95\x28\x21\x11\xb5\x74\xef\xc8\x6a\x8a\xe6\x6c\x24\x7d\x84\xdc\x3d\xed\x64\xfc\x00\xc7\xf0\x2f\x62\xe3\xea\x7a\x68\x4f\xa5\xbe\xfe\x64\xf2\x7d\xbe\x9d\x9c\xeb\x15\xd2\xc3\xba\x90\xc5\x97\x78\xce\x5e\x90\x70\x03\xb9\x32\x01\x00\x00")
+
+func migrations20200516_112043_add_device_group_modeSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200516_112043_add_device_group_modeSql,
+		"migrations/20200516_112043_add_device_group_mode.sql",
+	)
+}
+
+func migrations20200516_112043_add_device_group_modeSql() (*asset, error) {
+	bytes, err := migrations20200516_112043_add_device_group_modeSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200516_112043_add_device_group_mode.sql", size: 306, mode: os.FileMode(420), modTime: time.Unix(1589628043, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200523_152406_add_storage_itemsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x52\xcb\x6e\x83\x30\x10\xbc\xfb\x2b\xf6\x06\x51\xc3\x17\xe4\x44\xc0\x91\x68\x29\xa4\x3c\xa4\xe4\x84\x5c\x58\x25\x56\xc0\x58\x60\x1a\xda\xaf\xaf\x21\x0d\x0a\x55\x5b\xa5\x7b\xdb\xdd\x99\x7d\x8c\xc6\xb2\xe0\xa1\xe2\x87\x86\x29\x84\x54\x12\xcb\x82\xf8\xc5\x07\x2e\xa0\xc5\x5c\xf1\x5a\x80\x91\x4a\x03\x78\x0b\xd8\x63\xde\x29\x2c\xe0\x7c\x44\x01\xea\xa8\x4b\x17\xde\x00\xd2\x09\x93\xb2\xe4\x58\x10\x27\xa2\x76\x42\x21\xb1\xd7\x3e\x85\x56\xd5\x0d\x3b\x60\xc6\x15\x56\x2d\x31\x09\xe8\x10\xac\xc2\x56\xb2\x1c\x01\x14\xf6\x0a\x7e\x8a\x20\x4c\x20\x48\x7d\x7f\x39\x32\x4e\xf8\x7e\x6d\xdc\xc9\x78\x63\x65\x87\x97\xc6\x63\x1c\x06\xeb\xbf\x18\xe0\xd2\x8d\x9d\xfa\x09\x18\xa2\x2b\x4b\xe3\x32\x00\x7b\xc9\x1b\xcc\x98\x5e\xa6\xb8\xbe\x57\xb1\x4a\xc2\x99\xab\xe3\x98\xc2\x47\x2d\xf0\x66\x5d\xde\xa0\xd6\xaf\x18\xe0\xbf\xa3\x67\x07\x76\xb2\xf8\x27\x63\x1b\x79\xcf\x76\xb4\x87\x27\xba\x07\x73\xd2\x70\x39\x88\xb3\x20\x8b\x15\xb9\x0a\xef\x05\x2e\xdd\xcd\x85\xcf\xa6\x6f\x32\x5e\xf4\xe3\xb4\x30\x98\x43\xc0\x9c\x30\xc3\x2c\xeb\xc6\x15\x6e\x7d\x16\x57\x5f\x4c\xa6\x18\x8a\x77\xd9\xa2\xa9\xcb\x52\x77\x5f\x59\x7e\x22\x6e\x14\x6e\xbf\x8c\xe1\x6d\x80\xee\xbc\x38\x89\xbf\x9d\xe1\xd8\xb1\x63\xbb\x74\x45\x3e\x01\x01\x60\x94\x10\x98\x02\x00\x00")
+
+func migrations20200523_152406_add_storage_itemsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200523_152406_add_storage_itemsSql,
+		"migrations/20200523_152406_add_storage_items.sql",
+	)
+}
+
+func migrations20200523_152406_add_storage_itemsSql() (*asset, error) {
+	bytes, err := migrations20200523_152406_add_storage_itemsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200523_152406_add_storage_items.sql", size: 664, mode: os.FileMode(420), modTime: time.Unix(1590247446, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200530_104127_add_node_alert_rulesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x54\xc1\x6e\xa3\x30\x10\xbd\xf3\x15\x73\x4b\xab\x16\x69\xef\x3d\xb9\xe0\x54\x68\x5d\xc8\x1a\x90\xda\x13\x72\xf1\x74\xb1\x42\x0c\x02\xb3\xa9\xf6\xeb\x6b\x63\xd2\x76\xb3\x8d\x94\x76\x6e\x1e\xcf\x9b\x79\x33\x9e\xe7\x30\x84\xab\x9d\xfa\x3d\x08\x83\x50\xf6\x41\x18\x42\xfe\x8b\x81\xd2\x30\x62\x6d\x54\xa7\x61\x55\xf6\x2b\x50\x23\xe0\x0b\xd6\x93\x41\x09\xfb\x06\x35\x98\xc6\xba\x3c\xce\x05\xd9\x83\xe8\xfb\x56\xa1\x0c\x08\x2b\x28\x87\x82\xdc\x32\x0a\x12\xff\xa8\x1a\xc7\x00\xac\x91\x38\x86\x28\x63\xe5\x7d\x0a\x4f\xa2\xde\x4e\x7d\xa5\x3b\x89\x95\x92\x70\x9b\xdc\x25\x69\x01\x69\xc9\xd8\x1c\xe9\x2c\xca\xd2\xbc\xe0\xc4\xf9\x3f\x46\x0b\x53\x2d\x39\xab\xe7\x2d\x70\xba\xa6\x9c\xa6\x11\xcd\xc1\xdd\x8e\x70\xa1\xe4\x25\x64\x29\x94\x9b\x98\x14\x14\x22\x92\x47\x24\xa6\xce\x13\x53\x46\xad\x27\xa7\xbe\xce\x4d\x10\x44\x9c\xba\x18\x4f\xd4\x27\x6f\x71\x30\xd5\x30\xb5\x96\xf1\xc5\xcc\xc4\x92\x3b\x98\x25\x99\x53\x9e\x10\x06\x1b\x9e\xdc\x13\xfe\x08\x3f\xe9\xe3\xf5\x1c\xa5\xc5\x0e\x97\x28\x83\x2f\x06\x3e\xb3\x34\xf3\x85\x17\xc4\xd2\xb9\xcf\xeb\x9a\xfc\x04\x71\x62\x1c\x87\x39\x1c\x53\xfe\xde\x40\x16\x8f\xa7\xb5\x55\x5a\x7e\xad\x11\xd3\x0c\x38\x36\x5d\x6b\x61\xb2\x9b\x9e\x5a\x84\x7e\xc0\x5a\x8d\x6e\x27\x8e\x11\xb6\xe4\x9a\x94\xac\x80\x1f\x1e\xdb\xe3\xa0\x3a\x5f\xcf\xf6\x45\xef\xec\xd6\x9c\xac\x76\x8c\xad\x1b\xa1\x35\xb6\x5f\x60\x2a\xa4\xb4\x54\xc7\xb3\x10\x6f\xd5\x56\x2b\x0f\x46\x2d\x6c\x6f\xfe\xbd\xb2\x8c\x51\x92\x9e\x01\x2e\x78\xb9\xcc\xb5\x1e\xd0\xca\x4b\xda\x57\x03\xa3\x76\x38\x1a\xb1\xeb\x61\xaf\x4c\x33\x1f\xe1\x6f\xa7\xf1\x88\xed\xd4\xcb\xb3\x11\xc1\xa5\xdd\xe6\xf0\x83\x8c\xe3\x6e\xaf\x0f\x42\x7e\x53\xb1\x73\x9e\xa5\xe3\xa1\x6b\x5d\xab\x4e\x75\x41\xcc\xb3\xcd\xa2\x90\x64\x0d\xf4\x21\xc9\x8b\xfc\x3f\xad\x1c\x76\xc8\xb2\x38\xa5\xfd\x39\xcf\x22\xfe\xf7\x44\xff\x7e\x03\x37\xc1\x2b\xa5\x0a\x18\xc2\x8b\x04\x00\x00")
+
+func migrations20200530_104127_add_node_alert_rulesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200530_104127_add_node_alert_rulesSql,
+		"migrations/20200530_104127_add_node_alert_rules.sql",
+	)
+}
+
+func migrations20200530_104127_add_node_alert_rulesSql() (*asset, error) {
+	bytes, err := migrations20200530_104127_add_node_alert_rulesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200530_104127_add_node_alert_rules.sql", size: 1163, mode: os.FileMode(420), modTime: time.Unix(1590835287, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200603_091512_add_workflow_scenario_rulesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbd\x56\xc1\x8e\x9b\x30\x10\xbd\xf3\x15\x73\x4b\x56\x5d\xa4\xde\xf7\x44\xc0\x59\xd1\x52\x48\x0d\x48\xbb\xaa\x2a\xc4\x82\x93\x58\x01\x8c\x8c\x23\x92\x7e\x7d\x4d\x92\x25\x4e\x58\x52\x88\xda\xfa\xe6\xd1\xcc\x9b\x37\x63\xbf\xb1\x75\x1d\x3e\xe5\x74\xc5\x63\x41\x20\x2c\x35\x5d\x07\xff\xbb\x03\xb4\x80\x8a\x24\x82\xb2\x02\x26\x61\x39\x01\x5a\x01\xd9\x91\x64\x2b\x48\x0a\xf5\x9a\x14\x20\xd6\xd2\x74\x8c\x6b\x9c\xe4\x26\x2e\xcb\x8c\x92\x54\x33\x31\x32\x02\x04\x81\x31\x73\x10\xd4\x8c\x6f\x96\x19\xab\xa3\x2a\x21\x45\xcc\x29\x8b\xf8\x36\x23\x95\x36\xd5\x40\x2e\x9a\x42\x77\xcd\xec\x67\x1f\x61\xdb\x70\x60\x81\xed\x6f\x06\x7e\x85\xaf\xe8\xf5\xf1\xe0\x5f\xc4\x39\xe9\xf8\x0b\xb2\x13\xf0\xd1\x72\xbd\x00\xdc\xd0\x71\x8e\xb1\x2d\x13\x35\xa9\xcc\x65\xbb\xc1\x8d\x58\xed\xdd\x60\x7a\xae\x1f\x60\xa3\xf1\x6e\x91\x62\x11\xf5\xd4\x17\x2d\x37\x80\xd1\x1c\x61\xe4\x9a\xc8\x6f\x23\x2a\x98\xd2\xf4\x01\x3c\x17\xc2\x85\xd5\x34\xc9\x34\x7c\xd3\xb0\x50\x63\xb1\x90\x83\xce\x96\x2b\xce\x2d\xba\x24\x7f\x17\xe7\x16\x60\x34\xe7\xd6\x6b\x2c\xf9\x52\xc6\x70\x2a\xf6\x17\x1c\x25\x17\xf4\x8c\x70\x3f\x79\x09\x35\x37\x42\x27\x80\xcf\x47\x94\x84\x15\x29\x6d\xae\x58\xa5\x38\x7f\xf1\x3d\x77\xd6\x7a\x4e\x7e\xfc\x9c\x7c\x78\xe4\x29\xc9\xe2\xfd\x75\xa2\xd1\x0c\xd6\x2c\xeb\x5e\xd3\xd1\x28\xb2\x87\x6f\x19\xb9\x02\x9a\x79\x9e\x83\x0c\x77\x00\x4a\x80\xc3\x53\x5b\x13\x4e\xa4\x54\x53\x79\x90\xaa\x06\x68\x4e\x2a\x11\xe7\x25\xd4\x54\xac\x0f\x5b\xf8\xc5\x0a\x72\xd5\x90\x6d\x99\xde\x11\xab\x3d\x3c\x69\x7f\x52\xb5\x9c\x06\x82\xf1\x7d\x9f\xae\x6f\x68\xba\xa3\xcb\x7f\xa0\xc9\x13\xbb\xbf\xa4\xca\x25\x67\xf9\x40\x45\xf6\xb0\xbd\x44\xb8\x83\xf2\x18\x51\xfa\x48\xbd\x02\x17\xa9\x07\x8d\xcf\xb3\xce\x26\x47\x08\xc1\x2e\xaa\x87\xf1\xf5\xab\x08\xff\xb5\x7a\x25\xf1\xc0\xc7\xa3\x5b\x3d\xa7\xab\x15\xe1\xa3\xdf\x1f\x5a\xc8\x31\x16\xcb\xaa\x06\xc7\x75\x52\x77\xa4\x3f\x4a\xba\xb6\x6b\xa1\x97\x7e\xe9\x46\xaa\x84\x68\xba\x6b\x7a\xd8\xeb\x0c\x53\x45\xb6\x8f\x0a\xb1\x26\x9f\xae\xfc\x28\x2c\x56\x17\xef\x7f\x8a\xf6\x43\xd1\x18\x07\x7d\x29\x38\xcb\x9a\x99\xf9\x16\x27\x1b\xcd\xc2\xde\xe2\x34\x7e\xec\x39\xa0\x17\xdb\x0f\xfc\x1b\x04\x4f\x37\xe1\x69\x68\xdc\xe1\x09\x3c\x47\xfd\x06\x6f\x7b\x88\x16\x16\x09\x00\x00")
+
+func migrations20200603_091512_add_workflow_scenario_rulesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200603_091512_add_workflow_scenario_rulesSql,
+		"migrations/20200603_091512_add_workflow_scenario_rules.sql",
+	)
+}
+
+func migrations20200603_091512_add_workflow_scenario_rulesSql() (*asset, error) {
+	bytes, err := migrations20200603_091512_add_workflow_scenario_rulesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200603_091512_add_workflow_scenario_rules.sql", size: 2326, mode: os.FileMode(420), modTime: time.Unix(1591175712, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200606_112038_add_script_limitsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x92\x31\x6f\x83\x30\x10\x85\x77\x7e\xc5\x2d\x11\x43\x85\xd4\x1d\x75\x70\x8a\xd3\x46\x75\x21\x05\xa3\x8e\x11\x85\x53\xb0\x62\x6c\x84\x8d\x48\xfe\x7d\x4d\x68\x9b\x4a\x49\x24\xa4\xd6\x9b\x7d\xdf\x3d\x9f\xde\xbd\x20\x80\xbb\x46\xec\xba\xc2\x22\xe4\xad\x17\x04\x90\xbd\x31\x10\x0a\x0c\x96\x56\x68\x05\x7e\xde\xfa\x20\x0c\xe0\x01\xcb\xde\x62\x05\x43\x8d\x0a\x6c\xed\x9e\xa6\xbe\x11\x72\x97\xa2\x6d\xa5\xc0\xca\x23\x8c\xd3\x14\x38\x59\x32\x0a\xa6\xec\x44\x6b\x8d\x07\xee\x90\x28\x82\xc7\x84\xe5\xaf\x31\x58\xd1\xa0\xee\x2d\xac\x63\x4e\x9f\x1c\x1c\x27\x1c\xe2\x9c\x31\x88\xe8\x8a\xe4\x8c\xc3\x7d\x38\x47\xa6\x29\x0e\x5b\x63\x8b\x72\xff\x1f\x42\x0d\x36\xba\x3b\xfe\x55\xa9\x90\x52\x0f\xdb\xd1\x2a\x58\x26\x09\xa3\x24\xbe\x54\x5a\x11\x96\xd1\xd0\x1b\xad\xb6\x35\x7e\x2b\xb9\xd6\x0e\x8b\xea\x08\xbd\x11\x6a\x37\x55\x6a\x94\x12\x4a\xdd\x34\x85\xaa\x0c\xec\x11\x5b\x18\x74\xb7\x77\x75\x2f\xdf\x44\x84\x9f\xc7\xc8\x28\xff\xfd\xf7\x03\xf0\x34\xa7\xde\xfb\x33\x4d\x1d\xa3\xfb\xae\x44\x60\xeb\x17\x0a\xfe\x82\x4e\x6b\xcc\x8e\xaa\x5c\xf8\xe3\xf8\x49\x7a\x9d\x20\xe6\x84\x4c\x83\xfe\x44\x24\xd2\x83\xba\x16\x92\xf1\x7d\x56\x4c\x3a\x2d\xa5\xab\x7e\xb8\xb5\xdd\x34\x34\x4a\x93\xcd\xa5\xa3\xe1\x2c\xfe\xbc\xcb\xf9\xfc\x29\x44\xf3\xf0\xaf\xe8\x86\xde\x27\xc1\x51\x09\x96\x38\x03\x00\x00")
+
+func migrations20200606_112038_add_script_limitsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200606_112038_add_script_limitsSql,
+		"migrations/20200606_112038_add_script_limits.sql",
+	)
+}
+
+func migrations20200606_112038_add_script_limitsSql() (*asset, error) {
+	bytes, err := migrations20200606_112038_add_script_limitsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200606_112038_add_script_limits.sql", size: 824, mode: os.FileMode(420), modTime: time.Unix(1591442438, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200609_143551_add_script_test_casesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\xce\xb1\x0e\x82\x30\x10\x06\xe0\xbd\x4f\xf1\x6f\x1d\x0c\x4f\xc0\x54\x2c\x0e\xa6\x82\x02\x9d\x8c\x31\x58\x2f\xd2\x88\xd0\xd0\x1a\x7c\x7c\x21\x26\x2e\x6a\xe2\x6d\xf7\xff\x77\xc9\x17\x45\x58\xdc\xec\x65\xa8\x03\x41\x3b\x16\x45\x28\x77\x0a\xb6\x83\x27\x13\x6c\xdf\x81\x6b\xc7\x61\x3d\xe8\x41\xe6\x1e\xe8\x8c\xb1\xa1\x0e\xa1\x99\xa2\xd7\xdf\x7c\x34\x2d\xb5\x73\xad\xa5\x33\x13\xaa\x4a\x0b\x54\x22\x51\x29\xbc\x19\xac\x0b\x9e\x61\x1a\x21\x25\x96\xb9\xd2\x9b\x0c\x81\x7c\x38\x9a\xda\x93\xc7\xba\xcc\xb3\x04\x32\x5d\x09\xad\x2a\xf0\xfd\x81\x23\xcb\x2b\x64\x5a\xa9\x98\xcd\x9a\x37\x4e\xf6\x63\xf7\x8d\x37\xe7\x7f\x01\x87\xbe\x6d\xa7\xf6\x54\x9b\xeb\x4f\xa4\x2c\xf2\xed\xa7\x32\x66\x4f\xc2\xed\xfd\x20\x26\x01\x00\x00")
+
+func migrations20200609_143551_add_script_test_casesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200609_143551_add_script_test_casesSql,
+		"migrations/20200609_143551_add_script_test_cases.sql",
+	)
+}
+
+func migrations20200609_143551_add_script_test_casesSql() (*asset, error) {
+	bytes, err := migrations20200609_143551_add_script_test_casesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200609_143551_add_script_test_cases.sql", size: 294, mode: os.FileMode(420), modTime: time.Unix(1591713351, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200613_101524_add_zigbee2mqtt_groupsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbd\x55\xc9\x72\x9b\x40\x10\xbd\xf3\x15\x7d\xb3\x54\x11\x97\x5c\x7d\xc2\x30\x76\x51\x21\x48\x66\xa9\x92\x4f\x14\x66\xda\xd2\x94\xc4\x62\x18\x45\xb1\xbf\x3e\x33\x6c\x01\x04\x89\x28\xa5\x32\x37\x7a\x7a\x79\xdd\xfd\x78\xa3\xaa\xf0\x25\x66\xbb\x3c\xe4\x08\x7e\xa6\xa8\x2a\xb8\xcf\x16\xb0\x04\x0a\x8c\x38\x4b\x13\xb8\xf3\xb3\x3b\x60\x05\xe0\x4f\x8c\x4e\x1c\x29\x9c\xf7\x98\x00\xdf\x0b\x53\x15\x27\x9d\xc4\x47\x98\x65\x47\x86\x54\xd1\x1d\xa2\x79\x04\x3c\xed\xc1\x22\xf0\xc9\x76\xaf\x88\x5f\xe3\x77\xce\x83\x5d\x9e\x9e\xb2\x42\x59\x28\x20\x0e\xa3\xd0\x3d\x0f\xe6\x93\x4b\x1c\x53\xb3\x60\xe3\x98\xdf\x35\xe7\x05\xbe\x91\x97\x55\xe9\xd9\x4d\x21\xa2\x84\xa7\x69\x7b\x70\x79\xec\xb5\x07\xb6\x6f\x59\x4a\x63\xd0\xd7\xb6\xeb\x39\x9a\xf4\xbe\x84\x11\x84\x3c\xe8\x5a\xdf\x0e\xe0\x90\x47\xe2\x10\x5b\x27\x6e\xd7\x1f\x16\x8c\x2e\x61\x6d\x83\xbf\x31\x64\x5f\xba\xe6\xea\x9a\x41\xa4\xc5\x20\x16\xf9\x6d\xa9\xd0\x96\xd9\x83\xb6\x3b\x51\x9c\x3c\x11\x67\x1a\x6d\x15\xf5\x96\x33\x4c\xe8\xf1\x23\x48\xc2\x18\x01\x3c\xb2\x1d\xeb\x70\x18\x45\xb1\x88\x72\x96\x95\x0b\x80\x2a\xaa\xba\x88\x72\x14\xeb\xa4\xa2\xc7\x32\x8a\xb3\x18\x0b\x1e\xc6\x19\x9c\x19\xdf\x97\x9f\xf0\x99\x26\x38\x48\x77\xca\xe8\xac\x28\x65\x79\xaf\x34\xdb\xf6\x6d\xf3\xd9\x27\xa2\x5d\x83\x6c\xdb\x19\x0c\x67\x5c\x4f\xfe\x94\xbc\xcb\xe9\x5d\xde\xc0\xa2\xbf\xeb\x55\x9b\x49\x54\x1a\x2b\xd4\x1b\xdb\xed\xd5\x7a\xe9\x3a\xcd\x4d\x50\x39\xa0\xf8\x83\x45\xd8\x30\xfa\xf2\x5e\xb2\xa0\xa6\xeb\x2c\x72\x36\x89\x27\x3a\x9a\xa4\x6a\xdb\xd8\x2c\xc6\x76\x13\x54\x85\x25\xf0\x9a\x82\xff\x04\x77\x63\x9e\x06\x5e\x7b\xcc\x44\x7e\x15\x96\xec\x80\x1f\x5d\x51\xe9\xaf\xbd\x59\xd4\x6a\x7c\x0c\x4b\xe5\x8f\x34\x28\x22\x4c\x70\x42\xd1\x66\xe9\x5a\xcb\x97\x9b\xd5\xad\x82\xf4\x7f\x98\x53\xd6\x0a\x06\x7d\x5f\xa9\x78\x95\xd0\x0d\xce\xa4\x82\xc1\x0d\x3a\x06\x37\xaa\x59\xd3\xe5\x70\xa6\xf5\xa4\x47\xf4\xa5\xba\x99\x22\x5a\x93\x4f\xd6\x53\x3b\x8f\xaf\x91\x9e\x93\xb1\xe7\x57\xda\xaf\x7a\x80\xf3\xf4\x78\x14\xb7\xaf\x61\x74\x50\x0c\x67\xbd\xa9\x09\x6b\x3e\x02\xd9\x9a\xae\xe7\x8e\x61\xac\xf7\x79\xff\xf7\x80\xde\x5f\x35\x37\xae\x13\xf0\x0b\x6d\x7c\xd5\xa8\x70\x08\x00\x00")
+
+func migrations20200613_101524_add_zigbee2mqtt_groupsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200613_101524_add_zigbee2mqtt_groupsSql,
+		"migrations/20200613_101524_add_zigbee2mqtt_groups.sql",
+	)
+}
+
+func migrations20200613_101524_add_zigbee2mqtt_groupsSql() (*asset, error) {
+	bytes, err := migrations20200613_101524_add_zigbee2mqtt_groupsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200613_101524_add_zigbee2mqtt_groups.sql", size: 2160, mode: os.FileMode(420), modTime: time.Unix(1592043324, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200616_182307_add_zigbee2mqtt_device_exposesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\xce\xbb\x0e\x82\x30\x14\x06\xe0\xbd\x4f\xf1\x6f\x0c\x86\xc5\xd5\x09\x2c\x0e\xa6\x82\x22\x9d\x8c\x31\x5c\x4e\xa0\x91\x4b\xb5\xf5\x12\x9f\xde\x12\x13\x07\xe3\xc0\xd9\xce\x7f\x2e\xf9\x7c\x1f\xb3\x4e\xd5\xd7\xdc\x12\xa4\x66\xbe\x8f\xfd\x4e\x40\xf5\x30\x54\x5a\x35\xf4\xf0\xa4\xf6\xa0\x0c\xe8\x49\xe5\xcd\x52\x85\x47\x43\x3d\x6c\xe3\xa2\xcf\xdd\xb8\xe4\x9a\x5c\xeb\x56\x51\xc5\x02\x91\x45\x29\xb2\x20\x14\x11\x5e\xaa\x2e\x88\xe6\xdd\xc5\xda\x53\x45\x77\x55\x92\x61\x70\x15\x70\x8e\x65\x22\xe4\x26\x76\x6f\xf5\x60\xc8\x60\xbd\x4f\xe2\x10\x3c\x5a\x05\x52\x64\xf0\x0e\x47\x0f\x71\x92\x21\x96\x42\x2c\xd8\xc8\xfa\x2a\xf9\xf0\xe8\xff\x39\xc7\x7c\x92\xf4\x3a\xb4\xad\x9b\x16\x79\x79\x9e\xa4\xe5\x69\xb2\xfd\xe1\x2e\xd8\x1b\x69\x94\x65\x83\x38\x01\x00\x00")
+
+func migrations20200616_182307_add_zigbee2mqtt_device_exposesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200616_182307_add_zigbee2mqtt_device_exposesSql,
+		"migrations/20200616_182307_add_zigbee2mqtt_device_exposes.sql",
+	)
+}
+
+func migrations20200616_182307_add_zigbee2mqtt_device_exposesSql() (*asset, error) {
+	bytes, err := migrations20200616_182307_add_zigbee2mqtt_device_exposesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200616_182307_add_zigbee2mqtt_device_exposes.sql", size: 312, mode: os.FileMode(420), modTime: time.Unix(1592331787, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200619_210841_add_zigbee2mqtt_ota_windowSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\xcf\x31\x4f\xc3\x30\x10\x05\xe0\xdd\xbf\xe2\x6d\x29\xa2\x59\x2a\x31\x75\x32\x75\xaa\x0e\x26\x29\x21\x66\xad\xdc\xe4\xd4\x5a\x4d\xed\x34\x39\x14\xc4\xaf\xc7\x11\x12\x13\x04\x6e\xbb\xbb\xf7\x86\x2f\x4d\x71\x7f\x75\xa7\xde\x32\xc1\x74\x22\x4d\xf1\xf2\xac\xe1\x3c\x06\xaa\xd9\x05\x8f\xc4\x74\x09\xdc\x00\x7a\xa7\xfa\x8d\xa9\xc1\x78\x26\x0f\x3e\xc7\xd3\x57\x6f\x0a\xc5\xc5\x76\x5d\xeb\xa8\x11\x52\x57\x59\x89\x4a\x3e\xea\x0c\x1f\xee\x74\x24\x5a\x5d\x6f\xcc\x02\x71\xa4\x52\xd8\x14\xda\x3c\xe5\x08\x6c\x0f\xa3\xf3\x4d\x18\x0f\x03\xdb\x9e\xf1\x2a\xcb\xcd\x4e\x96\x8b\x87\x3b\xa8\x6c\x2b\x8d\xae\x90\x24\xc8\x8b\x0a\xb9\xd1\x7a\x39\xd3\x27\xdf\xfc\xd1\x5e\x8b\x09\xf6\xed\x54\x61\xf4\x3f\x49\xa7\xfb\xbf\xac\x7d\x68\xdb\xf8\x3d\xda\xfa\x32\xeb\x55\x65\xb1\xff\x0d\xbc\x9c\x4b\x44\xd2\x5a\x7c\x02\x47\xfb\x04\xba\x9b\x01\x00\x00")
+
+func migrations20200619_210841_add_zigbee2mqtt_ota_windowSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200619_210841_add_zigbee2mqtt_ota_windowSql,
+		"migrations/20200619_210841_add_zigbee2mqtt_ota_window.sql",
+	)
+}
+
+func migrations20200619_210841_add_zigbee2mqtt_ota_windowSql() (*asset, error) {
+	bytes, err := migrations20200619_210841_add_zigbee2mqtt_ota_windowSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200619_210841_add_zigbee2mqtt_ota_window.sql", size: 411, mode: os.FileMode(420), modTime: time.Unix(1592600921, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations20200622_193418_add_zigbee2mqtt_networkmapsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x52\x5d\x6f\x82\x30\x14\x7d\xef\xaf\xb8\x6f\x68\x26\x2f\x7b\xf5\xa9\x42\x5d\xd8\x58\x71\x2d\x24\x9a\x65\x31\x08\x9d\x36\x40\x61\xd0\x45\xe3\xaf\x5f\xf1\x23\x76\x6e\x26\xdb\x7d\xeb\xb9\xe7\xdc\x9e\xfb\xe1\xba\x70\x57\xc9\x75\x9b\x6a\x01\x49\x83\x5c\x17\xf8\x4b\x08\x52\x41\x27\x32\x2d\x6b\x05\x4e\xd2\x38\x20\x3b\x10\x3b\x91\x7d\x6a\x91\xc3\x76\x23\x14\xe8\x8d\x81\x8e\xba\x9e\x64\x1e\x69\xd3\x94\x52\xe4\xc8\x63\x04\xc7\x04\x62\x3c\x09\x09\xec\xe5\x7a\x25\xc4\x7d\xf5\xa1\xf5\x52\x09\xbd\xad\xdb\xa2\x4a\x9b\x0e\x0d\x10\x98\x90\x39\xd8\x31\x09\x1e\x38\x61\x01\x0e\x61\xc6\x82\x67\xcc\x16\xf0\x44\x16\xa3\x03\xd3\xae\x63\x54\x86\x19\xd0\x18\x7e\x06\x8d\x62\xa0\x49\x18\xa2\x33\xe0\x45\x94\xc7\x0c\xf7\xec\x1b\x5e\x96\xa9\x5e\xda\xa9\xf7\x02\x18\x99\x12\x46\xa8\x47\xb8\x2d\x82\x81\xcc\x87\x10\x51\x48\x66\x7e\xdf\xa1\x87\xb9\x87\x7d\xd2\x23\x3e\x09\xc9\x05\x39\x5a\x56\x75\x2e\xba\x8b\xb3\x47\x1e\xd1\x89\x21\x4e\x71\x12\xc6\xe0\xbc\xbe\x39\x57\x96\x8f\xaa\x52\xaa\xe2\xff\xaa\xac\x15\x66\x7f\xb9\x69\xe5\x90\xd4\xb2\x12\x9d\x4e\xab\x06\xb6\x52\x6f\x0e\x4f\xd8\xd7\x4a\x5c\xc6\x33\x1c\xa3\xf3\xa2\x02\xea\x93\xb9\x55\xe1\x7a\x1e\xf6\xa8\x64\xbe\xeb\xdb\xbd\x91\x86\xc1\xf7\x35\x8d\xac\xaa\xfd\x87\xae\x75\x6a\x7e\xbd\x55\xbf\x1d\x5b\x8f\xff\xe9\xdc\xda\xba\x2c\x4d\x76\x95\x66\x05\xf2\x59\x34\x3b\x1d\x5c\x30\x05\x32\x0f\x78\xcc\x6f\x9a\x3c\x2d\x69\x8c\xbe\x00\x03\x3a\x45\x4f\xfa\x02\x00\x00")
+
+func migrations20200622_193418_add_zigbee2mqtt_networkmapsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20200622_193418_add_zigbee2mqtt_networkmapsSql,
+		"migrations/20200622_193418_add_zigbee2mqtt_networkmaps.sql",
+	)
+}
+
+func migrations20200622_193418_add_zigbee2mqtt_networkmapsSql() (*asset, error) {
+	bytes, err := migrations20200622_193418_add_zigbee2mqtt_networkmapsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20200622_193418_add_zigbee2mqtt_networkmaps.sql", size: 762, mode: os.FileMode(420), modTime: time.Unix(1592854458, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -812,6 +1127,21 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/20200321_115133_update_map_device.sql":                  migrations20200321_115133_update_map_deviceSql,
 	"migrations/20200326_232201_update_map_device_history.sql":          migrations20200326_232201_update_map_device_historySql,
 	"migrations/20200404_235500_add_alexa.sql":                          migrations20200404_235500_add_alexaSql,
+	"migrations/20200411_184512_add_flow_gateways.sql":                  migrations20200411_184512_add_flow_gatewaysSql,
+	"migrations/20200418_213307_add_flow_concurrency.sql":               migrations20200418_213307_add_flow_concurrencySql,
+	"migrations/20200425_161048_add_flow_runs.sql":                      migrations20200425_161048_add_flow_runsSql,
+	"migrations/20200502_124417_add_flow_timer_elements.sql":            migrations20200502_124417_add_flow_timer_elementsSql,
+	"migrations/20200509_183254_add_device_current_states.sql":          migrations20200509_183254_add_device_current_statesSql,
+	"migrations/20200516_112043_add_device_group_mode.sql":              migrations20200516_112043_add_device_group_modeSql,
+	"migrations/20200523_152406_add_storage_items.sql":                  migrations20200523_152406_add_storage_itemsSql,
+	"migrations/20200530_104127_add_node_alert_rules.sql":               migrations20200530_104127_add_node_alert_rulesSql,
+	"migrations/20200603_091512_add_workflow_scenario_rules.sql":        migrations20200603_091512_add_workflow_scenario_rulesSql,
+	"migrations/20200606_112038_add_script_limits.sql":                  migrations20200606_112038_add_script_limitsSql,
+	"migrations/20200609_143551_add_script_test_cases.sql":              migrations20200609_143551_add_script_test_casesSql,
+	"migrations/20200613_101524_add_zigbee2mqtt_groups.sql":             migrations20200613_101524_add_zigbee2mqtt_groupsSql,
+	"migrations/20200616_182307_add_zigbee2mqtt_device_exposes.sql":     migrations20200616_182307_add_zigbee2mqtt_device_exposesSql,
+	"migrations/20200619_210841_add_zigbee2mqtt_ota_window.sql":         migrations20200619_210841_add_zigbee2mqtt_ota_windowSql,
+	"migrations/20200622_193418_add_zigbee2mqtt_networkmaps.sql":        migrations20200622_193418_add_zigbee2mqtt_networkmapsSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -887,6 +1217,21 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"20200321_115133_update_map_device.sql":                  &bintree{migrations20200321_115133_update_map_deviceSql, map[string]*bintree{}},
 		"20200326_232201_update_map_device_history.sql":          &bintree{migrations20200326_232201_update_map_device_historySql, map[string]*bintree{}},
 		"20200404_235500_add_alexa.sql":                          &bintree{migrations20200404_235500_add_alexaSql, map[string]*bintree{}},
+		"20200411_184512_add_flow_gateways.sql":                  &bintree{migrations20200411_184512_add_flow_gatewaysSql, map[string]*bintree{}},
+		"20200418_213307_add_flow_concurrency.sql":               &bintree{migrations20200418_213307_add_flow_concurrencySql, map[string]*bintree{}},
+		"20200425_161048_add_flow_runs.sql":                      &bintree{migrations20200425_161048_add_flow_runsSql, map[string]*bintree{}},
+		"20200502_124417_add_flow_timer_elements.sql":            &bintree{migrations20200502_124417_add_flow_timer_elementsSql, map[string]*bintree{}},
+		"20200509_183254_add_device_current_states.sql":          &bintree{migrations20200509_183254_add_device_current_statesSql, map[string]*bintree{}},
+		"20200516_112043_add_device_group_mode.sql":              &bintree{migrations20200516_112043_add_device_group_modeSql, map[string]*bintree{}},
+		"20200523_152406_add_storage_items.sql":                  &bintree{migrations20200523_152406_add_storage_itemsSql, map[string]*bintree{}},
+		"20200530_104127_add_node_alert_rules.sql":               &bintree{migrations20200530_104127_add_node_alert_rulesSql, map[string]*bintree{}},
+		"20200603_091512_add_workflow_scenario_rules.sql":        &bintree{migrations20200603_091512_add_workflow_scenario_rulesSql, map[string]*bintree{}},
+		"20200606_112038_add_script_limits.sql":                  &bintree{migrations20200606_112038_add_script_limitsSql, map[string]*bintree{}},
+		"20200609_143551_add_script_test_cases.sql":              &bintree{migrations20200609_143551_add_script_test_casesSql, map[string]*bintree{}},
+		"20200613_101524_add_zigbee2mqtt_groups.sql":             &bintree{migrations20200613_101524_add_zigbee2mqtt_groupsSql, map[string]*bintree{}},
+		"20200616_182307_add_zigbee2mqtt_device_exposes.sql":     &bintree{migrations20200616_182307_add_zigbee2mqtt_device_exposesSql, map[string]*bintree{}},
+		"20200619_210841_add_zigbee2mqtt_ota_window.sql":         &bintree{migrations20200619_210841_add_zigbee2mqtt_ota_windowSql, map[string]*bintree{}},
+		"20200622_193418_add_zigbee2mqtt_networkmaps.sql":        &bintree{migrations20200622_193418_add_zigbee2mqtt_networkmapsSql, map[string]*bintree{}},
 	}},
 }}
 
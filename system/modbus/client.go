@@ -0,0 +1,288 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout ...
+	DefaultTimeout = time.Second
+	// DefaultRetries ...
+	DefaultRetries = 2
+	// DefaultPoolSize ...
+	DefaultPoolSize = 2
+
+	mbapHeaderLength = 7
+	// the largest pdu is 253 bytes
+	maxAduLength = 260
+
+	retryDelay = time.Millisecond * 100
+)
+
+// Request ...
+type Request struct {
+	SlaveId  uint8
+	Function string
+	Address  uint16
+	Count    uint16
+	Command  []uint16
+	// zero means DefaultTimeout
+	Timeout time.Duration
+	// zero means DefaultRetries, negative value disables the retries
+	Retries int
+}
+
+// Client modbus tcp client of the single address.
+// Client keeps the pool of the connections, the requests to the different slaves
+// run in parallel, the requests to the same slave one after another
+type Client struct {
+	address       string
+	slots         chan struct{}
+	idle          chan net.Conn
+	slavesLock    sync.Mutex
+	slaves        map[uint8]*sync.Mutex
+	transactionId uint16
+	idLock        sync.Mutex
+	closed        chan struct{}
+	closeOnce     sync.Once
+}
+
+// NewClient ...
+func NewClient(address string, poolSize int) *Client {
+
+	if poolSize <= 0 {
+		poolSize = DefaultPoolSize
+	}
+
+	return &Client{
+		address: address,
+		slots:   make(chan struct{}, poolSize),
+		idle:    make(chan net.Conn, poolSize),
+		slaves:  make(map[uint8]*sync.Mutex),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Address ...
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Do send the request, the broken connection is dropped and the request is repeated.
+// The exception response of the slave is returned without the retries
+func (c *Client) Do(req Request) (result []uint16, err error) {
+
+	var reqPdu pdu
+	if reqPdu, err = encodeRequest(req.Function, req.Address, req.Count, req.Command); err != nil {
+		return
+	}
+
+	timeout := req.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	retries := req.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+
+	slave := c.slave(req.SlaveId)
+	slave.Lock()
+	defer slave.Unlock()
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-c.closed:
+				return
+			case <-time.After(retryDelay):
+			}
+		}
+
+		var conn net.Conn
+		if conn, err = c.get(timeout); err != nil {
+			continue
+		}
+
+		var respPdu pdu
+		if respPdu, err = c.send(conn, req.SlaveId, reqPdu, timeout); err != nil {
+			c.drop(conn)
+			continue
+		}
+
+		c.put(conn)
+
+		result, err = decodeResponse(reqPdu, respPdu, req.Count)
+
+		return
+	}
+
+	return
+}
+
+// Close close the idle connections, the busy ones are closed after the request
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	for {
+		select {
+		case conn := <-c.idle:
+			c.drop(conn)
+		default:
+			return
+		}
+	}
+}
+
+func (c *Client) slave(slaveId uint8) *sync.Mutex {
+	c.slavesLock.Lock()
+	defer c.slavesLock.Unlock()
+	slave, ok := c.slaves[slaveId]
+	if !ok {
+		slave = &sync.Mutex{}
+		c.slaves[slaveId] = slave
+	}
+	return slave
+}
+
+// get the idle connection or open the new one if the pool is not full
+func (c *Client) get(timeout time.Duration) (conn net.Conn, err error) {
+
+	select {
+	case <-c.closed:
+		err = fmt.Errorf("modbus client %s is closed", c.address)
+		return
+	case conn = <-c.idle:
+		return
+	default:
+	}
+
+	select {
+	case <-c.closed:
+		err = fmt.Errorf("modbus client %s is closed", c.address)
+	case conn = <-c.idle:
+	case c.slots <- struct{}{}:
+		if conn, err = net.DialTimeout("tcp", c.address, timeout); err != nil {
+			<-c.slots
+		}
+	case <-time.After(timeout):
+		err = fmt.Errorf("modbus client %s: no free connection", c.address)
+	}
+
+	return
+}
+
+// put return the connection to the pool
+func (c *Client) put(conn net.Conn) {
+	select {
+	case <-c.closed:
+		c.drop(conn)
+	default:
+		c.idle <- conn
+	}
+}
+
+// drop close the connection and free the slot of the pool
+func (c *Client) drop(conn net.Conn) {
+	_ = conn.Close()
+	<-c.slots
+}
+
+func (c *Client) nextTransactionId() uint16 {
+	c.idLock.Lock()
+	c.transactionId++
+	id := c.transactionId
+	c.idLock.Unlock()
+	return id
+}
+
+// send write the request adu and read the response with the same transaction id
+func (c *Client) send(conn net.Conn, slaveId uint8, req pdu, timeout time.Duration) (resp pdu, err error) {
+
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return
+	}
+
+	transactionId := c.nextTransactionId()
+
+	adu := make([]byte, mbapHeaderLength+1+len(req.data))
+	binary.BigEndian.PutUint16(adu[0:], transactionId)
+	binary.BigEndian.PutUint16(adu[2:], 0)
+	binary.BigEndian.PutUint16(adu[4:], uint16(2+len(req.data)))
+	adu[6] = slaveId
+	adu[7] = req.function
+	copy(adu[8:], req.data)
+
+	if _, err = conn.Write(adu); err != nil {
+		return
+	}
+
+	var respTransactionId uint16
+	var respSlaveId uint8
+	if respTransactionId, respSlaveId, resp, err = readAdu(conn); err != nil {
+		return
+	}
+
+	if respTransactionId != transactionId || respSlaveId != slaveId {
+		err = fmt.Errorf("%s: transaction %d slave %d, expected transaction %d slave %d",
+			ErrBadResponse.Error(), respTransactionId, respSlaveId, transactionId, slaveId)
+	}
+
+	return
+}
+
+// readAdu read the single modbus tcp frame
+func readAdu(r io.Reader) (transactionId uint16, unitId uint8, p pdu, err error) {
+
+	header := make([]byte, mbapHeaderLength)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+
+	transactionId = binary.BigEndian.Uint16(header[0:])
+	protocolId := binary.BigEndian.Uint16(header[2:])
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	unitId = header[6]
+
+	if protocolId != 0 || length < 2 || length > maxAduLength-mbapHeaderLength+1 {
+		err = fmt.Errorf("%s: protocol %d length %d", ErrBadResponse.Error(), protocolId, length)
+		return
+	}
+
+	body := make([]byte, length-1)
+	if _, err = io.ReadFull(r, body); err != nil {
+		return
+	}
+
+	p.function = body[0]
+	p.data = body[1:]
+
+	return
+}
@@ -0,0 +1,75 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"github.com/e154/smart-home/common"
+	"github.com/e154/smart-home/system/graceful_service"
+	"sync"
+)
+
+var (
+	log = common.MustGetLogger("modbus")
+)
+
+// Modbus in-process modbus tcp driver, one client with the pool of connections per address
+type Modbus struct {
+	sync.Mutex
+	clients map[string]*Client
+}
+
+// NewModbus ...
+func NewModbus(graceful *graceful_service.GracefulService) *Modbus {
+	m := &Modbus{
+		clients: make(map[string]*Client),
+	}
+	graceful.Subscribe(m)
+	return m
+}
+
+// Client the client of the address, poolSize is used when the client is created
+func (m *Modbus) Client(address string, poolSize int) *Client {
+	m.Lock()
+	defer m.Unlock()
+
+	client, ok := m.clients[address]
+	if !ok {
+		log.Infof("new modbus tcp client %s", address)
+		client = NewClient(address, poolSize)
+		m.clients[address] = client
+	}
+
+	return client
+}
+
+// Do send the request to the slave of the address
+func (m *Modbus) Do(address string, poolSize int, req Request) ([]uint16, error) {
+	return m.Client(address, poolSize).Do(req)
+}
+
+// Shutdown ...
+func (m *Modbus) Shutdown() {
+	m.Lock()
+	defer m.Unlock()
+
+	for address, client := range m.clients {
+		client.Close()
+		delete(m.clients, address)
+	}
+}
@@ -0,0 +1,303 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// function names of the requests, the same as used by the node
+const (
+	ReadCoils                  = "ReadCoils"
+	ReadDiscreteInputs         = "ReadDiscreteInputs"
+	WriteSingleCoil            = "WriteSingleCoil"
+	WriteMultipleCoils         = "WriteMultipleCoils"
+	ReadInputRegisters         = "ReadInputRegisters"
+	ReadHoldingRegisters       = "ReadHoldingRegisters"
+	ReadWriteMultipleRegisters = "ReadWriteMultipleRegisters"
+	WriteSingleRegister        = "WriteSingleRegister"
+	WriteMultipleRegisters     = "WriteMultipleRegisters"
+)
+
+const (
+	funcReadCoils                  = byte(0x01)
+	funcReadDiscreteInputs         = byte(0x02)
+	funcReadHoldingRegisters       = byte(0x03)
+	funcReadInputRegisters         = byte(0x04)
+	funcWriteSingleCoil            = byte(0x05)
+	funcWriteSingleRegister        = byte(0x06)
+	funcWriteMultipleCoils         = byte(0x0F)
+	funcWriteMultipleRegisters     = byte(0x10)
+	funcReadWriteMultipleRegisters = byte(0x17)
+
+	// the function code of the exception response has the high bit set
+	exceptionBit = byte(0x80)
+
+	maxReadBits       = 2000
+	maxReadRegisters  = 125
+	maxWriteBits      = 1968
+	maxWriteRegisters = 123
+	// write registers of the read/write function
+	maxReadWriteRegisters = 121
+)
+
+const (
+	// ExceptionIllegalFunction ...
+	ExceptionIllegalFunction = byte(0x01)
+	// ExceptionIllegalDataAddress ...
+	ExceptionIllegalDataAddress = byte(0x02)
+	// ExceptionIllegalDataValue ...
+	ExceptionIllegalDataValue = byte(0x03)
+	// ExceptionServerDeviceFailure ...
+	ExceptionServerDeviceFailure = byte(0x04)
+	// ExceptionServerDeviceBusy ...
+	ExceptionServerDeviceBusy = byte(0x06)
+)
+
+var (
+	// ErrUnknownFunction ...
+	ErrUnknownFunction = errors.New("unknown modbus function")
+	// ErrBadResponse ...
+	ErrBadResponse = errors.New("bad modbus response")
+)
+
+var functionCodes = map[string]byte{
+	ReadCoils:                  funcReadCoils,
+	ReadDiscreteInputs:         funcReadDiscreteInputs,
+	ReadHoldingRegisters:       funcReadHoldingRegisters,
+	ReadInputRegisters:         funcReadInputRegisters,
+	WriteSingleCoil:            funcWriteSingleCoil,
+	WriteSingleRegister:        funcWriteSingleRegister,
+	WriteMultipleCoils:         funcWriteMultipleCoils,
+	WriteMultipleRegisters:     funcWriteMultipleRegisters,
+	ReadWriteMultipleRegisters: funcReadWriteMultipleRegisters,
+}
+
+// IsReadFunction the function only reads the data, it can be used by the polling groups
+func IsReadFunction(function string) bool {
+	switch function {
+	case ReadCoils, ReadDiscreteInputs, ReadHoldingRegisters, ReadInputRegisters:
+		return true
+	}
+	return false
+}
+
+// ExceptionError the slave answered with the exception response
+type ExceptionError struct {
+	Function byte
+	Code     byte
+}
+
+// Error ...
+func (e *ExceptionError) Error() string {
+	var name string
+	switch e.Code {
+	case ExceptionIllegalFunction:
+		name = "illegal function"
+	case ExceptionIllegalDataAddress:
+		name = "illegal data address"
+	case ExceptionIllegalDataValue:
+		name = "illegal data value"
+	case ExceptionServerDeviceFailure:
+		name = "server device failure"
+	case ExceptionServerDeviceBusy:
+		name = "server device busy"
+	default:
+		name = "unknown exception"
+	}
+	return fmt.Sprintf("modbus exception %d (%s), function %d", e.Code, name, e.Function)
+}
+
+// pdu protocol data unit, the function code and the data
+type pdu struct {
+	function byte
+	data     []byte
+}
+
+// encodeRequest build the request pdu for the function
+func encodeRequest(function string, address, count uint16, command []uint16) (req pdu, err error) {
+
+	code, ok := functionCodes[function]
+	if !ok {
+		err = fmt.Errorf("%s: %s", ErrUnknownFunction.Error(), function)
+		return
+	}
+
+	req.function = code
+
+	switch code {
+	case funcReadCoils, funcReadDiscreteInputs:
+		if count == 0 || count > maxReadBits {
+			err = fmt.Errorf("count must be between 1 and %d", maxReadBits)
+			return
+		}
+		req.data = uint16ToBytes(address, count)
+
+	case funcReadHoldingRegisters, funcReadInputRegisters:
+		if count == 0 || count > maxReadRegisters {
+			err = fmt.Errorf("count must be between 1 and %d", maxReadRegisters)
+			return
+		}
+		req.data = uint16ToBytes(address, count)
+
+	case funcWriteSingleCoil:
+		if len(command) != 1 {
+			err = errors.New("command must contain one value")
+			return
+		}
+		var value uint16
+		if command[0] != 0 {
+			value = 0xFF00
+		}
+		req.data = uint16ToBytes(address, value)
+
+	case funcWriteSingleRegister:
+		if len(command) != 1 {
+			err = errors.New("command must contain one value")
+			return
+		}
+		req.data = uint16ToBytes(address, command[0])
+
+	case funcWriteMultipleCoils:
+		if len(command) == 0 || len(command) > maxWriteBits {
+			err = fmt.Errorf("command must contain from 1 to %d values", maxWriteBits)
+			return
+		}
+		bits := packBits(command)
+		req.data = append(uint16ToBytes(address, uint16(len(command))), byte(len(bits)))
+		req.data = append(req.data, bits...)
+
+	case funcWriteMultipleRegisters:
+		if len(command) == 0 || len(command) > maxWriteRegisters {
+			err = fmt.Errorf("command must contain from 1 to %d values", maxWriteRegisters)
+			return
+		}
+		req.data = append(uint16ToBytes(address, uint16(len(command))), byte(len(command)*2))
+		req.data = append(req.data, uint16ToBytes(command...)...)
+
+	case funcReadWriteMultipleRegisters:
+		// the registers are read and written from the same address
+		if count == 0 || count > maxReadRegisters {
+			err = fmt.Errorf("count must be between 1 and %d", maxReadRegisters)
+			return
+		}
+		if len(command) == 0 || len(command) > maxReadWriteRegisters {
+			err = fmt.Errorf("command must contain from 1 to %d values", maxReadWriteRegisters)
+			return
+		}
+		req.data = uint16ToBytes(address, count, address, uint16(len(command)))
+		req.data = append(req.data, byte(len(command)*2))
+		req.data = append(req.data, uint16ToBytes(command...)...)
+	}
+
+	return
+}
+
+// decodeResponse check the response pdu and extract the values
+func decodeResponse(req, resp pdu, count uint16) (result []uint16, err error) {
+
+	if resp.function == req.function|exceptionBit {
+		if len(resp.data) != 1 {
+			err = ErrBadResponse
+			return
+		}
+		err = &ExceptionError{Function: req.function, Code: resp.data[0]}
+		return
+	}
+
+	if resp.function != req.function {
+		err = fmt.Errorf("%s: function %d, expected %d", ErrBadResponse.Error(), resp.function, req.function)
+		return
+	}
+
+	switch req.function {
+	case funcReadCoils, funcReadDiscreteInputs:
+		if len(resp.data) < 1 || int(resp.data[0]) != len(resp.data)-1 || len(resp.data)-1 < (int(count)+7)/8 {
+			err = ErrBadResponse
+			return
+		}
+		result = unpackBits(resp.data[1:], count)
+
+	case funcReadHoldingRegisters, funcReadInputRegisters, funcReadWriteMultipleRegisters:
+		if len(resp.data) < 1 || int(resp.data[0]) != len(resp.data)-1 || len(resp.data)-1 != int(count)*2 {
+			err = ErrBadResponse
+			return
+		}
+		result = bytesToUint16(resp.data[1:])
+
+	case funcWriteSingleCoil, funcWriteSingleRegister:
+		if len(resp.data) != 4 || !bytes.Equal(resp.data, req.data) {
+			err = ErrBadResponse
+			return
+		}
+		value := binary.BigEndian.Uint16(resp.data[2:])
+		if req.function == funcWriteSingleCoil && value != 0 {
+			value = 1
+		}
+		result = []uint16{value}
+
+	case funcWriteMultipleCoils, funcWriteMultipleRegisters:
+		if len(resp.data) != 4 || !bytes.Equal(resp.data, req.data[:4]) {
+			err = ErrBadResponse
+			return
+		}
+	}
+
+	return
+}
+
+func uint16ToBytes(values ...uint16) []byte {
+	b := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.BigEndian.PutUint16(b[i*2:], v)
+	}
+	return b
+}
+
+func bytesToUint16(b []byte) []uint16 {
+	values := make([]uint16, len(b)/2)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint16(b[i*2:])
+	}
+	return values
+}
+
+// packBits any non zero value is the set bit, the first value is the lowest bit of the first byte
+func packBits(values []uint16) []byte {
+	b := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v != 0 {
+			b[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return b
+}
+
+func unpackBits(b []byte, count uint16) []uint16 {
+	values := make([]uint16, count)
+	for i := range values {
+		if b[i/8]&(1<<uint(i%8)) != 0 {
+			values[i] = 1
+		}
+	}
+	return values
+}
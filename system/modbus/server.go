@@ -0,0 +1,329 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+)
+
+// Server modbus tcp slave simulator, keeps the coils and the registers of the slaves
+// in memory. It is used to check the client and the devices without the hardware
+type Server struct {
+	sync.Mutex
+	listener net.Listener
+	slaves   map[uint8]*slaveData
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+type slaveData struct {
+	coils            map[uint16]bool
+	discreteInputs   map[uint16]bool
+	holdingRegisters map[uint16]uint16
+	inputRegisters   map[uint16]uint16
+}
+
+// NewServer ...
+func NewServer() *Server {
+	return &Server{
+		slaves: make(map[uint8]*slaveData),
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+// Listen start the server on the address, e.g. "127.0.0.1:0" for the random port
+func (s *Server) Listen(address string) (err error) {
+
+	if s.listener, err = net.Listen("tcp", address); err != nil {
+		return
+	}
+
+	s.wg.Add(1)
+	go s.accept()
+
+	return
+}
+
+// Addr ...
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Close stop the server and close the client connections
+func (s *Server) Close() {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	s.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.Unlock()
+	s.wg.Wait()
+}
+
+// SetCoils ...
+func (s *Server) SetCoils(slaveId uint8, address uint16, values ...bool) {
+	s.Lock()
+	slave := s.slave(slaveId)
+	for i, v := range values {
+		slave.coils[address+uint16(i)] = v
+	}
+	s.Unlock()
+}
+
+// SetDiscreteInputs ...
+func (s *Server) SetDiscreteInputs(slaveId uint8, address uint16, values ...bool) {
+	s.Lock()
+	slave := s.slave(slaveId)
+	for i, v := range values {
+		slave.discreteInputs[address+uint16(i)] = v
+	}
+	s.Unlock()
+}
+
+// SetHoldingRegisters ...
+func (s *Server) SetHoldingRegisters(slaveId uint8, address uint16, values ...uint16) {
+	s.Lock()
+	slave := s.slave(slaveId)
+	for i, v := range values {
+		slave.holdingRegisters[address+uint16(i)] = v
+	}
+	s.Unlock()
+}
+
+// SetInputRegisters ...
+func (s *Server) SetInputRegisters(slaveId uint8, address uint16, values ...uint16) {
+	s.Lock()
+	slave := s.slave(slaveId)
+	for i, v := range values {
+		slave.inputRegisters[address+uint16(i)] = v
+	}
+	s.Unlock()
+}
+
+// Coils ...
+func (s *Server) Coils(slaveId uint8, address, count uint16) (values []bool) {
+	s.Lock()
+	slave := s.slave(slaveId)
+	for i := uint16(0); i < count; i++ {
+		values = append(values, slave.coils[address+i])
+	}
+	s.Unlock()
+	return
+}
+
+// HoldingRegisters ...
+func (s *Server) HoldingRegisters(slaveId uint8, address, count uint16) (values []uint16) {
+	s.Lock()
+	slave := s.slave(slaveId)
+	for i := uint16(0); i < count; i++ {
+		values = append(values, slave.holdingRegisters[address+i])
+	}
+	s.Unlock()
+	return
+}
+
+func (s *Server) slave(slaveId uint8) *slaveData {
+	slave, ok := s.slaves[slaveId]
+	if !ok {
+		slave = &slaveData{
+			coils:            make(map[uint16]bool),
+			discreteInputs:   make(map[uint16]bool),
+			holdingRegisters: make(map[uint16]uint16),
+			inputRegisters:   make(map[uint16]uint16),
+		}
+		s.slaves[slaveId] = slave
+	}
+	return slave
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.Lock()
+		s.conns[conn] = struct{}{}
+		s.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		s.Lock()
+		delete(s.conns, conn)
+		s.Unlock()
+		_ = conn.Close()
+		s.wg.Done()
+	}()
+
+	for {
+		transactionId, unitId, req, err := readAdu(conn)
+		if err != nil {
+			return
+		}
+
+		resp := s.handle(unitId, req)
+
+		adu := make([]byte, mbapHeaderLength+1+len(resp.data))
+		binary.BigEndian.PutUint16(adu[0:], transactionId)
+		binary.BigEndian.PutUint16(adu[4:], uint16(2+len(resp.data)))
+		adu[6] = unitId
+		adu[7] = resp.function
+		copy(adu[8:], resp.data)
+
+		if _, err = conn.Write(adu); err != nil {
+			return
+		}
+	}
+}
+
+// handle execute the request on the slave memory
+func (s *Server) handle(slaveId uint8, req pdu) (resp pdu) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	slave := s.slave(slaveId)
+	resp.function = req.function
+
+	exception := func(code byte) pdu {
+		return pdu{function: req.function | exceptionBit, data: []byte{code}}
+	}
+
+	data := req.data
+	switch req.function {
+	case funcReadCoils, funcReadDiscreteInputs:
+		if len(data) != 4 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		address, count := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		if count == 0 || count > maxReadBits {
+			return exception(ExceptionIllegalDataValue)
+		}
+		bits := slave.coils
+		if req.function == funcReadDiscreteInputs {
+			bits = slave.discreteInputs
+		}
+		values := make([]uint16, count)
+		for i := range values {
+			if bits[address+uint16(i)] {
+				values[i] = 1
+			}
+		}
+		packed := packBits(values)
+		resp.data = append([]byte{byte(len(packed))}, packed...)
+
+	case funcReadHoldingRegisters, funcReadInputRegisters:
+		if len(data) != 4 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		address, count := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		if count == 0 || count > maxReadRegisters {
+			return exception(ExceptionIllegalDataValue)
+		}
+		registers := slave.holdingRegisters
+		if req.function == funcReadInputRegisters {
+			registers = slave.inputRegisters
+		}
+		resp.data = []byte{byte(count * 2)}
+		for i := uint16(0); i < count; i++ {
+			resp.data = append(resp.data, uint16ToBytes(registers[address+i])...)
+		}
+
+	case funcWriteSingleCoil:
+		if len(data) != 4 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		address, value := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		if value != 0 && value != 0xFF00 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		slave.coils[address] = value == 0xFF00
+		resp.data = data
+
+	case funcWriteSingleRegister:
+		if len(data) != 4 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		slave.holdingRegisters[binary.BigEndian.Uint16(data)] = binary.BigEndian.Uint16(data[2:])
+		resp.data = data
+
+	case funcWriteMultipleCoils:
+		if len(data) < 5 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		address, count := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		if count == 0 || int(data[4]) != (int(count)+7)/8 || len(data)-5 != int(data[4]) {
+			return exception(ExceptionIllegalDataValue)
+		}
+		for i, v := range unpackBits(data[5:], count) {
+			slave.coils[address+uint16(i)] = v != 0
+		}
+		resp.data = data[:4]
+
+	case funcWriteMultipleRegisters:
+		if len(data) < 5 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		address, count := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		if count == 0 || int(data[4]) != int(count)*2 || len(data)-5 != int(data[4]) {
+			return exception(ExceptionIllegalDataValue)
+		}
+		for i, v := range bytesToUint16(data[5:]) {
+			slave.holdingRegisters[address+uint16(i)] = v
+		}
+		resp.data = data[:4]
+
+	case funcReadWriteMultipleRegisters:
+		if len(data) < 9 {
+			return exception(ExceptionIllegalDataValue)
+		}
+		readAddress, readCount := binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:])
+		writeAddress, writeCount := binary.BigEndian.Uint16(data[4:]), binary.BigEndian.Uint16(data[6:])
+		if readCount == 0 || readCount > maxReadRegisters ||
+			int(data[8]) != int(writeCount)*2 || len(data)-9 != int(data[8]) {
+			return exception(ExceptionIllegalDataValue)
+		}
+		// the write operation is performed before the read
+		for i, v := range bytesToUint16(data[9:]) {
+			slave.holdingRegisters[writeAddress+uint16(i)] = v
+		}
+		resp.data = []byte{byte(readCount * 2)}
+		for i := uint16(0); i < readCount; i++ {
+			resp.data = append(resp.data, uint16ToBytes(slave.holdingRegisters[readAddress+i])...)
+		}
+
+	default:
+		return exception(ExceptionIllegalFunction)
+	}
+
+	return
+}
@@ -0,0 +1,110 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// Javascript Binding
+//
+// Crypto
+// 	 .Base64Encode(str)
+// 	 .Base64Decode(str)
+// 	 .Md5(str)
+// 	 .Sha1(str)
+// 	 .Sha256(str)
+// 	 .Sha512(str)
+// 	 .Hmac(algorithm, key, str)
+//
+type CryptoBind struct{}
+
+// Base64Encode ...
+func (c *CryptoBind) Base64Encode(data string) string {
+	return base64.StdEncoding.EncodeToString([]byte(data))
+}
+
+// Base64Decode the empty string if the data is not base64
+func (c *CryptoBind) Base64Decode(data string) string {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		log.Warnf("crypto: %s", err.Error())
+		return ""
+	}
+	return string(b)
+}
+
+// Md5 ...
+func (c *CryptoBind) Md5(data string) string {
+	return sum(md5.New(), data)
+}
+
+// Sha1 ...
+func (c *CryptoBind) Sha1(data string) string {
+	return sum(sha1.New(), data)
+}
+
+// Sha256 ...
+func (c *CryptoBind) Sha256(data string) string {
+	return sum(sha256.New(), data)
+}
+
+// Sha512 ...
+func (c *CryptoBind) Sha512(data string) string {
+	return sum(sha512.New(), data)
+}
+
+// Hmac hex digest, the algorithm: md5, sha1, sha256 or sha512
+func (c *CryptoBind) Hmac(algorithm, key, data string) string {
+	f, err := hashFunc(algorithm)
+	if err != nil {
+		log.Warnf("crypto: %s", err.Error())
+		return ""
+	}
+	return sum(hmac.New(f, []byte(key)), data)
+}
+
+func hashFunc(algorithm string) (f func() hash.Hash, err error) {
+	switch algorithm {
+	case "md5":
+		f = md5.New
+	case "sha1":
+		f = sha1.New
+	case "sha256":
+		f = sha256.New
+	case "sha512":
+		f = sha512.New
+	default:
+		err = fmt.Errorf("unknown algorithm \"%s\"", algorithm)
+	}
+	return
+}
+
+func sum(h hash.Hash, data string) string {
+	_, _ = h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,257 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHttpTimeout ...
+	DefaultHttpTimeout = time.Second * 10
+	httpMaxBody        = 10 << 20
+)
+
+var (
+	// ErrHostNotAllowed ...
+	ErrHostNotAllowed = errors.New("host not allowed")
+)
+
+// HttpResponse ...
+type HttpResponse struct {
+	Status  int
+	Body    string
+	Headers map[string]string
+	Err     string
+}
+
+// Json the body parsed as json
+func (r *HttpResponse) Json() (value interface{}) {
+	if err := json.Unmarshal([]byte(r.Body), &value); err != nil {
+		log.Warnf("http: %s", err.Error())
+	}
+	return
+}
+
+// Javascript Binding
+//
+// Http
+// 	 .Get(url, options)
+// 	 .Post(url, body, options)
+// 	 .Put(url, body, options)
+// 	 .Delete(url, options)
+//
+// options: {headers: {}, timeout: ms, user: "", password: "", token: ""},
+// the timeout of the options can not exceed the timeout of the config
+//
+type HttpBind struct {
+	hosts   []string
+	timeout time.Duration
+}
+
+// NewHttpBind only the hosts of the list are available: "host", "host:port", "*.domain", "192.168.1.0/24" or "*"
+func NewHttpBind(hosts []string, timeout time.Duration) *HttpBind {
+	if timeout <= 0 {
+		timeout = DefaultHttpTimeout
+	}
+	return &HttpBind{
+		hosts:   hosts,
+		timeout: timeout,
+	}
+}
+
+// Get ...
+func (h *HttpBind) Get(uri string, options map[string]interface{}) *HttpResponse {
+	return h.do(http.MethodGet, uri, nil, options)
+}
+
+// Post ...
+func (h *HttpBind) Post(uri string, body interface{}, options map[string]interface{}) *HttpResponse {
+	return h.do(http.MethodPost, uri, body, options)
+}
+
+// Put ...
+func (h *HttpBind) Put(uri string, body interface{}, options map[string]interface{}) *HttpResponse {
+	return h.do(http.MethodPut, uri, body, options)
+}
+
+// Delete ...
+func (h *HttpBind) Delete(uri string, options map[string]interface{}) *HttpResponse {
+	return h.do(http.MethodDelete, uri, nil, options)
+}
+
+func (h *HttpBind) do(method, uri string, body interface{}, options map[string]interface{}) (r *HttpResponse) {
+
+	r = &HttpResponse{
+		Headers: make(map[string]string),
+	}
+
+	req, err := h.request(method, uri, body, options)
+	if err != nil {
+		r.Err = err.Error()
+		log.Warnf("http: %s %s: %s", method, uri, r.Err)
+		return
+	}
+
+	client := &http.Client{
+		Timeout: h.requestTimeout(options),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return h.allowed(req.URL)
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		r.Err = err.Error()
+		log.Warnf("http: %s %s: %s", method, uri, r.Err)
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, httpMaxBody))
+	if err != nil {
+		r.Err = err.Error()
+	}
+
+	r.Status = resp.StatusCode
+	r.Body = string(data)
+	for name := range resp.Header {
+		r.Headers[name] = resp.Header.Get(name)
+	}
+
+	return
+}
+
+func (h *HttpBind) request(method, uri string, body interface{}, options map[string]interface{}) (req *http.Request, err error) {
+
+	var u *url.URL
+	if u, err = url.Parse(uri); err != nil {
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		err = fmt.Errorf("unsupported scheme \"%s\"", u.Scheme)
+		return
+	}
+	if err = h.allowed(u); err != nil {
+		return
+	}
+
+	// strings are sent as is, the rest as json
+	var payload []byte
+	var isJson bool
+	switch v := body.(type) {
+	case nil:
+	case string:
+		payload = []byte(v)
+	case []byte:
+		payload = v
+	default:
+		if payload, err = json.Marshal(v); err != nil {
+			return
+		}
+		isJson = true
+	}
+
+	if req, err = http.NewRequest(method, u.String(), bytes.NewReader(payload)); err != nil {
+		return
+	}
+
+	if isJson {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if headers, ok := options["headers"].(map[string]interface{}); ok {
+		for name, value := range headers {
+			req.Header.Set(name, fmt.Sprintf("%v", value))
+		}
+	}
+
+	if user, ok := options["user"].(string); ok {
+		password, _ := options["password"].(string)
+		req.SetBasicAuth(user, password)
+	}
+
+	if token, ok := options["token"].(string); ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return
+}
+
+// requestTimeout the timeout of the options, up to the timeout of the config
+func (h *HttpBind) requestTimeout(options map[string]interface{}) time.Duration {
+	if v, ok := options["timeout"]; ok {
+		if ms := toInt64(v); ms > 0 {
+			if timeout := time.Millisecond * time.Duration(ms); timeout < h.timeout {
+				return timeout
+			}
+		}
+	}
+	return h.timeout
+}
+
+// allowed the host is in the allowlist of the config
+func (h *HttpBind) allowed(u *url.URL) error {
+
+	host := strings.ToLower(u.Hostname())
+	ip := net.ParseIP(host)
+
+	for _, pattern := range h.hosts {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case pattern == "*":
+			return nil
+		case pattern == host || pattern == strings.ToLower(u.Host):
+			return nil
+		case strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:]):
+			return nil
+		case ip != nil && strings.Contains(pattern, "/"):
+			if _, network, err := net.ParseCIDR(pattern); err == nil && network.Contains(ip) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Host)
+}
+
+func toInt64(v interface{}) int64 {
+	switch value := v.(type) {
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	case float64:
+		return int64(value)
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestHttpAllowed(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		hosts   []string
+		uri     string
+		allowed bool
+	}{
+		{"empty list", nil, "http://example.com", false},
+		{"any host", []string{"*"}, "http://example.com:8080/path", true},
+		{"host", []string{"example.com"}, "http://example.com/path", true},
+		{"host with any port", []string{"example.com"}, "http://example.com:8080", true},
+		{"host case", []string{"Example.COM"}, "http://EXAMPLE.com", true},
+		{"other host", []string{"example.com"}, "http://example.org", false},
+		{"subdomain of the host", []string{"example.com"}, "http://api.example.com", false},
+		{"host and port", []string{"example.com:8080"}, "http://example.com:8080", true},
+		{"host and other port", []string{"example.com:8080"}, "http://example.com:9090", false},
+		{"host and default port", []string{"example.com:8080"}, "http://example.com", false},
+		{"wildcard", []string{"*.example.com"}, "https://api.example.com", true},
+		{"wildcard deep", []string{"*.example.com"}, "https://v1.api.example.com", true},
+		{"wildcard domain itself", []string{"*.example.com"}, "https://example.com", false},
+		{"wildcard suffix", []string{"*.example.com"}, "https://badexample.com", false},
+		{"cidr", []string{"192.168.1.0/24"}, "http://192.168.1.10/api", true},
+		{"cidr with port", []string{"192.168.1.0/24"}, "http://192.168.1.10:8123", true},
+		{"cidr outside", []string{"192.168.1.0/24"}, "http://192.168.2.10", false},
+		{"cidr hostname", []string{"192.168.1.0/24"}, "http://localhost", false},
+		{"cidr ipv6", []string{"fd00::/8"}, "http://[fd00::1]:8080", true},
+		{"ip", []string{"10.0.0.1"}, "http://10.0.0.1:80", true},
+		{"second pattern", []string{"example.org", "example.com"}, "http://example.com", true},
+	}
+
+	for _, test := range tests {
+		u, err := url.Parse(test.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = NewHttpBind(test.hosts, 0).allowed(u)
+		if test.allowed && err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err.Error())
+		}
+		if !test.allowed && !errors.Is(err, ErrHostNotAllowed) {
+			t.Errorf("%s: expected ErrHostNotAllowed, got %v", test.name, err)
+		}
+	}
+}
+
+func TestHttpRequestTimeout(t *testing.T) {
+
+	h := NewHttpBind(nil, time.Second*10)
+
+	tests := []struct {
+		name     string
+		options  map[string]interface{}
+		expected time.Duration
+	}{
+		{"default", nil, time.Second * 10},
+		{"shorter", map[string]interface{}{"timeout": int64(500)}, time.Millisecond * 500},
+		{"float", map[string]interface{}{"timeout": float64(1500)}, time.Millisecond * 1500},
+		{"longer is clamped", map[string]interface{}{"timeout": int64(60000)}, time.Second * 10},
+		{"zero", map[string]interface{}{"timeout": 0}, time.Second * 10},
+		{"negative", map[string]interface{}{"timeout": -1}, time.Second * 10},
+	}
+
+	for _, test := range tests {
+		if timeout := h.requestTimeout(test.options); timeout != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, timeout)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"time"
+)
+
+var layouts = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"RFC822":   time.RFC822,
+	"Kitchen":  time.Kitchen,
+	"DateTime": "2006-01-02 15:04:05",
+	"Date":     "2006-01-02",
+	"Time":     "15:04:05",
+}
+
+// Javascript Binding
+//
+// Time
+// 	 .Now()
+// 	 .Format(ms, layout, zone)
+// 	 .Parse(layout, value, zone)
+//
+// the time is in milliseconds since the epoch as in Date.now(), the layout is the go layout
+// ("2006-01-02 15:04") or the name: RFC3339, RFC1123, RFC822, Kitchen, DateTime, Date, Time
+//
+type TimeBind struct{}
+
+// Now ...
+func (t *TimeBind) Now() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// Format the time in the zone ("Europe/Moscow"), the local zone if empty
+func (t *TimeBind) Format(ms int64, layout, zone string) string {
+	return time.Unix(0, ms*int64(time.Millisecond)).In(location(zone)).Format(timeLayout(layout))
+}
+
+// Parse the time in the zone if the value has not it, zero on error
+func (t *TimeBind) Parse(layout, value, zone string) int64 {
+	tm, err := time.ParseInLocation(timeLayout(layout), value, location(zone))
+	if err != nil {
+		log.Warnf("time: %s", err.Error())
+		return 0
+	}
+	return tm.UnixNano() / int64(time.Millisecond)
+}
+
+func timeLayout(layout string) string {
+	if v, ok := layouts[layout]; ok {
+		return v
+	}
+	if layout == "" {
+		return time.RFC3339
+	}
+	return layout
+}
+
+func location(zone string) *time.Location {
+	if zone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		log.Warnf("time: %s", err.Error())
+		return time.Local
+	}
+	return loc
+}
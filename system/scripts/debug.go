@@ -0,0 +1,370 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja/parser"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// the probe is called before the every statement of the script in the debug session
+	debugProbe = "__debug_line__"
+
+	// DebugIdleTimeout the paused session is stopped if there are no commands
+	DebugIdleTimeout = time.Minute * 10
+)
+
+var (
+	// ErrDebugStopped ...
+	ErrDebugStopped = errors.New("debug session stopped")
+	// ErrDebugNotPaused ...
+	ErrDebugNotPaused = errors.New("debug session is not paused")
+)
+
+type debugCommand int
+
+const (
+	debugContinue = debugCommand(iota)
+	debugStep
+	debugStop
+)
+
+// DebugState the script paused on the line, the lines are of the compiled javascript
+type DebugState struct {
+	Line    int                    `json:"line"`
+	Globals map[string]interface{} `json:"globals"`
+	Vars    map[string]interface{} `json:"vars"` // vars of the message
+}
+
+// DebugSession step-wise run of the script with the breakpoints by line.
+// The script pauses before the statement on the line with the breakpoint, or before
+// the next statement after Step, the callbacks are called from the goroutine of the script.
+type DebugSession struct {
+	sync.Mutex
+	breakpoints map[int]bool
+	step        bool
+	stopped     bool
+	state       *DebugState
+	commands    chan debugCommand
+	onPause     func(state *DebugState)
+	onFinish    func(result string, err error)
+}
+
+// NewDebugSession ...
+func NewDebugSession(breakpoints []int,
+	onPause func(state *DebugState),
+	onFinish func(result string, err error)) *DebugSession {
+
+	session := &DebugSession{
+		commands: make(chan debugCommand, 1),
+		onPause:  onPause,
+		onFinish: onFinish,
+	}
+	session.SetBreakpoints(breakpoints)
+
+	return session
+}
+
+// SetBreakpoints replace the breakpoints
+func (d *DebugSession) SetBreakpoints(lines []int) {
+	d.Lock()
+	d.breakpoints = make(map[int]bool, len(lines))
+	for _, line := range lines {
+		d.breakpoints[line] = true
+	}
+	d.Unlock()
+}
+
+// Breakpoints ...
+func (d *DebugSession) Breakpoints() (lines []int) {
+	d.Lock()
+	lines = make([]int, 0, len(d.breakpoints))
+	for line := range d.breakpoints {
+		lines = append(lines, line)
+	}
+	d.Unlock()
+	sort.Ints(lines)
+	return
+}
+
+// State the state of the paused script, nil if it is running
+func (d *DebugSession) State() *DebugState {
+	d.Lock()
+	defer d.Unlock()
+	return d.state
+}
+
+// Continue run to the next breakpoint
+func (d *DebugSession) Continue() error {
+	return d.command(debugContinue)
+}
+
+// Step pause before the next statement
+func (d *DebugSession) Step() error {
+	return d.command(debugStep)
+}
+
+// Stop abort the script, the running script is stopped before the next statement
+func (d *DebugSession) Stop() {
+	d.Lock()
+	d.stopped = true
+	paused := d.state != nil
+	d.Unlock()
+	if paused {
+		_ = d.command(debugStop)
+	}
+}
+
+func (d *DebugSession) command(cmd debugCommand) error {
+	d.Lock()
+	defer d.Unlock()
+	if d.state == nil {
+		return ErrDebugNotPaused
+	}
+	select {
+	case d.commands <- cmd:
+	default:
+	}
+	return nil
+}
+
+// pause is called by the probe, returns false if the script must be stopped
+func (d *DebugSession) pause(line int, state func() *DebugState) bool {
+
+	d.Lock()
+	if d.stopped {
+		d.Unlock()
+		return false
+	}
+	if !d.step && !d.breakpoints[line] {
+		d.Unlock()
+		return true
+	}
+	d.state = state()
+	d.Unlock()
+
+	if d.onPause != nil {
+		d.onPause(d.state)
+	}
+
+	var cmd debugCommand
+	select {
+	case cmd = <-d.commands:
+	case <-time.After(DebugIdleTimeout):
+		cmd = debugStop
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.state = nil
+	d.step = cmd == debugStep
+	if cmd == debugStop {
+		d.stopped = true
+	}
+
+	return !d.stopped
+}
+
+func (d *DebugSession) finish(result string, err error) {
+	d.Lock()
+	d.stopped = true
+	d.Unlock()
+	if d.onFinish != nil {
+		d.onFinish(result, err)
+	}
+}
+
+// compileDebugProgram compile the script with the probe before the every statement
+func compileDebugProgram(source string) (program *goja.Program, err error) {
+
+	var prg *ast.Program
+	if prg, err = parser.ParseFile(nil, "", source, 0); err != nil {
+		return goja.Compile("", source, false)
+	}
+
+	lines := newLineIndex(source)
+	prg.Body = probeList(prg.Body, lines)
+	probeStatements(reflect.ValueOf(prg.Body), lines, make(map[*ast.FunctionLiteral]bool))
+
+	guardFunctions(reflect.ValueOf(prg), make(map[ast.Node]bool))
+
+	program, err = goja.CompileAST(prg, false)
+
+	return
+}
+
+var statementsType = reflect.TypeOf([]ast.Statement{})
+
+// probeStatements walk the tree and add the probe to the every list of statements
+func probeStatements(v reflect.Value, lines lineIndex, visited map[*ast.FunctionLiteral]bool) {
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() || v.Type() == fileType {
+			return
+		}
+		// function declarations are referenced from the body and from the declaration list
+		if v.Kind() == reflect.Ptr && v.CanInterface() {
+			if fn, ok := v.Interface().(*ast.FunctionLiteral); ok {
+				if visited[fn] {
+					return
+				}
+				visited[fn] = true
+			}
+		}
+		probeStatements(v.Elem(), lines, visited)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if field.Type() == statementsType && field.CanSet() {
+				field.Set(reflect.ValueOf(probeList(field.Interface().([]ast.Statement), lines)))
+			}
+			probeStatements(field, lines, visited)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			probeStatements(v.Index(i), lines, visited)
+		}
+	}
+}
+
+// probeList the probe before the every statement, the directive prologue stays at the beginning
+func probeList(list []ast.Statement, lines lineIndex) []ast.Statement {
+
+	result := make([]ast.Statement, 0, len(list)*2)
+	prologue := true
+	for _, st := range list {
+		if prologue {
+			if expr, ok := st.(*ast.ExpressionStatement); ok {
+				if _, ok = expr.Expression.(*ast.StringLiteral); ok {
+					result = append(result, st)
+					continue
+				}
+			}
+			prologue = false
+		}
+		result = append(result, probeCall(st.Idx0(), lines.line(st.Idx0())), st)
+	}
+
+	return result
+}
+
+func probeCall(idx file.Idx, line int) ast.Statement {
+	return &ast.ExpressionStatement{
+		Expression: &ast.CallExpression{
+			Callee:           &ast.Identifier{Name: debugProbe, Idx: idx},
+			LeftParenthesis:  idx,
+			ArgumentList:     []ast.Expression{&ast.NumberLiteral{Idx: idx, Literal: strconv.Itoa(line), Value: int64(line)}},
+			RightParenthesis: idx,
+		},
+	}
+}
+
+// lineIndex offsets of the beginning of the lines
+type lineIndex []int
+
+func newLineIndex(source string) lineIndex {
+	lines := lineIndex{0}
+	for i, c := range source {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// line the line number of the position in the source, idx is 1-based
+func (l lineIndex) line(idx file.Idx) int {
+	return sort.Search(len(l), func(i int) bool { return l[i] > int(idx)-1 })
+}
+
+// debugValue the value of the global variable suitable for json, functions and bindings are skipped
+func debugValue(v interface{}) (value interface{}, ok bool) {
+
+	if v == nil {
+		return nil, true
+	}
+
+	if reflect.TypeOf(v).Kind() == reflect.Func {
+		return nil, false
+	}
+
+	if m, isMap := v.(map[string]interface{}); isMap {
+		result := make(map[string]interface{}, len(m))
+		for k, item := range m {
+			if value, ok = debugValue(item); ok {
+				result[k] = value
+			}
+		}
+		return result, true
+	}
+
+	if list, isList := v.([]interface{}); isList {
+		result := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			if value, ok = debugValue(item); ok {
+				result = append(result, value)
+			}
+		}
+		return result, true
+	}
+
+	if _, err := json.Marshal(v); err != nil {
+		return nil, false
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Struct:
+		return nil, false
+	}
+
+	return v, true
+}
+
+// the globals of the engine, not of the script
+var debugSkipGlobals = map[string]bool{
+	"self":         true,
+	"console":      true,
+	"global":       true,
+	"exports":      true,
+	"module":       true,
+	"message":      true,
+	"CoffeeScript": true,
+	"ts":           true,
+	sandboxEnter:   true,
+	sandboxLeave:   true,
+	sandboxAlloc:   true,
+	sandboxGrow:    true,
+	debugProbe:     true,
+}
+
+func isEngineGlobal(name string) bool {
+	return debugSkipGlobals[name] || strings.HasPrefix(name, "__")
+}
@@ -0,0 +1,243 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"encoding/json"
+	"fmt"
+	m "github.com/e154/smart-home/models"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// the bindings without the side effects, the rest are replaced by the mocks in the dry run
+// and the calls are recorded instead of the real work
+var dryRunAllowed = map[string]bool{
+	"Log":      true,
+	"Crypto":   true,
+	"Time":     true,
+	"Template": true,
+}
+
+// recorder the calls of the mocks
+type recorder struct {
+	sync.Mutex
+	calls   []*m.ScriptCall
+	returns map[string]interface{}
+}
+
+func newRecorder(returns map[string]interface{}) *recorder {
+	if returns == nil {
+		returns = make(map[string]interface{})
+	}
+	return &recorder{
+		calls:   make([]*m.ScriptCall, 0),
+		returns: returns,
+	}
+}
+
+func (r *recorder) add(name string, args []interface{}) {
+	r.Lock()
+	r.calls = append(r.calls, &m.ScriptCall{
+		Name: name,
+		Args: args,
+	})
+	r.Unlock()
+}
+
+func (r *recorder) result(name string) (value interface{}, ok bool) {
+	value, ok = r.returns[name]
+	return
+}
+
+func (r *recorder) list() []*m.ScriptCall {
+	r.Lock()
+	defer r.Unlock()
+	return r.calls
+}
+
+// DryRun run the script with the all bindings mocked except Log, Crypto, Time and Template
+func (service *ScriptService) DryRun(script *m.Script, vars, returns map[string]interface{}) (run *m.ScriptDryRun, err error) {
+
+	var engine *Engine
+	if engine, err = service.NewEngine(script); err != nil {
+		return
+	}
+	defer engine.Close()
+
+	if script.Compiled == "" {
+		if err = engine.Compile(); err != nil {
+			return
+		}
+	}
+
+	rec := newRecorder(returns)
+	for _, name := range service.dryRunMocks() {
+		engine.script.Mock(name, service.mockPrototype(name), rec)
+	}
+
+	for name, value := range vars {
+		engine.PushStruct(name, value)
+	}
+
+	run = &m.ScriptDryRun{}
+	var runErr error
+	if run.Result, runErr = engine.Do(); runErr != nil {
+		run.Error = runErr.Error()
+	}
+	run.Calls = rec.list()
+
+	return
+}
+
+// RunTests run the test cases of the script in the dry run mode
+func (service *ScriptService) RunTests(script *m.Script) (results []*m.ScriptTestResult, err error) {
+
+	results = make([]*m.ScriptTestResult, 0, len(script.TestCases))
+	for _, testCase := range script.TestCases {
+		var run *m.ScriptDryRun
+		if run, err = service.DryRun(script, testCase.Vars, testCase.Returns); err != nil {
+			return
+		}
+		results = append(results, checkTestCase(testCase, run))
+	}
+
+	return
+}
+
+// dryRunMocks the names of the pushed bindings with the side effects
+func (service *ScriptService) dryRunMocks() (names []string) {
+
+	mocked := make(map[string]bool)
+	for _, pull := range []*Pull{service.mocks, service.structures, service.functions} {
+		for _, name := range pull.Names() {
+			if dryRunAllowed[name] || mocked[name] {
+				continue
+			}
+			mocked[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return
+}
+
+// mockPrototype the binding that is replaced by the mock, the last pushed one as in the engine
+func (service *ScriptService) mockPrototype(name string) (prototype interface{}) {
+
+	var ok bool
+	if prototype, ok = service.mocks.Get(name); ok {
+		return
+	}
+	if prototype, ok = service.structures.Get(name); ok {
+		return
+	}
+	if prototype, ok = service.functions.Get(name); ok {
+		return
+	}
+
+	return struct{}{}
+}
+
+func checkTestCase(testCase *m.ScriptTestCase, run *m.ScriptDryRun) (result *m.ScriptTestResult) {
+
+	result = &m.ScriptTestResult{
+		Name:     testCase.Name,
+		Failures: make([]string, 0),
+		Result:   run.Result,
+		Error:    run.Error,
+		Calls:    run.Calls,
+	}
+
+	switch {
+	case testCase.Error == "" && run.Error != "":
+		result.Failures = append(result.Failures, fmt.Sprintf("unexpected error: %s", run.Error))
+	case testCase.Error != "" && !strings.Contains(run.Error, testCase.Error):
+		result.Failures = append(result.Failures, fmt.Sprintf("expected error \"%s\", got \"%s\"", testCase.Error, run.Error))
+	}
+
+	if testCase.Result != nil && *testCase.Result != run.Result {
+		result.Failures = append(result.Failures, fmt.Sprintf("expected result \"%s\", got \"%s\"", *testCase.Result, run.Result))
+	}
+
+	// the expected calls are looked for in the order, the other calls between them are allowed
+	var i int
+	for _, expected := range testCase.Calls {
+		found := false
+		for ; i < len(run.Calls); i++ {
+			if run.Calls[i].Name == expected.Name && (expected.Args == nil || equalArgs(expected.Args, run.Calls[i].Args)) {
+				found = true
+				i++
+				break
+			}
+		}
+		if !found {
+			args, _ := json.Marshal(expected.Args)
+			result.Failures = append(result.Failures, fmt.Sprintf("expected call %s(%s) not found", expected.Name, args))
+			break
+		}
+	}
+
+	result.Passed = len(result.Failures) == 0
+
+	return
+}
+
+// equalArgs compare the arguments as json, numbers of the script and of the test case have different types
+func equalArgs(expected, actual []interface{}) bool {
+	var a, b interface{}
+	if !normalize(expected, &a) || !normalize(actual, &b) {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func normalize(v interface{}, out *interface{}) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+// exportArg the argument of the mock call suitable for json
+func exportArg(v interface{}) interface{} {
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[k] = exportArg(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = exportArg(item)
+		}
+		return result
+	}
+
+	if v != nil && reflect.TypeOf(v).Kind() == reflect.Func {
+		return "[function]"
+	}
+
+	return v
+}
@@ -25,6 +25,8 @@ import (
 	m "github.com/e154/smart-home/models"
 	"io/ioutil"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // IScript ...
@@ -37,8 +39,10 @@ type IScript interface {
 	PushFunction(string, interface{})
 	EvalString(string) (string, error)
 	Close()
-	CreateProgram(name, source string) (err error)
+	CreateProgram(name string, script *m.Script) (err error)
 	RunProgram(name string) (result string, err error)
+	Mock(name string, prototype interface{}, rec *recorder)
+	Debug(session *DebugSession) (string, error)
 }
 
 // Engine ...
@@ -49,16 +53,26 @@ type Engine struct {
 	IsRun      bool
 	functions  *Pull
 	structures *Pull
+	defaults   Limits
+	modules    *Modules
+	events     EventSource
+	eventsLock sync.Mutex
+	eventIds   map[int64]bool
+	closed     bool
 }
 
 // NewEngine ...
-func NewEngine(s *m.Script, functions, structures *Pull) (engine *Engine, err error) {
+func NewEngine(s *m.Script, functions, structures *Pull, defaults Limits, modules *Modules, events EventSource) (engine *Engine, err error) {
 
 	engine = &Engine{
 		model:      s,
 		buf:        make([]string, 0),
 		functions:  functions,
 		structures: structures,
+		defaults:   defaults,
+		modules:    modules,
+		events:     events,
+		eventIds:   make(map[int64]bool),
 	}
 
 	switch s.Lang {
@@ -103,7 +117,7 @@ func (s *Engine) EvalScript(script *m.Script) (result string, err error) {
 	}
 
 	if err == ErrorProgramNotFound {
-		if err = s.script.CreateProgram(programName, script.Compiled); err != nil {
+		if err = s.script.CreateProgram(programName, script); err != nil {
 			return
 		}
 		result, err = s.script.RunProgram(programName)
@@ -111,11 +125,57 @@ func (s *Engine) EvalScript(script *m.Script) (result string, err error) {
 	return
 }
 
-// Close ...
+// Close drop the event subscriptions of the scripts, the engine is not used after that
 func (s *Engine) Close() {
+
+	s.eventsLock.Lock()
+	if s.closed {
+		s.eventsLock.Unlock()
+		return
+	}
+	s.closed = true
+	ids := s.eventIds
+	s.eventIds = make(map[int64]bool)
+	s.eventsLock.Unlock()
+
+	for id := range ids {
+		s.events.Off(id)
+	}
+
 	s.script.Close()
 }
 
+// on subscribe the handler of the script to the events, see Events.on()
+func (s *Engine) on(eventType string, fields map[string]interface{}, handler func(event map[string]interface{})) (id int64, err error) {
+
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	if s.events == nil || s.closed {
+		err = ErrEventsNotAvailable
+		return
+	}
+
+	id = s.events.On(eventType, fields, handler)
+	s.eventIds[id] = true
+
+	return
+}
+
+// off ...
+func (s *Engine) off(id int64) {
+
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	if !s.eventIds[id] {
+		return
+	}
+
+	delete(s.eventIds, id)
+	s.events.Off(id)
+}
+
 // DoFull ...
 func (s *Engine) DoFull() (res string, err error) {
 	if s.IsRun {
@@ -143,6 +203,11 @@ func (s *Engine) Do() (string, error) {
 	return s.script.Do()
 }
 
+// Debug run the script step-wise, see DebugSession
+func (s *Engine) Debug(session *DebugSession) (string, error) {
+	return s.script.Debug(session)
+}
+
 // AssertFunction ...
 func (s *Engine) AssertFunction(f string) (result string, err error) {
 
@@ -171,6 +236,28 @@ func (s *Engine) Get() IScript {
 	return s.script
 }
 
+// Limits the sandbox limits of the script, the zero fields are taken from the config
+func (s *Engine) Limits(script *m.Script) (limits Limits) {
+
+	limits = s.defaults
+	if script == nil {
+		return
+	}
+
+	if script.Timeout > 0 {
+		limits.Timeout = time.Millisecond * time.Duration(script.Timeout)
+	}
+	if script.MaxStack > 0 {
+		limits.MaxStack = script.MaxStack
+	}
+	if script.MaxMemory > 0 {
+		limits.MaxMemory = uint64(script.MaxMemory) << 20
+	}
+	limits.AllowExec = script.AllowExec
+
+	return
+}
+
 // File ...
 func (s *Engine) File(path string) ([]byte, error) {
 	b, err := ioutil.ReadFile(path)
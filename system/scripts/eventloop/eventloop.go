@@ -22,6 +22,7 @@ import (
 	"github.com/dop251/goja"
 	"github.com/dop251/goja_nodejs/console"
 	"github.com/dop251/goja_nodejs/require"
+	"sync"
 	"time"
 )
 
@@ -48,6 +49,8 @@ type EventLoop struct {
 	jobChan  chan func()
 	jobCount int32
 	running  bool
+	quitLock sync.Mutex
+	quit     chan struct{}
 }
 
 // NewEventLoop ...
@@ -98,10 +101,38 @@ func (loop *EventLoop) setInterval(call goja.FunctionCall) goja.Value {
 // of the function.
 // Do NOT use this function while the loop is already running. Use RunOnLoop() instead.
 func (loop *EventLoop) Run(fn func(*goja.Runtime)) {
+	loop.quitLock.Lock()
+	loop.quit = make(chan struct{})
+	loop.quitLock.Unlock()
+	loop.jobCount = 0
+
 	fn(loop.vm)
 	loop.run()
 }
 
+// Terminate breaks the loop started with Run(), the delayed jobs are dropped. It does not stop the job
+// that is being executed, use goja.Runtime.Interrupt() for it. It is safe to call from another goroutine.
+func (loop *EventLoop) Terminate() {
+	loop.quitLock.Lock()
+	defer loop.quitLock.Unlock()
+
+	if loop.quit == nil {
+		return
+	}
+
+	select {
+	case <-loop.quit:
+	default:
+		close(loop.quit)
+	}
+}
+
+func (loop *EventLoop) quitChan() chan struct{} {
+	loop.quitLock.Lock()
+	defer loop.quitLock.Unlock()
+	return loop.quit
+}
+
 // Start the event loop in the background. The loop continues to run until Stop() is called.
 func (loop *EventLoop) Start() {
 	go loop.runInBackground()
@@ -132,13 +163,18 @@ func (loop *EventLoop) RunOnLoop(fn func(*goja.Runtime)) {
 }
 
 func (loop *EventLoop) run() {
+	quit := loop.quitChan()
 	loop.running = true
 	for loop.running && loop.jobCount > 0 {
-		job, ok := <-loop.jobChan
-		if !ok {
-			break
+		select {
+		case job, ok := <-loop.jobChan:
+			if !ok {
+				return
+			}
+			job()
+		case <-quit:
+			return
 		}
-		job()
 	}
 }
 
@@ -157,9 +193,13 @@ func (loop *EventLoop) addTimeout(f goja.Callable, timeout time.Duration, args [
 		job: job{Callable: f, args: args},
 	}
 
+	quit := loop.quitChan()
 	t.timer = time.AfterFunc(timeout, func() {
-		loop.jobChan <- func() {
+		select {
+		case loop.jobChan <- func() {
 			loop.doTimeout(t)
+		}:
+		case <-quit:
 		}
 	})
 
@@ -174,7 +214,7 @@ func (loop *EventLoop) addInterval(f goja.Callable, timeout time.Duration, args
 		stopChan: make(chan struct{}),
 	}
 
-	go i.run(loop)
+	go i.run(loop, loop.quitChan())
 	loop.jobCount++
 	return i
 }
@@ -204,20 +244,26 @@ func (loop *EventLoop) clearTimeout(t *timer) {
 func (loop *EventLoop) clearInterval(i *interval) {
 	if !i.cancelled {
 		i.cancelled = true
-		i.stopChan <- struct{}{}
+		close(i.stopChan)
 		loop.jobCount--
 	}
 }
 
-func (i *interval) run(loop *EventLoop) {
+func (i *interval) run(loop *EventLoop, quit chan struct{}) {
+	defer i.ticker.Stop()
 	for {
 		select {
 		case <-i.stopChan:
-			i.ticker.Stop()
-			break
+			return
+		case <-quit:
+			return
 		case <-i.ticker.C:
-			loop.jobChan <- func() {
+			select {
+			case loop.jobChan <- func() {
 				loop.doInterval(i)
+			}:
+			case <-quit:
+				return
 			}
 		}
 	}
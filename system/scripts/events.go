@@ -0,0 +1,102 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"errors"
+	"github.com/dop251/goja"
+)
+
+var (
+	// ErrEventsNotAvailable ...
+	ErrEventsNotAvailable = errors.New("events are not available")
+	// ErrEventHandler ...
+	ErrEventHandler = errors.New("event handler is not a function")
+)
+
+// EventSource the events of the core, see core.EventBus
+type EventSource interface {
+	On(eventType string, fields map[string]interface{}, handler func(event map[string]interface{})) int64
+	Off(id int64)
+}
+
+// Javascript Binding
+//
+// Events
+//	 .on(type, [filter], handler) -> id
+//	 .off(id)
+//
+func (j *Javascript) eventsBind() *goja.Object {
+
+	events := j.vm.NewObject()
+
+	_ = events.Set("on", func(call goja.FunctionCall) goja.Value {
+
+		eventType := call.Argument(0).String()
+
+		var fields map[string]interface{}
+		arg := call.Argument(1)
+		if len(call.Arguments) > 2 {
+			if filter, ok := arg.Export().(map[string]interface{}); ok {
+				fields = filter
+			}
+			arg = call.Argument(2)
+		}
+
+		handler, ok := goja.AssertFunction(arg)
+		if !ok {
+			j.throw(ErrEventHandler)
+		}
+
+		// the handler runs with the limits of the script that subscribed it
+		name, limits := j.engine.model.Name, j.engine.Limits(j.engine.model)
+		if j.sandbox != nil {
+			name, limits = j.sandbox.name, j.sandbox.limits
+		}
+
+		id, err := j.engine.on(eventType, fields, func(event map[string]interface{}) {
+			j.onEvent(name, limits, handler, event)
+		})
+		if err != nil {
+			j.throw(err)
+		}
+
+		return j.vm.ToValue(id)
+	})
+
+	_ = events.Set("off", func(id int64) {
+		j.engine.off(id)
+	})
+
+	return events
+}
+
+func (j *Javascript) onEvent(name string, limits Limits, handler goja.Callable, event map[string]interface{}) {
+
+	err := j.sandboxed(name, limits, func() (err error) {
+		j.loop.Run(func(vm *goja.Runtime) {
+			_, err = handler(goja.Undefined(), vm.ToValue(event))
+		})
+		return
+	})
+
+	if err != nil {
+		log.Errorf("script \"%s\": event %v handler: %s", name, event["type"], err.Error())
+	}
+}
@@ -23,7 +23,10 @@ import (
 	"fmt"
 	"github.com/dop251/goja"
 	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/scripts/bind"
 	"github.com/e154/smart-home/system/scripts/eventloop"
+	"reflect"
 	"strings"
 	"sync"
 )
@@ -41,7 +44,29 @@ type Javascript struct {
 	loop         *eventloop.EventLoop
 	program      *goja.Program
 	lockPrograms sync.Mutex
-	programs     map[string]*goja.Program
+	programs     map[string]*program
+	sandbox      *sandbox
+	modules      map[string]*module
+	loading      []string
+	debug        *DebugSession
+	// the vm is not safe for the concurrent use, the event handlers wait for the running script.
+	// The lock is not reentrant: the binding must not run the other script of the same engine
+	// synchronously, e.g. Workflow.SetScenario runs the scenario scripts in its own goroutine
+	runLock sync.Mutex
+}
+
+// module stored script loaded by require()
+type module struct {
+	object    *goja.Object
+	version   int64
+	allowExec bool
+}
+
+// program compiled script with its limits
+type program struct {
+	*goja.Program
+	name   string
+	limits Limits
 }
 
 // NewJavascript ...
@@ -49,7 +74,8 @@ func NewJavascript(engine *Engine) *Javascript {
 	return &Javascript{
 		engine: engine,
 
-		programs: make(map[string]*goja.Program),
+		programs: make(map[string]*program),
+		modules:  make(map[string]*module),
 	}
 }
 
@@ -65,7 +91,7 @@ func (j *Javascript) Init() (err error) {
 		return
 	}
 
-	if j.program, err = goja.Compile("", j.engine.model.Compiled, false); err != nil {
+	if j.program, err = compileProgram(j.engine.model.Compiled); err != nil {
 		log.Error(err.Error())
 	}
 
@@ -108,7 +134,7 @@ func (j *Javascript) Compile() (err error) {
 
 	}
 
-	j.program, err = goja.Compile("", j.engine.model.Compiled, false)
+	j.program, err = compileProgram(j.engine.model.Compiled)
 
 	return
 }
@@ -144,7 +170,7 @@ func (j *Javascript) GetCompiler() error {
 
 func (j *Javascript) tsCompile() (result goja.Value, err error) {
 
-	if _, err = j.EvalString(j.compiler); err != nil {
+	if err = j.loadCompiler(); err != nil {
 		return
 	}
 
@@ -169,7 +195,7 @@ func (j *Javascript) tsCompile() (result goja.Value, err error) {
 
 func (j *Javascript) coffeeCompile() (result goja.Value, err error) {
 
-	if _, err = j.EvalString(j.compiler); err != nil {
+	if err = j.loadCompiler(); err != nil {
 		return
 	}
 
@@ -190,9 +216,22 @@ func (j *Javascript) coffeeCompile() (result goja.Value, err error) {
 	return
 }
 
+// loadCompiler the compiler is trusted, it runs without the sandbox limits
+func (j *Javascript) loadCompiler() (err error) {
+
+	var program *goja.Program
+	if program, err = goja.Compile("", j.compiler, false); err != nil {
+		return
+	}
+
+	_, err = j.unsafeRun(program, j.engine.model.Name, Limits{})
+
+	return
+}
+
 // Do ...
 func (j *Javascript) Do() (result string, err error) {
-	result, err = j.unsafeRun(j.program)
+	result, err = j.unsafeRun(j.program, j.engine.model.Name, j.engine.Limits(j.engine.model))
 	return
 }
 
@@ -200,7 +239,11 @@ func (j *Javascript) Do() (result string, err error) {
 func (j *Javascript) AssertFunction(f string) (result string, err error) {
 	if assertFunc, ok := goja.AssertFunction(j.vm.Get(f)); ok {
 		var value goja.Value
-		if value, err = assertFunc(goja.Undefined(), j.vm.ToValue(4), j.vm.ToValue(10)); err != nil {
+		err = j.sandboxed(j.engine.model.Name, j.engine.Limits(j.engine.model), func() (err error) {
+			value, err = assertFunc(goja.Undefined(), j.vm.ToValue(4), j.vm.ToValue(10))
+			return
+		})
+		if err != nil {
 			return
 		}
 		result = value.String()
@@ -222,11 +265,11 @@ func (j *Javascript) PushFunction(name string, s interface{}) {
 func (j *Javascript) EvalString(src string) (result string, err error) {
 
 	var program *goja.Program
-	if program, err = goja.Compile("", src, false); err != nil {
+	if program, err = compileProgram(src); err != nil {
 		return
 	}
 
-	result, err = j.unsafeRun(program)
+	result, err = j.unsafeRun(program, j.engine.model.Name, j.engine.Limits(j.engine.model))
 
 	return
 }
@@ -242,11 +285,72 @@ func (j *Javascript) bind() {
 
 	j.vm.Set("print", fmt.Println)
 
+	// the call depth and the allocation guards of the sandbox, see compileProgram
+	global := j.vm.GlobalObject()
+	_ = global.DefineDataProperty(sandboxEnter, j.vm.ToValue(func() {
+		if j.sandbox != nil {
+			j.sandbox.enter()
+		}
+	}), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+	_ = global.DefineDataProperty(sandboxLeave, j.vm.ToValue(func() {
+		if j.sandbox != nil {
+			j.sandbox.leave()
+		}
+	}), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+
+	_ = global.DefineDataProperty(sandboxAlloc, j.vm.ToValue(func(call goja.FunctionCall) goja.Value {
+		value := call.Argument(0)
+		if j.sandbox != nil {
+			j.sandbox.alloc(value)
+		}
+		return value
+	}), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+	_ = global.DefineDataProperty(sandboxGrow, j.vm.ToValue(func(call goja.FunctionCall) goja.Value {
+		value := call.Argument(0)
+		if j.sandbox != nil {
+			j.sandbox.grow(value)
+		}
+		return value
+	}), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+
+	// the probe of the debug session, see compileDebugProgram
+	_ = global.DefineDataProperty(debugProbe, j.vm.ToValue(func(line int) {
+		if j.debug != nil && !j.debug.pause(line, func() *DebugState { return j.debugState(line) }) && j.sandbox != nil {
+			j.sandbox.abort(ErrDebugStopped)
+		}
+	}), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+
+	// stored scripts are required by the name, the rest by the file path
+	fileRequire, _ := goja.AssertFunction(j.vm.Get("require"))
+	j.vm.Set("require", func(call goja.FunctionCall) goja.Value {
+		exports, err := j.require(call.Argument(0).String())
+		if err == ErrModuleNotFound && fileRequire != nil {
+			exports, err = fileRequire(goja.Undefined(), call.Arguments...)
+		}
+		if err != nil {
+			j.throw(err)
+		}
+		return exports
+	})
+
+	// shell commands only for the scripts saved by the role with the script.exec_command permission
+	j.vm.Set("ExecuteSync", func(name string, arg ...string) *bind.Response {
+		j.checkExec(name)
+		return bind.ExecuteSync(name, arg...)
+	})
+	j.vm.Set("ExecuteAsync", func(name string, arg ...string) *bind.Response {
+		j.checkExec(name)
+		return bind.ExecuteAsync(name, arg...)
+	})
+
+	j.vm.Set("Events", j.eventsBind())
+
 	_, _ = j.vm.RunString(`
 
 	var self = {},
     console = {log:print,warn:print,error:print,info:print},
-    global = {};
+    global = {},
+    exports = {};
 
 	hex2arr = function (hexString) {
 	   var result = [];
@@ -308,9 +412,17 @@ func (j *Javascript) bind() {
 }
 
 // CreateProgram ...
-func (j *Javascript) CreateProgram(name, source string) (err error) {
+func (j *Javascript) CreateProgram(name string, script *m.Script) (err error) {
+	var p *goja.Program
+	if p, err = compileProgram(script.Compiled); err != nil {
+		return
+	}
 	j.lockPrograms.Lock()
-	j.programs[name], err = goja.Compile("", source, false)
+	j.programs[name] = &program{
+		Program: p,
+		name:    script.Name,
+		limits:  j.engine.Limits(script),
+	}
 	j.lockPrograms.Unlock()
 	return
 }
@@ -318,32 +430,37 @@ func (j *Javascript) CreateProgram(name, source string) (err error) {
 // RunProgram ...
 func (j *Javascript) RunProgram(name string) (result string, err error) {
 	j.lockPrograms.Lock()
-	defer j.lockPrograms.Unlock()
-
 	program, ok := j.programs[name]
+	j.lockPrograms.Unlock()
+
 	if !ok {
 		err = ErrorProgramNotFound
 		return
 	}
 
-	result, err = j.unsafeRun(program)
+	result, err = j.unsafeRun(program.Program, program.name, program.limits)
 
 	return
 }
 
-func (j *Javascript) unsafeRun(program *goja.Program) (result string, err error) {
+func (j *Javascript) unsafeRun(program *goja.Program, name string, limits Limits) (result string, err error) {
 
 	var value goja.Value
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
+	err = j.sandboxed(name, limits, func() (err error) {
 
-	j.loop.Run(func(vm *goja.Runtime) {
-		value, err = vm.RunProgram(program)
-		wg.Done()
-	})
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+
+		j.loop.Run(func(vm *goja.Runtime) {
+			value, err = vm.RunProgram(program)
+			wg.Done()
+		})
 
-	wg.Wait()
+		wg.Wait()
+
+		return
+	})
 
 	if err != nil {
 		return
@@ -353,3 +470,278 @@ func (j *Javascript) unsafeRun(program *goja.Program) (result string, err error)
 
 	return
 }
+
+// sandboxed run f under the limits, the script is aborted when one of them is exceeded
+func (j *Javascript) sandboxed(name string, limits Limits, f func() error) (err error) {
+
+	j.runLock.Lock()
+	defer j.runLock.Unlock()
+
+	box := newSandbox(name, j.vm, j.loop, limits)
+
+	j.reloadModules()
+	j.sandbox = box
+	box.start()
+
+	err = box.finish(f())
+	j.sandbox = nil
+
+	if box.aborted() {
+		err = &SandboxError{Script: name, Err: err}
+		log.Error(err.Error())
+	}
+
+	return
+}
+
+// checkExec throws the script error if the shell commands are not allowed
+func (j *Javascript) checkExec(name string) {
+	if j.sandbox == nil {
+		panic(j.vm.NewGoError(ErrScriptExecNotAllowed))
+	}
+	if j.sandbox.limits.AllowExec {
+		return
+	}
+	log.Warnf("script \"%s\": command \"%s\" rejected: %s", j.sandbox.name, name, ErrScriptExecNotAllowed.Error())
+	panic(j.vm.NewGoError(ErrScriptExecNotAllowed))
+}
+
+// throw the error to the script, the interruption of the sandbox can not be caught
+func (j *Javascript) throw(err error) {
+	switch e := err.(type) {
+	case *goja.InterruptedError:
+		panic(e)
+	case *goja.Exception:
+		panic(e.Value())
+	default:
+		panic(j.vm.NewGoError(err))
+	}
+}
+
+// require load the stored script as the module, the exports are cached until the script is updated.
+// The module runs in the sandbox of the script, so the script with the shell commands
+// takes only the modules that are allowed to run them too
+func (j *Javascript) require(name string) (exports goja.Value, err error) {
+
+	if mod, ok := j.modules[name]; ok {
+		if err = j.checkModuleExec(name, mod.allowExec); err != nil {
+			return
+		}
+		exports = mod.object.Get("exports")
+		return
+	}
+
+	if j.engine.modules == nil {
+		err = ErrModuleNotFound
+		return
+	}
+
+	var script *m.Script
+	var version int64
+	if script, version, err = j.engine.modules.load(name); err != nil {
+		return
+	}
+
+	if err = j.checkModuleExec(name, script.AllowExec); err != nil {
+		return
+	}
+
+	j.engine.modules.AddDependency(j.requirer(), name)
+
+	var p *goja.Program
+	if p, err = compileProgram("(function(module, exports) {" + script.Compiled + "\n})"); err != nil {
+		err = fmt.Errorf("could not compile module %s: %s", name, err.Error())
+		return
+	}
+
+	// the cyclic require gets the unfinished exports, as in node.js
+	object := j.vm.NewObject()
+	_ = object.Set("exports", j.vm.NewObject())
+	j.modules[name] = &module{
+		object:    object,
+		version:   version,
+		allowExec: script.AllowExec,
+	}
+
+	j.loading = append(j.loading, name)
+	defer func() {
+		j.loading = j.loading[:len(j.loading)-1]
+	}()
+
+	var f goja.Value
+	if f, err = j.vm.RunProgram(p); err == nil {
+		if call, ok := goja.AssertFunction(f); ok {
+			exports = object.Get("exports")
+			_, err = call(exports, object, exports)
+		}
+	}
+
+	if err != nil {
+		delete(j.modules, name)
+		if _, ok := err.(*goja.InterruptedError); !ok {
+			err = fmt.Errorf("could not load module %s: %s", name, err.Error())
+		}
+		return
+	}
+
+	exports = object.Get("exports")
+
+	return
+}
+
+// checkModuleExec the module without the shell commands is rejected by the script with them
+func (j *Javascript) checkModuleExec(name string, allowExec bool) error {
+	if allowExec || j.sandbox == nil || !j.sandbox.limits.AllowExec {
+		return nil
+	}
+	log.Warnf("script \"%s\": module \"%s\" rejected: %s", j.sandbox.name, name, ErrModuleExecNotAllowed.Error())
+	return fmt.Errorf("%s: %w", name, ErrModuleExecNotAllowed)
+}
+
+// requirer the name of the script or the module that calls require()
+func (j *Javascript) requirer() string {
+	if len(j.loading) > 0 {
+		return j.loading[len(j.loading)-1]
+	}
+	if j.sandbox != nil && j.sandbox.name != "" {
+		return j.sandbox.name
+	}
+	return j.engine.model.Name
+}
+
+// reloadModules drop the cache if one of the modules was updated since it was loaded
+func (j *Javascript) reloadModules() {
+
+	if j.engine.modules == nil {
+		return
+	}
+
+	for name, mod := range j.modules {
+		if mod.version == j.engine.modules.Version(name) {
+			continue
+		}
+		log.Infof("module %s was updated, reload modules of the script %s", name, j.engine.model.Name)
+		j.modules = make(map[string]*module)
+		return
+	}
+}
+
+// Mock replace the binding by the mock with the methods of the prototype, the calls are recorded
+func (j *Javascript) Mock(name string, prototype interface{}, rec *recorder) {
+	j.vm.Set(name, j.mockValue(name, reflect.ValueOf(prototype), rec))
+}
+
+func (j *Javascript) mockValue(name string, v reflect.Value, rec *recorder) goja.Value {
+
+	if v.IsValid() && v.Kind() == reflect.Func {
+		return j.vm.ToValue(j.mockFunc(name, v.Type(), rec))
+	}
+
+	object := j.vm.NewObject()
+	if !v.IsValid() {
+		return object
+	}
+
+	for i := 0; i < v.NumMethod(); i++ {
+		method := v.Type().Method(i).Name
+		_ = object.Set(method, j.mockFunc(name+"."+method, v.Method(i).Type(), rec))
+	}
+
+	return object
+}
+
+func (j *Javascript) mockFunc(name string, t reflect.Type, rec *recorder) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		args := make([]interface{}, len(call.Arguments))
+		for i, arg := range call.Arguments {
+			args[i] = exportArg(arg.Export())
+		}
+		rec.add(name, args)
+		return j.mockResult(name, t, rec)
+	}
+}
+
+// mockResult the value of the test case or the empty value of the result type
+func (j *Javascript) mockResult(name string, t reflect.Type, rec *recorder) goja.Value {
+
+	if value, ok := rec.result(name); ok {
+		return j.vm.ToValue(value)
+	}
+
+	if t.NumOut() == 0 {
+		return goja.Undefined()
+	}
+
+	out := t.Out(0)
+	switch out.Kind() {
+	case reflect.Ptr:
+		if out.NumMethod() > 0 {
+			return j.mockValue(name, reflect.New(out.Elem()), rec)
+		}
+		return j.vm.ToValue(reflect.New(out.Elem()).Interface())
+	case reflect.Interface:
+		return goja.Null()
+	case reflect.Func:
+		return j.vm.ToValue(j.mockFunc(name, out, rec))
+	}
+
+	return j.vm.ToValue(reflect.Zero(out).Interface())
+}
+
+// Debug run the script in the debug session, the time of the pauses is not limited
+func (j *Javascript) Debug(session *DebugSession) (result string, err error) {
+
+	var program *goja.Program
+	if program, err = compileDebugProgram(j.engine.model.Compiled); err != nil {
+		session.finish(result, err)
+		return
+	}
+
+	limits := j.engine.Limits(j.engine.model)
+	limits.Timeout = 0
+
+	j.debug = session
+	result, err = j.unsafeRun(program, j.engine.model.Name, limits)
+	j.debug = nil
+
+	session.finish(result, err)
+
+	return
+}
+
+func (j *Javascript) debugState(line int) *DebugState {
+
+	state := &DebugState{
+		Line:    line,
+		Globals: make(map[string]interface{}),
+		Vars:    make(map[string]interface{}),
+	}
+
+	global := j.vm.GlobalObject()
+	for _, name := range global.Keys() {
+		if isEngineGlobal(name) {
+			continue
+		}
+		if _, ok := j.engine.structures.Get(name); ok {
+			continue
+		}
+		if _, ok := j.engine.functions.Get(name); ok {
+			continue
+		}
+		if value, ok := debugValue(global.Get(name).Export()); ok {
+			state.Globals[name] = value
+		}
+	}
+
+	if message := global.Get("message"); message != nil {
+		if msg, ok := message.Export().(interface{ Vars() map[string]interface{} }); ok {
+			for name, v := range msg.Vars() {
+				if value, ok := debugValue(v); ok {
+					state.Vars[name] = value
+				}
+			}
+		}
+	}
+
+	return state
+}
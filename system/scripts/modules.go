@@ -0,0 +1,130 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrModuleNotFound ...
+	ErrModuleNotFound = errors.New("module not found")
+	// ErrModuleExecNotAllowed the script with the shell commands requires the module saved without them
+	ErrModuleExecNotAllowed = errors.New("module without the shell commands permission can not be loaded into the script with it")
+)
+
+// Modules stored scripts available to the other scripts by require("name").
+// Every require is recorded, so the update of the script is known to all engines that use it
+// directly or through the other modules.
+type Modules struct {
+	sync.Mutex
+	adaptors   *adaptors.Adaptors
+	versions   map[string]int64
+	dependents map[string]map[string]bool
+}
+
+// NewModules ...
+func NewModules(adaptors *adaptors.Adaptors) *Modules {
+	return &Modules{
+		adaptors:   adaptors,
+		versions:   make(map[string]int64),
+		dependents: make(map[string]map[string]bool),
+	}
+}
+
+// load the script of the module with the current version
+func (s *Modules) load(name string) (script *m.Script, version int64, err error) {
+
+	if s.adaptors == nil {
+		err = ErrModuleNotFound
+		return
+	}
+
+	// the version before the script, the update between them reloads the module once more
+	version = s.Version(name)
+
+	if script, err = s.adaptors.Script.GetByName(name); err != nil {
+		if err.Error() == "record not found" {
+			err = ErrModuleNotFound
+		}
+		return
+	}
+
+	if script.Compiled == "" {
+		err = fmt.Errorf("module %s is not compiled", name)
+		return
+	}
+
+	return
+}
+
+// Version ...
+func (s *Modules) Version(name string) int64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.versions[name]
+}
+
+// AddDependency the script requires the module
+func (s *Modules) AddDependency(script, module string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.dependents[module] == nil {
+		s.dependents[module] = make(map[string]bool)
+	}
+	s.dependents[module][script] = true
+}
+
+// Dependents the names of the scripts that require the module directly or through the other modules
+func (s *Modules) Dependents(module string) (list []string) {
+	s.Lock()
+	defer s.Unlock()
+
+	visited := map[string]bool{module: true}
+	queue := []string{module}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for dependent := range s.dependents[name] {
+			if visited[dependent] {
+				continue
+			}
+			visited[dependent] = true
+			list = append(list, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+
+	sort.Strings(list)
+
+	return
+}
+
+// Update the script was changed or removed, the engines reload it on the next run
+func (s *Modules) Update(name string) {
+	s.Lock()
+	s.versions[name]++
+	s.Unlock()
+}
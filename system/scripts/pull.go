@@ -50,3 +50,15 @@ func (p *Pull) Add(name string, s interface{}) {
 
 	p.heap[name] = s
 }
+
+// Names ...
+func (p *Pull) Names() (names []string) {
+	p.Lock()
+	defer p.Unlock()
+
+	names = make([]string, 0, len(p.heap))
+	for name := range p.heap {
+		names = append(names, name)
+	}
+	return
+}
@@ -0,0 +1,426 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja/parser"
+	"github.com/dop251/goja/token"
+	"github.com/e154/smart-home/system/scripts/eventloop"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout ...
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxStack ...
+	DefaultMaxStack = 1000
+	// DefaultMaxMemory megabytes
+	DefaultMaxMemory = 64
+
+	// the guard is called at the beginning and at the end of the every function of the script
+	sandboxEnter = "__sandbox_enter__"
+	sandboxLeave = "__sandbox_leave__"
+	// the guard is wrapped around the string concatenation and the array concat of the script
+	sandboxAlloc = "__sandbox_alloc__"
+	// the guard is wrapped around the every value pushed to the array
+	sandboxGrow = "__sandbox_grow__"
+
+	// the estimated size of the object header and of the single property or array element
+	allocObjectSize  = 64
+	allocElementSize = 16
+)
+
+var (
+	// ErrScriptTimeout ...
+	ErrScriptTimeout = errors.New("execution timeout")
+	// ErrScriptStackOverflow ...
+	ErrScriptStackOverflow = errors.New("maximum call stack depth exceeded")
+	// ErrScriptMemoryLimit ...
+	ErrScriptMemoryLimit = errors.New("memory limit exceeded")
+	// ErrScriptExecNotAllowed ...
+	ErrScriptExecNotAllowed = errors.New("shell commands are not allowed for the script")
+)
+
+// Limits of the single script run, zero value means no limit
+type Limits struct {
+	Timeout   time.Duration
+	MaxStack  int
+	MaxMemory uint64 // bytes allocated by the script during the run
+	AllowExec bool
+}
+
+// SandboxError the script was aborted by the sandbox
+type SandboxError struct {
+	Script string
+	Err    error
+}
+
+// Error ...
+func (e *SandboxError) Error() string {
+	return fmt.Sprintf("script \"%s\" aborted: %s", e.Script, e.Err.Error())
+}
+
+// sandbox watches the single script run and interrupts the vm when one of the limits is exceeded.
+// The memory limit is approximate: only the growth sites are counted, the sizes of the strings built
+// by the concatenation and of the arrays built by concat, push and unshift are summed up, the memory
+// released by the garbage collector is not subtracted, the allocations of the bindings are not counted.
+type sandbox struct {
+	sync.Mutex
+	name      string
+	vm        *goja.Runtime
+	loop      *eventloop.EventLoop
+	limits    Limits
+	depth     int
+	allocated uint64
+	reason    error
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func newSandbox(name string, vm *goja.Runtime, loop *eventloop.EventLoop, limits Limits) *sandbox {
+	return &sandbox{
+		name:   name,
+		vm:     vm,
+		loop:   loop,
+		limits: limits,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *sandbox) start() {
+	go s.watch()
+}
+
+// finish stop the watcher and reset the vm, returns the reason of the abort if it was
+func (s *sandbox) finish(err error) error {
+
+	close(s.stop)
+	<-s.done
+
+	s.vm.ClearInterrupt()
+
+	s.Lock()
+	defer s.Unlock()
+	if s.reason != nil {
+		return s.reason
+	}
+	return err
+}
+
+func (s *sandbox) aborted() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.reason != nil
+}
+
+func (s *sandbox) abort(reason error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.reason != nil {
+		return
+	}
+
+	s.reason = reason
+	s.vm.Interrupt(reason)
+	if s.loop != nil {
+		s.loop.Terminate()
+	}
+}
+
+// enter is called by the script on the each function call
+func (s *sandbox) enter() {
+	s.depth++
+	if s.limits.MaxStack > 0 && s.depth > s.limits.MaxStack {
+		s.abort(ErrScriptStackOverflow)
+	}
+}
+
+// leave is called by the script on the each function return
+func (s *sandbox) leave() {
+	if s.depth > 0 {
+		s.depth--
+	}
+}
+
+// alloc is called by the script with the result of the every concatenation
+func (s *sandbox) alloc(v goja.Value) {
+	s.count(allocSize(v))
+}
+
+// grow is called by the script with the every value pushed to the array
+func (s *sandbox) grow(v goja.Value) {
+	s.count(allocElementSize + allocSize(v))
+}
+
+func (s *sandbox) count(size uint64) {
+	if s.limits.MaxMemory == 0 {
+		return
+	}
+	s.allocated += size
+	if s.allocated > s.limits.MaxMemory {
+		s.abort(ErrScriptMemoryLimit)
+	}
+}
+
+func allocSize(v goja.Value) uint64 {
+	switch {
+	case v == nil:
+		return 0
+	case v.ExportType() == stringType:
+		return uint64(len(v.String()))
+	}
+	obj, ok := v.(*goja.Object)
+	if !ok {
+		return 0
+	}
+	if obj.ClassName() == "Array" {
+		if length := obj.Get("length").ToInteger(); length > 0 {
+			return allocObjectSize + uint64(length)*allocElementSize
+		}
+		return allocObjectSize
+	}
+	return allocObjectSize + uint64(len(obj.Keys()))*allocElementSize
+}
+
+func (s *sandbox) watch() {
+	defer close(s.done)
+
+	var timeout <-chan time.Time
+	if s.limits.Timeout > 0 {
+		timer := time.NewTimer(s.limits.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-timeout:
+			s.abort(ErrScriptTimeout)
+			return
+		}
+	}
+}
+
+// compileProgram compile the script with the call depth and the allocation guards
+func compileProgram(source string) (program *goja.Program, err error) {
+
+	var prg *ast.Program
+	if prg, err = parser.ParseFile(nil, "", source, 0); err != nil {
+		// the same error as goja.Compile returns
+		return goja.Compile("", source, false)
+	}
+
+	guardFunctions(reflect.ValueOf(prg), make(map[ast.Node]bool))
+
+	program, err = goja.CompileAST(prg, false)
+
+	return
+}
+
+var (
+	fileType       = reflect.TypeOf(&file.File{})
+	expressionType = reflect.TypeOf((*ast.Expression)(nil)).Elem()
+	stringType     = reflect.TypeOf("")
+)
+
+// guardFunctions walk the tree and wrap the body of the every function literal into
+// __sandbox_enter__(); try { ... } finally { __sandbox_leave__(); },
+// the every concatenation into __sandbox_alloc__(...) and the every value
+// pushed to the array into __sandbox_grow__(...)
+func guardFunctions(v reflect.Value, visited map[ast.Node]bool) {
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() || v.Type() == fileType {
+			return
+		}
+		if v.CanInterface() {
+			switch node := v.Interface().(type) {
+			case *ast.FunctionLiteral:
+				// function declarations are referenced from the body and from the declaration list
+				if visited[node] {
+					return
+				}
+				visited[node] = true
+				guardFunctions(v.Elem(), visited)
+				guardFunction(node)
+				return
+			case *ast.CallExpression:
+				// the expression is already guarded
+				if visited[node] {
+					return
+				}
+				if v.Kind() == reflect.Ptr {
+					visited[node] = true
+					guardFunctions(v.Elem(), visited)
+					guardGrow(node)
+					return
+				}
+			}
+		}
+		guardFunctions(v.Elem(), visited)
+		if v.Kind() == reflect.Interface && v.Type() == expressionType && v.CanSet() {
+			if expr, ok := v.Interface().(ast.Expression); ok && allocates(expr) {
+				call := guardAlloc(expr)
+				visited[call] = true
+				v.Set(reflect.ValueOf(call))
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			guardFunctions(v.Field(i), visited)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			guardFunctions(v.Index(i), visited)
+		}
+	}
+}
+
+func guardFunction(fn *ast.FunctionLiteral) {
+
+	// the body without the braces is guarded the same way
+	body, ok := fn.Body.(*ast.BlockStatement)
+	if !ok {
+		body = &ast.BlockStatement{
+			LeftBrace:  fn.Body.Idx0(),
+			List:       []ast.Statement{fn.Body},
+			RightBrace: fn.Body.Idx1(),
+		}
+	}
+
+	// the directive prologue ("use strict") stays at the beginning of the body
+	var n int
+	for ; n < len(body.List); n++ {
+		st, ok := body.List[n].(*ast.ExpressionStatement)
+		if !ok {
+			break
+		}
+		if _, ok = st.Expression.(*ast.StringLiteral); !ok {
+			break
+		}
+	}
+
+	idx := body.LeftBrace
+	list := make([]ast.Statement, 0, n+2)
+	list = append(list, body.List[:n]...)
+	list = append(list, guardCall(sandboxEnter, idx), &ast.TryStatement{
+		Try: idx,
+		Body: &ast.BlockStatement{
+			LeftBrace:  idx,
+			List:       body.List[n:],
+			RightBrace: body.RightBrace,
+		},
+		Finally: &ast.BlockStatement{
+			LeftBrace:  idx,
+			List:       []ast.Statement{guardCall(sandboxLeave, idx)},
+			RightBrace: body.RightBrace,
+		},
+	})
+
+	fn.Body = &ast.BlockStatement{
+		LeftBrace:  body.LeftBrace,
+		List:       list,
+		RightBrace: body.RightBrace,
+	}
+}
+
+// allocates the expression grows the string or the array: the concatenation with the string,
+// the appending assignment and the array concat, the arithmetic addition is not guarded
+func allocates(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.BinaryExpression:
+		return e.Operator == token.PLUS && (concatenates(e.Left) || concatenates(e.Right))
+	case *ast.AssignExpression:
+		return e.Operator == token.PLUS
+	case *ast.CallExpression:
+		return isMethodCall(e, "concat")
+	}
+	return false
+}
+
+// concatenates the operand is the string or the guarded concatenation
+func concatenates(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.StringLiteral:
+		return true
+	case *ast.CallExpression:
+		callee, ok := e.Callee.(*ast.Identifier)
+		return ok && callee.Name == sandboxAlloc
+	}
+	return false
+}
+
+func isMethodCall(call *ast.CallExpression, names ...string) bool {
+	dot, ok := call.Callee.(*ast.DotExpression)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		if dot.Identifier.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// guardGrow wrap the values pushed to the array
+func guardGrow(call *ast.CallExpression) {
+	if !isMethodCall(call, "push", "unshift") {
+		return
+	}
+	for i, arg := range call.ArgumentList {
+		call.ArgumentList[i] = guardValue(sandboxGrow, arg)
+	}
+}
+
+func guardAlloc(expr ast.Expression) *ast.CallExpression {
+	return guardValue(sandboxAlloc, expr)
+}
+
+func guardValue(name string, expr ast.Expression) *ast.CallExpression {
+	idx := expr.Idx0()
+	return &ast.CallExpression{
+		Callee:           &ast.Identifier{Name: name, Idx: idx},
+		LeftParenthesis:  idx,
+		ArgumentList:     []ast.Expression{expr},
+		RightParenthesis: expr.Idx1(),
+	}
+}
+
+func guardCall(name string, idx file.Idx) ast.Statement {
+	return &ast.ExpressionStatement{
+		Expression: &ast.CallExpression{
+			Callee:           &ast.Identifier{Name: name, Idx: idx},
+			LeftParenthesis:  idx,
+			RightParenthesis: idx,
+		},
+	}
+}
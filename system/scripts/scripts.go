@@ -19,11 +19,13 @@
 package scripts
 
 import (
+	"github.com/e154/smart-home/adaptors"
 	"github.com/e154/smart-home/common"
 	m "github.com/e154/smart-home/models"
 	"github.com/e154/smart-home/models/devices"
 	"github.com/e154/smart-home/system/config"
 	"github.com/e154/smart-home/system/scripts/bind"
+	"time"
 )
 
 var (
@@ -35,20 +37,46 @@ type ScriptService struct {
 	cfg        *config.AppConfig
 	functions  *Pull
 	structures *Pull
+	limits     Limits
+	modules    *Modules
+	mocks      *Pull
+	events     EventSource
 }
 
 // NewScriptService ...
-func NewScriptService(cfg *config.AppConfig) (service *ScriptService) {
+func NewScriptService(cfg *config.AppConfig,
+	adaptors *adaptors.Adaptors) (service *ScriptService) {
 
 	service = &ScriptService{
 		cfg:        cfg,
 		functions:  NewPull(),
 		structures: NewPull(),
+		modules:    NewModules(adaptors),
+		mocks:      NewPull(),
+		limits: Limits{
+			Timeout:   DefaultTimeout,
+			MaxStack:  DefaultMaxStack,
+			MaxMemory: DefaultMaxMemory << 20,
+		},
 	}
 
+	if cfg.ScriptTimeout > 0 {
+		service.limits.Timeout = time.Millisecond * time.Duration(cfg.ScriptTimeout)
+	}
+	if cfg.ScriptMaxStack > 0 {
+		service.limits.MaxStack = cfg.ScriptMaxStack
+	}
+	if cfg.ScriptMaxMemory > 0 {
+		service.limits.MaxMemory = uint64(cfg.ScriptMaxMemory) << 20
+	}
+
+	// ExecuteSync and ExecuteAsync are bound by the engine, they are allowed only for some scripts
 	service.PushStruct("Log", &bind.LogBind{})
-	service.PushFunctions("ExecuteSync", bind.ExecuteSync)
-	service.PushFunctions("ExecuteAsync", bind.ExecuteAsync)
+	service.PushStruct("Http", bind.NewHttpBind(cfg.ScriptHttpHosts, time.Millisecond*time.Duration(cfg.ScriptHttpTimeout)))
+	service.PushStruct("Crypto", &bind.CryptoBind{})
+	service.PushStruct("Time", &bind.TimeBind{})
+	service.PushMock("ExecuteSync", bind.ExecuteSync)
+	service.PushMock("ExecuteAsync", bind.ExecuteAsync)
 	service.PushFunctions("RunCommand", devices.NewRunCommandBind)
 	service.PushFunctions("Zigbee2mqtt", devices.NewZigbee2mqttBind)
 	service.PushFunctions("ModBus", devices.NewModBusBind)
@@ -58,7 +86,17 @@ func NewScriptService(cfg *config.AppConfig) (service *ScriptService) {
 
 // NewEngine ...
 func (service ScriptService) NewEngine(s *m.Script) (*Engine, error) {
-	return NewEngine(s, service.structures, service.functions)
+	return NewEngine(s, service.structures, service.functions, service.limits, service.modules, service.events)
+}
+
+// SetEvents the source of the events for Events.on(), it is set by the core
+func (service *ScriptService) SetEvents(events EventSource) {
+	service.events = events
+}
+
+// Modules ...
+func (service *ScriptService) Modules() *Modules {
+	return service.modules
 }
 
 // PushStruct ...
@@ -70,3 +108,9 @@ func (service *ScriptService) PushStruct(name string, s interface{}) {
 func (service *ScriptService) PushFunctions(name string, s interface{}) {
 	service.functions.Add(name, s)
 }
+
+// PushMock the prototype of the binding that is not available globally (e.g. Device of the action),
+// the dry run mocks it by the methods of the prototype
+func (service *ScriptService) PushMock(name string, prototype interface{}) {
+	service.mocks.Add(name, prototype)
+}
@@ -19,6 +19,7 @@
 package stream
 
 import (
+	m "github.com/e154/smart-home/models"
 	"github.com/gorilla/websocket"
 	"sync"
 	"time"
@@ -39,6 +40,12 @@ type Client struct {
 	Send      chan []byte // message buffered channel
 	writeLock sync.Mutex
 	Connect   *websocket.Conn
+	User      *m.User
+}
+
+// CurrentUser the user of the session, nil for the client without the authorization
+func (c *Client) CurrentUser() *m.User {
+	return c.User
 }
 
 // UpdateInfo ...
@@ -38,6 +38,7 @@ const (
 type Hub struct {
 	sessions    map[*Client]bool
 	subscribers map[string]func(client IStreamClient, msg Message)
+	onClose     map[string]func(client IStreamClient)
 	sync.Mutex
 	broadcast chan []byte
 	interrupt chan os.Signal
@@ -53,6 +54,7 @@ func NewHub() *Hub {
 		sessions:    make(map[*Client]bool),
 		broadcast:   make(chan []byte, maxMessageSize),
 		subscribers: make(map[string]func(client IStreamClient, msg Message)),
+		onClose:     make(map[string]func(client IStreamClient)),
 		interrupt:   interrupt,
 	}
 	go hub.Run()
@@ -66,7 +68,14 @@ func (h *Hub) AddClient(client *Client) {
 	defer func() {
 		h.Lock()
 		delete(h.sessions, client)
+		handlers := make([]func(client IStreamClient), 0, len(h.onClose))
+		for _, f := range h.onClose {
+			handlers = append(handlers, f)
+		}
 		h.Unlock()
+		for _, f := range handlers {
+			f(client)
+		}
 		log.Infof("websocket session from ip: %s closed", client.Ip)
 	}()
 
@@ -194,3 +203,17 @@ func (h *Hub) Subscriber(command string) (f func(client IStreamClient, msg Messa
 	h.Unlock()
 	return
 }
+
+// SubscribeClose call f when the client session is closed
+func (h *Hub) SubscribeClose(name string, f func(client IStreamClient)) {
+	h.Lock()
+	h.onClose[name] = f
+	h.Unlock()
+}
+
+// UnSubscribeClose ...
+func (h *Hub) UnSubscribeClose(name string) {
+	h.Lock()
+	delete(h.onClose, name)
+	h.Unlock()
+}
@@ -21,6 +21,7 @@ package stream
 import (
 	"errors"
 	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"net/http"
@@ -65,6 +66,16 @@ func (s *StreamService) UnSubscribe(command string) {
 	s.Hub.UnSubscribe(command)
 }
 
+// SubscribeClose ...
+func (s *StreamService) SubscribeClose(name string, f func(client IStreamClient)) {
+	s.Hub.SubscribeClose(name, f)
+}
+
+// UnSubscribeClose ...
+func (s *StreamService) UnSubscribeClose(name string) {
+	s.Hub.UnSubscribeClose(name)
+}
+
 // Ws ...
 func (w *StreamService) Ws(ctx *gin.Context) {
 
@@ -98,6 +109,10 @@ func (w *StreamService) Ws(ctx *gin.Context) {
 		Send:      make(chan []byte),
 	}
 
+	if user, ok := ctx.Get("currentUser"); ok {
+		client.User, _ = user.(*m.User)
+	}
+
 	go client.WritePump()
 	w.Hub.AddClient(client)
 }
@@ -18,6 +18,10 @@
 
 package stream
 
+import (
+	m "github.com/e154/smart-home/models"
+)
+
 const (
 	// Request ...
 	Request = "request"
@@ -38,6 +42,11 @@ type BroadcastClient interface {
 	Broadcast(message []byte)
 }
 
+// IUserClient the client knows the authorized user
+type IUserClient interface {
+	CurrentUser() *m.User
+}
+
 // IStreamClient ...
 type IStreamClient interface {
 	Write(payload []byte) error
@@ -54,19 +54,31 @@ type Bridge struct {
 	scanInProcess  bool
 	lastScan       time.Time
 	networkmap     string
+	protocolLock   sync.Mutex
+	protocol       protocol
+	otaLock        sync.Mutex
+	otaStatuses    map[string]*OtaStatus
+	otaQuit        chan struct{}
+	ota            *otaSubscribers
+	states         *stateSubscribers
 }
 
 // NewBridge ...
 func NewBridge(mqtt *mqtt.Mqtt,
 	adaptors *adaptors.Adaptors,
 	model *m.Zigbee2mqtt,
-	metric *metrics.MetricManager) *Bridge {
+	metric *metrics.MetricManager,
+	ota *otaSubscribers,
+	states *stateSubscribers) *Bridge {
 	return &Bridge{
-		adaptors: adaptors,
-		devices:  make(map[string]*Device),
-		model:    model,
-		metric:   metric,
-		mqtt:     mqtt,
+		adaptors:    adaptors,
+		devices:     make(map[string]*Device),
+		model:       model,
+		metric:      metric,
+		mqtt:        mqtt,
+		otaStatuses: make(map[string]*OtaStatus),
+		ota:         ota,
+		states:      states,
 	}
 }
 
@@ -88,18 +100,34 @@ func (g *Bridge) Start() {
 		g.mqttClient = g.mqtt.NewClient(fmt.Sprintf("bridge_%v", g.model.Name))
 	}
 
+	// the legacy topics until the version of the bridge is known
+	g.protocolLock.Lock()
+	g.protocol = newProtocolLegacy(g.mqttClient, g.topic)
+	g.protocolLock.Unlock()
+
 	// /zigbee2mqtt/bridge/#
 	g.mqttClient.Subscribe(fmt.Sprintf("%s/bridge/#", g.model.BaseTopic), g.onBridgePublish)
 
 	// /homeassistant/#
 	g.mqttClient.Subscribe(fmt.Sprintf("%s/#", homeassistantTopic), g.onAssistPublish)
 
+	// /zigbee2mqtt/+, the states of the devices
+	g.mqttClient.Subscribe(fmt.Sprintf("%s/+", g.model.BaseTopic), g.onDevicePublish)
+
+	g.otaQuit = make(chan struct{})
+	go g.otaWorker(g.otaQuit)
+
 	if err := g.safeGetDeviceList(); err != nil {
 		log.Error(err.Error())
 
 	}
 
-	g.configPermitJoin(g.model.PermitJoin)
+	if err := g.configPermitJoin(g.model.PermitJoin); err != nil {
+		log.Error(err.Error())
+	}
+
+	// the version comes with the config
+	g.getConfig()
 }
 
 // Stop ...
@@ -109,12 +137,17 @@ func (g *Bridge) Stop(ctx context.Context) {
 	}
 	g.isStarted = false
 	g.mqttClient.UnsubscribeAll()
+	close(g.otaQuit)
 }
 
 func (g *Bridge) onBridgePublish(client *mqtt.Client, message mqtt.Message) {
 
 	var topic = strings.Split(message.Topic, "/")
 
+	if len(topic) < 3 {
+		return
+	}
+
 	switch topic[2] {
 	case "state":
 		g.onBridgeStatePublish(client, message)
@@ -124,6 +157,18 @@ func (g *Bridge) onBridgePublish(client *mqtt.Client, message mqtt.Message) {
 		g.onConfigPublish(client, message)
 	case "networkmap":
 		g.onNetworkmapPublish(client, message)
+	// zigbee2mqtt >= 1.17
+	case "info":
+		g.onBridgeInfoPublish(client, message)
+	case "devices":
+		g.onBridgeDevicesPublish(client, message)
+	case "event":
+		g.onBridgeEventPublish(client, message)
+	case "response":
+		g.onBridgeResponsePublish(client, message)
+	case "logging":
+		g.onLoggingPublish(client, message)
+	case "request", "groups", "definitions", "extensions":
 	default:
 		log.Warnf("unknown topic %v", topic)
 	}
@@ -157,9 +202,66 @@ func (g *Bridge) onAssistPublish(client *mqtt.Client, message mqtt.Message) {
 	}
 }
 
+// onDevicePublish the state of the device goes to the subscribers, the update of the firmware is taken from it too
+func (g *Bridge) onDevicePublish(client *mqtt.Client, message mqtt.Message) {
+
+	var friendlyName = strings.TrimPrefix(message.Topic, g.model.BaseTopic+"/")
+
+	if friendlyName == "bridge" || friendlyName == message.Topic {
+		return
+	}
+
+	payload := make(map[string]interface{})
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		return
+	}
+
+	deviceId := friendlyName
+	if device, err := g.safeGetDevice(friendlyName); err == nil {
+		deviceId = device.GetModel().Id
+	}
+
+	g.states.publish(DeviceState{
+		BridgeId:     g.model.Id,
+		DeviceId:     deviceId,
+		FriendlyName: friendlyName,
+		Payload:      payload,
+	})
+
+	state := DeviceOtaState{}
+	if err := json.Unmarshal(message.Payload, &state); err != nil {
+		return
+	}
+
+	if state.Update == nil {
+		if state.UpdateAvailable != nil && *state.UpdateAvailable {
+			g.otaDeviceAvailable(friendlyName)
+		}
+		return
+	}
+
+	switch state.Update.State {
+	case "updating":
+		g.otaProgress(friendlyName, state.Update.Progress, state.Update.Remaining)
+	case "available":
+		g.otaDeviceAvailable(friendlyName)
+	}
+}
+
 func (g *Bridge) onBridgeStatePublish(client *mqtt.Client, message mqtt.Message) {
+	var state = string(message.Payload)
+
+	// the newer releases publish {"state": "online"}
+	if strings.HasPrefix(state, "{") {
+		var payload struct {
+			State string `json:"state"`
+		}
+		_ = json.Unmarshal(message.Payload, &payload)
+		state = payload.State
+	}
+
 	g.settingsLock.Lock()
-	g.state = string(message.Payload)
+	g.state = state
 	g.settingsLock.Unlock()
 }
 
@@ -173,7 +275,7 @@ func (g *Bridge) onNetworkmapPublish(client *mqtt.Client, message mqtt.Message)
 
 	switch topic[3] {
 	case "raw":
-		log.Info("method not implemented")
+		g.saveNetworkmap(message.Payload)
 	case "graphviz":
 		g.networkmapLock.Lock()
 		g.scanInProcess = false
@@ -200,6 +302,171 @@ func (g *Bridge) onConfigPublish(client *mqtt.Client, message mqtt.Message) {
 	g.settingsLock.Lock()
 	g.config = config
 	g.settingsLock.Unlock()
+
+	g.detectProtocol(config.Version)
+}
+
+func (g *Bridge) onBridgeInfoPublish(client *mqtt.Client, message mqtt.Message) {
+	info := BridgeInfo{}
+	if err := json.Unmarshal(message.Payload, &info); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	g.settingsLock.Lock()
+	g.config = BridgeConfig{
+		Version:     info.Version,
+		Commit:      info.Commit,
+		Coordinator: info.Coordinator,
+		LogLevel:    info.Config.Advanced.LogLevel,
+		PermitJoin:  fmt.Sprintf("%t", info.PermitJoin),
+	}
+	g.settingsLock.Unlock()
+
+	g.detectProtocol(info.Version)
+}
+
+// detectProtocol the request api is used since zigbee2mqtt 1.17, the older bridges get the legacy topics
+func (g *Bridge) detectProtocol(version string) {
+	if version == "" {
+		return
+	}
+
+	g.protocolLock.Lock()
+	defer g.protocolLock.Unlock()
+
+	if requestApiSupported(version) {
+		if g.protocol.Name() != ProtocolRequest {
+			log.Infof("bridge id %v, zigbee2mqtt %v, the request api is used", g.model.Id, version)
+			g.protocol = newProtocolRequest(g.mqttClient, g.topic, fmt.Sprintf("bridge_%v", g.model.Id))
+		}
+		return
+	}
+
+	if g.protocol.Name() != ProtocolLegacy {
+		log.Infof("bridge id %v, zigbee2mqtt %v, the legacy api is used", g.model.Id, version)
+		g.protocol = newProtocolLegacy(g.mqttClient, g.topic)
+	}
+}
+
+func (g *Bridge) getProtocol() protocol {
+	g.protocolLock.Lock()
+	defer g.protocolLock.Unlock()
+	return g.protocol
+}
+
+func (g *Bridge) protocolName() string {
+	if p := g.getProtocol(); p != nil {
+		return p.Name()
+	}
+	return ""
+}
+
+func (g *Bridge) onBridgeDevicesPublish(client *mqtt.Client, message mqtt.Message) {
+	var devices []BridgeDevice
+	if err := json.Unmarshal(message.Payload, &devices); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	for _, device := range devices {
+		// the definition is known after the interview
+		if device.Type == "Coordinator" || device.Definition == nil {
+			continue
+		}
+		g.devicePairing(BridgePairingMeta{
+			FriendlyName: device.FriendlyName,
+			Model:        device.Definition.Model,
+			Vendor:       device.Definition.Vendor,
+			Description:  device.Definition.Description,
+			Supported:    device.Supported,
+			Exposes:      device.Definition.Exposes,
+		})
+	}
+}
+
+func (g *Bridge) onBridgeEventPublish(client *mqtt.Client, message mqtt.Message) {
+	event := BridgeEvent{}
+	if err := json.Unmarshal(message.Payload, &event); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	log.Infof("%v, %v, %v", event.Type, event.Data.FriendlyName, event.Data.Status)
+
+	switch event.Type {
+	case "device_interview":
+		if event.Data.Status != "successful" || event.Data.Definition == nil {
+			return
+		}
+		g.devicePairing(BridgePairingMeta{
+			FriendlyName: event.Data.FriendlyName,
+			Model:        event.Data.Definition.Model,
+			Vendor:       event.Data.Definition.Vendor,
+			Description:  event.Data.Definition.Description,
+			Supported:    event.Data.Supported,
+			Exposes:      event.Data.Definition.Exposes,
+		})
+	case "device_leave":
+		g.deviceRemoved(event.Data.FriendlyName)
+	}
+}
+
+func (g *Bridge) onBridgeResponsePublish(client *mqtt.Client, message mqtt.Message) {
+
+	var topic = strings.Split(message.Topic, "/")
+
+	if len(topic) < 4 {
+		return
+	}
+
+	resp := &BridgeResponse{}
+	if err := json.Unmarshal(message.Payload, resp); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	var path = strings.Join(topic[3:], "/")
+	if resp.Status != "ok" {
+		log.Warnf("bridge id %v, request %v: %v", g.model.Id, path, resp.Error)
+	}
+
+	// the map of any request, not only of the own one
+	if path == "networkmap" {
+		networkmap := BridgeNetworkmap{}
+		_ = json.Unmarshal(resp.Data, &networkmap)
+		switch {
+		case resp.Status == "ok" && networkmap.Type == "raw":
+			g.saveNetworkmap(networkmap.Value)
+		case resp.Status == "ok" && networkmap.Type == "graphviz":
+			var value string
+			_ = json.Unmarshal(networkmap.Value, &value)
+			g.networkmapLock.Lock()
+			g.scanInProcess = false
+			g.lastScan = time.Now()
+			g.networkmap = value
+			g.networkmapLock.Unlock()
+		default:
+			g.networkmapLock.Lock()
+			g.scanInProcess = false
+			g.networkmapLock.Unlock()
+		}
+	}
+
+	switch path {
+	case "device/ota_update/check", "device/ota_update/update":
+		g.onOtaResponse(path, resp)
+	}
+
+	if p, ok := g.getProtocol().(*protocolRequest); ok && resp.Transaction != "" {
+		p.OnResponse(resp)
+	}
+}
+
+func (g *Bridge) onLoggingPublish(client *mqtt.Client, message mqtt.Message) {
+	var lm BridgeLogging
+	_ = json.Unmarshal(message.Payload, &lm)
+	log.Infof("%v, %v", lm.Level, lm.Message)
 }
 
 func (g *Bridge) onConfigDevicesPublish(client *mqtt.Client, message mqtt.Message) {}
@@ -285,6 +552,10 @@ func (g *Bridge) onLogPublish(client *mqtt.Client, message mqtt.Message) {
 		params := BridgePairingMeta{}
 		_ = common.Copy(&params, lm.Meta, common.JsonEngine)
 		g.devicePairing(params)
+	case "ota_update":
+		params := BridgeOtaMeta{}
+		_ = common.Copy(&params, lm.Meta, common.JsonEngine)
+		g.onOtaLog(lm.Message, params)
 	}
 }
 
@@ -304,16 +575,19 @@ func (g *Bridge) getDevices() {
 	g.mqttClient.Publish(g.topic("/bridge/config/devices/get"), []byte{})
 }
 
-func (g *Bridge) configPermitJoin(tr bool) {
-	var permitJoin = "true"
-	if !tr {
-		permitJoin = "false"
-	}
-	g.mqttClient.Publish(g.topic("/bridge/config/permit_join"), []byte(permitJoin))
+func (g *Bridge) configPermitJoin(tr bool) error {
+	return g.getProtocol().PermitJoin(tr)
+}
+
+// Add the last_seen attribute to the messages of the devices: disable|ISO_8601|ISO_8601_local|epoch
+func (g *Bridge) configLastSeen(format string) error {
+	return g.getProtocol().LastSeen(format)
 }
 
-func (g *Bridge) configLastSeen() {}
-func (g *Bridge) configElapsed()  {}
+// Add the elapsed attribute (milliseconds since the previous message) to the messages of the devices
+func (g *Bridge) configElapsed(tr bool) error {
+	return g.getProtocol().Elapsed(tr)
+}
 
 // Resets the ZNP (CC2530/CC2531).
 func (g *Bridge) configReset() {
@@ -326,19 +600,24 @@ func (g *Bridge) ConfigReset() {
 	time.Sleep(time.Second * 5)
 }
 
-func (g *Bridge) configLogLevel() {}
+// debug|info|warn|error
+func (g *Bridge) configLogLevel(level string) error {
+	return g.getProtocol().LogLevel(level)
+}
 
 // DeviceOptions ...
-func (g *Bridge) DeviceOptions() {}
+func (g *Bridge) DeviceOptions(friendlyName string, options map[string]interface{}) error {
+	return g.getProtocol().DeviceOptions(friendlyName, options)
+}
 
 // Remove ...
-func (g *Bridge) Remove(friendlyName string) {
-	g.mqttClient.Publish(g.topic("/bridge/config/remove"), []byte(friendlyName))
+func (g *Bridge) Remove(friendlyName string) error {
+	return g.getProtocol().Remove(friendlyName)
 }
 
 // Ban ...
-func (g *Bridge) Ban(friendlyName string) {
-	g.mqttClient.Publish(g.topic("/bridge/config/force_remove"), []byte(friendlyName))
+func (g *Bridge) Ban(friendlyName string) error {
+	return g.getProtocol().Ban(friendlyName)
 }
 
 // Whitelist ...
@@ -361,14 +640,51 @@ func (g *Bridge) RenameDevice(friendlyName, name string) (err error) {
 	return
 }
 
-// RenameLast ...
-func (g *Bridge) RenameLast() {}
+// RenameLast the last joined device
+func (g *Bridge) RenameLast(name string) error {
+	return g.getProtocol().RenameLast(name)
+}
 
 // AddGroup ...
-func (g *Bridge) AddGroup() {}
+func (g *Bridge) AddGroup(friendlyName string, groupId int) error {
+	return g.getProtocol().AddGroup(friendlyName, groupId)
+}
 
 // RemoveGroup ...
-func (g *Bridge) RemoveGroup() {}
+func (g *Bridge) RemoveGroup(friendlyName string) error {
+	return g.getProtocol().RemoveGroup(friendlyName)
+}
+
+// AddGroupDevice ...
+func (g *Bridge) AddGroupDevice(groupName, deviceName string) error {
+	return g.getProtocol().AddGroupDevice(groupName, deviceName)
+}
+
+// RemoveGroupDevice ...
+func (g *Bridge) RemoveGroupDevice(groupName, deviceName string) error {
+	return g.getProtocol().RemoveGroupDevice(groupName, deviceName)
+}
+
+// SetGroupState all the devices of the group are switched by the one message, e.g. {"state": "ON", "brightness": 128}
+func (g *Bridge) SetGroupState(groupName string, state map[string]interface{}) {
+	payload, _ := json.Marshal(state)
+	g.mqttClient.Publish(g.topic(fmt.Sprintf("/%s/set", groupName)), payload)
+}
+
+// StoreScene the current state of the devices of the group
+func (g *Bridge) StoreScene(groupName string, sceneId int) {
+	g.SetGroupState(groupName, map[string]interface{}{"scene_store": sceneId})
+}
+
+// RecallScene ...
+func (g *Bridge) RecallScene(groupName string, sceneId int) {
+	g.SetGroupState(groupName, map[string]interface{}{"scene_recall": sceneId})
+}
+
+// RemoveScene ...
+func (g *Bridge) RemoveScene(groupName string, sceneId int) {
+	g.SetGroupState(groupName, map[string]interface{}{"scene_remove": sceneId})
+}
 
 // UpdateNetworkmap ...
 func (g *Bridge) UpdateNetworkmap() {
@@ -380,7 +696,10 @@ func (g *Bridge) UpdateNetworkmap() {
 	}
 	g.scanInProcess = true
 
-	g.mqttClient.Publish(g.topic("/bridge/networkmap"), []byte("graphviz"))
+	if err := g.getProtocol().Networkmap(); err != nil {
+		log.Error(err.Error())
+		g.scanInProcess = false
+	}
 }
 
 // Networkmap ...
@@ -451,6 +770,9 @@ func (g *Bridge) devicePairing(params BridgePairingMeta) {
 	device.SetModel(params.Model)
 	device.SetDescription(params.Description)
 	device.SetVendor(params.Vendor)
+	if len(params.Exposes) > 0 {
+		device.SetExposes(params.Exposes)
+	}
 
 	if err = g.safeUpdateDevice(device); err != nil {
 		log.Error(err.Error())
@@ -458,28 +780,39 @@ func (g *Bridge) devicePairing(params BridgePairingMeta) {
 }
 
 // PermitJoin ...
-func (g *Bridge) PermitJoin(permitJoin bool) {
+func (g *Bridge) PermitJoin(permitJoin bool) (err error) {
 	g.modelLock.Lock()
-	defer g.modelLock.Unlock()
-
 	g.model.PermitJoin = permitJoin
-	if err := g.adaptors.Zigbee2mqtt.Update(g.model); err != nil {
+	err = g.adaptors.Zigbee2mqtt.Update(g.model)
+	g.modelLock.Unlock()
+
+	if err != nil {
 		return
 	}
-	g.configPermitJoin(g.model.PermitJoin)
+
+	// the lock is not held while the bridge answers
+	err = g.configPermitJoin(permitJoin)
+
+	return
 }
 
 // UpdateModel ...
-func (g *Bridge) UpdateModel(model *m.Zigbee2mqtt) {
+func (g *Bridge) UpdateModel(model *m.Zigbee2mqtt) (err error) {
 	g.modelLock.Lock()
-	defer g.modelLock.Unlock()
-
 	g.model.Login = model.Login
 	g.model.BaseTopic = model.BaseTopic
 	g.model.PermitJoin = model.PermitJoin
 	g.model.EncryptedPassword = model.EncryptedPassword
+	g.model.OtaWindowStart = model.OtaWindowStart
+	g.model.OtaWindowEnd = model.OtaWindowEnd
+	g.modelLock.Unlock()
+
+	// the scheduled updates wait for the new window
+	g.otaNext()
 
-	g.configPermitJoin(g.model.PermitJoin)
+	err = g.configPermitJoin(model.PermitJoin)
+
+	return
 }
 
 // Info ...
@@ -504,6 +837,8 @@ func (g *Bridge) Info() (info *Zigbee2mqttInfo) {
 		LastScan:      g.lastScan,
 		Networkmap:    g.networkmap,
 		Status:        g.state,
+		Version:       g.config.Version,
+		Protocol:      g.protocolName(),
 		Model:         model,
 	}
 
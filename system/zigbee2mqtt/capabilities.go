@@ -0,0 +1,206 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	m "github.com/e154/smart-home/models"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// the preset colors of the lights with the color feature
+var capabilityColors = []struct {
+	name string
+	hex  string
+}{
+	{"red", "#FF0000"},
+	{"green", "#00FF00"},
+	{"blue", "#0000FF"},
+	{"white", "#FFFFFF"},
+}
+
+var capabilitySlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CapabilityAction the action publishes the payload to {friendly_name}/set
+type CapabilityAction struct {
+	Name        string
+	Description string
+	Payload     map[string]interface{}
+}
+
+// Script the source of the action script
+func (a CapabilityAction) Script() string {
+	payload, _ := json.Marshal(a.Payload)
+	return fmt.Sprintf("Device.Send(Zigbee2mqtt('set', %s));\n", strconv.Quote(string(payload)))
+}
+
+// CapabilityState the state of the device is taken when the property gets the value
+type CapabilityState struct {
+	SystemName  string
+	Description string
+	Property    string
+	Value       interface{}
+}
+
+// Capabilities the actions and the states of the device from the exposes of the definition:
+//
+// binary   - {property}_on, {property}_off, {property}_toggle
+// enum     - {property}_{value}
+// numeric  - {property}_min, {property}_max
+// color    - color_red, color_green, color_blue, color_white
+//
+// the actions are made for the features with the "set" access, the states for the features with the "state" access
+type Capabilities struct {
+	Actions []CapabilityAction
+	States  []CapabilityState
+	names   map[string]bool
+}
+
+// NewCapabilities ...
+func NewCapabilities(exposes []*m.Zigbee2mqttExpose) (c *Capabilities) {
+	c = &Capabilities{
+		Actions: make([]CapabilityAction, 0),
+		States:  make([]CapabilityState, 0),
+		names:   make(map[string]bool),
+	}
+
+	for _, feature := range m.Zigbee2mqttFeatures(exposes) {
+		switch feature.Type {
+		case m.Zigbee2mqttExposeBinary:
+			c.binary(feature)
+		case m.Zigbee2mqttExposeEnum:
+			c.enum(feature)
+		case m.Zigbee2mqttExposeNumeric:
+			c.numeric(feature)
+		case m.Zigbee2mqttExposeComposite:
+			c.composite(feature)
+		}
+	}
+
+	return
+}
+
+func (c *Capabilities) binary(feature *m.Zigbee2mqttExpose) {
+	if feature.CanSet() {
+		c.addAction(feature, "on", feature.ValueOn)
+		c.addAction(feature, "off", feature.ValueOff)
+		if feature.ValueToggle != nil {
+			c.addAction(feature, "toggle", feature.ValueToggle)
+		}
+	}
+	if feature.HasState() {
+		c.addState(feature, "on", feature.ValueOn)
+		c.addState(feature, "off", feature.ValueOff)
+	}
+}
+
+func (c *Capabilities) enum(feature *m.Zigbee2mqttExpose) {
+	for _, value := range feature.Values {
+		if feature.CanSet() {
+			c.addAction(feature, fmt.Sprintf("%v", value), value)
+		}
+		if feature.HasState() {
+			c.addState(feature, fmt.Sprintf("%v", value), value)
+		}
+	}
+}
+
+func (c *Capabilities) numeric(feature *m.Zigbee2mqttExpose) {
+	if !feature.CanSet() {
+		return
+	}
+	if feature.ValueMin != nil {
+		c.addAction(feature, "min", *feature.ValueMin)
+	}
+	if feature.ValueMax != nil {
+		c.addAction(feature, "max", *feature.ValueMax)
+	}
+}
+
+func (c *Capabilities) composite(feature *m.Zigbee2mqttExpose) {
+	// color_xy and color_hs take the hex color as well
+	if feature.Property != "color" || !feature.CanSet() {
+		return
+	}
+	for _, color := range capabilityColors {
+		c.addAction(feature, color.name, map[string]interface{}{"hex": color.hex})
+	}
+}
+
+func (c *Capabilities) addAction(feature *m.Zigbee2mqttExpose, suffix string, value interface{}) {
+	if value == nil {
+		return
+	}
+	name := capabilityName(feature.Property, suffix)
+	if c.names["action:"+name] {
+		return
+	}
+	c.names["action:"+name] = true
+	c.Actions = append(c.Actions, CapabilityAction{
+		Name:        name,
+		Description: fmt.Sprintf("set %s to %v", feature.Property, capabilityValue(value)),
+		Payload:     map[string]interface{}{feature.Property: value},
+	})
+}
+
+func (c *Capabilities) addState(feature *m.Zigbee2mqttExpose, suffix string, value interface{}) {
+	if value == nil {
+		return
+	}
+	name := capabilityName(feature.Property, suffix)
+	if c.names["state:"+name] {
+		return
+	}
+	c.names["state:"+name] = true
+	c.States = append(c.States, CapabilityState{
+		SystemName:  name,
+		Description: fmt.Sprintf("%s is %v", feature.Property, capabilityValue(value)),
+		Property:    feature.Property,
+		Value:       value,
+	})
+}
+
+// MatchStates the names of the states matched by the values of the state payload of the device,
+// in the order of the exposes
+func (c *Capabilities) MatchStates(payload map[string]interface{}) (names []string) {
+	for _, state := range c.States {
+		if value, ok := payload[state.Property]; ok && reflect.DeepEqual(value, state.Value) {
+			names = append(names, state.SystemName)
+		}
+	}
+	return
+}
+
+func capabilityName(property, suffix string) string {
+	name := capabilitySlug.ReplaceAllString(strings.ToLower(property+"_"+suffix), "_")
+	return strings.Trim(name, "_")
+}
+
+func capabilityValue(value interface{}) interface{} {
+	if v, ok := value.(map[string]interface{}); ok {
+		if hex, ok := v["hex"]; ok {
+			return hex
+		}
+	}
+	return value
+}
@@ -111,6 +111,17 @@ func (d *Device) SetVendor(vendor string) {
 	d.modelLock.Unlock()
 }
 
+// SetExposes the properties of the features are the functions of the device too
+func (d *Device) SetExposes(exposes []*models.Zigbee2mqttExpose) {
+	d.modelLock.Lock()
+	d.model.Exposes = exposes
+	d.modelLock.Unlock()
+
+	for _, feature := range models.Zigbee2mqttFeatures(exposes) {
+		d.AddFunc(feature.Property)
+	}
+}
+
 // GetImage ...
 func (d *Device) GetImage() string {
 	d.modelLock.Lock()
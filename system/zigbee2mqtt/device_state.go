@@ -0,0 +1,66 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import "sync"
+
+// DeviceState the state payload of the device published to {base_topic}/{friendly_name}
+type DeviceState struct {
+	BridgeId     int64
+	DeviceId     string
+	FriendlyName string
+	Payload      map[string]interface{}
+}
+
+// stateSubscribers the listeners of the state payloads of the devices of all bridges
+type stateSubscribers struct {
+	sync.Mutex
+	subscribers map[string]func(state DeviceState)
+}
+
+func newStateSubscribers() *stateSubscribers {
+	return &stateSubscribers{
+		subscribers: make(map[string]func(state DeviceState)),
+	}
+}
+
+func (s *stateSubscribers) subscribe(name string, f func(state DeviceState)) {
+	s.Lock()
+	s.subscribers[name] = f
+	s.Unlock()
+}
+
+func (s *stateSubscribers) unsubscribe(name string) {
+	s.Lock()
+	delete(s.subscribers, name)
+	s.Unlock()
+}
+
+func (s *stateSubscribers) publish(state DeviceState) {
+	s.Lock()
+	handlers := make([]func(state DeviceState), 0, len(s.subscribers))
+	for _, f := range s.subscribers {
+		handlers = append(handlers, f)
+	}
+	s.Unlock()
+
+	for _, f := range handlers {
+		f(state)
+	}
+}
@@ -0,0 +1,80 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	m "github.com/e154/smart-home/models"
+)
+
+// Javascript Binding
+//
+// Zigbee2mqttGroup
+//	.Set(name, state)
+//	.StoreScene(name, sceneId, sceneName)
+//	.RecallScene(name, sceneId)
+//
+type GroupBind struct {
+	zigbee2mqtt *Zigbee2mqtt
+}
+
+// NewGroupBind ...
+func NewGroupBind(zigbee2mqtt *Zigbee2mqtt) *GroupBind {
+	return &GroupBind{zigbee2mqtt: zigbee2mqtt}
+}
+
+// Set the state of all the devices of the group by the one message, e.g. {"state": "ON"}
+func (b *GroupBind) Set(name string, state map[string]interface{}) (err error) {
+	var group *m.Zigbee2mqttGroup
+	if group, err = b.zigbee2mqtt.GetGroupByName(name); err != nil {
+		return
+	}
+	err = b.zigbee2mqtt.SetGroupState(group.Zigbee2mqttId, group.Id, state)
+	return
+}
+
+// StoreScene ...
+func (b *GroupBind) StoreScene(name string, sceneId int, sceneName string) (err error) {
+	var group *m.Zigbee2mqttGroup
+	if group, err = b.zigbee2mqtt.GetGroupByName(name); err != nil {
+		return
+	}
+
+	scene := &m.Zigbee2mqttScene{
+		Zigbee2mqttGroupId: group.Id,
+		SceneId:            sceneId,
+		Name:               sceneName,
+	}
+	if _, errs := scene.Valid(); len(errs) > 0 {
+		err = errs[0]
+		return
+	}
+
+	_, err = b.zigbee2mqtt.StoreScene(group.Zigbee2mqttId, scene)
+	return
+}
+
+// RecallScene ...
+func (b *GroupBind) RecallScene(name string, sceneId int) (err error) {
+	var group *m.Zigbee2mqttGroup
+	if group, err = b.zigbee2mqtt.GetGroupByName(name); err != nil {
+		return
+	}
+	err = b.zigbee2mqtt.RecallScene(group.Zigbee2mqttId, group.Id, sceneId)
+	return
+}
@@ -0,0 +1,327 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	m "github.com/e154/smart-home/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// NetworkmapMinLqi the link with the lower quality is weak
+	NetworkmapMinLqi = 50
+	// NetworkmapMaxChildren the router with more children is overloaded
+	NetworkmapMaxChildren = 20
+)
+
+// NetworkmapLink the link of the report with the names of the devices
+type NetworkmapLink struct {
+	Source       string `json:"source"`
+	SourceName   string `json:"source_name"`
+	Target       string `json:"target"`
+	TargetName   string `json:"target_name"`
+	Lqi          int    `json:"lqi"`
+	Relationship int    `json:"relationship"`
+}
+
+// NetworkmapNode the node of the report
+type NetworkmapNode struct {
+	IeeeAddr     string `json:"ieee_addr"`
+	FriendlyName string `json:"friendly_name"`
+	Type         string `json:"type"`
+	Children     int    `json:"children"`
+}
+
+// NetworkmapReport the problems of the mesh found in the scan
+type NetworkmapReport struct {
+	NetworkmapId      int64             `json:"networkmap_id"`
+	BridgeId          int64             `json:"bridge_id"`
+	CreatedAt         time.Time         `json:"created_at"`
+	MinLqi            int               `json:"min_lqi"`
+	MaxChildren       int               `json:"max_children"`
+	Nodes             int               `json:"nodes"`
+	Links             int               `json:"links"`
+	Routers           int               `json:"routers"`
+	EndDevices        int               `json:"end_devices"`
+	WeakLinks         []*NetworkmapLink `json:"weak_links"`
+	Orphans           []*NetworkmapNode `json:"orphans"`
+	OverloadedRouters []*NetworkmapNode `json:"overloaded_routers"`
+}
+
+// ParseNetworkmapRaw ...
+func ParseNetworkmapRaw(data []byte) (networkmap *m.Zigbee2mqttNetworkmap, err error) {
+
+	raw := BridgeNetworkmapRaw{}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+
+	networkmap = &m.Zigbee2mqttNetworkmap{
+		Nodes:     make([]*m.Zigbee2mqttNetworkNode, 0, len(raw.Nodes)),
+		Links:     make([]*m.Zigbee2mqttNetworkLink, 0, len(raw.Links)),
+		CreatedAt: time.Now(),
+	}
+
+	for _, node := range raw.Nodes {
+		item := &m.Zigbee2mqttNetworkNode{
+			IeeeAddr:       node.IeeeAddr,
+			FriendlyName:   node.FriendlyName,
+			Type:           node.Type,
+			NetworkAddress: node.NetworkAddress,
+			Manufacturer:   node.ManufacturerName,
+			Model:          node.ModelID,
+			Failed:         node.Failed,
+		}
+		if node.LastSeen != nil {
+			lastSeen := time.Unix(0, *node.LastSeen*int64(time.Millisecond))
+			item.LastSeen = &lastSeen
+		}
+		networkmap.Nodes = append(networkmap.Nodes, item)
+	}
+
+	for _, link := range raw.Links {
+		item := &m.Zigbee2mqttNetworkLink{
+			Source:       link.SourceIeeeAddr,
+			Target:       link.TargetIeeeAddr,
+			Lqi:          link.Lqi,
+			Depth:        link.Depth,
+			Relationship: link.Relationship,
+		}
+		if link.Source != nil {
+			item.Source = link.Source.IeeeAddr
+		}
+		if link.Target != nil {
+			item.Target = link.Target.IeeeAddr
+		}
+		if link.Linkquality != nil {
+			item.Lqi = *link.Linkquality
+		}
+		if item.Source == "" || item.Target == "" {
+			continue
+		}
+		networkmap.Links = append(networkmap.Links, item)
+	}
+
+	return
+}
+
+// AnalyzeNetworkmap the weak links, the end devices without the parent and the routers with too many children,
+// the zero thresholds are replaced by the defaults
+func AnalyzeNetworkmap(networkmap *m.Zigbee2mqttNetworkmap, minLqi, maxChildren int) (report *NetworkmapReport) {
+
+	if minLqi <= 0 {
+		minLqi = NetworkmapMinLqi
+	}
+	if maxChildren <= 0 {
+		maxChildren = NetworkmapMaxChildren
+	}
+
+	report = &NetworkmapReport{
+		NetworkmapId:      networkmap.Id,
+		BridgeId:          networkmap.Zigbee2mqttId,
+		CreatedAt:         networkmap.CreatedAt,
+		MinLqi:            minLqi,
+		MaxChildren:       maxChildren,
+		Nodes:             len(networkmap.Nodes),
+		Links:             len(networkmap.Links),
+		WeakLinks:         make([]*NetworkmapLink, 0),
+		Orphans:           make([]*NetworkmapNode, 0),
+		OverloadedRouters: make([]*NetworkmapNode, 0),
+	}
+
+	nodes := make(map[string]*m.Zigbee2mqttNetworkNode)
+	for _, node := range networkmap.Nodes {
+		nodes[node.IeeeAddr] = node
+		switch node.Type {
+		case m.Zigbee2mqttNodeRouter:
+			report.Routers++
+		case m.Zigbee2mqttNodeEndDevice:
+			report.EndDevices++
+		}
+	}
+
+	name := func(ieeeAddr string) string {
+		if node, ok := nodes[ieeeAddr]; ok && node.FriendlyName != "" {
+			return node.FriendlyName
+		}
+		return ieeeAddr
+	}
+
+	// the link is reported by both neighbors, the worse quality is taken
+	weak := make(map[string]*NetworkmapLink)
+	parents := make(map[string]string)
+	children := make(map[string]map[string]bool)
+
+	for _, link := range networkmap.Links {
+
+		var parent, child string
+		switch link.Relationship {
+		case m.Zigbee2mqttRelationPreviousChild:
+			// the stale entry of the neighbor table
+			continue
+		case m.Zigbee2mqttRelationParent:
+			parent, child = link.Source, link.Target
+		case m.Zigbee2mqttRelationChild:
+			parent, child = link.Target, link.Source
+		}
+
+		if parent != "" {
+			parents[child] = parent
+			if _, ok := children[parent]; !ok {
+				children[parent] = make(map[string]bool)
+			}
+			children[parent][child] = true
+		}
+
+		if link.Lqi >= minLqi {
+			continue
+		}
+
+		key := link.Source + ":" + link.Target
+		if link.Target < link.Source {
+			key = link.Target + ":" + link.Source
+		}
+		if prev, ok := weak[key]; ok && prev.Lqi <= link.Lqi {
+			continue
+		}
+		weak[key] = &NetworkmapLink{
+			Source:       link.Source,
+			SourceName:   name(link.Source),
+			Target:       link.Target,
+			TargetName:   name(link.Target),
+			Lqi:          link.Lqi,
+			Relationship: link.Relationship,
+		}
+	}
+
+	for _, link := range weak {
+		report.WeakLinks = append(report.WeakLinks, link)
+	}
+	sort.Slice(report.WeakLinks, func(i, j int) bool {
+		if report.WeakLinks[i].Lqi != report.WeakLinks[j].Lqi {
+			return report.WeakLinks[i].Lqi < report.WeakLinks[j].Lqi
+		}
+		return report.WeakLinks[i].Source < report.WeakLinks[j].Source
+	})
+
+	for _, node := range networkmap.Nodes {
+		switch node.Type {
+		case m.Zigbee2mqttNodeEndDevice:
+			if _, ok := parents[node.IeeeAddr]; !ok {
+				report.Orphans = append(report.Orphans, &NetworkmapNode{
+					IeeeAddr:     node.IeeeAddr,
+					FriendlyName: name(node.IeeeAddr),
+					Type:         node.Type,
+				})
+			}
+		case m.Zigbee2mqttNodeRouter, m.Zigbee2mqttNodeCoordinator:
+			if len(children[node.IeeeAddr]) > maxChildren {
+				report.OverloadedRouters = append(report.OverloadedRouters, &NetworkmapNode{
+					IeeeAddr:     node.IeeeAddr,
+					FriendlyName: name(node.IeeeAddr),
+					Type:         node.Type,
+					Children:     len(children[node.IeeeAddr]),
+				})
+			}
+		}
+	}
+	sort.Slice(report.Orphans, func(i, j int) bool {
+		return report.Orphans[i].IeeeAddr < report.Orphans[j].IeeeAddr
+	})
+	sort.Slice(report.OverloadedRouters, func(i, j int) bool {
+		return report.OverloadedRouters[i].Children > report.OverloadedRouters[j].Children
+	})
+
+	return
+}
+
+// NetworkmapGraphviz the text of the map for the graphviz render,
+// the parent-child links are solid, the other links are dashed
+func NetworkmapGraphviz(networkmap *m.Zigbee2mqttNetworkmap) string {
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph G {\nnode[shape=record];\n")
+
+	for _, node := range networkmap.Nodes {
+		var style string
+		switch node.Type {
+		case m.Zigbee2mqttNodeCoordinator:
+			style = `style="bold, filled", fillcolor="#e04e5d", fontcolor="#ffffff"`
+		case m.Zigbee2mqttNodeRouter:
+			style = `style="rounded, filled", fillcolor="#4ea3e0", fontcolor="#ffffff"`
+		default:
+			style = `style="rounded, dashed, filled", fillcolor="#fff8ce", fontcolor="#000000"`
+		}
+		label := []string{graphvizEscape(node.FriendlyName), fmt.Sprintf("%s (0x%04x)", node.IeeeAddr, node.NetworkAddress)}
+		if node.Manufacturer != "" || node.Model != "" {
+			label = append(label, graphvizEscape(strings.TrimSpace(node.Manufacturer+" "+node.Model)))
+		}
+		buf.WriteString(fmt.Sprintf("  \"%s\" [%s, label=\"{%s}\"];\n", node.IeeeAddr, style, strings.Join(label, "|")))
+	}
+
+	for _, link := range networkmap.Links {
+		if link.Relationship == m.Zigbee2mqttRelationPreviousChild {
+			continue
+		}
+		style := "dashed"
+		if link.Relationship == m.Zigbee2mqttRelationParent || link.Relationship == m.Zigbee2mqttRelationChild {
+			style = "solid"
+		}
+		buf.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [style=%s, label=\"%d\"]\n", link.Source, link.Target, style, link.Lqi))
+	}
+
+	buf.WriteString("}")
+
+	return buf.String()
+}
+
+func graphvizEscape(s string) string {
+	return strings.NewReplacer(`"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace(s)
+}
+
+// saveNetworkmap the scan is stored, the graphviz text is made from it
+func (g *Bridge) saveNetworkmap(data []byte) {
+
+	networkmap, err := ParseNetworkmapRaw(data)
+	if err != nil {
+		log.Error(err.Error())
+		g.networkmapLock.Lock()
+		g.scanInProcess = false
+		g.networkmapLock.Unlock()
+		return
+	}
+
+	networkmap.Zigbee2mqttId = g.model.Id
+	if networkmap.Id, err = g.adaptors.Zigbee2mqttNetworkmap.Add(networkmap); err != nil {
+		log.Error(err.Error())
+	}
+
+	g.networkmapLock.Lock()
+	g.scanInProcess = false
+	g.lastScan = networkmap.CreatedAt
+	g.networkmap = NetworkmapGraphviz(networkmap)
+	g.networkmapLock.Unlock()
+
+	log.Infof("bridge id %v, network map: %d nodes, %d links", g.model.Id, len(networkmap.Nodes), len(networkmap.Links))
+}
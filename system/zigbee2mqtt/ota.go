@@ -0,0 +1,422 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// the statuses of the firmware update of the device
+const (
+	OtaIdle         = "idle"
+	OtaChecking     = "checking"
+	OtaAvailable    = "available"
+	OtaNotAvailable = "not_available"
+	OtaScheduled    = "scheduled"
+	OtaUpdating     = "updating"
+	OtaSucceeded    = "succeeded"
+	OtaFailed       = "failed"
+)
+
+const (
+	// how often the scheduled updates are looked for
+	otaInterval = time.Minute
+	// the update without the progress for this time is failed
+	otaTimeout = time.Hour
+)
+
+var (
+	// ErrOtaInProgress ...
+	ErrOtaInProgress = errors.New("the update of the device is in progress")
+)
+
+// OtaStatus the firmware update of the device
+type OtaStatus struct {
+	BridgeId  int64      `json:"bridge_id"`
+	DeviceId  string     `json:"device_id"`
+	Status    string     `json:"status"`
+	Progress  float64    `json:"progress"`  // percents
+	Remaining int64      `json:"remaining"` // seconds
+	Error     string     `json:"error"`
+	CheckedAt *time.Time `json:"checked_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// OtaWindow the time of the day when the updates run, the end may be after midnight: 23:00 - 04:00,
+// the empty window allows the updates at any time
+type OtaWindow struct {
+	Start string
+	End   string
+}
+
+// Contains ...
+func (w OtaWindow) Contains(t time.Time) bool {
+	start, err := parseOtaClock(w.Start)
+	if err != nil {
+		return true
+	}
+	end, err := parseOtaClock(w.End)
+	if err != nil || start == end {
+		return true
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// the minutes since midnight of the HH:MM clock
+func parseOtaClock(clock string) (minutes int, err error) {
+	var h, m int
+	if _, err = fmt.Sscanf(clock, "%d:%d", &h, &m); err != nil {
+		return
+	}
+	minutes = h*60 + m
+	return
+}
+
+// otaSubscribers the listeners of the update statuses of all bridges
+type otaSubscribers struct {
+	sync.Mutex
+	subscribers map[string]func(status OtaStatus)
+}
+
+func newOtaSubscribers() *otaSubscribers {
+	return &otaSubscribers{
+		subscribers: make(map[string]func(status OtaStatus)),
+	}
+}
+
+func (s *otaSubscribers) subscribe(name string, f func(status OtaStatus)) {
+	s.Lock()
+	s.subscribers[name] = f
+	s.Unlock()
+}
+
+func (s *otaSubscribers) unsubscribe(name string) {
+	s.Lock()
+	delete(s.subscribers, name)
+	s.Unlock()
+}
+
+func (s *otaSubscribers) publish(status OtaStatus) {
+	s.Lock()
+	handlers := make([]func(status OtaStatus), 0, len(s.subscribers))
+	for _, f := range s.subscribers {
+		handlers = append(handlers, f)
+	}
+	s.Unlock()
+
+	for _, f := range handlers {
+		f(status)
+	}
+}
+
+// OtaCheck ...
+func (g *Bridge) OtaCheck(friendlyName string) (err error) {
+
+	if _, err = g.safeGetDevice(friendlyName); err != nil {
+		return
+	}
+
+	g.otaLock.Lock()
+	status := g.unsafeOtaStatus(friendlyName)
+	if status.Status == OtaUpdating {
+		g.otaLock.Unlock()
+		err = ErrOtaInProgress
+		return
+	}
+	g.otaLock.Unlock()
+
+	g.otaSet(friendlyName, OtaChecking, "")
+
+	if err = g.getProtocol().OtaCheck(friendlyName); err != nil {
+		g.otaSet(friendlyName, OtaFailed, err.Error())
+	}
+
+	return
+}
+
+// OtaUpdate the update waits for the window and for the end of the update of the other device,
+// the bridge updates one device at a time
+func (g *Bridge) OtaUpdate(friendlyName string) (err error) {
+
+	if _, err = g.safeGetDevice(friendlyName); err != nil {
+		return
+	}
+
+	g.otaLock.Lock()
+	status := g.unsafeOtaStatus(friendlyName)
+	if status.Status == OtaUpdating || status.Status == OtaScheduled {
+		g.otaLock.Unlock()
+		return
+	}
+	g.otaLock.Unlock()
+
+	g.otaSet(friendlyName, OtaScheduled, "")
+	g.otaNext()
+
+	return
+}
+
+// OtaStatuses the statuses of the devices ordered by the device id
+func (g *Bridge) OtaStatuses() (list []OtaStatus) {
+	g.otaLock.Lock()
+	defer g.otaLock.Unlock()
+
+	list = make([]OtaStatus, 0, len(g.otaStatuses))
+	for _, status := range g.otaStatuses {
+		list = append(list, *status)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].DeviceId < list[j].DeviceId
+	})
+	return
+}
+
+// OtaAvailable the devices with the available update
+func (g *Bridge) OtaAvailable() (list []string) {
+	for _, status := range g.OtaStatuses() {
+		if status.Status == OtaAvailable {
+			list = append(list, status.DeviceId)
+		}
+	}
+	return
+}
+
+// OtaCheckAll the removed and banned devices are skipped
+func (g *Bridge) OtaCheckAll() {
+	g.devicesLock.Lock()
+	var list []string
+	for friendlyName, device := range g.devices {
+		if device.Status() == active {
+			list = append(list, friendlyName)
+		}
+	}
+	g.devicesLock.Unlock()
+
+	sort.Strings(list)
+	for _, friendlyName := range list {
+		if err := g.OtaCheck(friendlyName); err != nil {
+			log.Warnf("bridge id %v, device %v: %v", g.model.Id, friendlyName, err.Error())
+		}
+	}
+}
+
+func (g *Bridge) otaWindow() OtaWindow {
+	g.modelLock.Lock()
+	defer g.modelLock.Unlock()
+	return OtaWindow{Start: g.model.OtaWindowStart, End: g.model.OtaWindowEnd}
+}
+
+// otaNext starts the oldest scheduled update if the window is open and no device is updated
+func (g *Bridge) otaNext() {
+
+	if !g.otaWindow().Contains(time.Now()) {
+		return
+	}
+
+	g.otaLock.Lock()
+	var next *OtaStatus
+	for _, status := range g.otaStatuses {
+		if status.Status == OtaUpdating {
+			g.otaLock.Unlock()
+			return
+		}
+		if status.Status == OtaScheduled && (next == nil || status.UpdatedAt.Before(next.UpdatedAt)) {
+			next = status
+		}
+	}
+	if next == nil {
+		g.otaLock.Unlock()
+		return
+	}
+	friendlyName := next.DeviceId
+	g.unsafeOtaSet(next, OtaUpdating, "")
+	status := *next
+	g.otaLock.Unlock()
+
+	log.Infof("bridge id %v, the update of the device %v is started", g.model.Id, friendlyName)
+
+	g.ota.publish(status)
+
+	if err := g.getProtocol().OtaUpdate(friendlyName); err != nil {
+		g.otaSet(friendlyName, OtaFailed, err.Error())
+	}
+}
+
+// otaWorker the scheduled updates start in the window, the hung update is failed by the timeout
+func (g *Bridge) otaWorker(quit chan struct{}) {
+	ticker := time.NewTicker(otaInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+
+		var hung []string
+		g.otaLock.Lock()
+		for _, status := range g.otaStatuses {
+			if status.Status == OtaUpdating && time.Since(status.UpdatedAt) > otaTimeout {
+				hung = append(hung, status.DeviceId)
+			}
+		}
+		g.otaLock.Unlock()
+
+		for _, friendlyName := range hung {
+			g.otaSet(friendlyName, OtaFailed, "the update timeout")
+		}
+
+		g.otaNext()
+	}
+}
+
+// otaProgress ...
+func (g *Bridge) otaProgress(friendlyName string, progress float64, remaining int64) {
+	g.otaLock.Lock()
+	status := g.unsafeOtaStatus(friendlyName)
+	g.unsafeOtaSet(status, OtaUpdating, "")
+	status.Progress = progress
+	status.Remaining = remaining
+	result := *status
+	g.otaLock.Unlock()
+
+	g.ota.publish(result)
+}
+
+// otaSet the end of the update starts the next scheduled one
+func (g *Bridge) otaSet(friendlyName, state, errMsg string) {
+	g.otaLock.Lock()
+	status := g.unsafeOtaStatus(friendlyName)
+	g.unsafeOtaSet(status, state, errMsg)
+	result := *status
+	g.otaLock.Unlock()
+
+	g.ota.publish(result)
+
+	// it is called from the mqtt handlers, the next update is not started under their lock
+	switch state {
+	case OtaSucceeded, OtaFailed:
+		go g.otaNext()
+	}
+}
+
+func (g *Bridge) unsafeOtaSet(status *OtaStatus, state, errMsg string) {
+	now := time.Now()
+	switch state {
+	case OtaAvailable, OtaNotAvailable:
+		status.CheckedAt = &now
+	}
+	// the progress is kept while the device is updated
+	if state != OtaUpdating || status.Status != OtaUpdating {
+		status.Progress, status.Remaining = 0, 0
+	}
+	if state == OtaSucceeded {
+		status.Progress = 100
+	}
+	status.Status = state
+	status.Error = errMsg
+	status.UpdatedAt = now
+}
+
+func (g *Bridge) unsafeOtaStatus(friendlyName string) (status *OtaStatus) {
+	var ok bool
+	if status, ok = g.otaStatuses[friendlyName]; !ok {
+		status = &OtaStatus{
+			BridgeId:  g.model.Id,
+			DeviceId:  friendlyName,
+			Status:    OtaIdle,
+			UpdatedAt: time.Now(),
+		}
+		g.otaStatuses[friendlyName] = status
+	}
+	return
+}
+
+// otaDeviceAvailable the device reports the update, the scheduled and the running updates are kept
+func (g *Bridge) otaDeviceAvailable(friendlyName string) {
+	g.otaLock.Lock()
+	status := g.unsafeOtaStatus(friendlyName)
+	state := status.Status
+	g.otaLock.Unlock()
+
+	switch state {
+	case OtaAvailable, OtaScheduled, OtaUpdating:
+		return
+	}
+
+	g.otaSet(friendlyName, OtaAvailable, "")
+}
+
+// onOtaLog the legacy api reports the update in bridge/log
+func (g *Bridge) onOtaLog(message string, meta BridgeOtaMeta) {
+	if meta.Device == "" {
+		return
+	}
+
+	switch meta.Status {
+	case "checking_if_available":
+		g.otaSet(meta.Device, OtaChecking, "")
+	case "available":
+		g.otaSet(meta.Device, OtaAvailable, "")
+	case "not_available":
+		g.otaSet(meta.Device, OtaNotAvailable, "")
+	case "update_in_progress", "update_progress":
+		g.otaProgress(meta.Device, meta.Progress, meta.Remaining)
+	case "update_succeeded":
+		g.otaSet(meta.Device, OtaSucceeded, "")
+	case "check_failed", "update_failed":
+		g.otaSet(meta.Device, OtaFailed, message)
+	}
+}
+
+// onOtaResponse the request api reports the result in bridge/response/device/ota_update/*
+func (g *Bridge) onOtaResponse(path string, resp *BridgeResponse) {
+	data := BridgeOtaResponse{}
+	_ = json.Unmarshal(resp.Data, &data)
+	if data.Id == "" {
+		return
+	}
+
+	if resp.Status != "ok" {
+		g.otaSet(data.Id, OtaFailed, resp.Error)
+		return
+	}
+
+	switch path {
+	case "device/ota_update/check":
+		if data.UpdateAvailable {
+			g.otaSet(data.Id, OtaAvailable, "")
+		} else {
+			g.otaSet(data.Id, OtaNotAvailable, "")
+		}
+	case "device/ota_update/update":
+		g.otaSet(data.Id, OtaSucceeded, "")
+	}
+}
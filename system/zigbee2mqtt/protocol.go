@@ -0,0 +1,69 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProtocolLegacy the bridge/config/* topics of zigbee2mqtt < 1.17
+	ProtocolLegacy = "legacy"
+	// ProtocolRequest the bridge/request/* and bridge/response/* topics of zigbee2mqtt >= 1.17
+	ProtocolRequest = "request"
+)
+
+// the commands of the bridge, every command returns the error of the bridge if the protocol reports it.
+// the whitelist, the reset of the adapter and the config request have no requests, they use the legacy topics
+type protocol interface {
+	Name() string
+	PermitJoin(permitJoin bool) error
+	Remove(friendlyName string) error
+	Ban(friendlyName string) error
+	RenameLast(name string) error
+	DeviceOptions(friendlyName string, options map[string]interface{}) error
+	LastSeen(format string) error
+	Elapsed(elapsed bool) error
+	LogLevel(level string) error
+	AddGroup(friendlyName string, groupId int) error
+	RemoveGroup(friendlyName string) error
+	AddGroupDevice(groupName, deviceName string) error
+	RemoveGroupDevice(groupName, deviceName string) error
+	Networkmap() error
+	OtaCheck(friendlyName string) error
+	OtaUpdate(friendlyName string) error
+}
+
+// the request api is available since zigbee2mqtt 1.17.0
+func requestApiSupported(version string) bool {
+	var parts = strings.SplitN(strings.SplitN(version, "-", 2)[0], ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return major > 1 || (major == 1 && minor >= 17)
+}
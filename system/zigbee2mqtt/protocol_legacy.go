@@ -0,0 +1,130 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/system/mqtt"
+)
+
+// the bridge/config/* topics, the bridge does not answer to the commands
+type protocolLegacy struct {
+	mqttClient *mqtt.Client
+	topic      func(string) string
+}
+
+func newProtocolLegacy(mqttClient *mqtt.Client, topic func(string) string) *protocolLegacy {
+	return &protocolLegacy{
+		mqttClient: mqttClient,
+		topic:      topic,
+	}
+}
+
+// Name ...
+func (p *protocolLegacy) Name() string {
+	return ProtocolLegacy
+}
+
+// PermitJoin ...
+func (p *protocolLegacy) PermitJoin(permitJoin bool) error {
+	return p.publish("/bridge/config/permit_join", []byte(fmt.Sprintf("%t", permitJoin)))
+}
+
+// Remove ...
+func (p *protocolLegacy) Remove(friendlyName string) error {
+	return p.publish("/bridge/config/remove", []byte(friendlyName))
+}
+
+// Ban ...
+func (p *protocolLegacy) Ban(friendlyName string) error {
+	return p.publish("/bridge/config/force_remove", []byte(friendlyName))
+}
+
+// RenameLast ...
+func (p *protocolLegacy) RenameLast(name string) error {
+	return p.publish("/bridge/config/rename_last", []byte(name))
+}
+
+// DeviceOptions ...
+func (p *protocolLegacy) DeviceOptions(friendlyName string, options map[string]interface{}) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"friendly_name": friendlyName,
+		"options":       options,
+	})
+	return p.publish("/bridge/config/device_options", payload)
+}
+
+// LastSeen ...
+func (p *protocolLegacy) LastSeen(format string) error {
+	return p.publish("/bridge/config/last_seen", []byte(format))
+}
+
+// Elapsed ...
+func (p *protocolLegacy) Elapsed(elapsed bool) error {
+	return p.publish("/bridge/config/elapsed", []byte(fmt.Sprintf("%t", elapsed)))
+}
+
+// LogLevel ...
+func (p *protocolLegacy) LogLevel(level string) error {
+	return p.publish("/bridge/config/log_level", []byte(level))
+}
+
+// AddGroup ...
+func (p *protocolLegacy) AddGroup(friendlyName string, groupId int) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"friendly_name": friendlyName,
+		"id":            groupId,
+	})
+	return p.publish("/bridge/config/add_group", payload)
+}
+
+// RemoveGroup ...
+func (p *protocolLegacy) RemoveGroup(friendlyName string) error {
+	return p.publish("/bridge/config/remove_group", []byte(friendlyName))
+}
+
+// AddGroupDevice ...
+func (p *protocolLegacy) AddGroupDevice(groupName, deviceName string) error {
+	return p.publish(fmt.Sprintf("/bridge/group/%s/add", groupName), []byte(deviceName))
+}
+
+// RemoveGroupDevice ...
+func (p *protocolLegacy) RemoveGroupDevice(groupName, deviceName string) error {
+	return p.publish(fmt.Sprintf("/bridge/group/%s/remove", groupName), []byte(deviceName))
+}
+
+// Networkmap the map comes to bridge/networkmap/raw
+func (p *protocolLegacy) Networkmap() error {
+	return p.publish("/bridge/networkmap", []byte("raw"))
+}
+
+// OtaCheck the result comes to bridge/log with the "ota_update" type
+func (p *protocolLegacy) OtaCheck(friendlyName string) error {
+	return p.publish("/bridge/ota_update/check", []byte(friendlyName))
+}
+
+// OtaUpdate the progress and the result come to bridge/log with the "ota_update" type
+func (p *protocolLegacy) OtaUpdate(friendlyName string) error {
+	return p.publish("/bridge/ota_update/update", []byte(friendlyName))
+}
+
+func (p *protocolLegacy) publish(topic string, payload []byte) error {
+	return p.mqttClient.Publish(p.topic(topic), payload)
+}
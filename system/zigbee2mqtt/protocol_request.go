@@ -0,0 +1,208 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package zigbee2mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/e154/smart-home/system/mqtt"
+	"sync"
+	"time"
+)
+
+const (
+	requestTimeout = time.Second * 10
+)
+
+var (
+	// ErrRequestTimeout ...
+	ErrRequestTimeout = errors.New("zigbee2mqtt request timeout")
+)
+
+// the bridge/request/* topics, the bridge answers to bridge/response/* with the transaction id of the request
+type protocolRequest struct {
+	mqttClient   *mqtt.Client
+	topic        func(string) string
+	name         string
+	timeout      time.Duration
+	lock         sync.Mutex
+	counter      int64
+	transactions map[string]chan *BridgeResponse
+}
+
+func newProtocolRequest(mqttClient *mqtt.Client, topic func(string) string, name string) *protocolRequest {
+	return &protocolRequest{
+		mqttClient:   mqttClient,
+		topic:        topic,
+		name:         name,
+		timeout:      requestTimeout,
+		transactions: make(map[string]chan *BridgeResponse),
+	}
+}
+
+// Name ...
+func (p *protocolRequest) Name() string {
+	return ProtocolRequest
+}
+
+// PermitJoin ...
+func (p *protocolRequest) PermitJoin(permitJoin bool) error {
+	return p.request("permit_join", map[string]interface{}{"value": permitJoin})
+}
+
+// Remove ...
+func (p *protocolRequest) Remove(friendlyName string) error {
+	return p.request("device/remove", map[string]interface{}{"id": friendlyName})
+}
+
+// Ban the "ban" option was renamed to "block" in zigbee2mqtt 1.21, the bridge takes the known one
+func (p *protocolRequest) Ban(friendlyName string) error {
+	return p.request("device/remove", map[string]interface{}{
+		"id":    friendlyName,
+		"force": true,
+		"ban":   true,
+		"block": true,
+	})
+}
+
+// RenameLast ...
+func (p *protocolRequest) RenameLast(name string) error {
+	return p.request("device/rename", map[string]interface{}{"last": true, "to": name})
+}
+
+// DeviceOptions ...
+func (p *protocolRequest) DeviceOptions(friendlyName string, options map[string]interface{}) error {
+	return p.request("device/options", map[string]interface{}{"id": friendlyName, "options": options})
+}
+
+// LastSeen ...
+func (p *protocolRequest) LastSeen(format string) error {
+	return p.request("config/last_seen", map[string]interface{}{"value": format})
+}
+
+// Elapsed ...
+func (p *protocolRequest) Elapsed(elapsed bool) error {
+	return p.request("config/elapsed", map[string]interface{}{"value": elapsed})
+}
+
+// LogLevel ...
+func (p *protocolRequest) LogLevel(level string) error {
+	return p.request("config/log_level", map[string]interface{}{"value": level})
+}
+
+// AddGroup ...
+func (p *protocolRequest) AddGroup(friendlyName string, groupId int) error {
+	return p.request("group/add", map[string]interface{}{"friendly_name": friendlyName, "id": groupId})
+}
+
+// RemoveGroup ...
+func (p *protocolRequest) RemoveGroup(friendlyName string) error {
+	return p.request("group/remove", map[string]interface{}{"id": friendlyName})
+}
+
+// AddGroupDevice ...
+func (p *protocolRequest) AddGroupDevice(groupName, deviceName string) error {
+	return p.request("group/members/add", map[string]interface{}{"group": groupName, "device": deviceName})
+}
+
+// RemoveGroupDevice ...
+func (p *protocolRequest) RemoveGroupDevice(groupName, deviceName string) error {
+	return p.request("group/members/remove", map[string]interface{}{"group": groupName, "device": deviceName})
+}
+
+// Networkmap the scan takes minutes, so the request does not wait for the response,
+// the map is taken from bridge/response/networkmap
+func (p *protocolRequest) Networkmap() error {
+	return p.publish("networkmap", map[string]interface{}{
+		"type":   "raw",
+		"routes": false,
+	})
+}
+
+// OtaCheck the check of the sleeping device takes a while, the result is taken from bridge/response/device/ota_update/check
+func (p *protocolRequest) OtaCheck(friendlyName string) error {
+	return p.publish("device/ota_update/check", map[string]interface{}{"id": friendlyName})
+}
+
+// OtaUpdate the update takes up to an hour, the result is taken from bridge/response/device/ota_update/update
+func (p *protocolRequest) OtaUpdate(friendlyName string) error {
+	return p.publish("device/ota_update/update", map[string]interface{}{"id": friendlyName})
+}
+
+// OnResponse passes the response to the waiting request
+func (p *protocolRequest) OnResponse(resp *BridgeResponse) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if ch, ok := p.transactions[resp.Transaction]; ok {
+		ch <- resp
+		delete(p.transactions, resp.Transaction)
+	}
+}
+
+func (p *protocolRequest) nextTransaction() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.counter++
+	return fmt.Sprintf("%s-%d", p.name, p.counter)
+}
+
+// publish the request without the waiting for the response
+func (p *protocolRequest) publish(path string, params map[string]interface{}) error {
+	params["transaction"] = p.nextTransaction()
+	payload, _ := json.Marshal(params)
+	return p.mqttClient.Publish(p.topic("/bridge/request/"+path), payload)
+}
+
+func (p *protocolRequest) request(path string, params map[string]interface{}) (err error) {
+
+	transaction := p.nextTransaction()
+	params["transaction"] = transaction
+
+	ch := make(chan *BridgeResponse, 1)
+	p.lock.Lock()
+	p.transactions[transaction] = ch
+	p.lock.Unlock()
+
+	defer func() {
+		p.lock.Lock()
+		delete(p.transactions, transaction)
+		p.lock.Unlock()
+	}()
+
+	payload, _ := json.Marshal(params)
+	if err = p.mqttClient.Publish(p.topic("/bridge/request/"+path), payload); err != nil {
+		return
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Status != "ok" {
+			if resp.Error == "" {
+				resp.Error = fmt.Sprintf("%s: status %s", path, resp.Status)
+			}
+			err = errors.New(resp.Error)
+		}
+	case <-time.After(p.timeout):
+		err = ErrRequestTimeout
+	}
+
+	return
+}
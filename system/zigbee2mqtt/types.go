@@ -19,6 +19,7 @@
 package zigbee2mqtt
 
 import (
+	"encoding/json"
 	m "github.com/e154/smart-home/models"
 	"time"
 )
@@ -35,11 +36,12 @@ type BridgeLog struct {
 
 // BridgePairingMeta ...
 type BridgePairingMeta struct {
-	FriendlyName string `json:"friendly_name"`
-	Model        string `json:"model"`
-	Vendor       string `json:"vendor"`
-	Description  string `json:"description"`
-	Supported    bool   `json:"supported"`
+	FriendlyName string                 `json:"friendly_name"`
+	Model        string                 `json:"model"`
+	Vendor       string                 `json:"vendor"`
+	Description  string                 `json:"description"`
+	Supported    bool                   `json:"supported"`
+	Exposes      []*m.Zigbee2mqttExpose `json:"exposes"`
 }
 
 // BridgeConfigMeta ...
@@ -67,6 +69,137 @@ type BridgeConfig struct {
 	PermitJoin  string                  `json:"permit_join"`
 }
 
+// BridgeResponse the answer to bridge/request/*
+type BridgeResponse struct {
+	Data        json.RawMessage `json:"data"`
+	Status      string          `json:"status"`
+	Error       string          `json:"error"`
+	Transaction string          `json:"transaction"`
+}
+
+// BridgeNetworkmap the value is the text of the graphviz map or the raw map
+type BridgeNetworkmap struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// BridgeNetworkmapRaw ...
+type BridgeNetworkmapRaw struct {
+	Nodes []BridgeNetworkmapNode `json:"nodes"`
+	Links []BridgeNetworkmapLink `json:"links"`
+}
+
+// BridgeNetworkmapNode ...
+type BridgeNetworkmapNode struct {
+	IeeeAddr         string   `json:"ieeeAddr"`
+	FriendlyName     string   `json:"friendlyName"`
+	Type             string   `json:"type"`
+	NetworkAddress   int      `json:"networkAddress"`
+	ManufacturerName string   `json:"manufacturerName"`
+	ModelID          string   `json:"modelID"`
+	Failed           []string `json:"failed"`
+	LastSeen         *int64   `json:"lastSeen"` // ms
+}
+
+// BridgeNetworkmapAddr ...
+type BridgeNetworkmapAddr struct {
+	IeeeAddr       string `json:"ieeeAddr"`
+	NetworkAddress int    `json:"networkAddress"`
+}
+
+// BridgeNetworkmapLink the old versions have only the flat fields: sourceIeeeAddr, targetIeeeAddr, lqi
+type BridgeNetworkmapLink struct {
+	Source         *BridgeNetworkmapAddr `json:"source"`
+	Target         *BridgeNetworkmapAddr `json:"target"`
+	SourceIeeeAddr string                `json:"sourceIeeeAddr"`
+	TargetIeeeAddr string                `json:"targetIeeeAddr"`
+	Linkquality    *int                  `json:"linkquality"`
+	Lqi            int                   `json:"lqi"`
+	Depth          int                   `json:"depth"`
+	Relationship   int                   `json:"relationship"`
+}
+
+// BridgeInfoConfig ...
+type BridgeInfoConfig struct {
+	Advanced struct {
+		LogLevel string `json:"log_level"`
+	} `json:"advanced"`
+}
+
+// BridgeInfo ...
+type BridgeInfo struct {
+	Version     string                  `json:"version"`
+	Commit      string                  `json:"commit"`
+	Coordinator BridgeConfigCoordinator `json:"coordinator"`
+	PermitJoin  bool                    `json:"permit_join"`
+	Config      BridgeInfoConfig        `json:"config"`
+}
+
+// BridgeDeviceDefinition ...
+type BridgeDeviceDefinition struct {
+	Model       string                 `json:"model"`
+	Vendor      string                 `json:"vendor"`
+	Description string                 `json:"description"`
+	Exposes     []*m.Zigbee2mqttExpose `json:"exposes"`
+}
+
+// BridgeDevice the item of bridge/devices
+type BridgeDevice struct {
+	IeeeAddress  string                  `json:"ieee_address"`
+	FriendlyName string                  `json:"friendly_name"`
+	Type         string                  `json:"type"`
+	Supported    bool                    `json:"supported"`
+	Definition   *BridgeDeviceDefinition `json:"definition"`
+}
+
+// BridgeEventData ...
+type BridgeEventData struct {
+	FriendlyName string                  `json:"friendly_name"`
+	IeeeAddress  string                  `json:"ieee_address"`
+	Status       string                  `json:"status"`
+	Supported    bool                    `json:"supported"`
+	Definition   *BridgeDeviceDefinition `json:"definition"`
+}
+
+// BridgeEvent device_joined|device_announce|device_interview|device_leave
+type BridgeEvent struct {
+	Type string          `json:"type"`
+	Data BridgeEventData `json:"data"`
+}
+
+// BridgeOtaMeta the meta of the "ota_update" message of bridge/log
+type BridgeOtaMeta struct {
+	Status    string  `json:"status"`
+	Device    string  `json:"device"`
+	Progress  float64 `json:"progress"`
+	Remaining int64   `json:"remaining"`
+}
+
+// BridgeOtaResponse the data of bridge/response/device/ota_update/*
+type BridgeOtaResponse struct {
+	Id              string `json:"id"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+}
+
+// DeviceOtaUpdate ...
+type DeviceOtaUpdate struct {
+	State     string  `json:"state"` // idle|available|updating
+	Progress  float64 `json:"progress"`
+	Remaining int64   `json:"remaining"`
+}
+
+// DeviceOtaState the update of the firmware in the state of the device
+type DeviceOtaState struct {
+	Update          *DeviceOtaUpdate `json:"update"`
+	UpdateAvailable *bool            `json:"update_available"`
+}
+
+// BridgeLogging ...
+type BridgeLogging struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
 // AssistDeviceInfo ...
 type AssistDeviceInfo struct {
 	Name         string `json:"name"`
@@ -91,5 +224,7 @@ type Zigbee2mqttInfo struct {
 	LastScan      time.Time     `json:"last_scan"`
 	Networkmap    string        `json:"networkmap"`
 	Status        string        `json:"status"`
+	Version       string        `json:"version"`
+	Protocol      string        `json:"protocol"`
 	Model         m.Zigbee2mqtt `json:"model"`
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/e154/smart-home/system/graceful_service"
 	"github.com/e154/smart-home/system/metrics"
 	"github.com/e154/smart-home/system/mqtt"
+	"github.com/e154/smart-home/system/scripts"
 	"sync"
 )
 
@@ -42,21 +43,31 @@ type Zigbee2mqtt struct {
 	isStarted   bool
 	bridgesLock *sync.Mutex
 	bridges     map[int64]*Bridge
+	ota         *otaSubscribers
+	states      *stateSubscribers
 }
 
 // NewZigbee2mqtt ...
 func NewZigbee2mqtt(graceful *graceful_service.GracefulService,
 	mqtt *mqtt.Mqtt,
 	adaptors *adaptors.Adaptors,
-	metric *metrics.MetricManager) *Zigbee2mqtt {
-	return &Zigbee2mqtt{
+	scriptService *scripts.ScriptService,
+	metric *metrics.MetricManager) (zigbee2mqtt *Zigbee2mqtt) {
+	zigbee2mqtt = &Zigbee2mqtt{
 		graceful:    graceful,
 		mqtt:        mqtt,
 		adaptors:    adaptors,
 		bridgesLock: &sync.Mutex{},
 		bridges:     make(map[int64]*Bridge),
 		metric:      metric,
+		ota:         newOtaSubscribers(),
+		states:      newStateSubscribers(),
 	}
+
+	// javascript binding, the dry run of the script replaces it by the mock
+	scriptService.PushStruct("Zigbee2mqttGroup", NewGroupBind(zigbee2mqtt))
+
+	return
 }
 
 // Start ...
@@ -86,7 +97,7 @@ func (z *Zigbee2mqtt) Start() {
 	}
 
 	for _, model := range models {
-		bridge := NewBridge(z.mqtt, z.adaptors, model, z.metric)
+		bridge := NewBridge(z.mqtt, z.adaptors, model, z.metric, z.ota, z.states)
 		bridge.Start()
 
 		z.bridgesLock.Lock()
@@ -122,7 +133,7 @@ func (z *Zigbee2mqtt) AddBridge(model *m.Zigbee2mqtt) (err error) {
 	z.bridgesLock.Lock()
 	defer z.bridgesLock.Unlock()
 
-	bridge := NewBridge(z.mqtt, z.adaptors, model, z.metric)
+	bridge := NewBridge(z.mqtt, z.adaptors, model, z.metric, z.ota, z.states)
 	bridge.Start()
 	z.bridges[model.Id] = bridge
 	return
@@ -167,13 +178,9 @@ func (z *Zigbee2mqtt) ListBridges(limit, offset int64, order, sortBy string) (mo
 
 // UpdateBridge ...
 func (z *Zigbee2mqtt) UpdateBridge(model *m.Zigbee2mqtt) (result *m.Zigbee2mqtt, err error) {
-	z.bridgesLock.Lock()
-	defer z.bridgesLock.Unlock()
 
 	var bridge *Bridge
-	if bridge, err = z.unsafeGetBridge(model.Id); err == nil {
-
-	} else {
+	if bridge, err = z.safeGetBridge(model.Id); err != nil {
 		return
 	}
 
@@ -181,8 +188,14 @@ func (z *Zigbee2mqtt) UpdateBridge(model *m.Zigbee2mqtt) (result *m.Zigbee2mqtt,
 		return
 	}
 
-	result, err = z.adaptors.Zigbee2mqtt.GetById(model.Id)
-	bridge.UpdateModel(result)
+	if result, err = z.adaptors.Zigbee2mqtt.GetById(model.Id); err != nil {
+		return
+	}
+
+	// the settings are stored, the bridge gets them on the next start
+	if err := bridge.UpdateModel(result); err != nil {
+		log.Warn(err.Error())
+	}
 
 	return
 }
@@ -219,12 +232,10 @@ func (z *Zigbee2mqtt) ResetBridge(bridgeId int64) (err error) {
 
 // BridgeDeviceBan ...
 func (z *Zigbee2mqtt) BridgeDeviceBan(bridgeId int64, friendlyName string) (err error) {
-	z.bridgesLock.Lock()
-	defer z.bridgesLock.Unlock()
 
 	var bridge *Bridge
-	if bridge, err = z.unsafeGetBridge(bridgeId); err == nil {
-		bridge.Ban(friendlyName)
+	if bridge, err = z.safeGetBridge(bridgeId); err == nil {
+		err = bridge.Ban(friendlyName)
 	}
 	return
 }
@@ -265,6 +276,88 @@ func (z *Zigbee2mqtt) BridgeUpdateNetworkmap(bridgeId int64) (err error) {
 	return
 }
 
+// BridgeOtaCheck the empty device id checks all devices of the bridge
+func (z *Zigbee2mqtt) BridgeOtaCheck(bridgeId int64, friendlyName string) (err error) {
+
+	var bridge *Bridge
+	if bridge, err = z.safeGetBridge(bridgeId); err != nil {
+		return
+	}
+
+	if friendlyName != "" {
+		err = bridge.OtaCheck(friendlyName)
+		return
+	}
+
+	bridge.OtaCheckAll()
+
+	return
+}
+
+// BridgeOtaUpdate the empty device id schedules the update of all devices with the available update
+func (z *Zigbee2mqtt) BridgeOtaUpdate(bridgeId int64, friendlyName string) (err error) {
+
+	var bridge *Bridge
+	if bridge, err = z.safeGetBridge(bridgeId); err != nil {
+		return
+	}
+
+	if friendlyName != "" {
+		err = bridge.OtaUpdate(friendlyName)
+		return
+	}
+
+	for _, name := range bridge.OtaAvailable() {
+		if err = bridge.OtaUpdate(name); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// BridgeOtaStatus ...
+func (z *Zigbee2mqtt) BridgeOtaStatus(bridgeId int64) (list []OtaStatus, err error) {
+
+	var bridge *Bridge
+	if bridge, err = z.safeGetBridge(bridgeId); err == nil {
+		list = bridge.OtaStatuses()
+	}
+	return
+}
+
+// OtaStatus the statuses of all bridges
+func (z *Zigbee2mqtt) OtaStatus() (list []OtaStatus) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	list = make([]OtaStatus, 0)
+	for _, bridge := range z.bridges {
+		list = append(list, bridge.OtaStatuses()...)
+	}
+	return
+}
+
+// SubscribeOta the handler gets the changes of the update statuses
+func (z *Zigbee2mqtt) SubscribeOta(name string, f func(status OtaStatus)) {
+	z.ota.subscribe(name, f)
+}
+
+// UnsubscribeOta ...
+func (z *Zigbee2mqtt) UnsubscribeOta(name string) {
+	z.ota.unsubscribe(name)
+}
+
+// SubscribeDeviceState the handler gets the state payloads of the devices
+func (z *Zigbee2mqtt) SubscribeDeviceState(name string, f func(state DeviceState)) {
+	z.states.subscribe(name, f)
+}
+
+// UnsubscribeDeviceState ...
+func (z *Zigbee2mqtt) UnsubscribeDeviceState(name string) {
+	z.states.unsubscribe(name)
+}
+
 func (z *Zigbee2mqtt) unsafeGetBridge(bridgeId int64) (bridge *Bridge, err error) {
 	var ok bool
 	if bridge, ok = z.bridges[bridgeId]; !ok {
@@ -273,6 +366,16 @@ func (z *Zigbee2mqtt) unsafeGetBridge(bridgeId int64) (bridge *Bridge, err error
 	return
 }
 
+// safeGetBridge the lock is not held while the bridge answers the request
+func (z *Zigbee2mqtt) safeGetBridge(bridgeId int64) (bridge *Bridge, err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	bridge, err = z.unsafeGetBridge(bridgeId)
+
+	return
+}
+
 // GetTopicByDevice ...
 func (z *Zigbee2mqtt) GetTopicByDevice(model *m.Zigbee2mqttDevice) (topic string, err error) {
 
@@ -301,3 +404,232 @@ func (z *Zigbee2mqtt) DeviceRename(friendlyName, name string) (err error) {
 
 	return
 }
+
+// AddGroup ...
+func (z *Zigbee2mqtt) AddGroup(group *m.Zigbee2mqttGroup) (result *m.Zigbee2mqttGroup, err error) {
+
+	var bridge *Bridge
+	if bridge, err = z.safeGetBridge(group.Zigbee2mqttId); err != nil {
+		return
+	}
+
+	if group.GroupId == 0 {
+		if group.GroupId, err = z.adaptors.Zigbee2mqttGroup.NextGroupId(group.Zigbee2mqttId); err != nil {
+			return
+		}
+	}
+
+	// the group is stored only if the bridge has accepted it
+	if err = bridge.AddGroup(group.FriendlyName, group.GroupId); err != nil {
+		return
+	}
+
+	var id int64
+	if id, err = z.adaptors.Zigbee2mqttGroup.Add(group); err != nil {
+		return
+	}
+
+	result, err = z.adaptors.Zigbee2mqttGroup.GetById(id)
+
+	return
+}
+
+// GetGroup ...
+func (z *Zigbee2mqtt) GetGroup(bridgeId, groupId int64) (group *m.Zigbee2mqttGroup, err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	_, group, err = z.unsafeGetGroup(bridgeId, groupId)
+
+	return
+}
+
+// GetGroupByName the group of any bridge with the friendly name
+func (z *Zigbee2mqtt) GetGroupByName(friendlyName string) (group *m.Zigbee2mqttGroup, err error) {
+	group, err = z.adaptors.Zigbee2mqttGroup.GetByFriendlyName(friendlyName)
+	return
+}
+
+// DeleteGroup ...
+func (z *Zigbee2mqtt) DeleteGroup(bridgeId, groupId int64) (err error) {
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.safeGetGroup(bridgeId, groupId); err != nil {
+		return
+	}
+
+	if err = bridge.RemoveGroup(group.FriendlyName); err != nil {
+		return
+	}
+
+	err = z.adaptors.Zigbee2mqttGroup.Delete(group.Id)
+
+	return
+}
+
+// AddGroupDevice ...
+func (z *Zigbee2mqtt) AddGroupDevice(bridgeId, groupId int64, deviceId string) (err error) {
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.safeGetGroup(bridgeId, groupId); err != nil {
+		return
+	}
+
+	var device *m.Zigbee2mqttDevice
+	if device, err = z.adaptors.Zigbee2mqttDevice.GetById(deviceId); err != nil {
+		return
+	}
+
+	if device.Zigbee2mqttId != bridgeId {
+		err = adaptors.ErrRecordNotFound
+		return
+	}
+
+	if err = bridge.AddGroupDevice(group.FriendlyName, device.Id); err != nil {
+		return
+	}
+
+	for _, member := range group.Devices {
+		if member.Id == device.Id {
+			return
+		}
+	}
+
+	err = z.adaptors.Zigbee2mqttGroup.AddDevice(group.Id, device.Id)
+
+	return
+}
+
+// RemoveGroupDevice ...
+func (z *Zigbee2mqtt) RemoveGroupDevice(bridgeId, groupId int64, deviceId string) (err error) {
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.safeGetGroup(bridgeId, groupId); err != nil {
+		return
+	}
+
+	if err = bridge.RemoveGroupDevice(group.FriendlyName, deviceId); err != nil {
+		return
+	}
+
+	err = z.adaptors.Zigbee2mqttGroup.DeleteDevice(group.Id, deviceId)
+
+	return
+}
+
+// SetGroupState ...
+func (z *Zigbee2mqtt) SetGroupState(bridgeId, groupId int64, state map[string]interface{}) (err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.unsafeGetGroup(bridgeId, groupId); err != nil {
+		return
+	}
+
+	bridge.SetGroupState(group.FriendlyName, state)
+
+	return
+}
+
+// StoreScene the current state of the devices of the group is stored in the devices by the scene id
+func (z *Zigbee2mqtt) StoreScene(bridgeId int64, scene *m.Zigbee2mqttScene) (result *m.Zigbee2mqttScene, err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.unsafeGetGroup(bridgeId, scene.Zigbee2mqttGroupId); err != nil {
+		return
+	}
+
+	if result, err = z.adaptors.Zigbee2mqttScene.GetBySceneId(group.Id, scene.SceneId); err == nil {
+		result.Name = scene.Name
+		if err = z.adaptors.Zigbee2mqttScene.Update(result); err != nil {
+			return
+		}
+	} else {
+		if err.Error() != "record not found" {
+			return
+		}
+		if _, err = z.adaptors.Zigbee2mqttScene.Add(scene); err != nil {
+			return
+		}
+	}
+
+	bridge.StoreScene(group.FriendlyName, scene.SceneId)
+
+	result, err = z.adaptors.Zigbee2mqttScene.GetBySceneId(group.Id, scene.SceneId)
+
+	return
+}
+
+// RecallScene ...
+func (z *Zigbee2mqtt) RecallScene(bridgeId, groupId int64, sceneId int) (err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.unsafeGetGroup(bridgeId, groupId); err != nil {
+		return
+	}
+
+	bridge.RecallScene(group.FriendlyName, sceneId)
+
+	return
+}
+
+// DeleteScene ...
+func (z *Zigbee2mqtt) DeleteScene(bridgeId, groupId int64, sceneId int) (err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	var bridge *Bridge
+	var group *m.Zigbee2mqttGroup
+	if bridge, group, err = z.unsafeGetGroup(bridgeId, groupId); err != nil {
+		return
+	}
+
+	var scene *m.Zigbee2mqttScene
+	if scene, err = z.adaptors.Zigbee2mqttScene.GetBySceneId(group.Id, sceneId); err != nil {
+		return
+	}
+
+	if err = z.adaptors.Zigbee2mqttScene.Delete(scene.Id); err != nil {
+		return
+	}
+
+	bridge.RemoveScene(group.FriendlyName, sceneId)
+
+	return
+}
+
+func (z *Zigbee2mqtt) unsafeGetGroup(bridgeId, groupId int64) (bridge *Bridge, group *m.Zigbee2mqttGroup, err error) {
+	if bridge, err = z.unsafeGetBridge(bridgeId); err != nil {
+		return
+	}
+
+	if group, err = z.adaptors.Zigbee2mqttGroup.GetById(groupId); err != nil {
+		return
+	}
+
+	if group.Zigbee2mqttId != bridgeId {
+		err = adaptors.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (z *Zigbee2mqtt) safeGetGroup(bridgeId, groupId int64) (bridge *Bridge, group *m.Zigbee2mqttGroup, err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+
+	bridge, group, err = z.unsafeGetGroup(bridgeId, groupId)
+
+	return
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/e154/smart-home/system/logging"
 	"github.com/e154/smart-home/system/metrics"
 	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/modbus"
 	"github.com/e154/smart-home/system/mqtt"
 	"github.com/e154/smart-home/system/mqtt_authenticator"
 	"github.com/e154/smart-home/system/notify"
@@ -86,6 +87,7 @@ func BuildContainer() (container *dig.Container) {
 	container.Provide(metrics.NewMetricConfig)
 	container.Provide(zigbee2mqtt.NewZigbee2mqttConfig)
 	container.Provide(zigbee2mqtt.NewZigbee2mqtt)
+	container.Provide(modbus.NewModbus)
 	container.Provide(logging.NewLogger)
 	container.Provide(logging.NewLogDbSaver)
 	container.Provide(alexa.NewAlexa)
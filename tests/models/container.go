@@ -36,6 +36,7 @@ import (
 	"github.com/e154/smart-home/system/initial"
 	"github.com/e154/smart-home/system/logging"
 	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/modbus"
 	"github.com/e154/smart-home/system/mqtt"
 	"github.com/e154/smart-home/system/mqtt_authenticator"
 	"github.com/e154/smart-home/system/notify"
@@ -85,6 +86,7 @@ func BuildContainer() (container *dig.Container) {
 	container.Provide(notify.NewNotify)
 	container.Provide(zigbee2mqtt.NewZigbee2mqttConfig)
 	container.Provide(zigbee2mqtt.NewZigbee2mqtt)
+	container.Provide(modbus.NewModbus)
 	container.Provide(gate.NewGate)
 	container.Provide(logging.NewLogger)
 	container.Provide(logging.NewLogDbSaver)
@@ -45,6 +45,18 @@ var coffeeScripts = map[string]string{
 	"coffeeScript24": coffeeScript24,
 	"coffeeScript25": coffeeScript25,
 	"coffeeScript26": coffeeScript26,
+	"coffeeScript27": coffeeScript27,
+	"coffeeScript28": coffeeScript28,
+	"coffeeScript29": coffeeScript29,
+	"coffeeScript30": coffeeScript30,
+	"coffeeScript31": coffeeScript31,
+	"coffeeScript32": coffeeScript32,
+	"coffeeScript33": coffeeScript33,
+	"coffeeScript34": coffeeScript34,
+	"coffeeScript35": coffeeScript35,
+	"coffeeScript36": coffeeScript36,
+	"coffeeScript37": coffeeScript37,
+	"coffeeScript38": coffeeScript38,
 }
 
 // test1
@@ -361,5 +373,127 @@ store(bar2.Foo.Bar)
 store(bar2.Foo.Foo)
 `
 
+// test12
+// ------------------------------------------------
+const coffeeScript27 = `
+"use strict";
+
+i = 0
+while true
+    i++
+`
+
+const coffeeScript28 = `
+"use strict";
+
+deep = (n)->
+    deep(n + 1)
+
+deep(0)
+`
+
+const coffeeScript29 = `
+"use strict";
+
+try
+    r = ExecuteSync "data/scripts/ping.sh", "google.com"
+    store(r.Out)
+catch e
+    store('rejected')
+`
+
+const coffeeScript36 = `
+"use strict";
+
+list = []
+while true
+    list.push {value: 'item' + list.length}
+`
+
+const coffeeScript37 = `
+"use strict";
+
+sum = 0
+for i in [0...3000]
+    item = {value: i}
+    sum += item.value
+store sum
+`
+
+// test13
+// ------------------------------------------------
+const coffeeScript30 = `
+"use strict";
+
+module.exports =
+    pi: 3
+`
+
+const coffeeScript31 = `
+"use strict";
+
+base = require('test13_base')
+
+exports.area = (r)->
+    base.pi * r * r
+`
+
+const coffeeScript32 = `
+"use strict";
+
+geometry = require('test13_geometry')
+store(geometry.area(2))
+`
+
+// test14
+// ------------------------------------------------
+const coffeeScript33 = `
+"use strict";
+
+if temperature > 25
+    r = Device.RunCommand "fan", ["on"]
+    Map.GetElement("fan").SetState "on"
+    ExecuteSync "beep"
+    "on:" + r.out
+else
+    "off"
+`
+
+const coffeeScript38 = `
+"use strict";
+
+DeviceStates.Set 1, "on"
+Flow.SetVar "state", "on"
+Crypto.Base64Encode("on")
+`
+
+// test15
+// ------------------------------------------------
+const coffeeScript34 = `
+"use strict";
+
+r = Http.Post url + "/light", {on: true}, {token: "secret"}
+store r.Status + " " + r.Json().auth + " " + r.Json().body
+
+r = Http.Get "http://example.com/"
+store r.Err
+
+store Crypto.Base64Encode("admin:pass") + " " + Crypto.Hmac("sha256", "key", "data")
+store Time.Format(Time.Parse("DateTime", "2020-06-01 12:30:00", "UTC"), "RFC3339", "UTC")
+`
+
+// test16
+// ------------------------------------------------
+const coffeeScript35 = `
+"use strict";
+
+counter = 0
+inc = (n)->
+    counter += n
+inc(2)
+inc(3)
+counter
+`
+
 // test...
 // ------------------------------------------------
@@ -0,0 +1,145 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test12(t *testing.T) {
+
+	var state string
+	store = func(i interface{}) {
+		state = fmt.Sprintf("%v", i)
+	}
+
+	newEngine := func(scriptService *scripts.ScriptService, script *m.Script) *scripts.Engine {
+		engine, err := scriptService.NewEngine(script)
+		So(err, ShouldBeNil)
+		err = engine.Compile()
+		So(err, ShouldBeNil)
+		return engine
+	}
+
+	Convey("script sandbox", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService) {
+
+			storeRegisterCallback(scriptService)
+
+			// infinite loop
+			// ------------------------------------------------
+			engine := newEngine(scriptService, &m.Script{
+				Lang:    "coffeescript",
+				Name:    "test12_loop",
+				Source:  coffeeScripts["coffeeScript27"],
+				Timeout: 200,
+			})
+
+			t := time.Now()
+			_, err := engine.Do()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `script "test12_loop" aborted: execution timeout`)
+			So(time.Since(t), ShouldBeLessThan, time.Second*2)
+
+			// the engine is usable after the abort
+			result, err := engine.EvalString("1 + 1")
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "2")
+
+			// endless recursion
+			// ------------------------------------------------
+			engine = newEngine(scriptService, &m.Script{
+				Lang:     "coffeescript",
+				Name:     "test12_stack",
+				Source:   coffeeScripts["coffeeScript28"],
+				MaxStack: 100,
+			})
+
+			_, err = engine.Do()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `script "test12_stack" aborted: maximum call stack depth exceeded`)
+
+			// shell commands
+			// ------------------------------------------------
+			engine = newEngine(scriptService, &m.Script{
+				Lang:   "coffeescript",
+				Name:   "test12_exec",
+				Source: coffeeScripts["coffeeScript29"],
+			})
+
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, "rejected")
+
+			engine = newEngine(scriptService, &m.Script{
+				Lang:      "coffeescript",
+				Name:      "test12_exec",
+				Source:    coffeeScripts["coffeeScript29"],
+				AllowExec: true,
+			})
+
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, "ok")
+
+			// runaway allocation, the concurrent script is not affected
+			// ------------------------------------------------
+			runaway := newEngine(scriptService, &m.Script{
+				Lang:      "coffeescript",
+				Name:      "test12_memory",
+				Source:    coffeeScripts["coffeeScript36"],
+				MaxMemory: 1,
+			})
+			normal := newEngine(scriptService, &m.Script{
+				Lang:      "coffeescript",
+				Name:      "test12_normal",
+				Source:    coffeeScripts["coffeeScript37"],
+				MaxMemory: 1,
+			})
+
+			var runawayErr, normalErr error
+			wg := sync.WaitGroup{}
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, runawayErr = runaway.Do()
+			}()
+			go func() {
+				defer wg.Done()
+				_, normalErr = normal.Do()
+			}()
+			wg.Wait()
+
+			So(runawayErr, ShouldNotBeNil)
+			So(runawayErr.Error(), ShouldEqual, `script "test12_memory" aborted: memory limit exceeded`)
+			So(normalErr, ShouldBeNil)
+			So(state, ShouldEqual, "4498500")
+		})
+	})
+}
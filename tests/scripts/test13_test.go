@@ -0,0 +1,137 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func Test13(t *testing.T) {
+
+	var state string
+	store = func(i interface{}) {
+		state = fmt.Sprintf("%v", i)
+	}
+
+	addScript := func(scriptService *scripts.ScriptService, adaptors *adaptors.Adaptors, name, source string) *m.Script {
+		script := &m.Script{
+			Lang:   "coffeescript",
+			Name:   name,
+			Source: source,
+		}
+		engine, err := scriptService.NewEngine(script)
+		So(err, ShouldBeNil)
+		err = engine.Compile()
+		So(err, ShouldBeNil)
+		script.Id, err = adaptors.Script.Add(script)
+		So(err, ShouldBeNil)
+		return script
+	}
+
+	Convey("require stored scripts", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService) {
+
+			// clear database
+			// ------------------------------------------------
+			migrations.Purge()
+
+			storeRegisterCallback(scriptService)
+
+			// test13_main -> test13_geometry -> test13_base
+			// ------------------------------------------------
+			base := addScript(scriptService, adaptors, "test13_base", coffeeScripts["coffeeScript30"])
+			addScript(scriptService, adaptors, "test13_geometry", coffeeScripts["coffeeScript31"])
+
+			main := &m.Script{
+				Lang:   "coffeescript",
+				Name:   "test13_main",
+				Source: coffeeScripts["coffeeScript32"],
+			}
+			engine, err := scriptService.NewEngine(main)
+			So(err, ShouldBeNil)
+			err = engine.Compile()
+			So(err, ShouldBeNil)
+
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, "12")
+
+			So(scriptService.Modules().Dependents("test13_base"), ShouldResemble, []string{"test13_geometry", "test13_main"})
+
+			// update the library
+			// ------------------------------------------------
+			base.Source = "module.exports =\n    pi: 10\n"
+			engine2, err := scriptService.NewEngine(base)
+			So(err, ShouldBeNil)
+			err = engine2.Compile()
+			So(err, ShouldBeNil)
+			err = adaptors.Script.Update(base)
+			So(err, ShouldBeNil)
+
+			// the cached module until the update is announced
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, "12")
+
+			scriptService.Modules().Update(base.Name)
+
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, "40")
+
+			// the script with the shell commands takes only the modules with them
+			// ------------------------------------------------
+			execMain := &m.Script{
+				Lang:      "coffeescript",
+				Name:      "test13_exec_main",
+				Source:    coffeeScripts["coffeeScript32"],
+				AllowExec: true,
+			}
+			execEngine, err := scriptService.NewEngine(execMain)
+			So(err, ShouldBeNil)
+			err = execEngine.Compile()
+			So(err, ShouldBeNil)
+
+			_, err = execEngine.Do()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, scripts.ErrModuleExecNotAllowed.Error())
+
+			for _, name := range []string{"test13_base", "test13_geometry"} {
+				module, err := adaptors.Script.GetByName(name)
+				So(err, ShouldBeNil)
+				module.AllowExec = true
+				err = adaptors.Script.Update(module)
+				So(err, ShouldBeNil)
+				scriptService.Modules().Update(name)
+			}
+
+			_, err = execEngine.Do()
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, "40")
+		})
+	})
+}
@@ -0,0 +1,122 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func Test14(t *testing.T) {
+
+	Convey("dry run and test cases", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			c *core.Core,
+			scriptService *scripts.ScriptService) {
+
+			// clear database
+			// ------------------------------------------------
+			migrations.Purge()
+
+			on, off := "on:ok", "off"
+			script := &m.Script{
+				Lang:   "coffeescript",
+				Name:   "test14",
+				Source: coffeeScripts["coffeeScript33"],
+				TestCases: []*m.ScriptTestCase{
+					{
+						Name:    "hot",
+						Vars:    map[string]interface{}{"temperature": 30},
+						Returns: map[string]interface{}{"Device.RunCommand": map[string]interface{}{"out": "ok"}},
+						Calls: []*m.ScriptCall{
+							{Name: "Device.RunCommand", Args: []interface{}{"fan", []interface{}{"on"}}},
+							{Name: "Map.GetElement.SetState", Args: []interface{}{"on"}},
+							{Name: "ExecuteSync"},
+						},
+						Result: &on,
+					},
+					{
+						Name:   "cold",
+						Vars:   map[string]interface{}{"temperature": 10},
+						Result: &off,
+					},
+					{
+						Name:  "broken",
+						Vars:  map[string]interface{}{"temperature": 30},
+						Calls: []*m.ScriptCall{{Name: "Notifr.Send"}},
+					},
+				},
+			}
+			engine, err := scriptService.NewEngine(script)
+			So(err, ShouldBeNil)
+			err = engine.Compile()
+			So(err, ShouldBeNil)
+			script.Id, err = adaptors.Script.Add(script)
+			So(err, ShouldBeNil)
+
+			script, err = adaptors.Script.GetById(script.Id)
+			So(err, ShouldBeNil)
+			So(len(script.TestCases), ShouldEqual, 3)
+
+			// dry run
+			// ------------------------------------------------
+			run, err := scriptService.DryRun(script, map[string]interface{}{"temperature": 30}, nil)
+			So(err, ShouldBeNil)
+			So(run.Error, ShouldEqual, "")
+			So(run.Result, ShouldEqual, "on:undefined")
+			So(len(run.Calls), ShouldEqual, 4)
+			So(run.Calls[0].Name, ShouldEqual, "Device.RunCommand")
+			So(run.Calls[1].Name, ShouldEqual, "Map.GetElement")
+			So(run.Calls[1].Args, ShouldResemble, []interface{}{"fan"})
+			So(run.Calls[2].Name, ShouldEqual, "Map.GetElement.SetState")
+			So(run.Calls[3].Name, ShouldEqual, "ExecuteSync")
+
+			// test cases
+			// ------------------------------------------------
+			results, err := scriptService.RunTests(script)
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 3)
+			So(results[0].Passed, ShouldBeTrue)
+			So(results[1].Passed, ShouldBeTrue)
+			So(results[2].Passed, ShouldBeFalse)
+			So(results[2].Failures, ShouldResemble, []string{"expected call Notifr.Send(null) not found"})
+
+			// only the bindings without the side effects are not mocked
+			// ------------------------------------------------
+			run, err = scriptService.DryRun(&m.Script{
+				Lang:   "coffeescript",
+				Name:   "test14_mocks",
+				Source: coffeeScripts["coffeeScript38"],
+			}, nil, nil)
+			So(err, ShouldBeNil)
+			So(run.Error, ShouldEqual, "")
+			So(run.Result, ShouldEqual, "b24=")
+			So(len(run.Calls), ShouldEqual, 2)
+			So(run.Calls[0].Name, ShouldEqual, "DeviceStates.Set")
+			So(run.Calls[0].Args, ShouldResemble, []interface{}{int64(1), "on"})
+			So(run.Calls[1].Name, ShouldEqual, "Flow.SetVar")
+		})
+	})
+}
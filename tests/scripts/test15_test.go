@@ -0,0 +1,74 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"fmt"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/scripts"
+	"github.com/e154/smart-home/system/scripts/bind"
+	. "github.com/smartystreets/goconvey/convey"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test15(t *testing.T) {
+
+	states := make([]string, 0)
+	store = func(i interface{}) {
+		states = append(states, fmt.Sprintf("%v", i))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"auth": "%s", "body": %q}`, r.Header.Get("Authorization"), body)
+	}))
+	defer server.Close()
+
+	Convey("http, crypto and time bindings", t, func(ctx C) {
+		_ = container.Invoke(func(scriptService *scripts.ScriptService) {
+
+			storeRegisterCallback(scriptService)
+
+			script := &m.Script{
+				Lang:   "coffeescript",
+				Name:   "test15",
+				Source: coffeeScripts["coffeeScript34"],
+			}
+			engine, err := scriptService.NewEngine(script)
+			So(err, ShouldBeNil)
+			err = engine.Compile()
+			So(err, ShouldBeNil)
+
+			engine.PushStruct("url", server.URL)
+			engine.PushStruct("Http", bind.NewHttpBind([]string{"127.0.0.0/8"}, 0))
+
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+			So(states, ShouldResemble, []string{
+				`200 Bearer secret {"on":true}`,
+				"host not allowed: example.com",
+				"YWRtaW46cGFzcw== 5031fe3d989c6d1537a013fa6e739da23463fdaec3b70137d828e36ace221bd0",
+				"2020-06-01T12:30:00Z",
+			})
+		})
+	})
+}
@@ -0,0 +1,89 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func Test16(t *testing.T) {
+
+	Convey("debug session", t, func(ctx C) {
+		_ = container.Invoke(func(scriptService *scripts.ScriptService) {
+
+			script := &m.Script{
+				Lang:   "coffeescript",
+				Name:   "test16",
+				Source: coffeeScripts["coffeeScript35"],
+			}
+			engine, err := scriptService.NewEngine(script)
+			So(err, ShouldBeNil)
+			err = engine.Compile()
+			So(err, ShouldBeNil)
+
+			message := core.NewMessage()
+			message.SetVar("foo", "bar")
+			engine.PushStruct("message", message)
+
+			// breakpoint on "inc(2);", step into the function, continue to "inc(3);"
+			// ------------------------------------------------
+			states := make([]*scripts.DebugState, 0)
+			var session *scripts.DebugSession
+			session = scripts.NewDebugSession([]int{10}, func(state *scripts.DebugState) {
+				states = append(states, state)
+				go func() {
+					if len(states) == 1 {
+						session.SetBreakpoints([]int{12})
+						_ = session.Step()
+						return
+					}
+					_ = session.Continue()
+				}()
+			}, nil)
+
+			result, err := engine.Debug(session)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "5")
+
+			So(len(states), ShouldEqual, 3)
+			So(states[0].Line, ShouldEqual, 10)
+			So(states[0].Globals["counter"], ShouldEqual, 0)
+			So(states[0].Vars["foo"], ShouldEqual, "bar")
+			So(states[1].Line, ShouldEqual, 7)
+			So(states[2].Line, ShouldEqual, 12)
+			So(states[2].Globals["counter"], ShouldEqual, 2)
+			_, ok := states[2].Globals["inc"]
+			So(ok, ShouldBeFalse)
+
+			// stop
+			// ------------------------------------------------
+			session = scripts.NewDebugSession([]int{12}, func(state *scripts.DebugState) {
+				go session.Stop()
+			}, nil)
+
+			_, err = engine.Debug(session)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, scripts.ErrDebugStopped.Error())
+		})
+	})
+}
@@ -48,6 +48,7 @@ func Test1(t *testing.T) {
 				Name:        "test1",
 				Source:      coffeeScript1,
 				Description: "test1",
+				AllowExec:   true,
 			}
 
 			engine1, err := scriptService.NewEngine(script1)
@@ -46,6 +46,16 @@ var coffeeScripts = map[string]string{
 	"coffeeScript25": coffeeScript25,
 	"coffeeScript26": coffeeScript26,
 	"coffeeScript27": coffeeScript27,
+	"coffeeScript28": coffeeScript28,
+	"coffeeScript29": coffeeScript29,
+	"coffeeScript30": coffeeScript30,
+	"coffeeScript31": coffeeScript31,
+	"coffeeScript32": coffeeScript32,
+	"coffeeScript33": coffeeScript33,
+	"coffeeScript34": coffeeScript34,
+	"coffeeScript35": coffeeScript35,
+	"coffeeScript36": coffeeScript36,
+	"coffeeScript37": coffeeScript37,
 }
 
 // test1, test2
@@ -272,5 +282,102 @@ const coffeeScript27 = `
 #print "run workflow script (script 27)"
 `
 
+// test13
+// ------------------------------------------------
+const coffeeScript28 = `
+#print "run workflow script (script 28)"
+message.SetVar('val', 30)
+`
+
+const coffeeScript29 = `
+#print "run workflow script (script 29)"
+store('hot')
+`
+
+const coffeeScript30 = `
+#print "run workflow script (script 30)"
+store('cold')
+`
+
+const coffeeScript31 = `
+#print "run workflow script (script 31)"
+store('branch')
+`
+
+const coffeeScript32 = `
+#print "run workflow script (script 32)"
+store('join')
+`
+
+// test18
+// ------------------------------------------------
+const coffeeScript33 = `
+#print "run device action script (script 33)"
+store(Device.GetName())
+`
+
+// test20
+// ------------------------------------------------
+const coffeeScript34 = `
+#print "run modbus tcp device action script (script 34)"
+res = Device.ModBus 'WriteMultipleRegisters', 5, 2, [42, 43]
+if res.Error
+    store res.Error
+else
+    res = Device.ModBus 'ReadHoldingRegisters', 5, 2, []
+    if res.Error
+        store res.Error
+    else
+        store res.Result[0] + ',' + res.Result[1]
+`
+
+// test23
+// ------------------------------------------------
+const coffeeScript35 = `
+#print "run device command through the node (script 35)"
+res = Device.RunCommand 'on', []
+if res.Error
+    store res.Error
+else
+    store res.Result
+`
+
 // test8...
 // ------------------------------------------------
+
+// test25
+// ------------------------------------------------
+const coffeeScript36 = `
+#print "subscribe to the device states (script 36)"
+id = Events.on 'DeviceStateChanged', {to: ['on', 'off']}, (e)->
+    store e.device_id + ':' + e.from + ':' + e.to
+    if e.to == 'off'
+        Events.off id
+`
+
+// test26
+// ------------------------------------------------
+const coffeeScript37 = `
+#print "switch the zigbee2mqtt group (script 37)"
+Zigbee2mqttGroup.Set 'living_room', {state: 'ON', brightness: 128}
+Zigbee2mqttGroup.StoreScene 'living_room', 1, 'evening'
+Zigbee2mqttGroup.RecallScene 'living_room', 1
+try
+    Zigbee2mqttGroup.Set 'kitchen', {state: 'ON'}
+catch e
+    store 'kitchen not found'
+`
+
+// test31
+// ------------------------------------------------
+const coffeeScript38 = `
+#print "the library of the workflow (script 38)"
+module.exports =
+    version: 'v1'
+`
+
+const coffeeScript39 = `
+#print "the workflow script requires the library (script 39)"
+lib = require('test31_lib')
+store 'workflow:' + lib.version
+`
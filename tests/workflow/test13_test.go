@@ -0,0 +1,222 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// create workflow
+//
+// add workflow scenarios (wf_scenario_1 + script7)
+//
+// add flow (flow1)
+//                                 val > 25
+// +----------+    +-----------+        +----------+    +----------+     +----------+     +----------+
+// | handler  |    | exclusive |        |   hot    |    | parallel +----->  task1   +----->          |
+// | script28 +---->  gateway  +--------> script29 +---->   fork   |     | script31 |     | parallel |
+// |          |    |           |        |          |    |          +--+  +----------+     |   join   |
+// +----------+    +-----+-----+        +----------+    +----------+  |  +----------+     |          |
+//                       |  default                                   +-->  task2   +----->          |
+//                 +-----v----+                                          | script31 |     +-----+----+
+//                 |   cold   |                                          +----------+           |
+//                 | script30 |                                                          +-----v----+
+//                 +----------+                                                          |  emitter |
+//                                                                                       | script32 |
+//                                                                                       +----------+
+//
+// run core
+//
+func Test13(t *testing.T) {
+
+	var story = make([]string, 0)
+	var storyLock = sync.Mutex{}
+
+	store = func(i interface{}) {
+		storyLock.Lock()
+		story = append(story, fmt.Sprintf("%v", i))
+		storyLock.Unlock()
+	}
+
+	Convey("exclusive and parallel gateways", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// create scripts
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 7, 28, 29, 30, 31, 32)
+
+			// create workflow
+			// ------------------------------------------------
+			workflow := &m.Workflow{
+				Name:        "main workflow",
+				Description: "main workflow desc",
+				Status:      "enabled",
+			}
+
+			ok, _ := workflow.Valid()
+			So(ok, ShouldEqual, true)
+
+			workflow.Id, err = adaptors.Workflow.Add(workflow)
+			So(err, ShouldBeNil)
+
+			// add workflow scenario
+			// ------------------------------------------------
+			wfScenario1 := &m.WorkflowScenario{
+				Name:       "wf scenario 1",
+				SystemName: "wf_scenario_1",
+				WorkflowId: workflow.Id,
+			}
+
+			ok, _ = wfScenario1.Valid()
+			So(ok, ShouldEqual, true)
+
+			wfScenario1.Id, err = adaptors.WorkflowScenario.Add(wfScenario1)
+			So(err, ShouldBeNil)
+
+			err = adaptors.WorkflowScenario.AddScript(wfScenario1, scripts["script7"])
+			So(err, ShouldBeNil)
+
+			workflow.Scenario = wfScenario1
+			err = adaptors.Workflow.Update(workflow)
+			So(err, ShouldBeNil)
+
+			flow1 := &m.Flow{
+				Name:               "flow1",
+				Status:             Enabled,
+				WorkflowId:         workflow.Id,
+				WorkflowScenarioId: wfScenario1.Id,
+			}
+
+			ok, _ = flow1.Valid()
+			So(ok, ShouldEqual, true)
+
+			flow1.Id, err = adaptors.Flow.Add(flow1)
+			So(err, ShouldBeNil)
+
+			// flow elements
+			// ------------------------------------------------
+			addElement := func(name string, prototype FlowElementsPrototypeType, gateway FlowElementsGatewayType, script *m.Script) *m.FlowElement {
+				element := &m.FlowElement{
+					Name:          name,
+					FlowId:        flow1.Id,
+					Status:        Enabled,
+					PrototypeType: prototype,
+					GatewayType:   gateway,
+				}
+				if script != nil {
+					element.ScriptId = &script.Id
+				}
+				ok, _ := element.Valid()
+				So(ok, ShouldEqual, true)
+				element.Uuid, err = adaptors.FlowElement.Add(element)
+				So(err, ShouldBeNil)
+				return element
+			}
+
+			feHandler := addElement("handler", FlowElementsPrototypeMessageHandler, "", scripts["script28"])
+			feExclusive := addElement("exclusive", FlowElementsPrototypeGateway, FlowElementsGatewayExclusive, nil)
+			feHot := addElement("hot", FlowElementsPrototypeTask, "", scripts["script29"])
+			feCold := addElement("cold", FlowElementsPrototypeTask, "", scripts["script30"])
+			feFork := addElement("fork", FlowElementsPrototypeGateway, FlowElementsGatewayParallel, nil)
+			feTask1 := addElement("task1", FlowElementsPrototypeTask, "", scripts["script31"])
+			feTask2 := addElement("task2", FlowElementsPrototypeTask, "", scripts["script31"])
+			feJoin := addElement("join", FlowElementsPrototypeGateway, FlowElementsGatewayParallel, nil)
+			feEmitter := addElement("emitter", FlowElementsPrototypeMessageEmitter, "", scripts["script32"])
+
+			// connections
+			// ------------------------------------------------
+			connect := func(name string, from, to *m.FlowElement, condition string) {
+				conn := &m.Connection{
+					Name:        name,
+					ElementFrom: from.Uuid,
+					ElementTo:   to.Uuid,
+					FlowId:      flow1.Id,
+					PointFrom:   1,
+					PointTo:     1,
+					Condition:   condition,
+				}
+				ok, _ := conn.Valid()
+				So(ok, ShouldEqual, true)
+				conn.Uuid, err = adaptors.Connection.Add(conn)
+				So(err, ShouldBeNil)
+			}
+
+			connect("con1", feHandler, feExclusive, "")
+			connect("con2", feExclusive, feCold, "")
+			connect("con3", feExclusive, feHot, "val > 25")
+			connect("con4", feHot, feFork, "")
+			connect("con5", feFork, feTask1, "")
+			connect("con6", feFork, feTask2, "")
+			connect("con7", feTask1, feJoin, "")
+			connect("con8", feTask2, feJoin, "")
+			connect("con9", feJoin, feEmitter, "")
+
+			err = c.Run()
+			So(err, ShouldBeNil)
+
+			workflowCore, err := c.GetWorkflow(workflow.Id)
+			So(err, ShouldBeNil)
+
+			flowCore, err := workflowCore.GetFLow(flow1.Id)
+			So(err, ShouldBeNil)
+
+			message := core.NewMessage()
+
+			// create context
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(60*time.Second))
+			defer cancel()
+			ctx = context.WithValue(ctx, "msg", message)
+
+			err = flowCore.NewMessage(ctx)
+			So(err, ShouldBeNil)
+
+			So(story, ShouldResemble, []string{"hot", "branch", "branch", "join"})
+
+			err = c.Stop()
+			So(err, ShouldBeNil)
+		})
+	})
+}
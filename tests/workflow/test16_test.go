@@ -0,0 +1,244 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// create workflow
+//
+// add workflow scenarios (wf_scenario_1 + script7)
+//
+// add flow (flow1)
+// +----------+    +----------+    +----------+    +----------+
+// | handler  |    | throttle |    |  delay   |    |  emitter |
+// | script28 +---->   60s    +---->    1s    +----> script31 |
+// |          |    |          |    |          |    |          |
+// +----------+    +----------+    +----------+    +----------+
+//
+// send 2 messages, the first one is suspended by the delay element,
+// the second one is stopped by the throttle element
+//
+func Test16(t *testing.T) {
+
+	var story = make([]string, 0)
+	var storyLock = sync.Mutex{}
+
+	store = func(i interface{}) {
+		storyLock.Lock()
+		story = append(story, fmt.Sprintf("%v", i))
+		storyLock.Unlock()
+	}
+
+	Convey("flow timer elements", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// create scripts
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 7, 28, 31)
+
+			// create workflow
+			// ------------------------------------------------
+			workflow := &m.Workflow{
+				Name:        "main workflow",
+				Description: "main workflow desc",
+				Status:      "enabled",
+			}
+
+			workflow.Id, err = adaptors.Workflow.Add(workflow)
+			So(err, ShouldBeNil)
+
+			// add workflow scenario
+			// ------------------------------------------------
+			wfScenario1 := &m.WorkflowScenario{
+				Name:       "wf scenario 1",
+				SystemName: "wf_scenario_1",
+				WorkflowId: workflow.Id,
+			}
+
+			wfScenario1.Id, err = adaptors.WorkflowScenario.Add(wfScenario1)
+			So(err, ShouldBeNil)
+
+			err = adaptors.WorkflowScenario.AddScript(wfScenario1, scripts["script7"])
+			So(err, ShouldBeNil)
+
+			workflow.Scenario = wfScenario1
+			err = adaptors.Workflow.Update(workflow)
+			So(err, ShouldBeNil)
+
+			flow1 := &m.Flow{
+				Name:               "flow1",
+				Status:             Enabled,
+				WorkflowId:         workflow.Id,
+				WorkflowScenarioId: wfScenario1.Id,
+			}
+
+			ok, _ := flow1.Valid()
+			So(ok, ShouldEqual, true)
+
+			flow1.Id, err = adaptors.Flow.Add(flow1)
+			So(err, ShouldBeNil)
+
+			feHandler := &m.FlowElement{
+				Name:          "handler",
+				FlowId:        flow1.Id,
+				Status:        Enabled,
+				PrototypeType: FlowElementsPrototypeMessageHandler,
+				ScriptId:      &scripts["script28"].Id,
+			}
+
+			feThrottle := &m.FlowElement{
+				Name:          "throttle",
+				FlowId:        flow1.Id,
+				Status:        Enabled,
+				PrototypeType: FlowElementsPrototypeThrottle,
+				Settings: m.FlowElementSettings{
+					Interval: 60,
+				},
+			}
+
+			feDelay := &m.FlowElement{
+				Name:          "delay",
+				FlowId:        flow1.Id,
+				Status:        Enabled,
+				PrototypeType: FlowElementsPrototypeDelay,
+				Settings: m.FlowElementSettings{
+					Delay: 1,
+				},
+			}
+
+			feEmitter := &m.FlowElement{
+				Name:          "emitter",
+				FlowId:        flow1.Id,
+				Status:        Enabled,
+				PrototypeType: FlowElementsPrototypeMessageEmitter,
+				ScriptId:      &scripts["script31"].Id,
+			}
+
+			feHandler.Uuid, err = adaptors.FlowElement.Add(feHandler)
+			So(err, ShouldBeNil)
+
+			feThrottle.Uuid, err = adaptors.FlowElement.Add(feThrottle)
+			So(err, ShouldBeNil)
+
+			feDelay.Uuid, err = adaptors.FlowElement.Add(feDelay)
+			So(err, ShouldBeNil)
+
+			feEmitter.Uuid, err = adaptors.FlowElement.Add(feEmitter)
+			So(err, ShouldBeNil)
+
+			connects := []*m.Connection{
+				{
+					Name:        "con1",
+					ElementFrom: feHandler.Uuid,
+					ElementTo:   feThrottle.Uuid,
+					FlowId:      flow1.Id,
+					PointFrom:   1,
+					PointTo:     1,
+				},
+				{
+					Name:        "con2",
+					ElementFrom: feThrottle.Uuid,
+					ElementTo:   feDelay.Uuid,
+					FlowId:      flow1.Id,
+					PointFrom:   1,
+					PointTo:     1,
+				},
+				{
+					Name:        "con3",
+					ElementFrom: feDelay.Uuid,
+					ElementTo:   feEmitter.Uuid,
+					FlowId:      flow1.Id,
+					PointFrom:   1,
+					PointTo:     1,
+				},
+			}
+
+			for _, connect := range connects {
+				connect.Uuid, err = adaptors.Connection.Add(connect)
+				So(err, ShouldBeNil)
+			}
+
+			err = c.Run()
+			So(err, ShouldBeNil)
+
+			workflowCore, err := c.GetWorkflow(workflow.Id)
+			So(err, ShouldBeNil)
+
+			flowCore, err := workflowCore.GetFLow(flow1.Id)
+			So(err, ShouldBeNil)
+
+			// create context
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(60*time.Second))
+			defer cancel()
+
+			// the run is suspended, the sender does not wait for it
+			err = flowCore.NewMessage(ctx)
+			So(err, ShouldBeNil)
+			So(story, ShouldBeEmpty)
+
+			// throttled
+			err = flowCore.NewMessage(ctx)
+			So(err, ShouldBeNil)
+			So(story, ShouldBeEmpty)
+
+			time.Sleep(time.Second * 2)
+
+			So(story, ShouldResemble, []string{"branch"})
+
+			runs, total, err := adaptors.FlowRun.ListByFlowId(flow1.Id, 10, 0)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 2)
+			for _, run := range runs {
+				So(run.Status, ShouldEqual, FlowRunDone)
+			}
+
+			err = c.Stop()
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,152 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+//
+// add device with states: off -> on -> error -> off
+//
+// change the device state, forbidden transitions return an error,
+// the current state is restored after the core restart
+//
+func Test17(t *testing.T) {
+
+	Convey("device state machine", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Load()
+			So(err, ShouldBeNil)
+
+			// add node
+			// ------------------------------------------------
+			node := &m.Node{
+				Name:     "node",
+				Login:    "node",
+				Password: "node",
+				Status:   "enabled",
+			}
+			ok, _ := node.Valid()
+			So(ok, ShouldEqual, true)
+
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// add device
+			// ------------------------------------------------
+			device := &m.Device{
+				Name:       "device",
+				Status:     "enabled",
+				Type:       "default",
+				Node:       node,
+				Properties: []byte("{}"),
+			}
+
+			ok, _ = device.Valid()
+			So(ok, ShouldEqual, true)
+
+			device.Id, err = adaptors.Device.Add(device)
+			So(err, ShouldBeNil)
+
+			// add device states
+			// ------------------------------------------------
+			for _, state := range []*m.DeviceState{
+				{SystemName: "off", DeviceId: device.Id, Transitions: []string{"on"}},
+				{SystemName: "on", DeviceId: device.Id, Transitions: []string{"error"}},
+				{SystemName: "error", DeviceId: device.Id},
+			} {
+				_, err = adaptors.DeviceState.Add(state)
+				So(err, ShouldBeNil)
+			}
+
+			// subscribe
+			// ------------------------------------------------
+			changes := make([]core.DeviceStateChange, 0)
+			handlerId := c.DeviceStates.Subscribe(func(change core.DeviceStateChange) {
+				changes = append(changes, change)
+			})
+			defer c.DeviceStates.Unsubscribe(handlerId)
+
+			_, err = c.DeviceStates.Get(device.Id)
+			So(err, ShouldNotBeNil)
+
+			err = c.DeviceStates.Set(device.Id, "off")
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Set(device.Id, "error")
+			So(err, ShouldNotBeNil)
+
+			err = c.DeviceStates.Set(device.Id, "unknown")
+			So(err, ShouldNotBeNil)
+
+			err = c.DeviceStates.Set(device.Id, "on")
+			So(err, ShouldBeNil)
+
+			// same state, no event
+			err = c.DeviceStates.Set(device.Id, "on")
+			So(err, ShouldBeNil)
+
+			So(len(changes), ShouldEqual, 2)
+			So(changes[0].From, ShouldEqual, "")
+			So(changes[0].To, ShouldEqual, "off")
+			So(changes[1].From, ShouldEqual, "off")
+			So(changes[1].To, ShouldEqual, "on")
+
+			// persisted
+			// ------------------------------------------------
+			current, err := adaptors.DeviceCurrentState.GetByDeviceId(device.Id)
+			So(err, ShouldBeNil)
+			So(current.DeviceState.SystemName, ShouldEqual, "on")
+			So(current.DeviceState.Transitions, ShouldResemble, []string{"error"})
+
+			// restore
+			// ------------------------------------------------
+			states := core.NewDeviceStates(adaptors, nil, nil)
+			err = states.Load()
+			So(err, ShouldBeNil)
+
+			current, err = states.Get(device.Id)
+			So(err, ShouldBeNil)
+			So(current.DeviceState.SystemName, ShouldEqual, "on")
+			So(current.ChangedAt.IsZero(), ShouldBeFalse)
+
+			err = states.Set(device.Id, "off")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,188 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	. "github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"sort"
+	"sync"
+	"testing"
+)
+
+//
+// add group device with members: member1, member2, member3 (disabled)
+//
+// the group action runs on the enabled members (script33),
+// the group state is derived from the member states
+//
+func Test18(t *testing.T) {
+
+	var story = make([]string, 0)
+	var storyLock = sync.Mutex{}
+
+	store = func(i interface{}) {
+		storyLock.Lock()
+		story = append(story, fmt.Sprintf("%v", i))
+		storyLock.Unlock()
+	}
+
+	Convey("device groups", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Load()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// create scripts
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 33)
+
+			// add node
+			// ------------------------------------------------
+			node := &m.Node{
+				Name:     "node",
+				Login:    "node",
+				Password: "node",
+				Status:   "enabled",
+			}
+			ok, _ := node.Valid()
+			So(ok, ShouldEqual, true)
+
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// add group
+			// ------------------------------------------------
+			group := &m.Device{
+				Name:       "group",
+				Status:     "enabled",
+				Type:       "default",
+				Node:       node,
+				Properties: []byte("{}"),
+				IsGroup:    true,
+				GroupMode:  DeviceGroupParallel,
+			}
+
+			ok, _ = group.Valid()
+			So(ok, ShouldEqual, true)
+
+			group.Id, err = adaptors.Device.Add(group)
+			So(err, ShouldBeNil)
+
+			for _, state := range []*m.DeviceState{
+				{SystemName: "off", DeviceId: group.Id},
+				{SystemName: "on", DeviceId: group.Id},
+			} {
+				_, err = adaptors.DeviceState.Add(state)
+				So(err, ShouldBeNil)
+			}
+
+			deviceAction := &m.DeviceAction{
+				Name:     "groupAction",
+				DeviceId: group.Id,
+				ScriptId: scripts["script33"].Id,
+			}
+			deviceAction.Id, err = adaptors.DeviceAction.Add(deviceAction)
+			So(err, ShouldBeNil)
+
+			// add members
+			// ------------------------------------------------
+			members := make([]*m.Device, 0)
+			for i, status := range []string{"enabled", "enabled", "disabled"} {
+				member := &m.Device{
+					Name:       fmt.Sprintf("member%d", i+1),
+					Status:     status,
+					Type:       "default",
+					Device:     group,
+					Properties: []byte("{}"),
+				}
+				member.Id, err = adaptors.Device.Add(member)
+				So(err, ShouldBeNil)
+				members = append(members, member)
+			}
+
+			// group action
+			// ------------------------------------------------
+			res, err := c.DoAction(deviceAction.Id)
+			So(err, ShouldBeNil)
+
+			result := &core.GroupResult{}
+			err = json.Unmarshal([]byte(res), result)
+			So(err, ShouldBeNil)
+			So(result.Mode, ShouldEqual, DeviceGroupParallel)
+			So(len(result.Members), ShouldEqual, 2)
+			So(result.Failed, ShouldEqual, 0)
+
+			storyLock.Lock()
+			sort.Strings(story)
+			So(story, ShouldResemble, []string{"member1", "member2"})
+			storyLock.Unlock()
+
+			// group state
+			// ------------------------------------------------
+			err = c.DeviceStates.Set(members[0].Id, "on")
+			So(err, ShouldBeNil)
+
+			groupModel, err := adaptors.Device.GetById(group.Id)
+			So(err, ShouldBeNil)
+
+			groupState := c.DeviceStates.GroupState(groupModel)
+			So(groupState.Mixed, ShouldBeTrue)
+			So(groupState.Any("on"), ShouldBeTrue)
+			So(groupState.All("on"), ShouldBeFalse)
+
+			_, err = c.DeviceStates.Get(group.Id)
+			So(err, ShouldNotBeNil)
+
+			err = c.DeviceStates.Set(members[1].Id, "on")
+			So(err, ShouldBeNil)
+
+			groupState = c.DeviceStates.GroupState(groupModel)
+			So(groupState.Mixed, ShouldBeFalse)
+			So(groupState.State, ShouldEqual, "on")
+			So(groupState.All("on"), ShouldBeTrue)
+
+			current, err := c.DeviceStates.Get(group.Id)
+			So(err, ShouldBeNil)
+			So(current.DeviceState.SystemName, ShouldEqual, "on")
+		})
+	})
+}
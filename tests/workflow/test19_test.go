@@ -0,0 +1,158 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+//
+// persistent storage of the workflows and flows
+//
+// the values survive the new storage instance (restart of the server),
+// the namespaces are isolated, incr and compare-and-set are atomic,
+// the expired keys are not visible and removed from the database
+//
+func Test19(t *testing.T) {
+
+	Convey("persistent storage", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			storage := core.NewPersistentStorage(adaptors, true)
+
+			flow1 := core.NewNamespaceStorage(core.FlowStorageNamespace(1), storage)
+			flow2 := core.NewNamespaceStorage(core.FlowStorageNamespace(2), storage)
+
+			// set / get
+			// ------------------------------------------------
+			err = flow1.SetVar("last_seen", "kitchen")
+			So(err, ShouldBeNil)
+			err = flow1.SetVar("options", map[string]interface{}{"level": 1})
+			So(err, ShouldBeNil)
+			So(flow1.GetVar("last_seen"), ShouldEqual, "kitchen")
+			So(flow1.GetVar("options"), ShouldResemble, map[string]interface{}{"level": 1})
+			So(flow2.GetVar("last_seen"), ShouldBeNil)
+
+			// the value that can not be stored in the database is kept in memory
+			err = flow1.SetVar("callback", func() {})
+			So(err, ShouldNotBeNil)
+			So(flow1.GetVar("callback"), ShouldNotBeNil)
+
+			// incr
+			// ------------------------------------------------
+			value, err := flow1.IncrVar("counter", 1)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+
+			value, err = flow1.IncrVar("counter", 2)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 3)
+
+			_, err = flow1.IncrVar("last_seen", 1)
+			So(err, ShouldNotBeNil)
+
+			// compare and set
+			// ------------------------------------------------
+			swapped, err := flow1.CompareAndSetVar("last_seen", "hall", "bedroom")
+			So(err, ShouldBeNil)
+			So(swapped, ShouldBeFalse)
+
+			swapped, err = flow1.CompareAndSetVar("last_seen", "kitchen", "bedroom")
+			So(err, ShouldBeNil)
+			So(swapped, ShouldBeTrue)
+
+			swapped, err = flow2.CompareAndSetVar("lock", nil, true)
+			So(err, ShouldBeNil)
+			So(swapped, ShouldBeTrue)
+
+			swapped, err = flow2.CompareAndSetVar("lock", nil, true)
+			So(err, ShouldBeNil)
+			So(swapped, ShouldBeFalse)
+
+			// ttl
+			// ------------------------------------------------
+			err = flow2.SetVarTTL("motion", true, time.Millisecond*100)
+			So(err, ShouldBeNil)
+			So(flow2.GetVar("motion"), ShouldEqual, true)
+
+			// restart
+			// ------------------------------------------------
+			storage = core.NewPersistentStorage(adaptors, true)
+			flow1 = core.NewNamespaceStorage(core.FlowStorageNamespace(1), storage)
+			flow2 = core.NewNamespaceStorage(core.FlowStorageNamespace(2), storage)
+
+			So(flow1.GetVar("last_seen"), ShouldEqual, "bedroom")
+			So(flow1.GetVar("counter"), ShouldEqual, 3)
+			So(flow1.GetVar("options"), ShouldResemble, map[string]interface{}{"level": float64(1)})
+			So(flow1.GetVar("callback"), ShouldBeNil)
+			So(flow2.GetVar("lock"), ShouldEqual, true)
+			So(flow2.GetVar("motion"), ShouldEqual, true)
+
+			list, total, err := storage.List(10, 0, "asc", "key", core.FlowStorageNamespace(1))
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+			So(len(list), ShouldEqual, 3)
+			So(list[0].Key, ShouldEqual, "counter")
+
+			// expired keys
+			// ------------------------------------------------
+			time.Sleep(time.Millisecond * 200)
+
+			So(flow2.GetVar("motion"), ShouldBeNil)
+			_, err = adaptors.StorageItem.Get(core.FlowStorageNamespace(2), "motion")
+			So(err, ShouldNotBeNil)
+
+			err = flow2.SetVarTTL("motion", true, time.Millisecond*100)
+			So(err, ShouldBeNil)
+
+			time.Sleep(time.Millisecond * 200)
+
+			err = storage.DeleteExpired()
+			So(err, ShouldBeNil)
+			_, err = adaptors.StorageItem.Get(core.FlowStorageNamespace(2), "motion")
+			So(err, ShouldNotBeNil)
+
+			// delete
+			// ------------------------------------------------
+			err = flow1.DelVar("counter")
+			So(err, ShouldBeNil)
+			So(flow1.GetVar("counter"), ShouldBeNil)
+
+			storage = core.NewPersistentStorage(adaptors, true)
+			So(storage.Get(core.FlowStorageNamespace(1), "counter"), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,350 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	. "github.com/e154/smart-home/models/devices"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/modbus"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// modbus tcp device without the node, served by the in-process driver
+// against the local modbus tcp simulator
+//
+// the device action writes and reads the registers (script34),
+// the polling groups store the registers and map the coil to the device state
+//
+func Test20(t *testing.T) {
+
+	var story = make([]string, 0)
+	var storyLock = sync.Mutex{}
+
+	store = func(i interface{}) {
+		storyLock.Lock()
+		story = append(story, fmt.Sprintf("%v", i))
+		storyLock.Unlock()
+	}
+
+	Convey("modbus tcp", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Load()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// simulator
+			// ------------------------------------------------
+			server := modbus.NewServer()
+			err = server.Listen("127.0.0.1:0")
+			So(err, ShouldBeNil)
+			defer server.Close()
+
+			server.SetHoldingRegisters(1, 0, 10, 20)
+			server.SetHoldingRegisters(2, 0, 30)
+
+			// client
+			// ------------------------------------------------
+			client := modbus.NewClient(server.Addr(), 2)
+			defer client.Close()
+
+			var wg sync.WaitGroup
+			var errLock sync.Mutex
+			var errs []error
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(slaveId uint8) {
+					defer wg.Done()
+					_, err := client.Do(modbus.Request{
+						SlaveId:  slaveId,
+						Function: modbus.ReadHoldingRegisters,
+						Address:  0,
+						Count:    1,
+					})
+					if err != nil {
+						errLock.Lock()
+						errs = append(errs, err)
+						errLock.Unlock()
+					}
+				}(uint8(i%2 + 1))
+			}
+			wg.Wait()
+			So(errs, ShouldBeEmpty)
+
+			result, err := client.Do(modbus.Request{
+				SlaveId:  1,
+				Function: modbus.WriteMultipleCoils,
+				Address:  3,
+				Command:  []uint16{1, 0, 1},
+			})
+			So(err, ShouldBeNil)
+			So(server.Coils(1, 3, 3), ShouldResemble, []bool{true, false, true})
+
+			result, err = client.Do(modbus.Request{
+				SlaveId:  1,
+				Function: modbus.ReadCoils,
+				Address:  3,
+				Count:    3,
+			})
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, []uint16{1, 0, 1})
+
+			_, err = client.Do(modbus.Request{
+				SlaveId:  1,
+				Function: "ReadSomething",
+			})
+			So(err, ShouldNotBeNil)
+
+			// the slave does not answer
+			// ------------------------------------------------
+			silent, err := net.Listen("tcp", "127.0.0.1:0")
+			So(err, ShouldBeNil)
+			defer silent.Close()
+			go func() {
+				for {
+					conn, err := silent.Accept()
+					if err != nil {
+						return
+					}
+					defer conn.Close()
+				}
+			}()
+
+			silentClient := modbus.NewClient(silent.Addr().String(), 1)
+			defer silentClient.Close()
+
+			startTime := time.Now()
+			_, err = silentClient.Do(modbus.Request{
+				SlaveId:  1,
+				Function: modbus.ReadHoldingRegisters,
+				Count:    1,
+				Timeout:  time.Millisecond * 100,
+				Retries:  1,
+			})
+			So(err, ShouldNotBeNil)
+			So(time.Since(startTime), ShouldBeLessThan, time.Second)
+
+			// create scripts
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 34)
+
+			// add device
+			// ------------------------------------------------
+			device := &m.Device{
+				Name:       "modbus",
+				Status:     "enabled",
+				Properties: []byte("{}"),
+			}
+
+			ok, _ := device.SetProperties(&DevModBusTcpConfig{
+				SlaveId:     1,
+				AddressPort: server.Addr(),
+				Timeout:     500,
+				Polling: []*DevModBusPollingGroup{
+					{Name: "registers", Function: ReadHoldingRegisters, Address: 0, Count: 2, Interval: 100},
+					{Name: "power", Function: ReadCoils, Address: 0, Count: 1, Interval: 100,
+						States: map[string]string{"0": "off", "1": "on"}},
+				},
+			})
+			So(ok, ShouldEqual, true)
+
+			ok, _ = device.Valid()
+			So(ok, ShouldEqual, true)
+
+			device.Id, err = adaptors.Device.Add(device)
+			So(err, ShouldBeNil)
+
+			for _, state := range []*m.DeviceState{
+				{SystemName: "off", DeviceId: device.Id},
+				{SystemName: "on", DeviceId: device.Id},
+			} {
+				_, err = adaptors.DeviceState.Add(state)
+				So(err, ShouldBeNil)
+			}
+
+			deviceAction := &m.DeviceAction{
+				Name:     "modbusAction",
+				DeviceId: device.Id,
+				ScriptId: scripts["script34"].Id,
+			}
+			deviceAction.Id, err = adaptors.DeviceAction.Add(deviceAction)
+			So(err, ShouldBeNil)
+
+			// device action
+			// ------------------------------------------------
+			_, err = c.DoAction(deviceAction.Id)
+			So(err, ShouldBeNil)
+
+			storyLock.Lock()
+			So(story, ShouldResemble, []string{"42,43"})
+			storyLock.Unlock()
+
+			So(server.HoldingRegisters(1, 5, 2), ShouldResemble, []uint16{42, 43})
+
+			// group of the devices without the node, the members are served by the server itself
+			// ------------------------------------------------
+			addGroup := func(name string, node *m.Node, slaveIds ...uint8) *m.DeviceAction {
+				group := &m.Device{
+					Name:       name,
+					Status:     "enabled",
+					Node:       node,
+					Properties: []byte("{}"),
+					IsGroup:    true,
+				}
+				ok, _ := group.SetProperties(&DevModBusTcpConfig{
+					SlaveId:     1,
+					AddressPort: server.Addr(),
+				})
+				So(ok, ShouldEqual, true)
+
+				group.Id, err = adaptors.Device.Add(group)
+				So(err, ShouldBeNil)
+
+				for _, slaveId := range slaveIds {
+					member := &m.Device{
+						Name:       fmt.Sprintf("%s_%d", name, slaveId),
+						Status:     "enabled",
+						Device:     group,
+						Properties: []byte("{}"),
+					}
+					ok, _ = member.SetProperties(&DevModBusTcpConfig{
+						SlaveId:     int(slaveId),
+						AddressPort: server.Addr(),
+						Timeout:     500,
+					})
+					So(ok, ShouldEqual, true)
+
+					member.Id, err = adaptors.Device.Add(member)
+					So(err, ShouldBeNil)
+				}
+
+				groupAction := &m.DeviceAction{
+					Name:     name + "Action",
+					DeviceId: group.Id,
+					ScriptId: scripts["script34"].Id,
+				}
+				groupAction.Id, err = adaptors.DeviceAction.Add(groupAction)
+				So(err, ShouldBeNil)
+
+				return groupAction
+			}
+
+			groupAction := addGroup("modbus_group", nil, 2, 3)
+
+			_, err = c.DoAction(groupAction.Id)
+			So(err, ShouldBeNil)
+
+			storyLock.Lock()
+			So(story, ShouldResemble, []string{"42,43", "42,43", "42,43"})
+			storyLock.Unlock()
+
+			So(server.HoldingRegisters(2, 5, 2), ShouldResemble, []uint16{42, 43})
+			So(server.HoldingRegisters(3, 5, 2), ShouldResemble, []uint16{42, 43})
+
+			// the members of the group with the node are not served by the server,
+			// the node is not running
+			// ------------------------------------------------
+			node := &m.Node{
+				Name:     "node20",
+				Login:    "node20",
+				Password: "node20",
+				Status:   "enabled",
+			}
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			groupAction = addGroup("modbus_node_group", node, 4)
+
+			_, err = c.DoAction(groupAction.Id)
+			So(err, ShouldBeNil)
+
+			storyLock.Lock()
+			So(story, ShouldResemble, []string{"42,43", "42,43", "42,43", "node is nil"})
+			storyLock.Unlock()
+
+			So(server.HoldingRegisters(4, 5, 2), ShouldResemble, []uint16{0, 0})
+
+			// polling
+			// ------------------------------------------------
+			err = c.ModbusPolling.Load()
+			So(err, ShouldBeNil)
+
+			time.Sleep(time.Millisecond * 300)
+
+			namespace := core.DeviceStorageNamespace(device.Id)
+			So(c.Storage.Get(namespace, "registers"), ShouldResemble, []interface{}{float64(10), float64(20)})
+
+			state, err := c.DeviceStates.Get(device.Id)
+			So(err, ShouldBeNil)
+			So(state.DeviceState.SystemName, ShouldEqual, "off")
+
+			server.SetHoldingRegisters(1, 1, 21)
+			server.SetCoils(1, 0, true)
+
+			time.Sleep(time.Millisecond * 300)
+
+			So(c.Storage.Get(namespace, "registers"), ShouldResemble, []interface{}{float64(10), float64(21)})
+
+			state, err = c.DeviceStates.Get(device.Id)
+			So(err, ShouldBeNil)
+			So(state.DeviceState.SystemName, ShouldEqual, "on")
+
+			// removed device is not polled
+			// ------------------------------------------------
+			err = adaptors.Device.Delete(device.Id)
+			So(err, ShouldBeNil)
+
+			c.ModbusPolling.Reload(device.Id)
+
+			time.Sleep(time.Millisecond * 200)
+
+			server.SetHoldingRegisters(1, 1, 22)
+
+			time.Sleep(time.Millisecond * 300)
+
+			So(c.Storage.Get(namespace, "registers"), ShouldResemble, []interface{}{float64(10), float64(21)})
+
+			c.ModbusPolling.Stop()
+		})
+	})
+}
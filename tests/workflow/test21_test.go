@@ -0,0 +1,215 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/metrics"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt"
+	"github.com/e154/smart-home/system/mqtt_client"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// node command queue
+//
+// the commands wait in the queue while the node is disconnected,
+// the user commands go ahead of the polling,
+// the lost request is repeated, the stale commands expire
+//
+func Test21(t *testing.T) {
+
+	Convey("node command queue", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			mqttServer *mqtt.Mqtt,
+			metric *metrics.MetricManager,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			model := &m.Node{
+				Name:     "node21",
+				Login:    "node21",
+				Password: "node21",
+				Status:   "enabled",
+			}
+			model.Id, err = adaptors.Node.Add(model)
+			So(err, ShouldBeNil)
+
+			conf := &core.NodeQueueConfig{
+				Timeout: map[common.DeviceType]time.Duration{
+					"default": time.Millisecond * 300,
+				},
+				Retries: 2,
+				Backoff: time.Millisecond * 100,
+				Ttl:     time.Second * 3,
+			}
+
+			node := core.NewNode(model, mqttServer, metric, conf).Connect()
+			defer node.Remove()
+
+			device1 := &m.Device{Id: 1, Type: "command"}
+			device2 := &m.Device{Id: 2, Type: "command"}
+
+			// node simulator
+			// ------------------------------------------------
+			var requests []string
+			var dropped = make(map[int64]bool)
+			var simLock sync.Mutex
+
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "node21_simulator",
+				Username:       model.Login,
+				Password:       model.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			err = sim.Subscribe(fmt.Sprintf("home/node/%s/req/#", model.Name), 0, func(client MQTT.Client, msg MQTT.Message) {
+				req := &core.NodeMessage{}
+				if err := json.Unmarshal(msg.Payload(), req); err != nil {
+					return
+				}
+
+				simLock.Lock()
+				requests = append(requests, fmt.Sprintf("%d:%s", req.DeviceId, string(req.Command)))
+				// the first request of the device2 is lost
+				if req.DeviceId == device2.Id && !dropped[req.DeviceId] {
+					dropped[req.DeviceId] = true
+					simLock.Unlock()
+					return
+				}
+				simLock.Unlock()
+
+				resp, _ := json.Marshal(&core.NodeResponse{
+					DeviceId:   req.DeviceId,
+					DeviceType: req.DeviceType,
+					Response:   req.Command,
+					Status:     "success",
+				})
+				_ = sim.Publish(fmt.Sprintf("home/node/%s/resp/device%d", model.Name, req.DeviceId), resp)
+			})
+			So(err, ShouldBeNil)
+
+			pingQuit := make(chan struct{})
+			ping := func() {
+				ticker := time.NewTicker(time.Millisecond * 500)
+				defer ticker.Stop()
+				for {
+					_ = sim.Publish(fmt.Sprintf("home/node/%s/ping", model.Name), []byte(`{}`))
+					select {
+					case <-ticker.C:
+					case <-pingQuit:
+						return
+					}
+				}
+			}
+
+			// the node is disconnected, the commands wait in the queue
+			// ------------------------------------------------
+			time.Sleep(time.Millisecond * 3500)
+			So(node.IsConnected(), ShouldBeFalse)
+
+			type result struct {
+				resp core.NodeResponse
+				err  error
+			}
+			lowCh := make(chan result, 1)
+			highCh := make(chan result, 1)
+
+			go func() {
+				resp, err := node.Send(device1, core.NodeCommandPriorityLow, []byte(`"poll"`))
+				lowCh <- result{resp, err}
+			}()
+			time.Sleep(time.Millisecond * 100)
+			go func() {
+				resp, err := node.Send(device1, core.NodeCommandPriorityHigh, []byte(`"user"`))
+				highCh <- result{resp, err}
+			}()
+			time.Sleep(time.Millisecond * 100)
+
+			So(node.GetStat().QueueDepth, ShouldEqual, 2)
+
+			// the node is connected, the user command is delivered first
+			// ------------------------------------------------
+			go ping()
+
+			high := <-highCh
+			So(high.err, ShouldBeNil)
+			So(string(high.resp.Response), ShouldEqual, `"user"`)
+
+			low := <-lowCh
+			So(low.err, ShouldBeNil)
+			So(string(low.resp.Response), ShouldEqual, `"poll"`)
+
+			simLock.Lock()
+			So(requests, ShouldResemble, []string{`1:"user"`, `1:"poll"`})
+			simLock.Unlock()
+
+			// the lost request is repeated
+			// ------------------------------------------------
+			resp, err := node.Send(device2, core.NodeCommandPriorityHigh, []byte(`"retry"`))
+			So(err, ShouldBeNil)
+			So(string(resp.Response), ShouldEqual, `"retry"`)
+
+			simLock.Lock()
+			So(requests[2:], ShouldResemble, []string{`2:"retry"`, `2:"retry"`})
+			simLock.Unlock()
+
+			So(node.GetStat().QueueDepth, ShouldEqual, 0)
+
+			// the stale command expires
+			// ------------------------------------------------
+			close(pingQuit)
+			time.Sleep(time.Millisecond * 3500)
+			So(node.IsConnected(), ShouldBeFalse)
+
+			_, err = node.Send(device1, core.NodeCommandPriorityHigh, []byte(`"stale"`))
+			So(err, ShouldEqual, core.ErrNodeCommandExpired)
+
+			simLock.Lock()
+			So(len(requests), ShouldEqual, 4)
+			simLock.Unlock()
+
+			So(node.GetStat().QueueDepth, ShouldEqual, 0)
+		})
+	})
+}
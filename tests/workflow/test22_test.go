@@ -0,0 +1,206 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	"github.com/e154/smart-home/common"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/metrics"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt"
+	"github.com/e154/smart-home/system/mqtt_client"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+//
+// request ids in the node protocol
+//
+// the old node does not echo the request id, the commands for one device go one by one,
+// the node with the request ids gets the parallel commands and answers in any order
+//
+func Test22(t *testing.T) {
+
+	Convey("node request ids", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			mqttServer *mqtt.Mqtt,
+			metric *metrics.MetricManager,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			model := &m.Node{
+				Name:     "node22",
+				Login:    "node22",
+				Password: "node22",
+				Status:   "enabled",
+			}
+			model.Id, err = adaptors.Node.Add(model)
+			So(err, ShouldBeNil)
+
+			conf := &core.NodeQueueConfig{
+				Timeout: map[common.DeviceType]time.Duration{
+					"default": time.Second * 2,
+				},
+				Retries: 0,
+				Backoff: time.Millisecond * 100,
+				Ttl:     time.Second * 10,
+			}
+
+			node := core.NewNode(model, mqttServer, metric, conf).Connect()
+			defer node.Remove()
+
+			device := &m.Device{Id: 1, Type: "command"}
+
+			// node simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "node22_simulator",
+				Username:       model.Login,
+				Password:       model.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			requests := make(chan *core.NodeMessage, 10)
+			err = sim.Subscribe(fmt.Sprintf("home/node/%s/req/#", model.Name), 0, func(client MQTT.Client, msg MQTT.Message) {
+				req := &core.NodeMessage{}
+				if err := json.Unmarshal(msg.Payload(), req); err != nil {
+					return
+				}
+				requests <- req
+			})
+			So(err, ShouldBeNil)
+
+			respond := func(req *core.NodeMessage, echo bool) {
+				resp := &core.NodeResponse{
+					DeviceId:   req.DeviceId,
+					DeviceType: req.DeviceType,
+					Response:   req.Command,
+					Status:     "success",
+				}
+				if echo {
+					resp.RequestId = req.RequestId
+				}
+				b, _ := json.Marshal(resp)
+				_ = sim.Publish(fmt.Sprintf("home/node/%s/resp/device%d", model.Name, req.DeviceId), b)
+			}
+
+			pingQuit := make(chan struct{})
+			defer close(pingQuit)
+			go func() {
+				ticker := time.NewTicker(time.Millisecond * 500)
+				defer ticker.Stop()
+				for {
+					_ = sim.Publish(fmt.Sprintf("home/node/%s/ping", model.Name), []byte(`{}`))
+					select {
+					case <-ticker.C:
+					case <-pingQuit:
+						return
+					}
+				}
+			}()
+
+			for i := 0; i < 30 && !node.IsConnected(); i++ {
+				time.Sleep(time.Millisecond * 100)
+			}
+			So(node.IsConnected(), ShouldBeTrue)
+
+			type result struct {
+				command string
+				resp    core.NodeResponse
+				err     error
+			}
+			results := make(chan result, 2)
+			send := func(command string) {
+				resp, err := node.Send(device, core.NodeCommandPriorityHigh, []byte(command))
+				results <- result{command, resp, err}
+			}
+
+			// the old node, one command per device at a time
+			// ------------------------------------------------
+			go send(`"a"`)
+			go send(`"b"`)
+
+			req1 := <-requests
+			So(req1.RequestId, ShouldNotEqual, 0)
+
+			var inFlight bool
+			select {
+			case <-requests:
+				inFlight = true
+			case <-time.After(time.Millisecond * 300):
+			}
+			So(inFlight, ShouldBeFalse)
+
+			respond(req1, false)
+			req2 := <-requests
+			respond(req2, false)
+
+			for i := 0; i < 2; i++ {
+				res := <-results
+				So(res.err, ShouldBeNil)
+				So(string(res.resp.Response), ShouldEqual, res.command)
+			}
+
+			// the node echoes the request id
+			// ------------------------------------------------
+			go send(`"c"`)
+			respond(<-requests, true)
+			res := <-results
+			So(res.err, ShouldBeNil)
+			So(string(res.resp.Response), ShouldEqual, `"c"`)
+
+			// both commands are in flight, the answers go in the reverse order
+			go send(`"d"`)
+			go send(`"e"`)
+
+			req1 = <-requests
+			req2 = <-requests
+			So(req1.RequestId, ShouldNotEqual, req2.RequestId)
+
+			respond(req2, true)
+			respond(req1, true)
+
+			for i := 0; i < 2; i++ {
+				res := <-results
+				So(res.err, ShouldBeNil)
+				So(string(res.resp.Response), ShouldEqual, res.command)
+			}
+		})
+	})
+}
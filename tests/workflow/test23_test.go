@@ -0,0 +1,230 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/metrics"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt_client"
+	"github.com/e154/smart-home/system/scripts"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// node health
+//
+// the device command goes through the backup node
+// while the main node is disconnected,
+// the offline alert rule sends the message
+//
+func Test23(t *testing.T) {
+
+	var story = make([]string, 0)
+	var storyLock = sync.Mutex{}
+
+	store = func(i interface{}) {
+		storyLock.Lock()
+		story = append(story, fmt.Sprintf("%v", i))
+		storyLock.Unlock()
+	}
+
+	Convey("node health", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			cfg *config.AppConfig,
+			metric *metrics.MetricManager,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Load()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// create scripts
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 35)
+
+			// add nodes
+			// ------------------------------------------------
+			mainNode := &m.Node{
+				Name:     "node23",
+				Login:    "node23",
+				Password: "node23",
+				Status:   "enabled",
+			}
+			mainNode.Id, err = adaptors.Node.Add(mainNode)
+			So(err, ShouldBeNil)
+
+			backupNode := &m.Node{
+				Name:     "node23_backup",
+				Login:    "node23_backup",
+				Password: "node23_backup",
+				Status:   "enabled",
+			}
+			backupNode.Id, err = adaptors.Node.Add(backupNode)
+			So(err, ShouldBeNil)
+
+			for _, node := range []*m.Node{mainNode, backupNode} {
+				_, err = c.AddNode(node)
+				So(err, ShouldBeNil)
+			}
+			defer func() {
+				_ = c.RemoveNode(mainNode)
+				_ = c.RemoveNode(backupNode)
+			}()
+
+			// add device
+			// ------------------------------------------------
+			device := &m.Device{
+				Name:       "device23",
+				Status:     "enabled",
+				Type:       "command",
+				Node:       mainNode,
+				BackupNode: backupNode,
+				Properties: []byte("{}"),
+			}
+			device.Id, err = adaptors.Device.Add(device)
+			So(err, ShouldBeNil)
+
+			deviceAction := &m.DeviceAction{
+				Name:     "command",
+				DeviceId: device.Id,
+				ScriptId: scripts["script35"].Id,
+			}
+			deviceAction.Id, err = adaptors.DeviceAction.Add(deviceAction)
+			So(err, ShouldBeNil)
+
+			// backup node simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "node23_simulator",
+				Username:       backupNode.Login,
+				Password:       backupNode.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			err = sim.Subscribe(fmt.Sprintf("home/node/%s/req/#", backupNode.Name), 0, func(client MQTT.Client, msg MQTT.Message) {
+				req := &core.NodeMessage{}
+				if err := json.Unmarshal(msg.Payload(), req); err != nil {
+					return
+				}
+				resp, _ := json.Marshal(&core.NodeResponse{
+					RequestId:  req.RequestId,
+					DeviceId:   req.DeviceId,
+					DeviceType: req.DeviceType,
+					Response:   []byte(`{"result":"backup"}`),
+					Status:     "success",
+				})
+				_ = sim.Publish(fmt.Sprintf("home/node/%s/resp/device%d", backupNode.Name, req.DeviceId), resp)
+			})
+			So(err, ShouldBeNil)
+
+			pingQuit := make(chan struct{})
+			defer close(pingQuit)
+			go func() {
+				ticker := time.NewTicker(time.Millisecond * 500)
+				defer ticker.Stop()
+				for {
+					_ = sim.Publish(fmt.Sprintf("home/node/%s/ping", backupNode.Name), []byte(`{}`))
+					select {
+					case <-ticker.C:
+					case <-pingQuit:
+						return
+					}
+				}
+			}()
+
+			time.Sleep(time.Millisecond * 3500)
+			So(c.GetNodeById(mainNode.Id).IsConnected(), ShouldBeFalse)
+			So(c.GetNodeById(backupNode.Id).IsConnected(), ShouldBeTrue)
+
+			// the command goes through the backup node
+			// ------------------------------------------------
+			_, err = c.DoAction(deviceAction.Id)
+			So(err, ShouldBeNil)
+
+			storyLock.Lock()
+			So(story, ShouldResemble, []string{"backup"})
+			storyLock.Unlock()
+
+			// the offline alert
+			// ------------------------------------------------
+			rule := &m.NodeAlertRule{
+				Name:      "main node offline",
+				NodeId:    &mainNode.Id,
+				Kind:      m.NodeAlertOffline,
+				Threshold: 1,
+				Channel:   m.NodeAlertEmail,
+				Address:   "admin@example.com",
+				Enabled:   true,
+			}
+			ok, _ := rule.Valid()
+			So(ok, ShouldBeTrue)
+			rule.Id, err = adaptors.NodeAlertRule.Add(rule)
+			So(err, ShouldBeNil)
+
+			err = c.NodeMonitor.Load()
+			So(err, ShouldBeNil)
+			defer c.NodeMonitor.Stop()
+
+			time.Sleep(time.Millisecond * 2500)
+
+			list, _, err := adaptors.MessageDelivery.List(10, 0, "desc", "id")
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 1)
+			So(list[0].Address, ShouldEqual, rule.Address)
+
+			// the latency of the backup node
+			// ------------------------------------------------
+			latency, err := metric.Node.GetLatency(backupNode.Id)
+			So(err, ShouldBeNil)
+			So(latency.Count, ShouldEqual, 1)
+			So(latency.Errors, ShouldEqual, 0)
+		})
+	})
+}
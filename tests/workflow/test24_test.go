@@ -0,0 +1,226 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+//
+// workflow scenario rules
+//
+// the script switches the scenario to "away",
+// the presence rule returns "home" after the hold time,
+// the device state rule with the higher priority switches to "away",
+// every switch is recorded to the scenario history
+//
+func Test24(t *testing.T) {
+
+	Convey("workflow scenario rules", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Load()
+			So(err, ShouldBeNil)
+
+			// presence device
+			// ------------------------------------------------
+			device := &m.Device{
+				Name:       "phone",
+				Status:     "enabled",
+				Type:       "default",
+				Properties: []byte("{}"),
+			}
+			device.Id, err = adaptors.Device.Add(device)
+			So(err, ShouldBeNil)
+
+			for _, state := range []*m.DeviceState{
+				{SystemName: "home", DeviceId: device.Id},
+				{SystemName: "away", DeviceId: device.Id},
+			} {
+				_, err = adaptors.DeviceState.Add(state)
+				So(err, ShouldBeNil)
+			}
+
+			err = c.DeviceStates.Set(device.Id, "home")
+			So(err, ShouldBeNil)
+
+			// workflow
+			// ------------------------------------------------
+			workflow := &m.Workflow{
+				Name:        "main workflow",
+				Description: "main workflow desc",
+				Status:      "enabled",
+			}
+			workflow.Id, err = adaptors.Workflow.Add(workflow)
+			So(err, ShouldBeNil)
+
+			home := &m.WorkflowScenario{
+				Name:       "home",
+				SystemName: "home",
+				WorkflowId: workflow.Id,
+			}
+			home.Id, err = adaptors.WorkflowScenario.Add(home)
+			So(err, ShouldBeNil)
+
+			away := &m.WorkflowScenario{
+				Name:       "away",
+				SystemName: "away",
+				WorkflowId: workflow.Id,
+			}
+			away.Id, err = adaptors.WorkflowScenario.Add(away)
+			So(err, ShouldBeNil)
+
+			workflow.Scenario = home
+			err = adaptors.Workflow.Update(workflow)
+			So(err, ShouldBeNil)
+
+			err = c.Run()
+			So(err, ShouldBeNil)
+			defer c.Stop()
+
+			scenario := func() string {
+				model, err := adaptors.Workflow.GetById(workflow.Id)
+				if err != nil || model.Scenario == nil {
+					return ""
+				}
+				return model.Scenario.SystemName
+			}
+
+			waitScenario := func(systemName string, timeout time.Duration) string {
+				deadline := time.Now().Add(timeout)
+				for time.Now().Before(deadline) {
+					if scenario() == systemName {
+						break
+					}
+					time.Sleep(time.Millisecond * 100)
+				}
+				return scenario()
+			}
+
+			// the script switches the scenario
+			// ------------------------------------------------
+			workflowCore, err := c.GetWorkflow(workflow.Id)
+			So(err, ShouldBeNil)
+
+			err = workflowCore.SetScenario("away")
+			So(err, ShouldBeNil)
+			So(scenario(), ShouldEqual, "away")
+
+			// rules
+			// ------------------------------------------------
+			homeRule := &m.WorkflowScenarioRule{
+				Name:               "somebody is home",
+				WorkflowId:         workflow.Id,
+				WorkflowScenarioId: home.Id,
+				Priority:           1,
+				Conditions: []*m.ScenarioCondition{
+					{Kind: m.ScenarioConditionPresence, DeviceIds: []int64{device.Id}, States: []string{"home"}},
+				},
+				Delay:   1,
+				Hold:    5,
+				Enabled: true,
+			}
+			ok, _ := homeRule.Valid()
+			So(ok, ShouldBeTrue)
+			homeRule.Id, err = adaptors.WorkflowScenarioRule.Add(homeRule)
+			So(err, ShouldBeNil)
+
+			awayRule := &m.WorkflowScenarioRule{
+				Name:               "everybody left",
+				WorkflowId:         workflow.Id,
+				WorkflowScenarioId: away.Id,
+				Priority:           2,
+				Conditions: []*m.ScenarioCondition{
+					{Kind: m.ScenarioConditionDeviceState, DeviceIds: []int64{device.Id}, States: []string{"away"}},
+					{Kind: m.ScenarioConditionTime, From: "00:00", To: "00:00"},
+				},
+				Delay:   1,
+				Enabled: true,
+			}
+			ok, _ = awayRule.Valid()
+			So(ok, ShouldBeTrue)
+			awayRule.Id, err = adaptors.WorkflowScenarioRule.Add(awayRule)
+			So(err, ShouldBeNil)
+
+			badRule := &m.WorkflowScenarioRule{
+				Name:               "bad",
+				WorkflowId:         workflow.Id,
+				WorkflowScenarioId: away.Id,
+				Conditions: []*m.ScenarioCondition{
+					{Kind: m.ScenarioConditionTime, From: "23:00", To: "7"},
+				},
+			}
+			ok, _ = badRule.Valid()
+			So(ok, ShouldBeFalse)
+
+			err = c.ScenarioRules.Reload()
+			So(err, ShouldBeNil)
+
+			// the hold time after the script switch is not passed
+			time.Sleep(time.Millisecond * 1500)
+			So(scenario(), ShouldEqual, "away")
+
+			So(waitScenario("home", time.Second*8), ShouldEqual, "home")
+
+			// the device state rule has the higher priority
+			// ------------------------------------------------
+			err = c.DeviceStates.Set(device.Id, "away")
+			So(err, ShouldBeNil)
+
+			So(waitScenario("away", time.Second*5), ShouldEqual, "away")
+
+			// history
+			// ------------------------------------------------
+			list, total, err := adaptors.WorkflowScenarioHistory.ListByWorkflow(workflow.Id, 10, 0, "asc", "id")
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 3)
+
+			So(list[0].Trigger, ShouldEqual, m.ScenarioTriggerScript)
+			So(list[0].FromScenario, ShouldEqual, "home")
+			So(list[0].ToScenario, ShouldEqual, "away")
+
+			So(list[1].Trigger, ShouldEqual, m.ScenarioTriggerRule)
+			So(list[1].Initiator, ShouldEqual, homeRule.Name)
+			So(list[1].ToScenario, ShouldEqual, "home")
+			So(list[1].CreatedAt.Sub(list[0].CreatedAt), ShouldBeGreaterThanOrEqualTo, time.Second*5)
+
+			So(list[2].Trigger, ShouldEqual, m.ScenarioTriggerRule)
+			So(list[2].Initiator, ShouldEqual, awayRule.Name)
+			So(*list[2].ToScenarioId, ShouldEqual, away.Id)
+		})
+	})
+}
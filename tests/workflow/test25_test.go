@@ -0,0 +1,208 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// event bus
+//
+// the workflow script subscribes to the device states by Events.on,
+// the handler unsubscribes itself on the state "off",
+// the go subscriber takes the scenario change by the filter
+//
+func Test25(t *testing.T) {
+
+	var lock sync.Mutex
+	var states []string
+	store = func(i interface{}) {
+		lock.Lock()
+		states = append(states, fmt.Sprintf("%v", i))
+		lock.Unlock()
+	}
+
+	stored := func(n int) []string {
+		deadline := time.Now().Add(time.Second * 3)
+		for time.Now().Before(deadline) {
+			lock.Lock()
+			l := len(states)
+			lock.Unlock()
+			if l >= n {
+				break
+			}
+			time.Sleep(time.Millisecond * 50)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, states...)
+	}
+
+	Convey("event bus", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Load()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// device
+			// ------------------------------------------------
+			device := &m.Device{
+				Name:       "lamp",
+				Status:     "enabled",
+				Type:       "default",
+				Properties: []byte("{}"),
+			}
+			device.Id, err = adaptors.Device.Add(device)
+			So(err, ShouldBeNil)
+
+			for _, state := range []*m.DeviceState{
+				{SystemName: "on", DeviceId: device.Id},
+				{SystemName: "off", DeviceId: device.Id},
+				{SystemName: "error", DeviceId: device.Id},
+			} {
+				_, err = adaptors.DeviceState.Add(state)
+				So(err, ShouldBeNil)
+			}
+
+			// workflow
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 36)
+
+			workflow := &m.Workflow{
+				Name:        "main workflow",
+				Description: "main workflow desc",
+				Status:      "enabled",
+			}
+			workflow.Id, err = adaptors.Workflow.Add(workflow)
+			So(err, ShouldBeNil)
+
+			err = adaptors.Workflow.AddScript(workflow, scripts["script36"])
+			So(err, ShouldBeNil)
+
+			home := &m.WorkflowScenario{
+				Name:       "home",
+				SystemName: "home",
+				WorkflowId: workflow.Id,
+			}
+			home.Id, err = adaptors.WorkflowScenario.Add(home)
+			So(err, ShouldBeNil)
+
+			away := &m.WorkflowScenario{
+				Name:       "away",
+				SystemName: "away",
+				WorkflowId: workflow.Id,
+			}
+			away.Id, err = adaptors.WorkflowScenario.Add(away)
+			So(err, ShouldBeNil)
+
+			workflow.Scenario = home
+			err = adaptors.Workflow.Update(workflow)
+			So(err, ShouldBeNil)
+
+			err = c.Run()
+			So(err, ShouldBeNil)
+			defer c.Stop()
+
+			// the script handler
+			// ------------------------------------------------
+			err = c.DeviceStates.Set(device.Id, "on")
+			So(err, ShouldBeNil)
+			So(stored(1), ShouldResemble, []string{fmt.Sprintf("%d::on", device.Id)})
+
+			// the state is not in the filter
+			err = c.DeviceStates.Set(device.Id, "error")
+			So(err, ShouldBeNil)
+
+			err = c.DeviceStates.Set(device.Id, "off")
+			So(err, ShouldBeNil)
+			So(stored(2), ShouldResemble, []string{
+				fmt.Sprintf("%d::on", device.Id),
+				fmt.Sprintf("%d:error:off", device.Id),
+			})
+
+			// the handler is unsubscribed
+			err = c.DeviceStates.Set(device.Id, "on")
+			So(err, ShouldBeNil)
+			So(stored(3), ShouldHaveLength, 2)
+
+			// the go subscriber
+			// ------------------------------------------------
+			changes := make(chan core.ScenarioChanged, 1)
+			id := c.Events.Subscribe(core.EventFilter{
+				Types:  []string{core.EventScenarioChanged},
+				Fields: map[string]interface{}{"workflow_id": workflow.Id},
+			}, func(event core.Event) {
+				changes <- event.(core.ScenarioChanged)
+			})
+			defer c.Events.Unsubscribe(id)
+
+			workflowCore, err := c.GetWorkflow(workflow.Id)
+			So(err, ShouldBeNil)
+
+			err = workflowCore.SetScenario("away")
+			So(err, ShouldBeNil)
+
+			select {
+			case change := <-changes:
+				So(change.From, ShouldEqual, "home")
+				So(change.To, ShouldEqual, "away")
+				So(change.Trigger, ShouldEqual, m.ScenarioTriggerScript)
+			case <-time.After(time.Second * 3):
+				t.Fatal("scenario change event timeout")
+			}
+
+			// the flow subscription topic
+			// ------------------------------------------------
+			filter, err := core.EventTopicFilter(fmt.Sprintf("events/DeviceStateChanged?device_id=%d&to=on&to=off", device.Id))
+			So(err, ShouldBeNil)
+			So(filter.Types, ShouldResemble, []string{core.EventDeviceStateChanged})
+			So(filter.Match(core.EventFields(core.DeviceStateChanged{
+				DeviceStateChange: core.DeviceStateChange{DeviceId: device.Id, To: "off"},
+			})), ShouldBeTrue)
+			So(filter.Match(core.EventFields(core.DeviceStateChanged{
+				DeviceStateChange: core.DeviceStateChange{DeviceId: device.Id, To: "error"},
+			})), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,227 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt_client"
+	"github.com/e154/smart-home/system/scripts"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// zigbee2mqtt groups
+//
+// the group and its members are created on the bridge by the config topics,
+// the script switches the whole group by one message and stores/recalls the scene
+//
+func Test26(t *testing.T) {
+
+	type message struct {
+		topic   string
+		payload string
+	}
+
+	var lock sync.Mutex
+	var messages []message
+	var stored []string
+	store = func(i interface{}) {
+		lock.Lock()
+		stored = append(stored, fmt.Sprintf("%v", i))
+		lock.Unlock()
+	}
+
+	// the payload of the first message of the topic after the last taken one
+	var taken int
+	waitFor := func(topic string) string {
+		deadline := time.Now().Add(time.Second * 3)
+		for time.Now().Before(deadline) {
+			lock.Lock()
+			for i := taken; i < len(messages); i++ {
+				if messages[i].topic == topic {
+					taken = i + 1
+					lock.Unlock()
+					return messages[i].payload
+				}
+			}
+			lock.Unlock()
+			time.Sleep(time.Millisecond * 50)
+		}
+		return ""
+	}
+
+	Convey("zigbee2mqtt groups", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			z2m *zigbee2mqtt.Zigbee2mqtt,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// mqtt credentials
+			node := &m.Node{
+				Name:     "node26",
+				Login:    "node26",
+				Password: "node26",
+				Status:   "enabled",
+			}
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// bridge
+			// ------------------------------------------------
+			bridge := &m.Zigbee2mqtt{
+				Name:      "zigbee2mqtt26",
+				BaseTopic: "zigbee2mqtt26",
+			}
+			err = z2m.AddBridge(bridge)
+			So(err, ShouldBeNil)
+			defer z2m.DeleteBridge(bridge.Id)
+
+			for _, id := range []string{"0x00158d0001", "0x00158d0002"} {
+				err = adaptors.Zigbee2mqttDevice.Add(&m.Zigbee2mqttDevice{
+					Id:            id,
+					Zigbee2mqttId: bridge.Id,
+					Name:          id,
+					Status:        "active",
+				})
+				So(err, ShouldBeNil)
+			}
+
+			// zigbee2mqtt simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "zigbee2mqtt26_simulator",
+				Username:       node.Login,
+				Password:       node.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			err = sim.Subscribe("zigbee2mqtt26/#", 0, func(client MQTT.Client, msg MQTT.Message) {
+				lock.Lock()
+				messages = append(messages, message{topic: msg.Topic(), payload: string(msg.Payload())})
+				lock.Unlock()
+			})
+			So(err, ShouldBeNil)
+
+			// group
+			// ------------------------------------------------
+			group, err := z2m.AddGroup(&m.Zigbee2mqttGroup{
+				Zigbee2mqttId: bridge.Id,
+				FriendlyName:  "living_room",
+			})
+			So(err, ShouldBeNil)
+			So(group.GroupId, ShouldEqual, 1)
+			So(waitFor("zigbee2mqtt26/bridge/config/add_group"), ShouldEqual, `{"friendly_name":"living_room","id":1}`)
+
+			for _, id := range []string{"0x00158d0001", "0x00158d0002"} {
+				err = z2m.AddGroupDevice(bridge.Id, group.Id, id)
+				So(err, ShouldBeNil)
+				So(waitFor("zigbee2mqtt26/bridge/group/living_room/add"), ShouldEqual, id)
+			}
+
+			err = z2m.AddGroupDevice(bridge.Id, group.Id, "0x00158d0003")
+			So(err, ShouldNotBeNil)
+
+			group, err = z2m.GetGroup(bridge.Id, group.Id)
+			So(err, ShouldBeNil)
+			So(len(group.Devices), ShouldEqual, 2)
+
+			// script
+			// ------------------------------------------------
+			scripts := GetScripts(ctx, scriptService, adaptors, 37)
+
+			engine, err := scriptService.NewEngine(scripts["script37"])
+			So(err, ShouldBeNil)
+			defer engine.Close()
+			err = engine.Compile()
+			So(err, ShouldBeNil)
+			_, err = engine.Do()
+			So(err, ShouldBeNil)
+
+			So(waitFor("zigbee2mqtt26/living_room/set"), ShouldEqual, `{"brightness":128,"state":"ON"}`)
+			So(waitFor("zigbee2mqtt26/living_room/set"), ShouldEqual, `{"scene_store":1}`)
+			So(waitFor("zigbee2mqtt26/living_room/set"), ShouldEqual, `{"scene_recall":1}`)
+			So(waitFor("zigbee2mqtt26/kitchen/set"), ShouldEqual, "")
+
+			lock.Lock()
+			So(stored, ShouldResemble, []string{"kitchen not found"})
+			lock.Unlock()
+
+			group, err = z2m.GetGroup(bridge.Id, group.Id)
+			So(err, ShouldBeNil)
+			So(len(group.Scenes), ShouldEqual, 1)
+			So(group.Scenes[0].SceneId, ShouldEqual, 1)
+			So(group.Scenes[0].Name, ShouldEqual, "evening")
+
+			// dry run, the group is not switched
+			// ------------------------------------------------
+			run, err := scriptService.DryRun(scripts["script37"], nil, nil)
+			So(err, ShouldBeNil)
+			So(run.Error, ShouldEqual, "")
+			So(len(run.Calls), ShouldEqual, 4)
+			So(run.Calls[0].Name, ShouldEqual, "Zigbee2mqttGroup.Set")
+			So(run.Calls[1].Name, ShouldEqual, "Zigbee2mqttGroup.StoreScene")
+			So(run.Calls[2].Name, ShouldEqual, "Zigbee2mqttGroup.RecallScene")
+			So(run.Calls[3].Name, ShouldEqual, "Zigbee2mqttGroup.Set")
+			So(run.Calls[3].Args, ShouldResemble, []interface{}{"kitchen", map[string]interface{}{"state": "ON"}})
+			So(waitFor("zigbee2mqtt26/living_room/set"), ShouldEqual, "")
+
+			// remove
+			// ------------------------------------------------
+			err = z2m.DeleteScene(bridge.Id, group.Id, 1)
+			So(err, ShouldBeNil)
+			So(waitFor("zigbee2mqtt26/living_room/set"), ShouldEqual, `{"scene_remove":1}`)
+
+			err = z2m.RemoveGroupDevice(bridge.Id, group.Id, "0x00158d0001")
+			So(err, ShouldBeNil)
+			So(waitFor("zigbee2mqtt26/bridge/group/living_room/remove"), ShouldEqual, "0x00158d0001")
+
+			err = z2m.DeleteGroup(bridge.Id, group.Id)
+			So(err, ShouldBeNil)
+			So(waitFor("zigbee2mqtt26/bridge/config/remove_group"), ShouldEqual, "living_room")
+
+			_, err = z2m.GetGroup(bridge.Id, group.Id)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
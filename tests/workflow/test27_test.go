@@ -0,0 +1,282 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt_client"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// zigbee2mqtt 1.x request api
+//
+// the bridge uses the legacy topics until bridge/info reports the version 1.17 or newer,
+// then the commands are sent to bridge/request/* and wait for the response with the same transaction
+//
+func Test27(t *testing.T) {
+
+	var lock sync.Mutex
+	var requests = make(map[string]string)
+
+	Convey("zigbee2mqtt request api", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			z2m *zigbee2mqtt.Zigbee2mqtt,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			// mqtt credentials
+			node := &m.Node{
+				Name:     "node27",
+				Login:    "node27",
+				Password: "node27",
+				Status:   "enabled",
+			}
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// bridge
+			// ------------------------------------------------
+			bridge := &m.Zigbee2mqtt{
+				Name:      "zigbee2mqtt27",
+				BaseTopic: "zigbee2mqtt27",
+			}
+			err = z2m.AddBridge(bridge)
+			So(err, ShouldBeNil)
+			defer z2m.DeleteBridge(bridge.Id)
+
+			err = adaptors.Zigbee2mqttDevice.Add(&m.Zigbee2mqttDevice{
+				Id:            "0x00158d0001",
+				Zigbee2mqttId: bridge.Id,
+				Name:          "0x00158d0001",
+				Status:        "active",
+			})
+			So(err, ShouldBeNil)
+
+			// zigbee2mqtt simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "zigbee2mqtt27_simulator",
+				Username:       node.Login,
+				Password:       node.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			publish := func(topic string, payload interface{}) {
+				var data []byte
+				switch v := payload.(type) {
+				case string:
+					data = []byte(v)
+				default:
+					data, _ = json.Marshal(v)
+				}
+				So(sim.Publish(topic, data), ShouldBeNil)
+			}
+
+			// the bridge answers the requests, the unknown device is not removed
+			err = sim.Subscribe("zigbee2mqtt27/bridge/#", 0, func(client MQTT.Client, msg MQTT.Message) {
+				var path string
+				if strings.HasPrefix(msg.Topic(), "zigbee2mqtt27/bridge/request/") {
+					path = strings.TrimPrefix(msg.Topic(), "zigbee2mqtt27/bridge/request/")
+				} else {
+					lock.Lock()
+					requests[msg.Topic()] = string(msg.Payload())
+					lock.Unlock()
+					return
+				}
+
+				params := make(map[string]interface{})
+				_ = json.Unmarshal(msg.Payload(), &params)
+
+				lock.Lock()
+				requests[path] = string(msg.Payload())
+				lock.Unlock()
+
+				response := map[string]interface{}{
+					"data":        map[string]interface{}{},
+					"status":      "ok",
+					"transaction": params["transaction"],
+				}
+				if path == "device/remove" && params["id"] != "0x00158d0001" {
+					response["status"] = "error"
+					response["error"] = fmt.Sprintf("Device '%v' does not exist", params["id"])
+				}
+				payload, _ := json.Marshal(response)
+				client.Publish("zigbee2mqtt27/bridge/response/"+path, 0, false, payload)
+			})
+			So(err, ShouldBeNil)
+
+			getRequest := func(path string) string {
+				deadline := time.Now().Add(time.Second * 3)
+				for time.Now().Before(deadline) {
+					lock.Lock()
+					payload, ok := requests[path]
+					lock.Unlock()
+					if ok {
+						return payload
+					}
+					time.Sleep(time.Millisecond * 50)
+				}
+				return ""
+			}
+
+			waitFor := func(check func() bool) bool {
+				deadline := time.Now().Add(time.Second * 3)
+				for time.Now().Before(deadline) {
+					if check() {
+						return true
+					}
+					time.Sleep(time.Millisecond * 50)
+				}
+				return false
+			}
+
+			// legacy
+			// ------------------------------------------------
+			info, err := z2m.GetBridgeInfo(bridge.Id)
+			So(err, ShouldBeNil)
+			So(info.Protocol, ShouldEqual, zigbee2mqtt.ProtocolLegacy)
+
+			err = z2m.BridgeDeviceBan(bridge.Id, "0x00158d0009")
+			So(err, ShouldBeNil)
+			So(getRequest("zigbee2mqtt27/bridge/config/force_remove"), ShouldEqual, "0x00158d0009")
+
+			// the version of the bridge
+			// ------------------------------------------------
+			publish("zigbee2mqtt27/bridge/state", `{"state":"online"}`)
+			publish("zigbee2mqtt27/bridge/info", map[string]interface{}{
+				"version":     "1.17.0",
+				"commit":      "2cd4e5b",
+				"permit_join": false,
+				"config": map[string]interface{}{
+					"advanced": map[string]interface{}{"log_level": "info"},
+				},
+			})
+
+			So(waitFor(func() bool {
+				info, _ = z2m.GetBridgeInfo(bridge.Id)
+				return info.Protocol == zigbee2mqtt.ProtocolRequest
+			}), ShouldBeTrue)
+			So(info.Version, ShouldEqual, "1.17.0")
+			So(info.Status, ShouldEqual, "online")
+
+			// request api
+			// ------------------------------------------------
+			group, err := z2m.AddGroup(&m.Zigbee2mqttGroup{
+				Zigbee2mqttId: bridge.Id,
+				FriendlyName:  "living_room",
+			})
+			So(err, ShouldBeNil)
+			So(group.GroupId, ShouldEqual, 1)
+
+			request := make(map[string]interface{})
+			err = json.Unmarshal([]byte(getRequest("group/add")), &request)
+			So(err, ShouldBeNil)
+			So(request["friendly_name"], ShouldEqual, "living_room")
+			So(request["id"], ShouldEqual, 1)
+			So(request["transaction"], ShouldNotBeEmpty)
+
+			err = z2m.AddGroupDevice(bridge.Id, group.Id, "0x00158d0001")
+			So(err, ShouldBeNil)
+			So(getRequest("group/members/add"), ShouldContainSubstring, `"device":"0x00158d0001"`)
+
+			// the error of the bridge
+			err = z2m.BridgeDeviceBan(bridge.Id, "0x00158d0009")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "Device '0x00158d0009' does not exist")
+
+			err = z2m.BridgeDeviceBan(bridge.Id, "0x00158d0001")
+			So(err, ShouldBeNil)
+
+			// events
+			// ------------------------------------------------
+			publish("zigbee2mqtt27/bridge/event", map[string]interface{}{
+				"type": "device_interview",
+				"data": map[string]interface{}{
+					"friendly_name": "0x00158d0002",
+					"ieee_address":  "0x00158d0002",
+					"status":        "successful",
+					"supported":     true,
+					"definition": map[string]interface{}{
+						"model":       "WXKG01LM",
+						"vendor":      "Xiaomi",
+						"description": "MiJia wireless switch",
+					},
+				},
+			})
+
+			So(waitFor(func() bool {
+				device, err := adaptors.Zigbee2mqttDevice.GetById("0x00158d0002")
+				return err == nil && device.Model == "WXKG01LM"
+			}), ShouldBeTrue)
+
+			publish("zigbee2mqtt27/bridge/event", map[string]interface{}{
+				"type": "device_leave",
+				"data": map[string]interface{}{
+					"friendly_name": "0x00158d0002",
+					"ieee_address":  "0x00158d0002",
+				},
+			})
+
+			So(waitFor(func() bool {
+				device, err := adaptors.Zigbee2mqttDevice.GetById("0x00158d0002")
+				return err == nil && device.Status == "removed"
+			}), ShouldBeTrue)
+
+			// networkmap
+			// ------------------------------------------------
+			publish("zigbee2mqtt27/bridge/response/networkmap", map[string]interface{}{
+				"data": map[string]interface{}{
+					"type":  "graphviz",
+					"value": "digraph G {}",
+				},
+				"status": "ok",
+			})
+
+			So(waitFor(func() bool {
+				networkmap, _ := z2m.BridgeNetworkmap(bridge.Id)
+				return networkmap == "digraph G {}"
+			}), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,265 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	"github.com/e154/smart-home/endpoint"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt_client"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// zigbee2mqtt capabilities
+//
+// the bulb comes with the exposes in bridge/devices, the device gets the actions and the states from them,
+// the generated action switches the bulb without the hand written script,
+// the state payload of the bulb switches the generated state of the device
+//
+func Test28(t *testing.T) {
+
+	const bulb = "0x00158d0028"
+
+	var lock sync.Mutex
+	var payloads []string
+
+	Convey("zigbee2mqtt capabilities", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			endpoint *endpoint.Endpoint,
+			z2m *zigbee2mqtt.Zigbee2mqtt,
+			c *core.Core,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			// mqtt credentials
+			node := &m.Node{
+				Name:     "node28",
+				Login:    "node28",
+				Password: "node28",
+				Status:   "enabled",
+			}
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// bridge
+			// ------------------------------------------------
+			bridge := &m.Zigbee2mqtt{
+				Name:      "zigbee2mqtt28",
+				BaseTopic: "zigbee2mqtt28",
+			}
+			err = z2m.AddBridge(bridge)
+			So(err, ShouldBeNil)
+			defer z2m.DeleteBridge(bridge.Id)
+
+			// zigbee2mqtt simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "zigbee2mqtt28_simulator",
+				Username:       node.Login,
+				Password:       node.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			err = sim.Subscribe(fmt.Sprintf("zigbee2mqtt28/%s/set", bulb), 0, func(client MQTT.Client, msg MQTT.Message) {
+				lock.Lock()
+				payloads = append(payloads, string(msg.Payload()))
+				lock.Unlock()
+			})
+			So(err, ShouldBeNil)
+
+			devices := `[
+  {"ieee_address": "0x00124b0018e2a2b1", "friendly_name": "Coordinator", "type": "Coordinator"},
+  {
+    "ieee_address": "0x00158d0028",
+    "friendly_name": "0x00158d0028",
+    "type": "Router",
+    "supported": true,
+    "definition": {
+      "model": "LED1545G12",
+      "vendor": "IKEA",
+      "description": "TRADFRI LED bulb E26/E27 980 lumen, dimmable, white spectrum, opal white",
+      "exposes": [
+        {
+          "type": "light",
+          "features": [
+            {"type": "binary", "name": "state", "property": "state", "access": 7, "value_on": "ON", "value_off": "OFF", "value_toggle": "TOGGLE"},
+            {"type": "numeric", "name": "brightness", "property": "brightness", "access": 7, "value_min": 0, "value_max": 254},
+            {
+              "type": "composite", "name": "color_xy", "property": "color",
+              "features": [
+                {"type": "numeric", "name": "x", "property": "x", "access": 7},
+                {"type": "numeric", "name": "y", "property": "y", "access": 7}
+              ]
+            }
+          ]
+        },
+        {"type": "enum", "name": "effect", "property": "effect", "access": 2, "values": ["blink", "breathe"]},
+        {"type": "numeric", "name": "linkquality", "property": "linkquality", "access": 1, "unit": "lqi", "value_min": 0, "value_max": 255}
+      ]
+    }
+  }
+]`
+			err = sim.Publish("zigbee2mqtt28/bridge/devices", []byte(devices))
+			So(err, ShouldBeNil)
+
+			var zigbeeDevice *m.Zigbee2mqttDevice
+			deadline := time.Now().Add(time.Second * 3)
+			for time.Now().Before(deadline) {
+				if zigbeeDevice, err = adaptors.Zigbee2mqttDevice.GetById(bulb); err == nil && len(zigbeeDevice.Exposes) > 0 {
+					break
+				}
+				time.Sleep(time.Millisecond * 50)
+			}
+			So(err, ShouldBeNil)
+			So(zigbeeDevice.Model, ShouldEqual, "LED1545G12")
+			So(len(zigbeeDevice.Exposes), ShouldEqual, 3)
+			So(len(zigbeeDevice.Exposes[0].Features), ShouldEqual, 3)
+			So(*zigbeeDevice.Exposes[0].Features[1].ValueMax, ShouldEqual, 254)
+			So(zigbeeDevice.Functions, ShouldContain, "brightness")
+
+			// device
+			// ------------------------------------------------
+			properties, _ := json.Marshal(map[string]interface{}{"zigbee2mqtt_device_id": bulb})
+			device, errs, err := endpoint.Device.Add(&m.Device{
+				Name:       "bulb",
+				Status:     "enabled",
+				Type:       "zigbee2mqtt",
+				Properties: properties,
+			})
+			So(errs, ShouldBeEmpty)
+			So(err, ShouldBeNil)
+
+			actionNames := func(device *m.Device) (names []string) {
+				for _, action := range device.Actions {
+					names = append(names, action.Name)
+				}
+				sort.Strings(names)
+				return
+			}
+			stateNames := func(device *m.Device) (names []string) {
+				for _, state := range device.States {
+					names = append(names, state.SystemName)
+				}
+				sort.Strings(names)
+				return
+			}
+
+			So(actionNames(device), ShouldResemble, []string{
+				"brightness_max", "brightness_min",
+				"color_blue", "color_green", "color_red", "color_white",
+				"effect_blink", "effect_breathe",
+				"state_off", "state_on", "state_toggle",
+			})
+			So(stateNames(device), ShouldResemble, []string{"state_off", "state_on"})
+
+			// the script name contains the device id
+			for _, action := range device.Actions {
+				if action.Name == "state_on" {
+					So(action.Script.Name, ShouldEqual, fmt.Sprintf("%s_%d_state_on", bulb, device.Id))
+				}
+			}
+
+			// the action
+			// ------------------------------------------------
+			for _, name := range []string{"state_on", "brightness_max", "color_red"} {
+				for _, action := range device.Actions {
+					if action.Name == name {
+						_, err = c.DoAction(action.Id)
+						So(err, ShouldBeNil)
+					}
+				}
+			}
+
+			deadline = time.Now().Add(time.Second * 3)
+			for time.Now().Before(deadline) {
+				lock.Lock()
+				n := len(payloads)
+				lock.Unlock()
+				if n >= 3 {
+					break
+				}
+				time.Sleep(time.Millisecond * 50)
+			}
+
+			lock.Lock()
+			So(payloads, ShouldResemble, []string{
+				`{"state":"ON"}`,
+				`{"brightness":254}`,
+				`{"color":{"hex":"#FF0000"}}`,
+			})
+			lock.Unlock()
+
+			// the state payload
+			// ------------------------------------------------
+			waitState := func(want string) (systemName string) {
+				deadline := time.Now().Add(time.Second * 3)
+				for time.Now().Before(deadline) {
+					if state, err := c.DeviceStates.Get(device.Id); err == nil && state.DeviceState != nil {
+						if systemName = state.DeviceState.SystemName; systemName == want {
+							return
+						}
+					}
+					time.Sleep(time.Millisecond * 50)
+				}
+				return
+			}
+
+			err = sim.Publish(fmt.Sprintf("zigbee2mqtt28/%s", bulb), []byte(`{"state":"ON","brightness":254,"linkquality":80}`))
+			So(err, ShouldBeNil)
+			So(waitState("state_on"), ShouldEqual, "state_on")
+
+			// the bulb is switched off outside of the system
+			err = sim.Publish(fmt.Sprintf("zigbee2mqtt28/%s", bulb), []byte(`{"state":"OFF","brightness":10}`))
+			So(err, ShouldBeNil)
+			So(waitState("state_off"), ShouldEqual, "state_off")
+
+			// the existing actions are kept
+			// ------------------------------------------------
+			device, err = endpoint.Device.AddCapabilities(device.Id)
+			So(err, ShouldBeNil)
+			So(len(device.Actions), ShouldEqual, 11)
+			So(len(device.States), ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,301 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt_client"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// zigbee2mqtt ota updates
+//
+// the check of the device gets the available update, the update waits for the window of the bridge,
+// the progress comes with the state of the device and the end of the update with the response
+//
+func Test29(t *testing.T) {
+
+	var lock sync.Mutex
+	var requests = make(map[string]string)
+	var events []zigbee2mqtt.OtaStatus
+
+	Convey("zigbee2mqtt ota updates", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			z2m *zigbee2mqtt.Zigbee2mqtt,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			// mqtt credentials
+			node := &m.Node{
+				Name:     "node29",
+				Login:    "node29",
+				Password: "node29",
+				Status:   "enabled",
+			}
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// bridge
+			// ------------------------------------------------
+			bridge := &m.Zigbee2mqtt{
+				Name:      "zigbee2mqtt29",
+				BaseTopic: "zigbee2mqtt29",
+			}
+			err = z2m.AddBridge(bridge)
+			So(err, ShouldBeNil)
+			defer z2m.DeleteBridge(bridge.Id)
+
+			z2m.SubscribeOta("test29", func(status zigbee2mqtt.OtaStatus) {
+				lock.Lock()
+				events = append(events, status)
+				lock.Unlock()
+			})
+			defer z2m.UnsubscribeOta("test29")
+
+			// zigbee2mqtt simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "zigbee2mqtt29_simulator",
+				Username:       node.Login,
+				Password:       node.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			publish := func(topic string, payload interface{}) {
+				data, _ := json.Marshal(payload)
+				So(sim.Publish(topic, data), ShouldBeNil)
+			}
+
+			// the check is answered at once, the update is answered by the test
+			err = sim.Subscribe("zigbee2mqtt29/bridge/request/#", 0, func(client MQTT.Client, msg MQTT.Message) {
+				path := strings.TrimPrefix(msg.Topic(), "zigbee2mqtt29/bridge/request/")
+
+				params := make(map[string]interface{})
+				_ = json.Unmarshal(msg.Payload(), &params)
+
+				lock.Lock()
+				requests[path] = string(msg.Payload())
+				lock.Unlock()
+
+				var data = map[string]interface{}{}
+				switch path {
+				case "device/ota_update/update":
+					return
+				case "device/ota_update/check":
+					data = map[string]interface{}{
+						"id":              params["id"],
+						"updateAvailable": true,
+					}
+				}
+
+				payload, _ := json.Marshal(map[string]interface{}{
+					"data":        data,
+					"status":      "ok",
+					"transaction": params["transaction"],
+				})
+				client.Publish("zigbee2mqtt29/bridge/response/"+path, 0, false, payload)
+			})
+			So(err, ShouldBeNil)
+
+			getRequest := func(path string) string {
+				deadline := time.Now().Add(time.Second * 3)
+				for time.Now().Before(deadline) {
+					lock.Lock()
+					payload, ok := requests[path]
+					lock.Unlock()
+					if ok {
+						return payload
+					}
+					time.Sleep(time.Millisecond * 50)
+				}
+				return ""
+			}
+
+			getStatus := func() (status zigbee2mqtt.OtaStatus) {
+				list, err := z2m.BridgeOtaStatus(bridge.Id)
+				So(err, ShouldBeNil)
+				for _, status = range list {
+					if status.DeviceId == "0x00158d0001" {
+						return
+					}
+				}
+				return zigbee2mqtt.OtaStatus{}
+			}
+
+			waitFor := func(state string) bool {
+				deadline := time.Now().Add(time.Second * 3)
+				for time.Now().Before(deadline) {
+					if getStatus().Status == state {
+						return true
+					}
+					time.Sleep(time.Millisecond * 50)
+				}
+				return false
+			}
+
+			// the request api and the device
+			// ------------------------------------------------
+			publish("zigbee2mqtt29/bridge/info", map[string]interface{}{
+				"version":     "1.17.0",
+				"permit_join": false,
+			})
+			publish("zigbee2mqtt29/bridge/event", map[string]interface{}{
+				"type": "device_interview",
+				"data": map[string]interface{}{
+					"friendly_name": "0x00158d0001",
+					"ieee_address":  "0x00158d0001",
+					"status":        "successful",
+					"supported":     true,
+					"definition": map[string]interface{}{
+						"model":       "ZNLDP12LM",
+						"vendor":      "Xiaomi",
+						"description": "Aqara smart LED bulb",
+					},
+				},
+			})
+
+			deadline := time.Now().Add(time.Second * 3)
+			for time.Now().Before(deadline) {
+				info, _ := z2m.GetBridgeInfo(bridge.Id)
+				_, err = adaptors.Zigbee2mqttDevice.GetById("0x00158d0001")
+				if info.Protocol == zigbee2mqtt.ProtocolRequest && err == nil {
+					break
+				}
+				time.Sleep(time.Millisecond * 50)
+			}
+			So(err, ShouldBeNil)
+
+			// check
+			// ------------------------------------------------
+			err = z2m.BridgeOtaCheck(bridge.Id, "0x00158d0001")
+			So(err, ShouldBeNil)
+			So(getRequest("device/ota_update/check"), ShouldContainSubstring, `"id":"0x00158d0001"`)
+
+			So(waitFor(zigbee2mqtt.OtaAvailable), ShouldBeTrue)
+			So(getStatus().CheckedAt, ShouldNotBeNil)
+
+			err = z2m.BridgeOtaCheck(bridge.Id, "0x00158d0009")
+			So(err, ShouldNotBeNil)
+
+			// the window is closed
+			// ------------------------------------------------
+			model, err := z2m.GetBridgeById(bridge.Id)
+			So(err, ShouldBeNil)
+			now := time.Now()
+			model.OtaWindowStart = now.Add(time.Hour * 2).Format("15:04")
+			model.OtaWindowEnd = now.Add(time.Hour * 3).Format("15:04")
+			_, err = z2m.UpdateBridge(model)
+			So(err, ShouldBeNil)
+
+			err = z2m.BridgeOtaUpdate(bridge.Id, "")
+			So(err, ShouldBeNil)
+			So(getStatus().Status, ShouldEqual, zigbee2mqtt.OtaScheduled)
+
+			time.Sleep(time.Millisecond * 300)
+			lock.Lock()
+			_, ok := requests["device/ota_update/update"]
+			lock.Unlock()
+			So(ok, ShouldBeFalse)
+
+			// the window is opened
+			// ------------------------------------------------
+			model.OtaWindowStart = now.Add(-time.Hour).Format("15:04")
+			model.OtaWindowEnd = now.Add(time.Hour).Format("15:04")
+			_, err = z2m.UpdateBridge(model)
+			So(err, ShouldBeNil)
+
+			So(getStatus().Status, ShouldEqual, zigbee2mqtt.OtaUpdating)
+			So(getRequest("device/ota_update/update"), ShouldContainSubstring, `"id":"0x00158d0001"`)
+
+			// progress
+			// ------------------------------------------------
+			publish("zigbee2mqtt29/0x00158d0001", map[string]interface{}{
+				"state": "ON",
+				"update": map[string]interface{}{
+					"state":     "updating",
+					"progress":  50,
+					"remaining": 60,
+				},
+			})
+
+			deadline = time.Now().Add(time.Second * 3)
+			for time.Now().Before(deadline) && getStatus().Progress != 50 {
+				time.Sleep(time.Millisecond * 50)
+			}
+			status := getStatus()
+			So(status.Status, ShouldEqual, zigbee2mqtt.OtaUpdating)
+			So(status.Progress, ShouldEqual, 50)
+			So(status.Remaining, ShouldEqual, 60)
+
+			// the end of the update
+			// ------------------------------------------------
+			publish("zigbee2mqtt29/bridge/response/device/ota_update/update", map[string]interface{}{
+				"data": map[string]interface{}{
+					"id": "0x00158d0001",
+				},
+				"status": "ok",
+			})
+
+			So(waitFor(zigbee2mqtt.OtaSucceeded), ShouldBeTrue)
+			So(getStatus().Progress, ShouldEqual, 100)
+
+			// the subscribers got all changes
+			lock.Lock()
+			var states []string
+			for _, event := range events {
+				if event.DeviceId == "0x00158d0001" && (len(states) == 0 || states[len(states)-1] != event.Status) {
+					states = append(states, event.Status)
+				}
+			}
+			lock.Unlock()
+			So(states, ShouldResemble, []string{
+				zigbee2mqtt.OtaChecking,
+				zigbee2mqtt.OtaAvailable,
+				zigbee2mqtt.OtaScheduled,
+				zigbee2mqtt.OtaUpdating,
+				zigbee2mqtt.OtaSucceeded,
+			})
+		})
+	})
+}
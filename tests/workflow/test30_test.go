@@ -0,0 +1,226 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/e154/smart-home/adaptors"
+	"github.com/e154/smart-home/endpoint"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/config"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/mqtt_client"
+	"github.com/e154/smart-home/system/zigbee2mqtt"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+//
+// zigbee2mqtt network map
+//
+// the raw map of the scan is stored with the time of the scan,
+// the analysis finds the weak links, the end devices without the parent and the overloaded routers
+//
+func Test30(t *testing.T) {
+
+	Convey("zigbee2mqtt network map", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			endpoint *endpoint.Endpoint,
+			z2m *zigbee2mqtt.Zigbee2mqtt,
+			cfg *config.AppConfig) {
+
+			// clear database
+			// ------------------------------------------------
+			err := migrations.Purge()
+			So(err, ShouldBeNil)
+
+			// mqtt credentials
+			node := &m.Node{
+				Name:     "node30",
+				Login:    "node30",
+				Password: "node30",
+				Status:   "enabled",
+			}
+			node.Id, err = adaptors.Node.Add(node)
+			So(err, ShouldBeNil)
+
+			// bridge
+			// ------------------------------------------------
+			bridge := &m.Zigbee2mqtt{
+				Name:      "zigbee2mqtt30",
+				BaseTopic: "zigbee2mqtt30",
+			}
+			err = z2m.AddBridge(bridge)
+			So(err, ShouldBeNil)
+			defer z2m.DeleteBridge(bridge.Id)
+
+			// zigbee2mqtt simulator
+			// ------------------------------------------------
+			sim, err := mqtt_client.NewClient(&mqtt_client.Config{
+				KeepAlive:      300,
+				PingTimeout:    5,
+				ConnectTimeout: 5,
+				CleanSession:   true,
+				Broker:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.MqttPort),
+				ClientID:       "zigbee2mqtt30_simulator",
+				Username:       node.Login,
+				Password:       node.Password,
+			})
+			So(err, ShouldBeNil)
+			err = sim.Connect()
+			So(err, ShouldBeNil)
+			defer sim.Disconnect()
+
+			publish := func(topic string, payload interface{}) {
+				data, _ := json.Marshal(payload)
+				So(sim.Publish(topic, data), ShouldBeNil)
+			}
+
+			waitFor := func(check func() bool) bool {
+				deadline := time.Now().Add(time.Second * 3)
+				for time.Now().Before(deadline) {
+					if check() {
+						return true
+					}
+					time.Sleep(time.Millisecond * 50)
+				}
+				return false
+			}
+
+			addr := func(ieeeAddr string) map[string]interface{} {
+				return map[string]interface{}{"ieeeAddr": ieeeAddr}
+			}
+
+			// the coordinator, the router with three children and the lost end device
+			nodes := []map[string]interface{}{
+				{"ieeeAddr": "0x00124b0001", "friendlyName": "Coordinator", "type": "Coordinator", "networkAddress": 0},
+				{"ieeeAddr": "0x00158d0001", "friendlyName": "plug", "type": "Router", "networkAddress": 4660, "modelID": "lumi.plug"},
+				{"ieeeAddr": "0x00158d0002", "friendlyName": "sensor_kitchen", "type": "EndDevice", "networkAddress": 1},
+				{"ieeeAddr": "0x00158d0003", "friendlyName": "sensor_hall", "type": "EndDevice", "networkAddress": 2},
+				{"ieeeAddr": "0x00158d0004", "friendlyName": "sensor_garage", "type": "EndDevice", "networkAddress": 3},
+				{"ieeeAddr": "0x00158d0005", "friendlyName": "sensor_attic", "type": "EndDevice", "networkAddress": 4},
+			}
+			links := []map[string]interface{}{
+				{"source": addr("0x00158d0001"), "target": addr("0x00124b0001"), "linkquality": 110, "depth": 1, "relationship": 1},
+				{"source": addr("0x00158d0002"), "target": addr("0x00158d0001"), "linkquality": 95, "depth": 2, "relationship": 1},
+				{"source": addr("0x00158d0003"), "target": addr("0x00158d0001"), "linkquality": 40, "depth": 2, "relationship": 1},
+				{"source": addr("0x00158d0004"), "target": addr("0x00158d0001"), "linkquality": 12, "depth": 2, "relationship": 1},
+				{"source": addr("0x00158d0005"), "target": addr("0x00158d0001"), "linkquality": 60, "depth": 2, "relationship": 4},
+			}
+
+			// the request api
+			// ------------------------------------------------
+			publish("zigbee2mqtt30/bridge/info", map[string]interface{}{
+				"version":     "1.17.0",
+				"permit_join": false,
+			})
+			So(waitFor(func() bool {
+				info, _ := z2m.GetBridgeInfo(bridge.Id)
+				return info.Protocol == zigbee2mqtt.ProtocolRequest
+			}), ShouldBeTrue)
+
+			publish("zigbee2mqtt30/bridge/response/networkmap", map[string]interface{}{
+				"data": map[string]interface{}{
+					"type":   "raw",
+					"routes": false,
+					"value": map[string]interface{}{
+						"nodes": nodes,
+						"links": links,
+					},
+				},
+				"status": "ok",
+			})
+
+			var list []*m.Zigbee2mqttNetworkmap
+			So(waitFor(func() bool {
+				list, _, _ = endpoint.Zigbee2mqtt.NetworkmapList(bridge.Id, 10, 0)
+				return len(list) == 1
+			}), ShouldBeTrue)
+			So(len(list[0].Nodes), ShouldEqual, 6)
+			So(len(list[0].Links), ShouldEqual, 5)
+			So(list[0].Links[3].Lqi, ShouldEqual, 12)
+
+			// the graphviz map is made from the raw map
+			networkmap, err := z2m.BridgeNetworkmap(bridge.Id)
+			So(err, ShouldBeNil)
+			So(networkmap, ShouldStartWith, "digraph G {")
+			So(networkmap, ShouldContainSubstring, `"0x00158d0004" -> "0x00158d0001"`)
+
+			// analysis
+			// ------------------------------------------------
+			report, err := endpoint.Zigbee2mqtt.NetworkmapAnalysis(bridge.Id, 0, 0, 2)
+			So(err, ShouldBeNil)
+			So(report.NetworkmapId, ShouldEqual, list[0].Id)
+			So(report.MinLqi, ShouldEqual, zigbee2mqtt.NetworkmapMinLqi)
+			So(report.Routers, ShouldEqual, 1)
+			So(report.EndDevices, ShouldEqual, 4)
+
+			So(len(report.WeakLinks), ShouldEqual, 2)
+			So(report.WeakLinks[0].SourceName, ShouldEqual, "sensor_garage")
+			So(report.WeakLinks[0].Lqi, ShouldEqual, 12)
+			So(report.WeakLinks[1].SourceName, ShouldEqual, "sensor_hall")
+
+			So(len(report.Orphans), ShouldEqual, 1)
+			So(report.Orphans[0].FriendlyName, ShouldEqual, "sensor_attic")
+
+			So(len(report.OverloadedRouters), ShouldEqual, 1)
+			So(report.OverloadedRouters[0].FriendlyName, ShouldEqual, "plug")
+			So(report.OverloadedRouters[0].Children, ShouldEqual, 3)
+
+			// the next scan, the router has lost the child
+			// ------------------------------------------------
+			publish("zigbee2mqtt30/bridge/response/networkmap", map[string]interface{}{
+				"data": map[string]interface{}{
+					"type":   "raw",
+					"routes": false,
+					"value": map[string]interface{}{
+						"nodes": nodes,
+						"links": links[:3],
+					},
+				},
+				"status": "ok",
+			})
+
+			So(waitFor(func() bool {
+				list, _, _ = endpoint.Zigbee2mqtt.NetworkmapList(bridge.Id, 10, 0)
+				return len(list) == 2
+			}), ShouldBeTrue)
+			So(len(list[0].Links), ShouldEqual, 3)
+			So(list[0].CreatedAt.After(list[1].CreatedAt), ShouldBeTrue)
+
+			report, err = endpoint.Zigbee2mqtt.NetworkmapAnalysis(bridge.Id, 0, 0, 0)
+			So(err, ShouldBeNil)
+			So(report.NetworkmapId, ShouldEqual, list[0].Id)
+			So(len(report.Orphans), ShouldEqual, 2)
+			So(len(report.OverloadedRouters), ShouldEqual, 0)
+
+			// the previous scan
+			report, err = endpoint.Zigbee2mqtt.NetworkmapAnalysis(bridge.Id, list[1].Id, 0, 2)
+			So(err, ShouldBeNil)
+			So(len(report.Orphans), ShouldEqual, 1)
+
+			_, err = endpoint.Zigbee2mqtt.NetworkmapAnalysis(bridge.Id, list[1].Id+100, 0, 0)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "record not found")
+		})
+	})
+}
@@ -0,0 +1,126 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2020, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package workflow
+
+import (
+	"github.com/e154/smart-home/adaptors"
+	"github.com/e154/smart-home/endpoint"
+	m "github.com/e154/smart-home/models"
+	"github.com/e154/smart-home/system/core"
+	"github.com/e154/smart-home/system/migrations"
+	"github.com/e154/smart-home/system/scripts"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+//
+// the workflow script requires the stored library (script38 <- script39),
+// the update of the library restarts the workflow with the new exports
+//
+func Test31(t *testing.T) {
+
+	var story []string
+
+	store = func(i interface{}) {
+		story = append(story, i.(string))
+	}
+
+	Convey("reload the workflow after the update of the module", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			scriptService *scripts.ScriptService,
+			endpoint *endpoint.Endpoint,
+			c *core.Core) {
+
+			// stop core
+			// ------------------------------------------------
+			err := c.Stop()
+			So(err, ShouldBeNil)
+
+			// clear database
+			// ------------------------------------------------
+			err = migrations.Purge()
+			So(err, ShouldBeNil)
+
+			storeRegisterCallback(scriptService)
+
+			// scripts
+			// ------------------------------------------------
+			addScript := func(name, source string) *m.Script {
+				script := &m.Script{
+					Lang:   "coffeescript",
+					Name:   name,
+					Source: source,
+				}
+				engine, err := scriptService.NewEngine(script)
+				So(err, ShouldBeNil)
+				err = engine.Compile()
+				So(err, ShouldBeNil)
+				script.Id, err = adaptors.Script.Add(script)
+				So(err, ShouldBeNil)
+				return script
+			}
+
+			lib := addScript("test31_lib", coffeeScript38)
+			wfScript := addScript("test31_workflow", coffeeScript39)
+
+			// workflow
+			// ------------------------------------------------
+			workflow := &m.Workflow{
+				Name:   "workflow31",
+				Status: "enabled",
+			}
+			workflow.Id, err = adaptors.Workflow.Add(workflow)
+			So(err, ShouldBeNil)
+
+			err = adaptors.Workflow.AddScript(workflow, wfScript)
+			So(err, ShouldBeNil)
+
+			wfScenario := &m.WorkflowScenario{
+				Name:       "wf scenario 31",
+				SystemName: "wf_scenario_31",
+				WorkflowId: workflow.Id,
+			}
+			wfScenario.Id, err = adaptors.WorkflowScenario.Add(wfScenario)
+			So(err, ShouldBeNil)
+
+			workflow.Scenario = wfScenario
+			err = adaptors.Workflow.Update(workflow)
+			So(err, ShouldBeNil)
+
+			err = c.Run()
+			So(err, ShouldBeNil)
+
+			So(story, ShouldResemble, []string{"workflow:v1"})
+			So(scriptService.Modules().Dependents(lib.Name), ShouldResemble, []string{wfScript.Name})
+
+			// update the library
+			// ------------------------------------------------
+			lib.Source = "module.exports =\n    version: 'v2'\n"
+			_, errs, err := endpoint.Script.Update(lib)
+			So(errs, ShouldBeEmpty)
+			So(err, ShouldBeNil)
+
+			So(story, ShouldResemble, []string{"workflow:v1", "workflow:v2"})
+
+			err = c.Stop()
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -246,11 +246,32 @@ func Test4(t *testing.T) {
 			worker.Id, err = adaptors.Worker.Add(worker)
 			So(err, ShouldBeNil)
 
+			// the worker with the bad time string is skipped, the flow runs with the rest
+			badWorker := &m.Worker{
+				Name:           "bad worker",
+				Time:           "0 0 0 31 2 *",
+				Status:         "enabled",
+				WorkflowId:     workflow.Id,
+				FlowId:         flow1.Id,
+				DeviceActionId: deviceAction.Id,
+			}
+
+			ok, _ = badWorker.Valid()
+			So(ok, ShouldEqual, false)
+
+			badWorker.Id, err = adaptors.Worker.Add(badWorker)
+			So(err, ShouldBeNil)
+
 			// get flow
 			// ------------------------------------------------
 			err = c.Run()
 			So(err, ShouldBeNil)
 
+			flow, err := c.GetFlow(flow1.Id)
+			So(err, ShouldBeNil)
+			So(len(flow.Workers), ShouldEqual, 1)
+			So(flow.Workers[worker.Id], ShouldNotBeNil)
+
 			err = c.Stop()
 			So(err, ShouldBeNil)
 		})
@@ -57,6 +57,7 @@ options:
 -restore    - restore settings from backup archive
 -reset      - cleanup and restore base settings
 -demo		- install demo settings
+-test-scripts [id] - run test cases of the scripts
 help	    - show this help text
 `
 